@@ -0,0 +1,234 @@
+// Package client is a Go API for winc that calls into the runtime directly
+// instead of running winc.exe. Errors are returned as the runtime produced
+// them, so the typed errors of the hcs, runtime, runtime/config and
+// runtime/container packages can be matched with errors.As.
+package client
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/hook"
+	"code.cloudfoundry.org/winc/runtime/mount"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultRootDir     = `C:\ProgramData\winc`
+	DefaultLockTimeout = 30 * time.Second
+)
+
+// Config is the equivalent of the global flags of winc.exe. Zero values use
+// the same defaults.
+type Config struct {
+	RootDir     string
+	LockTimeout time.Duration
+
+	// Version is recorded in the state of the containers the client creates.
+	Version string
+
+	// Tracer, if set, records the calls made to HCS, as --hcs-trace does.
+	Tracer *hcs.Tracer
+}
+
+type Client struct {
+	runtime *runtime.Runtime
+}
+
+// New returns a client that operates on the containers under config.RootDir.
+func New(config Config) *Client {
+	if config.RootDir == "" {
+		config.RootDir = DefaultRootDir
+	}
+	if config.LockTimeout == 0 {
+		config.LockTimeout = DefaultLockTimeout
+	}
+
+	r := runtime.New(
+		&stateFactory{lockTimeout: config.LockTimeout, version: config.Version},
+		&containerFactory{},
+		&mount.Mounter{},
+		&hcs.Client{Tracer: config.Tracer},
+		&processWrapper{},
+		&hook.Runner{},
+		config.RootDir,
+	).WithTracer(config.Tracer)
+	return NewFromRuntime(r)
+}
+
+// NewFromRuntime returns a client that operates through r.
+func NewFromRuntime(r *runtime.Runtime) *Client {
+	return &Client{runtime: r}
+}
+
+type CreateOpts struct {
+	// ConsoleSocket is the path of a unix socket the console of a terminal
+	// init process is relayed over by Monitor once it has started.
+	ConsoleSocket string
+}
+
+// Create creates the container id from the bundle at bundlePath.
+func (c *Client) Create(ctx context.Context, id, bundlePath string, opts CreateOpts) error {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Create(id, bundlePath, opts.ConsoleSocket)
+}
+
+// Start starts the init process of the container id.
+func (c *Client) Start(ctx context.Context, id string) error {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Start(id, "")
+}
+
+type ExecOpts struct {
+	// ExecID addresses the process in State, Kill and Wait. One is generated
+	// if it is empty, which leaves a detached process unaddressable.
+	ExecID string
+	IO     runtime.IO
+	Detach bool
+}
+
+// Exec runs process in the container id. Unless opts.Detach is set it waits
+// for the process to exit and returns its exit code. The console of a
+// detached process is relayed, and its exit code recorded, by Monitor.
+func (c *Client) Exec(ctx context.Context, id string, process *specs.Process, opts ExecOpts) (int, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	return r.Exec(id, opts.ExecID, process, "", opts.IO, opts.Detach)
+}
+
+// Monitor waits for the init process of the container id, or the process
+// exec'd under execId if it is not empty, to exit. Until then it relays the
+// console of the process over its console socket, if it has one. The exit code
+// of the process is recorded for Wait. winc start runs it in a detached
+// process, callers of the client usually run it in a goroutine.
+func (c *Client) Monitor(ctx context.Context, id, execId string) error {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Monitor(id, execId)
+}
+
+// Kill sends signal to the init process of the container id, or to the process
+// exec'd under execId if it is not empty.
+func (c *Client) Kill(ctx context.Context, id, signal, execId string) error {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Kill(id, signal, false, execId)
+}
+
+// Wait waits for the init process of the container id, or the process exec'd
+// under execId if it is not empty, to exit and returns its exit code.
+func (c *Client) Wait(ctx context.Context, id, execId string) (int, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	exit, err := r.Wait(id, 0, execId, 0)
+	if err != nil {
+		return 1, err
+	}
+	return exit.ExitCode, nil
+}
+
+type DeleteOpts struct {
+	// Force succeeds if the container does not exist.
+	Force bool
+	Stop  container.StopPolicy
+}
+
+// Delete stops and deletes the container id and its sidecars, and reports how
+// each was stopped.
+func (c *Client) Delete(ctx context.Context, id string, opts DeleteOpts) ([]runtime.DeleteResult, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Delete(id, opts.Force, opts.Stop)
+}
+
+// State returns the state of the container id.
+func (c *Client) State(ctx context.Context, id string) (*specs.State, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.State(id)
+}
+
+// ProcessState returns the state of the process exec'd under execId in the
+// container id.
+func (c *Client) ProcessState(ctx context.Context, id, execId string) (*runtime.ProcessState, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ProcessState(id, execId)
+}
+
+// Stats returns the resource usage of the container id.
+func (c *Client) Stats(ctx context.Context, id string) (container.Statistics, error) {
+	r, err := c.withContext(ctx)
+	if err != nil {
+		return container.Statistics{}, err
+	}
+
+	return r.Stats(id)
+}
+
+// withContext returns the runtime bound to ctx, or the error of ctx if it is
+// already done.
+func (c *Client) withContext(ctx context.Context) (*runtime.Runtime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.runtime.WithContext(ctx), nil
+}
+
+type stateFactory struct {
+	lockTimeout time.Duration
+	version     string
+}
+
+func (f *stateFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, winSyscall *winsyscall.WinSyscall, id, rootDir string) runtime.StateManager {
+	return state.New(logger, hcsClient, winSyscall, id, rootDir, f.lockTimeout, f.version).WithContext(hcsClient.Context)
+}
+
+type containerFactory struct{}
+
+func (f *containerFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, id string) runtime.ContainerManager {
+	return container.New(logger, hcsClient, id)
+}
+
+type processWrapper struct{}
+
+func (w *processWrapper) Wrap(p hcs.Process) runtime.WrappedProcess {
+	return hcsprocess.New(p)
+}
@@ -0,0 +1,18 @@
+package client_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	logrus.SetOutput(ioutil.Discard)
+
+	RunSpecs(t, "Client Suite")
+}
@@ -0,0 +1,286 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/winc/client"
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Client", func() {
+	const (
+		containerId = "some-container"
+		bundlePath  = "some/bundle"
+		rootDir     = "some-root-dir"
+	)
+
+	var (
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		wrappedProcess   *fakes.WrappedProcess
+		hcsQuery         *fakes.HCSQuery
+		c                *client.Client
+		ctx              context.Context
+	)
+
+	BeforeEach(func() {
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		wrappedProcess = &fakes.WrappedProcess{}
+		hcsQuery = &fakes.HCSQuery{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+		processWrapper.WrapReturns(wrappedProcess)
+
+		r := runtime.New(stateFactory, containerFactory, &fakes.Mounter{}, hcsQuery, processWrapper, &fakes.HookRunner{}, rootDir)
+		c = client.NewFromRuntime(r)
+		ctx = context.Background()
+	})
+
+	It("binds the hcs client the runtime uses to the context", func() {
+		sm.StateReturns(&specs.State{ID: containerId, Status: "running"}, nil)
+		ctx = context.WithValue(ctx, "some-key", "some-value")
+
+		_, err := c.State(ctx, containerId)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, hcsClient, _, _, _ := stateFactory.NewManagerArgsForCall(0)
+		Expect(hcsClient.Context).To(Equal(ctx))
+	})
+
+	Context("the context is already done", func() {
+		BeforeEach(func() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(ctx)
+			cancel()
+		})
+
+		It("returns the error of the context without doing anything", func() {
+			Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(MatchError(context.Canceled))
+			_, err := c.Delete(ctx, containerId, client.DeleteOpts{})
+			Expect(err).To(MatchError(context.Canceled))
+
+			Expect(stateFactory.NewManagerCallCount()).To(Equal(0))
+			Expect(containerFactory.NewManagerCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Create", func() {
+		BeforeEach(func() {
+			cm.SpecReturns(&specs.Spec{Process: &specs.Process{}}, nil)
+		})
+
+		It("creates the container from the bundle", func() {
+			Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(Succeed())
+
+			_, _, id := containerFactory.NewManagerArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			_, _, _, _, rd := stateFactory.NewManagerArgsForCall(0)
+			Expect(rd).To(Equal(rootDir))
+
+			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+			Expect(cm.CreateCallCount()).To(Equal(1))
+		})
+
+		Context("a console socket is passed for a process without a terminal", func() {
+			It("returns a config.ConsoleSocketError", func() {
+				err := c.Create(ctx, containerId, bundlePath, client.CreateOpts{ConsoleSocket: "some.sock"})
+
+				var consoleErr *config.ConsoleSocketError
+				Expect(errors.As(err, &consoleErr)).To(BeTrue())
+				Expect(cm.CreateCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Exec", func() {
+		var process *specs.Process
+
+		BeforeEach(func() {
+			process = &specs.Process{
+				Args: []string{"cmd.exe", "/c", "ver"},
+				Cwd:  "C:\\",
+				Env:  []string{"FOO=bar"},
+				User: specs.User{Username: "vcap"},
+			}
+			sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"})
+			cm.ExecReturns(&hcsfakes.Process{}, nil)
+		})
+
+		It("runs the process in the container under the exec id", func() {
+			exitCode, err := c.Exec(ctx, containerId, process, client.ExecOpts{ExecID: "some-exec", Detach: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitCode).To(Equal(0))
+
+			spec, _ := cm.ExecArgsForCall(0)
+			Expect(spec.Args).To(Equal(process.Args))
+			Expect(spec.Cwd).To(Equal(process.Cwd))
+			Expect(spec.Env).To(Equal(process.Env))
+			Expect(spec.User.Username).To(Equal("vcap"))
+
+			execId, _, _, detach, _ := sm.AddProcessArgsForCall(0)
+			Expect(execId).To(Equal("some-exec"))
+			Expect(detach).To(BeTrue())
+		})
+
+		Context("the process is invalid", func() {
+			BeforeEach(func() {
+				process.Args = nil
+			})
+
+			It("returns a config.ProcessConfigValidationError", func() {
+				_, err := c.Exec(ctx, containerId, process, client.ExecOpts{Detach: true})
+
+				var validationErr *config.ProcessConfigValidationError
+				Expect(errors.As(err, &validationErr)).To(BeTrue())
+				Expect(validationErr.ErrorMessages).To(ContainElement("args must not be empty"))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "running", Pid: 99}, nil)
+			cm.StopReturns(container.StopStageSignal, nil)
+		})
+
+		It("stops the container with the stop policy and returns how it was stopped", func() {
+			policy := container.StopPolicy{GracePeriod: 1, ShutdownTimeout: 2}
+
+			results, err := c.Delete(ctx, containerId, client.DeleteOpts{Force: true, Stop: policy})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(Equal([]runtime.DeleteResult{{ID: containerId, StoppedBy: "signal"}}))
+
+			pid, p := cm.StopArgsForCall(0)
+			Expect(pid).To(Equal(99))
+			Expect(p).To(Equal(policy))
+			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+		})
+	})
+
+	Describe("State", func() {
+		It("returns the state of the container", func() {
+			sm.StateReturns(&specs.State{ID: containerId, Status: "running", Pid: 99, Bundle: bundlePath}, nil)
+
+			s, err := c.State(ctx, containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*s).To(Equal(specs.State{ID: containerId, Status: "running", Pid: 99, Bundle: bundlePath}))
+		})
+
+		Context("the container does not exist", func() {
+			BeforeEach(func() {
+				sm.StateReturns(nil, &hcs.NotFoundError{Id: containerId})
+			})
+
+			It("returns an hcs.NotFoundError", func() {
+				_, err := c.State(ctx, containerId)
+
+				var notFound *hcs.NotFoundError
+				Expect(errors.As(err, &notFound)).To(BeTrue())
+				Expect(notFound.Id).To(Equal(containerId))
+			})
+		})
+	})
+
+	Describe("ProcessState", func() {
+		It("returns the state of the exec'd process", func() {
+			sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 42, Args: []string{"cmd.exe"}}, nil)
+			sm.ProcessStatusReturns("running", nil)
+
+			s, err := c.ProcessState(ctx, containerId, "some-exec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.ExecID).To(Equal("some-exec"))
+			Expect(s.Pid).To(Equal(42))
+			Expect(s.Status).To(Equal("running"))
+		})
+	})
+
+	Describe("Stats", func() {
+		It("returns the statistics of the container", func() {
+			var stats container.Statistics
+			stats.Data.Memory.Raw.TotalRss = 1024
+			stats.Data.CPUStats.CPUUsage.Usage = 2048
+			cm.StatsReturns(stats, nil)
+
+			s, err := c.Stats(ctx, containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(Equal(stats))
+		})
+	})
+
+	Describe("Monitor", func() {
+		It("relays the console of the init process and records its exit code", func() {
+			sm.StateReturns(&specs.State{ID: containerId, Status: "running", Pid: 42}, nil)
+			sm.ConsoleSocketReturns("C:\\console.sock", nil)
+			cm.OpenProcessReturns(&hcsfakes.Process{}, nil)
+			wrappedProcess.AttachConsoleReturns(3, nil)
+
+			Expect(c.Monitor(ctx, containerId, "")).To(Succeed())
+			Expect(wrappedProcess.AttachConsoleArgsForCall(0)).To(Equal("C:\\console.sock"))
+			Expect(sm.SetExitedArgsForCall(0)).To(Equal(3))
+		})
+	})
+
+	Describe("Kill", func() {
+		Context("the signal is not supported", func() {
+			It("returns a container.InvalidSignalError", func() {
+				err := c.Kill(ctx, containerId, "SIGHUP", "")
+
+				var invalidSignal *container.InvalidSignalError
+				Expect(errors.As(err, &invalidSignal)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Wait", func() {
+		It("returns the exit code of the init process", func() {
+			sm.StateReturns(&specs.State{Status: "running", Pid: 99}, nil)
+			cm.WaitReturns(3, nil)
+
+			exitCode, err := c.Wait(ctx, containerId, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitCode).To(Equal(3))
+
+			pid, _ := cm.WaitArgsForCall(0)
+			Expect(pid).To(Equal(99))
+		})
+
+		Context("the context is cancelled while waiting", func() {
+			It("returns the error of the context", func() {
+				sm.StateReturns(&specs.State{Status: "running", Pid: 99}, nil)
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cm.WaitStub = func(int, time.Duration) (int, error) {
+					cancel()
+					<-ctx.Done()
+					return 1, fmt.Errorf("waiting for process: %w", ctx.Err())
+				}
+
+				_, err := c.Wait(ctx, containerId, "")
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+
+				var noExitStatus *runtime.NoExitStatusError
+				Expect(errors.As(err, &noExitStatus)).To(BeFalse())
+			})
+		})
+	})
+})
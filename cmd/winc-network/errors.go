@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/winc/errcode"
+)
+
+type InvalidLogFormatError struct {
+	Format string
+}
+
+func (e *InvalidLogFormatError) Error() string {
+	return fmt.Sprintf("invalid log format: %s", e.Format)
+}
+
+func (e *InvalidLogFormatError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_log_format", Class: errcode.ClassInvalid}
+}
+
+type InvalidErrorFormatError struct {
+	Format string
+}
+
+func (e *InvalidErrorFormatError) Error() string {
+	return fmt.Sprintf("invalid error format: %s", e.Format)
+}
+
+func (e *InvalidErrorFormatError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_error_format", Class: errcode.ClassInvalid}
+}
+
+type MissingFlagError struct {
+	Flag string
+}
+
+func (e *MissingFlagError) Error() string {
+	return fmt.Sprintf("missing required flag '%s'", e.Flag)
+}
+
+func (e *MissingFlagError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_flag", Class: errcode.ClassInvalid}
+}
+
+type InvalidActionError struct {
+	Action string
+}
+
+func (e *InvalidActionError) Error() string {
+	return fmt.Sprintf("invalid action: %s", e.Action)
+}
+
+func (e *InvalidActionError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_action", Class: errcode.ClassInvalid}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"code.cloudfoundry.org/filelock"
+	"code.cloudfoundry.org/winc/errcode"
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/mtu"
@@ -20,7 +21,15 @@ import (
 	"github.com/urfave/cli"
 )
 
+// errorFormat is the format fatal writes errors in.
+var errorFormat = errcode.FormatText
+
+// tracer records the calls made to HCS and HNS if --hcs-trace is passed.
+var tracer *hcs.Tracer
+
 func main() {
+	var traceFile *os.File
+
 	app := cli.NewApp()
 	app.Name = "winc-network.exe"
 	app.Usage = "winc-network is a command line client for managing container networks"
@@ -54,11 +63,28 @@ func main() {
 			Value: "json",
 			Usage: "set the format used by logs ('json' (default), or 'text')",
 		},
+		cli.StringFlag{
+			Name:  "error-format",
+			Value: errcode.FormatText,
+			Usage: "set the format errors are written to stderr in ('text' (default), or 'json')",
+		},
+		cli.StringFlag{
+			Name:  "hcs-trace",
+			Usage: "append a JSON record of every call made to HCS and HNS to this file",
+		},
 	}
 	app.Before = func(context *cli.Context) error {
+		switch format := context.GlobalString("error-format"); format {
+		case errcode.FormatText, errcode.FormatJSON:
+			errorFormat = format
+		default:
+			return &InvalidErrorFormatError{Format: format}
+		}
+
 		debug := context.GlobalBool("debug")
 		logFile := context.GlobalString("log")
 		logFormat := context.GlobalString("log-format")
+		hcsTrace := context.GlobalString("hcs-trace")
 
 		if debug {
 			logrus.SetLevel(logrus.DebugLevel)
@@ -68,7 +94,7 @@ func main() {
 		if logFile == "" || logFile == os.DevNull {
 			logWriter = ioutil.Discard
 		} else {
-			if err := os.MkdirAll(filepath.Dir(logFile), 0666); err != nil {
+			if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
 				return err
 			}
 
@@ -87,7 +113,21 @@ func main() {
 		case "json":
 			logrus.SetFormatter(&logrus.JSONFormatter{TimestampFormat: "2006-01-02T15:04:05.000000000Z"})
 		default:
-			return fmt.Errorf("invalid log format: %s", logFormat)
+			return &InvalidLogFormatError{Format: logFormat}
+		}
+
+		if hcsTrace != "" {
+			if err := os.MkdirAll(filepath.Dir(hcsTrace), 0755); err != nil {
+				return err
+			}
+
+			var err error
+			traceFile, err = os.OpenFile(hcsTrace, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+			if err != nil {
+				return err
+			}
+
+			tracer = hcs.NewTracer(traceFile)
 		}
 
 		return nil
@@ -95,12 +135,12 @@ func main() {
 	app.Action = func(context *cli.Context) error {
 		config, err := parseConfig(context.String("configFile"))
 		if err != nil {
-			return fmt.Errorf("configFile: %s", err.Error())
+			return fmt.Errorf("configFile: %w", err)
 		}
 		handle := context.String("handle")
 		action := context.String("action")
 		if (action == "up" || action == "down") && handle == "" {
-			return fmt.Errorf("missing required flag 'handle'")
+			return &MissingFlagError{Flag: "handle"}
 		}
 
 		networkManager, err := wireNetworkManager(config, handle)
@@ -112,35 +152,42 @@ func main() {
 		case "up":
 			var inputs network.UpInputs
 			if err := json.NewDecoder(os.Stdin).Decode(&inputs); err != nil {
-				return fmt.Errorf("networkUp: %s", err.Error())
+				return fmt.Errorf("networkUp: %w", err)
 			}
 
 			outputs, err := networkManager.Up(inputs)
 			if err != nil {
-				return fmt.Errorf("networkUp: %s", err.Error())
+				return fmt.Errorf("networkUp: %w", err)
 			}
 
 			if err := json.NewEncoder(os.Stdout).Encode(outputs); err != nil {
-				return fmt.Errorf("networkUp: %s", err.Error())
+				return fmt.Errorf("networkUp: %w", err)
 			}
 
 		case "create":
 			if err := networkManager.CreateHostNATNetwork(); err != nil {
-				return fmt.Errorf("network create: %s", err.Error())
+				return fmt.Errorf("network create: %w", err)
 			}
 
 		case "delete":
 			if err := networkManager.DeleteHostNATNetwork(); err != nil {
-				return fmt.Errorf("network delete: %s", err.Error())
+				return fmt.Errorf("network delete: %w", err)
 			}
 
 		case "down":
 			if err := networkManager.Down(); err != nil {
-				return fmt.Errorf("networkDown: %s", err.Error())
+				return fmt.Errorf("networkDown: %w", err)
 			}
 
 		default:
-			return fmt.Errorf("invalid action: %s", action)
+			return &InvalidActionError{Action: action}
+		}
+		return nil
+	}
+
+	app.After = func(context *cli.Context) error {
+		if traceFile != nil {
+			return traceFile.Close()
 		}
 		return nil
 	}
@@ -167,7 +214,7 @@ func parseConfig(configFile string) (network.Config, error) {
 }
 
 func wireNetworkManager(config network.Config, handle string) (*network.NetworkManager, error) {
-	hcsClient := &hcs.Client{}
+	hcsClient := &hcs.Client{Tracer: tracer}
 	runner := netsh.NewRunner(hcsClient, handle, config.WaitTimeoutInSeconds)
 
 	tracker := &port_allocator.Tracker{
@@ -206,7 +253,7 @@ func wireNetworkManager(config network.Config, handle string) (*network.NetworkM
 }
 
 func fatal(err error) {
-	logrus.Error(err)
-	fmt.Fprintln(os.Stderr, err)
-	os.Exit(1)
+	logrus.WithField("code", errcode.Of(err).Name).Error(err)
+	errcode.Write(os.Stderr, err, errorFormat)
+	os.Exit(errcode.ExitCode(err))
 }
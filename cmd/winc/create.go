@@ -26,6 +26,11 @@ your host.`,
 			Value: "",
 			Usage: `path to the root of the bundle directory, defaults to the current directory`,
 		},
+		cli.StringFlag{
+			Name:  "console-socket",
+			Value: "",
+			Usage: "path to an AF_UNIX socket the pseudo-TTY of the container's process is relayed over",
+		},
 		cli.BoolFlag{
 			Name:  "no-new-keyring",
 			Usage: "ignored",
@@ -38,7 +43,8 @@ your host.`,
 
 		containerId := context.Args().First()
 		bundlePath := context.String("bundle")
+		consoleSocket := context.String("console-socket")
 
-		return run.Create(containerId, bundlePath)
+		return run.Create(containerId, bundlePath, consoleSocket)
 	},
 }
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/winc/runtime/container"
 	"github.com/urfave/cli"
 )
 
@@ -16,12 +21,27 @@ For example, if the container id is "windows01" and winc list currently shows th
 status of "windows01" as "stopped" the following will delete resources held for
 "windows01" removing "windows01" from the winc list of containers:
 
-       # winc delete windows01`,
+       # winc delete windows01
+
+Before the container is deleted it is stopped in stages: its init process is
+sent a Ctrl-C (or Ctrl-Break) and given "--grace-period" to exit, then the
+container is shut down, waiting up to "--shutdown-timeout", and finally it is
+terminated. The stage that stopped each deleted container is written to stdout
+as JSON.`,
 	Flags: []cli.Flag{
 		cli.BoolFlag{
 			Name:  "force, f",
 			Usage: "Do not return an error if <container-id> does not exist",
 		},
+		cli.DurationFlag{
+			Name:  "grace-period",
+			Usage: "time the init process is given to exit after Ctrl-C before the container is shut down, defaults to not signalling it",
+		},
+		cli.DurationFlag{
+			Name:  "shutdown-timeout",
+			Value: time.Minute,
+			Usage: "time the container is given to shut down before it is terminated",
+		},
 	},
 
 	Action: func(context *cli.Context) error {
@@ -32,6 +52,24 @@ status of "windows01" as "stopped" the following will delete resources held for
 		containerId := context.Args().First()
 		force := context.Bool("force")
 
-		return run.Delete(containerId, force)
+		gracePeriod := context.Duration("grace-period")
+		if gracePeriod < 0 {
+			return &InvalidTimeoutError{Timeout: gracePeriod}
+		}
+		shutdownTimeout := context.Duration("shutdown-timeout")
+		if shutdownTimeout < 0 {
+			return &InvalidTimeoutError{Timeout: shutdownTimeout}
+		}
+
+		policy := container.StopPolicy{
+			GracePeriod:     gracePeriod,
+			ShutdownTimeout: shutdownTimeout,
+		}
+
+		results, err := run.Delete(containerId, force, policy)
+		if encodeErr := json.NewEncoder(os.Stdout).Encode(results); err == nil {
+			err = encodeErr
+		}
+		return err
 	},
 }
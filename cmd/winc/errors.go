@@ -2,6 +2,11 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/errcode"
 )
 
 type InvalidLogFormatError struct {
@@ -11,3 +16,105 @@ type InvalidLogFormatError struct {
 func (e *InvalidLogFormatError) Error() string {
 	return fmt.Sprintf("invalid log format %s", e.Format)
 }
+
+func (e *InvalidLogFormatError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_log_format", Class: errcode.ClassInvalid}
+}
+
+type InvalidFormatError struct {
+	Format string
+}
+
+func (e *InvalidFormatError) Error() string {
+	return fmt.Sprintf("invalid format %s", e.Format)
+}
+
+func (e *InvalidFormatError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_format", Class: errcode.ClassInvalid}
+}
+
+type InvalidFilterError struct {
+	Filter string
+}
+
+func (e *InvalidFilterError) Error() string {
+	return fmt.Sprintf("invalid filter %s, must be <key>=<value>", e.Filter)
+}
+
+func (e *InvalidFilterError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_filter", Class: errcode.ClassInvalid}
+}
+
+type IncompatibleFlagsError struct {
+	Flags []string
+}
+
+func (e *IncompatibleFlagsError) Error() string {
+	return fmt.Sprintf("flags --%s cannot be used together", strings.Join(e.Flags, " and --"))
+}
+
+func (e *IncompatibleFlagsError) Code() errcode.Code {
+	return errcode.Code{Name: "incompatible_flags", Class: errcode.ClassInvalid}
+}
+
+type InvalidCPUSharesError struct {
+	Shares uint64
+}
+
+func (e *InvalidCPUSharesError) Error() string {
+	return fmt.Sprintf("invalid cpu shares %d", e.Shares)
+}
+
+func (e *InvalidCPUSharesError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_cpu_shares", Class: errcode.ClassInvalid}
+}
+
+type InvalidIntervalError struct {
+	Interval time.Duration
+}
+
+func (e *InvalidIntervalError) Error() string {
+	return fmt.Sprintf("duration interval must be greater than 0: %s", e.Interval)
+}
+
+func (e *InvalidIntervalError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_interval", Class: errcode.ClassInvalid}
+}
+
+type InvalidTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *InvalidTimeoutError) Error() string {
+	return fmt.Sprintf("timeout must not be negative: %s", e.Timeout)
+}
+
+func (e *InvalidTimeoutError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_timeout", Class: errcode.ClassInvalid}
+}
+
+type InvalidErrorFormatError struct {
+	Format string
+}
+
+func (e *InvalidErrorFormatError) Error() string {
+	return fmt.Sprintf("invalid error format %s", e.Format)
+}
+
+func (e *InvalidErrorFormatError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_error_format", Class: errcode.ClassInvalid}
+}
+
+type InvalidArgsError struct {
+	Command     string
+	Requirement string
+	Expected    int
+}
+
+func (e *InvalidArgsError) Error() string {
+	return fmt.Sprintf("%s: %q requires %s %d argument(s)", os.Args[0], e.Command, e.Requirement, e.Expected)
+}
+
+func (e *InvalidArgsError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_args", Class: errcode.ClassInvalid}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/urfave/cli"
 )
@@ -12,8 +13,14 @@ var eventsCommand = cli.Command{
 	ArgsUsage: `<container-id>
 
 Where "<container-id>" is your name for the instance of the container.`,
-	Description: `The events command displays information about the container.`,
+	Description: `The events command displays information about the container. By default the
+information is displayed once every 5 seconds until the container stops.
+
+Each event is written as a single line of JSON with "type", "id" and "data"
+fields. The types are "stats", "oom" when the container runs out of memory,
+and "exit" once the container has stopped.`,
 	Flags: []cli.Flag{
+		cli.DurationFlag{Name: "interval", Value: 5 * time.Second, Usage: "set the stats collection interval"},
 		cli.BoolFlag{Name: "stats", Usage: "display the container's stats then exit"},
 	},
 	Action: func(context *cli.Context) error {
@@ -23,7 +30,11 @@ Where "<container-id>" is your name for the instance of the container.`,
 
 		containerId := context.Args().First()
 		showStats := context.Bool("stats")
+		interval := context.Duration("interval")
+		if interval <= 0 {
+			return &InvalidIntervalError{Interval: interval}
+		}
 
-		return run.Events(containerId, os.Stdout, showStats)
+		return run.Events(containerId, os.Stdout, showStats, interval)
 	},
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/urfave/cli"
 )
@@ -23,6 +24,11 @@ following will output a list of processes running in the container:
 
        # winc exec <container-id> ps`,
 	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "id to record the process under, defaults to a generated id",
+		},
 		cli.StringFlag{
 			Name:  "pid-file",
 			Value: "",
@@ -51,10 +57,15 @@ following will output a list of processes running in the container:
 			Name:  "env, e",
 			Usage: "set environment variables",
 		},
-		// 	cli.BoolFlag{
-		// 		Name:  "tty, t",
-		// 		Usage: "allocate a pseudo-TTY",
-		// 	},
+		cli.BoolFlag{
+			Name:  "tty, t",
+			Usage: "allocate a pseudo-TTY",
+		},
+		cli.StringFlag{
+			Name:  "console-socket",
+			Value: "",
+			Usage: "path to an AF_UNIX socket the pseudo-TTY of the process is relayed over",
+		},
 	},
 	Action: func(context *cli.Context) error {
 		if err := checkArgs(context, 1, minArgs); err != nil {
@@ -62,6 +73,7 @@ following will output a list of processes running in the container:
 		}
 
 		containerId := context.Args().First()
+		execId := context.String("exec-id")
 		processConfig := context.String("process")
 		args := context.Args()[1:]
 		cwd := context.String("cwd")
@@ -69,6 +81,8 @@ following will output a list of processes running in the container:
 		env := context.StringSlice("env")
 		pidFile := context.String("pid-file")
 		detach := context.Bool("detach")
+		tty := context.Bool("tty")
+		consoleSocket := context.String("console-socket")
 
 		processOverrides := &specs.Process{
 			Args: args,
@@ -76,11 +90,27 @@ following will output a list of processes running in the container:
 			User: specs.User{
 				Username: user,
 			},
-			Env: env,
+			Env:      env,
+			Terminal: tty,
 		}
 
-		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
-		exitCode, err := run.Exec(containerId, processConfig, pidFile, processOverrides, io, detach)
+		// the monitor that relays the console of a detached process and records
+		// its exit code addresses it by its exec id
+		if detach && execId == "" {
+			var err error
+			execId, err = runtime.NewExecId()
+			if err != nil {
+				return err
+			}
+		}
+
+		processSpec, err := config.LoadProcess(processConfig, processOverrides)
+		if err != nil {
+			return err
+		}
+
+		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr, ConsoleSocket: consoleSocket}
+		exitCode, err := run.Exec(containerId, execId, processSpec, pidFile, io, detach)
 		if err != nil {
 			return err
 		}
@@ -89,6 +119,8 @@ following will output a list of processes running in the container:
 			os.Exit(exitCode)
 		}
 
+		startMonitor(context, containerId, execId)
+
 		return nil
 	},
 	SkipArgReorder: true,
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var gcCommand = cli.Command{
+	Name:  "gc",
+	Usage: "remove state, HCS containers and volume mount points left behind by winc",
+	ArgsUsage: `
+
+Where the given root is specified via the global option "--root"
+(default: "C:\ProgramData\winc").`,
+	Description: `The gc command cross-references the state directories under the root, the
+containers HCS knows about and the volume mount points under c:\proc, and
+removes the ones that a create or delete which did not finish left behind:
+
+   state       a state directory without an HCS container
+   container   an HCS container without a state directory that winc created
+               under the same root, or the sidecar of one
+   mount       a mount point whose pid is not the init process of a container
+
+Each orphan is printed with the reason it is considered orphaned. Do not run gc
+while containers are being created or deleted with the same root.
+
+EXAMPLE:
+To see what would be removed without removing it:
+       # winc gc --dry-run`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "report orphans without removing them",
+		},
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 0, exactArgs); err != nil {
+			return err
+		}
+
+		format := context.String("format")
+		if format != "table" && format != "json" {
+			return &InvalidFormatError{Format: format}
+		}
+
+		return run.GC(os.Stdout, format, context.Bool("dry-run"))
+	},
+}
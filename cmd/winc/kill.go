@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/urfave/cli"
+)
+
+var killCommand = cli.Command{
+	Name:  "kill",
+	Usage: "kill sends the specified signal (default: SIGTERM) to the container's init process",
+	ArgsUsage: `<container-id> [signal]
+
+Where "<container-id>" is the name for the instance of the container and
+"[signal]" is the signal to be sent to the init process.
+
+SIGTERM and SIGINT gracefully shut the container down. SIGKILL kills the init
+process, or every process in the container when --all is passed.
+
+EXAMPLE:
+For example, if the container id is "windows01" the following will send a "KILL"
+signal to the init process of the "windows01" container:
+
+       # winc kill windows01 KILL`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all, a",
+			Usage: "send the specified signal to all processes inside the container",
+		},
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "send the signal to the process started by winc exec with this id instead, only KILL is supported",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, minArgs); err != nil {
+			return err
+		}
+		if err := checkArgs(context, 2, maxArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		signal := context.Args().Get(1)
+		all := context.Bool("all")
+		execId := context.String("exec-id")
+
+		if all && execId != "" {
+			return &IncompatibleFlagsError{Flags: []string{"all", "exec-id"}}
+		}
+
+		return run.Kill(containerId, signal, all, execId)
+	},
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+var listCommand = cli.Command{
+	Name:  "list",
+	Usage: "lists containers started by winc with the given root",
+	ArgsUsage: `
+
+Where the given root is specified via the global option "--root"
+(default: "C:\ProgramData\winc").
+
+Containers that only exist in HCS, or only have a state directory under the
+root, are listed as orphaned.
+
+EXAMPLE 1:
+To list containers created via the default "--root":
+       # winc list
+
+EXAMPLE 2:
+To list containers created using a non-default value for "--root":
+       # winc --root value list
+
+EXAMPLE 3:
+To list containers whose spec has the annotation "app-guid" set to "1234":
+       # winc list --filter app-guid=1234`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+		cli.BoolFlag{
+			Name:  "quiet, q",
+			Usage: "display only container IDs",
+		},
+		cli.StringSliceFlag{
+			Name:  "filter",
+			Value: &cli.StringSlice{},
+			Usage: "only list containers with the annotation <key>=<value>, can be repeated",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 0, exactArgs); err != nil {
+			return err
+		}
+
+		format := context.String("format")
+		if format != "table" && format != "json" {
+			return &InvalidFormatError{Format: format}
+		}
+
+		filters := map[string]string{}
+		for _, filter := range context.StringSlice("filter") {
+			kv := strings.SplitN(filter, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return &InvalidFilterError{Filter: filter}
+			}
+			filters[kv[0]] = kv[1]
+		}
+
+		return run.List(os.Stdout, format, context.Bool("quiet"), filters)
+	},
+}
@@ -8,12 +8,15 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 	"unsafe"
 
+	"code.cloudfoundry.org/winc/errcode"
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/hook"
 	"code.cloudfoundry.org/winc/runtime/mount"
 	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
@@ -35,15 +38,24 @@ implementation of the Open Container Initiative specification.`
 
 var run *runtime.Runtime
 
+// errorFormat is the format fatal writes errors in.
+var errorFormat = errcode.FormatText
+
+// version is set at build time with -ldflags "-X main.version=<version>".
+var version = "dev"
+
 var (
 	kernel32             = windows.NewLazySystemDLL("kernel32.dll")
 	getHandleInformation = kernel32.NewProc("GetHandleInformation")
 )
 
-type stateFactory struct{}
+type stateFactory struct {
+	lockTimeout time.Duration
+	version     string
+}
 
 func (f *stateFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, winSyscall *winsyscall.WinSyscall, id, rootDir string) runtime.StateManager {
-	return state.New(logger, hcsClient, winSyscall, id, rootDir)
+	return state.New(logger, hcsClient, winSyscall, id, rootDir, f.lockTimeout, f.version).WithContext(hcsClient.Context)
 }
 
 type containerFactory struct{}
@@ -59,7 +71,7 @@ func (w *processWrapper) Wrap(p hcs.Process) runtime.WrappedProcess {
 }
 
 func main() {
-	var logFile *os.File
+	var logFile, traceFile *os.File
 	defer func() {
 		if logFile != nil {
 			logFile.Close()
@@ -69,6 +81,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "winc.exe"
 	app.Usage = usage
+	app.Version = version
 
 	app.Flags = []cli.Flag{
 		cli.BoolFlag{
@@ -89,6 +102,11 @@ func main() {
 			Value: "json",
 			Usage: "set the format used by logs ('json' (default), or 'text')",
 		},
+		cli.StringFlag{
+			Name:  "error-format",
+			Value: errcode.FormatText,
+			Usage: "set the format errors are written to stderr in ('text' (default), or 'json')",
+		},
 		cli.StringFlag{
 			Name:  "image-store",
 			Value: "",
@@ -99,6 +117,15 @@ func main() {
 			Value: "C:\\ProgramData\\winc",
 			Usage: "directory for storage of container state",
 		},
+		cli.DurationFlag{
+			Name:  "lock-timeout",
+			Value: 30 * time.Second,
+			Usage: "how long to wait for another winc operating on the same container",
+		},
+		cli.StringFlag{
+			Name:  "hcs-trace",
+			Usage: "append a JSON record of every call made to HCS and HNS to this file",
+		},
 	}
 
 	app.Commands = []cli.Command{
@@ -109,14 +136,32 @@ func main() {
 		startCommand,
 		execCommand,
 		eventsCommand,
+		killCommand,
+		listCommand,
+		pauseCommand,
+		resumeCommand,
+		psCommand,
+		updateCommand,
+		waitCommand,
+		gcCommand,
+		monitorCommand,
 	}
 
 	app.Before = func(context *cli.Context) error {
+		switch format := context.GlobalString("error-format"); format {
+		case errcode.FormatText, errcode.FormatJSON:
+			errorFormat = format
+		default:
+			return &InvalidErrorFormatError{Format: format}
+		}
+
 		debug := context.GlobalBool("debug")
 		logHandle := context.GlobalUint64("log-handle")
 		log := context.GlobalString("log")
 		logFormat := context.GlobalString("log-format")
 		rootDir := context.GlobalString("root")
+		lockTimeout := context.GlobalDuration("lock-timeout")
+		hcsTrace := context.GlobalString("hcs-trace")
 
 		if debug {
 			logrus.SetLevel(logrus.DebugLevel)
@@ -139,7 +184,7 @@ func main() {
 		}
 
 		if !emptyLog(log) {
-			if err := os.MkdirAll(filepath.Dir(log), 0666); err != nil {
+			if err := os.MkdirAll(filepath.Dir(log), 0755); err != nil {
 				return err
 			}
 
@@ -163,17 +208,42 @@ func main() {
 			return &InvalidLogFormatError{Format: logFormat}
 		}
 
+		var tracer *hcs.Tracer
+		if hcsTrace != "" {
+			if err := os.MkdirAll(filepath.Dir(hcsTrace), 0755); err != nil {
+				return err
+			}
+
+			var err error
+			traceFile, err = os.OpenFile(hcsTrace, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+			if err != nil {
+				return err
+			}
+
+			tracer = hcs.NewTracer(traceFile)
+		}
+
 		containerFactory := &containerFactory{}
-		stateFactory := &stateFactory{}
+		stateFactory := &stateFactory{lockTimeout: lockTimeout, version: version}
 		mounter := &mount.Mounter{}
-		hcsClient := &hcs.Client{}
+		hcsClient := &hcs.Client{Tracer: tracer}
 		processWrapper := &processWrapper{}
+		hookRunner := &hook.Runner{}
 
-		run = runtime.New(stateFactory, containerFactory, mounter, hcsClient, processWrapper, rootDir)
+		run = runtime.New(stateFactory, containerFactory, mounter, hcsClient, processWrapper, hookRunner, rootDir).WithTracer(tracer)
 		return nil
 	}
 
 	cli.ErrWriter = &fatalWriter{cli.ErrWriter}
+	// the trace file is closed once the command has run, as fatal exits
+	// without running deferred calls
+	app.After = func(context *cli.Context) error {
+		if traceFile != nil {
+			return traceFile.Close()
+		}
+		return nil
+	}
+
 	if err := app.Run(os.Args); err != nil {
 		fatal(err)
 	}
@@ -194,15 +264,15 @@ func checkArgs(context *cli.Context, expected, checkType int) error {
 	switch checkType {
 	case exactArgs:
 		if context.NArg() != expected {
-			err = fmt.Errorf("%s: %q requires exactly %d argument(s)", os.Args[0], cmdName, expected)
+			err = &InvalidArgsError{Command: cmdName, Requirement: "exactly", Expected: expected}
 		}
 	case minArgs:
 		if context.NArg() < expected {
-			err = fmt.Errorf("%s: %q requires a minimum of %d argument(s)", os.Args[0], cmdName, expected)
+			err = &InvalidArgsError{Command: cmdName, Requirement: "a minimum of", Expected: expected}
 		}
 	case maxArgs:
 		if context.NArg() > expected {
-			err = fmt.Errorf("%s: %q requires a maximum of %d argument(s)", os.Args[0], cmdName, expected)
+			err = &InvalidArgsError{Command: cmdName, Requirement: "a maximum of", Expected: expected}
 		}
 	}
 
@@ -215,9 +285,9 @@ func checkArgs(context *cli.Context, expected, checkType int) error {
 }
 
 func fatal(err error) {
-	logrus.Error(err)
-	fmt.Fprintln(os.Stderr, err)
-	os.Exit(1)
+	logrus.WithField("code", errcode.Of(err).Name).Error(err)
+	errcode.Write(os.Stderr, err, errorFormat)
+	os.Exit(errcode.ExitCode(err))
 }
 
 func validHandle(handle syscall.Handle) error {
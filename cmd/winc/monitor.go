@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"golang.org/x/sys/windows"
+)
+
+var monitorCommand = cli.Command{
+	Name:   "monitor",
+	Usage:  "wait for a process in a container to exit, relaying its console, and record its exit code",
+	Hidden: true,
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "wait for the process started by winc exec under this exec id instead of the init process",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		execId := context.String("exec-id")
+
+		return run.Monitor(containerId, execId)
+	},
+}
+
+// startMonitor launches a detached winc monitor for the init process of the
+// container, or for the process started by winc exec under execId if it is not
+// empty. The monitor relays the console of the process, and records its exit
+// code, even though nothing stays attached to it. Failing
+// to launch it does not fail the command that started the process.
+func startMonitor(context *cli.Context, containerId, execId string) {
+	logger := logrus.WithFields(logrus.Fields{"containerId": containerId, "execId": execId})
+
+	exe, err := os.Executable()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	args := []string{"--root", context.GlobalString("root"), "--log-format", context.GlobalString("log-format"), "--lock-timeout", context.GlobalDuration("lock-timeout").String()}
+	if context.GlobalBool("debug") {
+		args = append(args, "--debug")
+	}
+	if log := context.GlobalString("log"); !emptyLog(log) {
+		args = append(args, "--log", log)
+	}
+	args = append(args, "monitor")
+	if execId != "" {
+		args = append(args, "--exec-id", execId)
+	}
+	args = append(args, containerId)
+
+	cmd := exec.Command(exe, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP}
+	if err := cmd.Start(); err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if err := cmd.Process.Release(); err != nil {
+		logger.Error(err)
+	}
+}
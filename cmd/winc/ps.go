@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var psCommand = cli.Command{
+	Name:  "ps",
+	Usage: "ps displays the processes running inside a container",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container.
+
+The init process of the container is marked with "*" in the INIT column, and
+processes started by winc exec show their exec id in the EXEC ID column.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: table or json`,
+		},
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "only display the process started by winc exec with this id",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		format := context.String("format")
+		if format != "table" && format != "json" {
+			return &InvalidFormatError{Format: format}
+		}
+
+		return run.Ps(containerId, os.Stdout, format, context.String("exec-id"))
+	},
+}
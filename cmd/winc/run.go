@@ -38,6 +38,11 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 			Value: "",
 			Usage: "specify the file to write the process id to",
 		},
+		cli.StringFlag{
+			Name:  "console-socket",
+			Value: "",
+			Usage: "path to an AF_UNIX socket the pseudo-TTY of the container's process is relayed over",
+		},
 		cli.BoolFlag{
 			Name:  "no-new-keyring",
 			Usage: "ignored",
@@ -52,16 +57,18 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 		bundlePath := context.String("bundle")
 		detach := context.Bool("detach")
 		pidFile := context.String("pid-file")
+		consoleSocket := context.String("console-socket")
 
 		logger := logrus.WithFields(logrus.Fields{
-			"bundle":      bundlePath,
-			"containerId": containerId,
-			"pidFile":     pidFile,
-			"detach":      detach,
+			"bundle":        bundlePath,
+			"containerId":   containerId,
+			"pidFile":       pidFile,
+			"consoleSocket": consoleSocket,
+			"detach":        detach,
 		})
 		logger.Debug("creating container")
 
-		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+		io := runtime.IO{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr, ConsoleSocket: consoleSocket}
 		exitCode, err := run.Run(containerId, bundlePath, pidFile, io, detach)
 		if err != nil {
 			return err
@@ -71,6 +78,7 @@ command(s) that get executed on start, edit the args parameter of the spec.`,
 			os.Exit(exitCode)
 		}
 
+		startMonitor(context, containerId, "")
 		return nil
 	},
 	SkipArgReorder: true,
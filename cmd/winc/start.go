@@ -25,7 +25,12 @@ Where "<container-id>" is the name for the instance of the container`,
 		containerId := context.Args().First()
 		pidFile := context.String("pid-file")
 
-		return run.Start(containerId, pidFile)
+		if err := run.Start(containerId, pidFile); err != nil {
+			return err
+		}
+
+		startMonitor(context, containerId, "")
+		return nil
 	},
 
 	SkipArgReorder: true,
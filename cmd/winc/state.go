@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -14,19 +15,46 @@ var stateCommand = cli.Command{
 
 Where "<container-id>" is your name for the instance of the container.`,
 	Description: `The state command outputs current state information for the
-instance of a container.`,
+instance of a container, or of a process started in it by winc exec when
+--exec-id is passed.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "output the state of the process started by winc exec with this id",
+		},
+	},
 	Action: func(context *cli.Context) error {
 		if err := checkArgs(context, 1, exactArgs); err != nil {
 			return err
 		}
 
 		containerId := context.Args().First()
+		execId := context.String("exec-id")
 
 		logger := logrus.WithFields(logrus.Fields{
 			"containerId": containerId,
+			"execId":      execId,
 		})
 		logger.Debug("retrieving state of container")
 
-		return run.State(containerId, os.Stdout)
+		var state interface{}
+		var err error
+		if execId != "" {
+			state, err = run.ProcessState(containerId, execId)
+		} else {
+			state, err = run.State(containerId)
+		}
+		if err != nil {
+			return err
+		}
+
+		stateJson, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(stateJson)
+		return err
 	},
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"os"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/urfave/cli"
+)
+
+var updateCommand = cli.Command{
+	Name:  "update",
+	Usage: "update container resource constraints",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container.
+
+The resources file uses the OCI "windows.resources" format, for example:
+
+{
+  "memory": {
+    "limit": 1073741824
+  },
+  "cpu": {
+    "shares": 5000
+  }
+}
+
+Values given on the command line take precedence over the resources file.
+The limits accepted and rejected by HCS are written to stdout as JSON.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "resources, r",
+			Value: "",
+			Usage: `path to a file containing the resources to update`,
+		},
+		cli.Uint64Flag{
+			Name:  "memory",
+			Usage: "memory limit (in bytes)",
+		},
+		cli.Uint64Flag{
+			Name:  "cpu-shares",
+			Usage: "CPU shares (relative weight vs. other containers)",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		resourcesFile := context.String("resources")
+
+		overrides := &specs.WindowsResources{}
+		if context.IsSet("memory") {
+			limit := context.Uint64("memory")
+			overrides.Memory = &specs.WindowsMemoryResources{Limit: &limit}
+		}
+		if context.IsSet("cpu-shares") {
+			shares := context.Uint64("cpu-shares")
+			if shares > math.MaxUint16 {
+				return &InvalidCPUSharesError{Shares: shares}
+			}
+			cpuShares := uint16(shares)
+			overrides.CPU = &specs.WindowsCPUResources{Shares: &cpuShares}
+		}
+
+		return run.Update(containerId, resourcesFile, overrides, os.Stdout)
+	},
+}
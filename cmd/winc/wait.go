@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var waitCommand = cli.Command{
+	Name:  "wait",
+	Usage: "wait for a process in a container to exit and return its exit code",
+	ArgsUsage: `<container-id>
+
+Where "<container-id>" is the name for the instance of the container`,
+	Description: `The wait command blocks until the init process of the container exits, prints
+its exit code as JSON and exits with that code. If the init process has already
+exited, the exit code winc recorded for it is used.
+
+Pass --exec-pid or --exec-id to wait on a process started by winc exec instead.
+Waiting on a process by its exec id also removes the record winc keeps of it.`,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "exec-pid",
+			Usage: "pid of a process started by winc exec to wait on instead of the init process",
+		},
+		cli.StringFlag{
+			Name:  "exec-id",
+			Value: "",
+			Usage: "exec id of a process started by winc exec to wait on instead of the init process",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up waiting after this duration, defaults to waiting indefinitely",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 1, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().First()
+		execPid := context.Int("exec-pid")
+		execId := context.String("exec-id")
+		timeout := context.Duration("timeout")
+		if timeout < 0 {
+			return &InvalidTimeoutError{Timeout: timeout}
+		}
+
+		if execPid != 0 && execId != "" {
+			return &IncompatibleFlagsError{Flags: []string{"exec-pid", "exec-id"}}
+		}
+
+		exit, err := run.Wait(containerId, execPid, execId, timeout)
+		if err != nil {
+			return err
+		}
+
+		if err := json.NewEncoder(os.Stdout).Encode(exit); err != nil {
+			return err
+		}
+
+		os.Exit(exit.ExitCode)
+		return nil
+	},
+}
@@ -0,0 +1,175 @@
+// Package errcode gives the errors reported by winc and winc-network stable
+// codes, and maps the class of each code to the exit code of the binaries.
+package errcode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Class groups codes that callers handle the same way.
+type Class int
+
+const (
+	ClassInternal Class = iota
+	ClassInvalid
+	ClassNotFound
+	ClassConflict
+	ClassTimeout
+	ClassResource
+)
+
+// Exit codes of winc and winc-network by class. Errors without a code are
+// internal.
+const (
+	ExitInternal = 1
+	ExitInvalid  = 2
+	ExitNotFound = 3
+	ExitConflict = 4
+	ExitTimeout  = 5
+	ExitResource = 6
+)
+
+// Unknown is the code of errors that do not have one.
+const Unknown = "unknown"
+
+// Formats of the errors written by Write.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Code is the stable identifier of an error and its class. Name never changes
+// once released, even if the message of the error does.
+type Code struct {
+	Name  string
+	Class Class
+}
+
+// Coder is implemented by errors that have a code.
+type Coder interface {
+	error
+	Code() Code
+}
+
+// Error is the JSON representation of an error written with FormatJSON.
+type Error struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+func (c Class) ExitCode() int {
+	switch c {
+	case ClassInvalid:
+		return ExitInvalid
+	case ClassNotFound:
+		return ExitNotFound
+	case ClassConflict:
+		return ExitConflict
+	case ClassTimeout:
+		return ExitTimeout
+	case ClassResource:
+		return ExitResource
+	default:
+		return ExitInternal
+	}
+}
+
+// Of returns the code of the first error in the chain of err that has one.
+func Of(err error) Code {
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+	return Code{Name: Unknown, Class: ClassInternal}
+}
+
+// ExitCode returns the exit code for err.
+func ExitCode(err error) int {
+	return Of(err).Class.ExitCode()
+}
+
+// Describe returns the JSON representation of err. The details are the
+// exported fields of the first error in its chain that has a code.
+func Describe(err error) Error {
+	e := Error{
+		Code:    Of(err).Name,
+		Message: err.Error(),
+	}
+
+	var coder Coder
+	if errors.As(err, &coder) {
+		e.Details = details(coder)
+	}
+
+	return e
+}
+
+// Write writes err to w in format, which is FormatText or FormatJSON.
+func Write(w io.Writer, err error, format string) error {
+	if format == FormatJSON {
+		return json.NewEncoder(w).Encode(Describe(err))
+	}
+
+	_, writeErr := fmt.Fprintln(w, err)
+	return writeErr
+}
+
+// details returns the exported fields of err keyed by their names in
+// snake_case. Errors are replaced by their message.
+func details(err error) map[string]interface{} {
+	v := reflect.ValueOf(err)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := map[string]interface{}{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		value := v.Field(i).Interface()
+		if fieldErr, ok := value.(error); ok {
+			value = fieldErr.Error()
+		} else if value == nil {
+			continue
+		}
+		fields[snakeCase(field.Name)] = value
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// start a new word at an upper case letter that follows a lower
+			// case one, or that starts a word after an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
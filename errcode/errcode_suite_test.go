@@ -0,0 +1,13 @@
+package errcode_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestErrcode(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Errcode Suite")
+}
@@ -0,0 +1,107 @@
+package errcode_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"code.cloudfoundry.org/winc/errcode"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type someError struct {
+	ContainerID   string
+	ExecId        string
+	Pid           int
+	InternalError error
+	unexported    string
+}
+
+func (e *someError) Error() string {
+	return "something went wrong"
+}
+
+func (e *someError) Code() errcode.Code {
+	return errcode.Code{Name: "something_wrong", Class: errcode.ClassNotFound}
+}
+
+var _ = Describe("Errcode", func() {
+	var err error
+
+	BeforeEach(func() {
+		err = &someError{
+			ContainerID:   "some-container",
+			ExecId:        "some-exec",
+			Pid:           42,
+			InternalError: errors.New("internal"),
+			unexported:    "hidden",
+		}
+	})
+
+	Describe("Of", func() {
+		It("returns the code of the error", func() {
+			Expect(errcode.Of(err)).To(Equal(errcode.Code{Name: "something_wrong", Class: errcode.ClassNotFound}))
+		})
+
+		It("returns the code of a wrapped error", func() {
+			Expect(errcode.Of(fmt.Errorf("networkUp: %w", err)).Name).To(Equal("something_wrong"))
+		})
+
+		It("returns unknown for an error without a code", func() {
+			Expect(errcode.Of(errors.New("plain"))).To(Equal(errcode.Code{Name: errcode.Unknown, Class: errcode.ClassInternal}))
+		})
+	})
+
+	Describe("ExitCode", func() {
+		It("maps each class to its exit code", func() {
+			Expect(errcode.ClassInternal.ExitCode()).To(Equal(1))
+			Expect(errcode.ClassInvalid.ExitCode()).To(Equal(2))
+			Expect(errcode.ClassNotFound.ExitCode()).To(Equal(3))
+			Expect(errcode.ClassConflict.ExitCode()).To(Equal(4))
+			Expect(errcode.ClassTimeout.ExitCode()).To(Equal(5))
+			Expect(errcode.ClassResource.ExitCode()).To(Equal(6))
+		})
+
+		It("returns the exit code of the class of the error", func() {
+			Expect(errcode.ExitCode(err)).To(Equal(errcode.ExitNotFound))
+			Expect(errcode.ExitCode(errors.New("plain"))).To(Equal(errcode.ExitInternal))
+		})
+	})
+
+	Describe("Write", func() {
+		var output *bytes.Buffer
+
+		BeforeEach(func() {
+			output = &bytes.Buffer{}
+		})
+
+		It("writes the message in text format", func() {
+			Expect(errcode.Write(output, err, errcode.FormatText)).To(Succeed())
+			Expect(output.String()).To(Equal("something went wrong\n"))
+		})
+
+		It("writes the code, message and exported fields in json format", func() {
+			Expect(errcode.Write(output, fmt.Errorf("networkUp: %w", err), errcode.FormatJSON)).To(Succeed())
+
+			var e map[string]interface{}
+			Expect(json.Unmarshal(output.Bytes(), &e)).To(Succeed())
+			Expect(e).To(Equal(map[string]interface{}{
+				"code":    "something_wrong",
+				"message": "networkUp: something went wrong",
+				"details": map[string]interface{}{
+					"container_id":   "some-container",
+					"exec_id":        "some-exec",
+					"pid":            float64(42),
+					"internal_error": "internal",
+				},
+			}))
+		})
+
+		It("omits the details of an error without a code", func() {
+			Expect(errcode.Write(output, errors.New("plain"), errcode.FormatJSON)).To(Succeed())
+			Expect(output.String()).To(MatchJSON(`{"code":"unknown","message":"plain"}`))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+// +build !windows
+
+package hcs
+
+// HCS and HNS only exist on Windows, so every call made through a Client on
+// other platforms fails with ErrPlatformNotSupported.
+
+func (c *Client) GetContainers(q ComputeSystemQuery) ([]ContainerProperties, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) NameToGuid(name string) (GUID, error) {
+	return GUID{}, ErrPlatformNotSupported
+}
+
+func (c *Client) GetLayerMountPath(info DriverInfo, id string) (string, error) {
+	return "", ErrPlatformNotSupported
+}
+
+func (c *Client) CreateContainer(id string, config *ContainerConfig) (Container, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) OpenContainer(id string) (Container, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) CreateEndpoint(endpoint *HNSEndpoint) (*HNSEndpoint, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) UpdateEndpoint(endpoint *HNSEndpoint) (*HNSEndpoint, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) DeleteEndpoint(endpoint *HNSEndpoint) (*HNSEndpoint, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) CreateNetwork(network *HNSNetwork, networkReady func() (bool, error)) (*HNSNetwork, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) DeleteNetwork(network *HNSNetwork) (*HNSNetwork, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) HNSListNetworkRequest() ([]HNSNetwork, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) GetHNSEndpointByID(id string) (*HNSEndpoint, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) GetHNSEndpointByName(name string) (*HNSEndpoint, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) GetHNSNetworkByName(name string) (*HNSNetwork, error) {
+	return nil, ErrPlatformNotSupported
+}
+
+func (c *Client) HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error {
+	return ErrPlatformNotSupported
+}
+
+func (c *Client) HotDetachEndpoint(containerID string, endpointID string) error {
+	return ErrPlatformNotSupported
+}
+
+func (c *Client) SignalProcess(containerId string, pid int, signal string) error {
+	return &SignalNotSupportedError{Call: "HcsSignalProcess", Err: ErrPlatformNotSupported}
+}
@@ -0,0 +1,225 @@
+package hcs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Microsoft/hcsshim"
+)
+
+func (c *Client) GetContainers(q ComputeSystemQuery) (cps []ContainerProperties, err error) {
+	defer c.Tracer.trace("GetContainers", "", 0, traceArgs{"query": q})(&cps, &err)
+
+	shimCps, err := hcsshim.GetContainers(hcsshim.ComputeSystemQuery(q))
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+	err = convert(shimCps, &cps)
+	return cps, err
+}
+
+func (c *Client) NameToGuid(name string) (guid GUID, err error) {
+	defer c.Tracer.trace("NameToGuid", "", 0, traceArgs{"name": name})(&guid, &err)
+
+	shimGuid, err := hcsshim.NameToGuid(name)
+	return GUID(shimGuid), shimError("", 0, err)
+}
+
+func (c *Client) GetLayerMountPath(info DriverInfo, id string) (path string, err error) {
+	defer c.Tracer.trace("GetLayerMountPath", "", 0, traceArgs{"info": info, "id": id})(&path, &err)
+
+	path, err = hcsshim.GetLayerMountPath(hcsshim.DriverInfo(info), id)
+	return path, shimError("", 0, err)
+}
+
+func (c *Client) CreateContainer(id string, config *ContainerConfig) (_ Container, err error) {
+	defer c.Tracer.trace("CreateContainer", id, 0, traceArgs{"config": config})(nil, &err)
+
+	var shimConfig hcsshim.ContainerConfig
+	if err := convert(config, &shimConfig); err != nil {
+		return nil, err
+	}
+
+	container, err := hcsshim.CreateContainer(id, &shimConfig)
+	if err != nil {
+		return nil, shimError(id, 0, err)
+	}
+	return c.wrap(id, &shimContainer{container: container, id: id}), nil
+}
+
+func (c *Client) OpenContainer(id string) (_ Container, err error) {
+	defer c.Tracer.trace("OpenContainer", id, 0, nil)(nil, &err)
+
+	container, err := hcsshim.OpenContainer(id)
+	if err != nil {
+		return nil, shimError(id, 0, err)
+	}
+	return c.wrap(id, &shimContainer{container: container, id: id}), nil
+}
+
+func (c *Client) CreateEndpoint(endpoint *HNSEndpoint) (created *HNSEndpoint, err error) {
+	defer c.Tracer.trace("CreateEndpoint", "", 0, traceArgs{"endpoint": endpoint})(&created, &err)
+	return convertEndpoint(endpoint, (*hcsshim.HNSEndpoint).Create)
+}
+
+func (c *Client) UpdateEndpoint(endpoint *HNSEndpoint) (updated *HNSEndpoint, err error) {
+	defer c.Tracer.trace("UpdateEndpoint", "", 0, traceArgs{"endpoint": endpoint})(&updated, &err)
+	return convertEndpoint(endpoint, (*hcsshim.HNSEndpoint).Update)
+}
+
+func (c *Client) DeleteEndpoint(endpoint *HNSEndpoint) (deleted *HNSEndpoint, err error) {
+	defer c.Tracer.trace("DeleteEndpoint", "", 0, traceArgs{"endpoint": endpoint})(&deleted, &err)
+	return convertEndpoint(endpoint, (*hcsshim.HNSEndpoint).Delete)
+}
+
+func (c *Client) CreateNetwork(network *HNSNetwork, networkReady func() (bool, error)) (net *HNSNetwork, err error) {
+	defer c.Tracer.trace("CreateNetwork", "", 0, traceArgs{"network": network})(&net, &err)
+
+	var shimNetwork hcsshim.HNSNetwork
+	if err := convert(network, &shimNetwork); err != nil {
+		return nil, err
+	}
+
+	/*
+	* This @errElmNotFound error is notorious for being thrown sometimes without any real reason
+	* (at least we believe so) -- possibly a bug in the Windows container networking stack.
+	* Let's give it a 2nd chance (and a 3rd) to get it right!
+	 */
+	const errElmNotFound = "network create: HNS failed with error : Element not found. "
+
+	var created *hcsshim.HNSNetwork
+	for i := 0; i < 3 && created == nil; i++ {
+		created, err = shimNetwork.Create()
+		if err != nil && err.Error() != errElmNotFound {
+			return nil, shimError("", 0, err)
+		}
+	}
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	net = &HNSNetwork{}
+	if err := convert(created, net); err != nil {
+		return nil, err
+	}
+
+	networkUp := false
+
+	for i := 0; i < 10; i++ {
+		time.Sleep(200 * time.Duration(i) * time.Millisecond)
+		networkUp, err = networkReady()
+		if err != nil {
+			return nil, err
+		}
+
+		if networkUp {
+			break
+		}
+
+	}
+
+	if !networkUp {
+		return nil, fmt.Errorf("network %s not ready in time", net.Name)
+	}
+
+	return net, nil
+}
+
+func (c *Client) DeleteNetwork(network *HNSNetwork) (deleted *HNSNetwork, err error) {
+	defer c.Tracer.trace("DeleteNetwork", "", 0, traceArgs{"network": network})(&deleted, &err)
+
+	var shimNetwork hcsshim.HNSNetwork
+	if err := convert(network, &shimNetwork); err != nil {
+		return nil, err
+	}
+
+	shimDeleted, err := shimNetwork.Delete()
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	deleted = &HNSNetwork{}
+	return deleted, convert(shimDeleted, deleted)
+}
+
+func (c *Client) HNSListNetworkRequest() (networks []HNSNetwork, err error) {
+	defer c.Tracer.trace("HNSListNetworkRequest", "", 0, nil)(&networks, &err)
+
+	shimNetworks, err := hcsshim.HNSListNetworkRequest("GET", "", "")
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+	err = convert(shimNetworks, &networks)
+	return networks, err
+}
+
+func (c *Client) GetHNSEndpointByID(id string) (endpoint *HNSEndpoint, err error) {
+	defer c.Tracer.trace("GetHNSEndpointByID", "", 0, traceArgs{"id": id})(&endpoint, &err)
+
+	shimEndpoint, err := hcsshim.GetHNSEndpointByID(id)
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	endpoint = &HNSEndpoint{}
+	return endpoint, convert(shimEndpoint, endpoint)
+}
+
+func (c *Client) GetHNSEndpointByName(name string) (endpoint *HNSEndpoint, err error) {
+	defer c.Tracer.trace("GetHNSEndpointByName", "", 0, traceArgs{"name": name})(&endpoint, &err)
+
+	shimEndpoint, err := hcsshim.GetHNSEndpointByName(name)
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	endpoint = &HNSEndpoint{}
+	return endpoint, convert(shimEndpoint, endpoint)
+}
+
+func (c *Client) GetHNSNetworkByName(name string) (network *HNSNetwork, err error) {
+	defer c.Tracer.trace("GetHNSNetworkByName", "", 0, traceArgs{"name": name})(&network, &err)
+
+	shimNetwork, err := hcsshim.GetHNSNetworkByName(name)
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	network = &HNSNetwork{}
+	return network, convert(shimNetwork, network)
+}
+
+func (c *Client) HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) (err error) {
+	defer c.Tracer.trace("HotAttachEndpoint", containerID, 0, traceArgs{"endpointId": endpointID})(nil, &err)
+
+	if err := hcsshim.HotAttachEndpoint(containerID, endpointID); err != nil {
+		return shimError(containerID, 0, err)
+	}
+
+	endpointUp := false
+
+	for i := 0; i < 10; i++ {
+		var err error
+		endpointUp, err = endpointReady()
+		if err != nil {
+			return err
+		}
+
+		if endpointUp {
+			break
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	if !endpointUp {
+		return fmt.Errorf("endpoint %s not ready in time", endpointID)
+	}
+
+	return nil
+}
+
+func (c *Client) HotDetachEndpoint(containerID string, endpointID string) (err error) {
+	defer c.Tracer.trace("HotDetachEndpoint", containerID, 0, traceArgs{"endpointId": endpointID})(nil, &err)
+	return shimError(containerID, 0, hcsshim.HotDetachEndpoint(containerID, endpointID))
+}
@@ -2,8 +2,18 @@ package hcs
 
 import (
 	"time"
+)
+
+// PausedState is the value HCS reports in ContainerProperties.State for a
+// compute system that has been paused.
+const PausedState = "Paused"
 
-	"github.com/Microsoft/hcsshim"
+// Resource and request types used with Container.Modify to change the limits
+// of a running compute system.
+const (
+	MemoryResource    ResourceType = "Memory"
+	ProcessorResource ResourceType = "Processor"
+	UpdateRequest     RequestType  = "Update"
 )
 
 //go:generate counterfeiter -o fakes/container.go --fake-name Container . Container
@@ -16,11 +26,11 @@ type Container interface {
 	Pause() error
 	Resume() error
 	HasPendingUpdates() (bool, error)
-	Statistics() (hcsshim.Statistics, error)
-	ProcessList() ([]hcsshim.ProcessListItem, error)
-	MappedVirtualDisks() (map[int]hcsshim.MappedVirtualDiskController, error)
-	CreateProcess(c *hcsshim.ProcessConfig) (hcsshim.Process, error)
-	OpenProcess(pid int) (hcsshim.Process, error)
+	Statistics() (Statistics, error)
+	ProcessList() ([]ProcessListItem, error)
+	MappedVirtualDisks() (map[int]MappedVirtualDiskController, error)
+	CreateProcess(c *ProcessConfig) (Process, error)
+	OpenProcess(pid int) (Process, error)
 	Close() error
-	Modify(config *hcsshim.ResourceModificationRequestResponse) error
+	Modify(config *ResourceModificationRequestResponse) error
 }
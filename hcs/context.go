@@ -0,0 +1,75 @@
+package hcs
+
+import (
+	"context"
+	"time"
+)
+
+// ContainerWithContext returns container with waits, and the waits of the
+// processes it creates or opens, that return ctx.Err() once ctx is done.
+func ContainerWithContext(ctx context.Context, container Container) Container {
+	return &contextContainer{Container: container, ctx: ctx}
+}
+
+type contextContainer struct {
+	Container
+	ctx context.Context
+}
+
+func (c *contextContainer) Wait() error {
+	return waitContext(c.ctx, c.Container.Wait)
+}
+
+func (c *contextContainer) WaitTimeout(timeout time.Duration) error {
+	return waitContext(c.ctx, func() error { return c.Container.WaitTimeout(timeout) })
+}
+
+func (c *contextContainer) CreateProcess(config *ProcessConfig) (Process, error) {
+	p, err := c.Container.CreateProcess(config)
+	if err != nil {
+		return nil, err
+	}
+	return &contextProcess{Process: p, ctx: c.ctx}, nil
+}
+
+func (c *contextContainer) OpenProcess(pid int) (Process, error) {
+	p, err := c.Container.OpenProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return &contextProcess{Process: p, ctx: c.ctx}, nil
+}
+
+type contextProcess struct {
+	Process
+	ctx context.Context
+}
+
+func (p *contextProcess) Wait() error {
+	return waitContext(p.ctx, p.Process.Wait)
+}
+
+func (p *contextProcess) WaitTimeout(timeout time.Duration) error {
+	return waitContext(p.ctx, func() error { return p.Process.WaitTimeout(timeout) })
+}
+
+// waitContext runs wait until it returns or ctx is done. HCS waits cannot be
+// interrupted, so a cancelled wait is left to finish once its handle is
+// closed.
+func waitContext(ctx context.Context, wait func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+package hcs_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContainerWithContext", func() {
+	var (
+		fakeContainer *fakes.Container
+		fakeProcess   *fakes.Process
+		ctx           context.Context
+		cancel        context.CancelFunc
+		container     hcs.Container
+		unblock       chan struct{}
+	)
+
+	BeforeEach(func() {
+		fakeContainer = &fakes.Container{}
+		fakeProcess = &fakes.Process{}
+		unblock = make(chan struct{})
+
+		block := func() error {
+			<-unblock
+			return nil
+		}
+		fakeContainer.WaitStub = block
+		fakeContainer.WaitTimeoutStub = func(time.Duration) error { return block() }
+		fakeProcess.WaitStub = block
+		fakeContainer.OpenProcessReturns(fakeProcess, nil)
+		fakeContainer.CreateProcessReturns(fakeProcess, nil)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		container = hcs.ContainerWithContext(ctx, fakeContainer)
+	})
+
+	AfterEach(func() {
+		cancel()
+		close(unblock)
+	})
+
+	It("returns the result of a wait that finishes", func() {
+		fakeContainer.WaitTimeoutStub = func(time.Duration) error { return errors.New("timed out") }
+
+		Expect(container.WaitTimeout(time.Second)).To(MatchError("timed out"))
+		Expect(fakeContainer.WaitTimeoutArgsForCall(0)).To(Equal(time.Second))
+	})
+
+	It("stops waiting on the container once the context is cancelled", func() {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- container.Wait()
+		}()
+
+		Consistently(errs).ShouldNot(Receive())
+		cancel()
+		Eventually(errs).Should(Receive(Equal(context.Canceled)))
+	})
+
+	It("stops waiting on processes it opens once the context is cancelled", func() {
+		p, err := container.OpenProcess(42)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(42))
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- p.Wait()
+		}()
+
+		Consistently(errs).ShouldNot(Receive())
+		cancel()
+		Eventually(errs).Should(Receive(Equal(context.Canceled)))
+	})
+
+	It("does not wait once the context is done", func() {
+		cancel()
+
+		p, err := container.CreateProcess(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.WaitTimeout(time.Minute)).To(Equal(context.Canceled))
+		Expect(fakeProcess.WaitTimeoutCallCount()).To(Equal(0))
+	})
+})
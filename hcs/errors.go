@@ -1,12 +1,178 @@
 package hcs
 
 import (
+	"errors"
 	"fmt"
 	"syscall"
 
-	"github.com/Microsoft/hcsshim"
+	"code.cloudfoundry.org/winc/errcode"
 )
 
+// The errors HCS returns, with the values and messages hcsshim gives them. The
+// Client returns these in place of the hcsshim ones, wrapped in a
+// ContainerError or ProcessError as hcsshim wraps them.
+var (
+	ErrComputeSystemDoesNotExist        = syscall.Errno(0xc037010e)
+	ErrElementNotFound                  = syscall.Errno(0x490)
+	ErrNotSupported                     = syscall.Errno(0x32)
+	ErrInvalidData                      = syscall.Errno(0xd)
+	ErrVmcomputeAlreadyStopped          = syscall.Errno(0xc0370110)
+	ErrVmcomputeOperationPending        = syscall.Errno(0xc0370103)
+	ErrVmcomputeOperationInvalidState   = syscall.Errno(0xc0370105)
+	ErrProcNotFound                     = syscall.Errno(0x7f)
+	ErrVmcomputeOperationAccessIsDenied = syscall.Errno(0x5)
+	ErrVmcomputeInvalidJSON             = syscall.Errno(0xc037010d)
+	ErrVmcomputeUnknownMessage          = syscall.Errno(0xc037010b)
+
+	ErrHandleClose             = errors.New("hcsshim: the handle generating this notification has been closed")
+	ErrAlreadyClosed           = errors.New("hcsshim: the handle has already been closed")
+	ErrInvalidNotificationType = errors.New("hcsshim: invalid notification type")
+	ErrInvalidProcessState     = errors.New("the process is in an invalid state for the attempted operation")
+	ErrTimeout                 = errors.New("hcsshim: timeout waiting for notification")
+	ErrUnexpectedContainerExit = errors.New("unexpected container exit")
+	ErrUnexpectedProcessAbort  = errors.New("lost communication with compute service")
+	ErrUnexpectedValue         = errors.New("unexpected value returned from hcs")
+	ErrPlatformNotSupported    = errors.New("unsupported platform request")
+)
+
+// ContainerError is an error HCS returned for an operation on the compute
+// system Id.
+type ContainerError struct {
+	Id        string
+	Operation string
+	ExtraInfo string
+	Err       error
+	Events    []string
+}
+
+func (e *ContainerError) Error() string {
+	s := "container " + e.Id
+	if e.Operation != "" {
+		s += " encountered an error during " + e.Operation
+	}
+	s += causeMessage(e.Err, e.Events)
+
+	if e.ExtraInfo != "" {
+		s += " extra info: " + e.ExtraInfo
+	}
+	return s
+}
+
+func (e *ContainerError) Code() errcode.Code {
+	return causeCode(e.Err, "container")
+}
+
+// ProcessError is an error HCS returned for an operation on the process Pid
+// of the compute system Id.
+type ProcessError struct {
+	Id        string
+	Pid       int
+	Operation string
+	Err       error
+	Events    []string
+}
+
+func (e *ProcessError) Error() string {
+	s := fmt.Sprintf("process %d in container %s", e.Pid, e.Id)
+	if e.Operation != "" {
+		s += " encountered an error during " + e.Operation
+	}
+	return s + causeMessage(e.Err, e.Events)
+}
+
+func (e *ProcessError) Code() errcode.Code {
+	return causeCode(e.Err, "process")
+}
+
+// causeCode classifies an error HCS returned for an operation on a compute
+// system or process, named after the kind of object it happened on.
+func causeCode(err error, kind string) errcode.Code {
+	switch err {
+	case ErrComputeSystemDoesNotExist, ErrElementNotFound, ErrProcNotFound:
+		return errcode.Code{Name: kind + "_not_found", Class: errcode.ClassNotFound}
+	case ErrTimeout:
+		return errcode.Code{Name: "hcs_timeout", Class: errcode.ClassTimeout}
+	case ErrVmcomputeOperationPending, ErrVmcomputeOperationInvalidState, ErrVmcomputeAlreadyStopped, ErrInvalidProcessState:
+		return errcode.Code{Name: "invalid_" + kind + "_state", Class: errcode.ClassConflict}
+	}
+	return errcode.Code{Name: "hcs_" + kind + "_error", Class: errcode.ClassInternal}
+}
+
+func causeMessage(err error, events []string) string {
+	var s string
+	switch e := err.(type) {
+	case nil:
+	case syscall.Errno:
+		s = fmt.Sprintf(": failure in a Windows system call: %s (0x%x)", e, uint32(e))
+	default:
+		s = fmt.Sprintf(": %s", err)
+	}
+
+	for _, event := range events {
+		s += "\n" + event
+	}
+	return s
+}
+
+type NetworkNotFoundError struct {
+	NetworkName string
+}
+
+func (e NetworkNotFoundError) Error() string {
+	return fmt.Sprintf("Network %s not found", e.NetworkName)
+}
+
+func (e NetworkNotFoundError) Code() errcode.Code {
+	return errcode.Code{Name: "network_not_found", Class: errcode.ClassNotFound}
+}
+
+type EndpointNotFoundError struct {
+	EndpointName string
+}
+
+func (e EndpointNotFoundError) Error() string {
+	return fmt.Sprintf("Endpoint %s not found", e.EndpointName)
+}
+
+func (e EndpointNotFoundError) Code() errcode.Code {
+	return errcode.Code{Name: "endpoint_not_found", Class: errcode.ClassNotFound}
+}
+
+// IsNotExist reports whether err is caused by a compute system, process,
+// network or endpoint that does not exist.
+func IsNotExist(err error) bool {
+	switch err.(type) {
+	case NetworkNotFoundError, EndpointNotFoundError:
+		return true
+	}
+
+	err = innerError(err)
+	return err == ErrComputeSystemDoesNotExist ||
+		err == ErrElementNotFound ||
+		err == ErrProcNotFound
+}
+
+// IsPending reports whether err is caused by an operation that HCS is still
+// carrying out.
+func IsPending(err error) bool {
+	return innerError(err) == ErrVmcomputeOperationPending
+}
+
+// IsTimeout reports whether err is caused by a wait that timed out.
+func IsTimeout(err error) bool {
+	return innerError(err) == ErrTimeout
+}
+
+func innerError(err error) error {
+	switch e := err.(type) {
+	case *ContainerError:
+		return e.Err
+	case *ProcessError:
+		return e.Err
+	}
+	return err
+}
+
 type NotFoundError struct {
 	Id string
 }
@@ -15,6 +181,10 @@ func (e *NotFoundError) Error() string {
 	return fmt.Sprintf("container not found: %s", e.Id)
 }
 
+func (e *NotFoundError) Code() errcode.Code {
+	return errcode.Code{Name: "container_not_found", Class: errcode.ClassNotFound}
+}
+
 type DuplicateError struct {
 	Id string
 }
@@ -23,14 +193,35 @@ func (e *DuplicateError) Error() string {
 	return fmt.Sprintf("multiple containers found with the same id: %s", e.Id)
 }
 
+func (e *DuplicateError) Code() errcode.Code {
+	return errcode.Code{Name: "duplicate_container", Class: errcode.ClassConflict}
+}
+
 type LowMemoryError struct{}
 
 func (e *LowMemoryError) Error() string {
 	return fmt.Sprintf("not enough memory")
 }
 
+func (e *LowMemoryError) Code() errcode.Code {
+	return errcode.Code{Name: "low_memory", Class: errcode.ClassResource}
+}
+
+type SignalNotSupportedError struct {
+	Call string
+	Err  error
+}
+
+func (e *SignalNotSupportedError) Error() string {
+	return fmt.Sprintf("HCS on this host cannot signal processes, %s is unavailable: %s", e.Call, e.Err)
+}
+
+func (e *SignalNotSupportedError) Code() errcode.Code {
+	return errcode.Code{Name: "signal_not_supported", Class: errcode.ClassInternal}
+}
+
 func CleanError(err error) error {
-	cErr, ok := err.(*hcsshim.ContainerError)
+	cErr, ok := err.(*ContainerError)
 	if !ok {
 		return err
 	}
@@ -4,18 +4,19 @@ import (
 	"errors"
 	"syscall"
 
+	"code.cloudfoundry.org/winc/errcode"
 	"code.cloudfoundry.org/winc/hcs"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("HCS Errors", func() {
 	Describe("CleanError", func() {
-		Context("when given an hcsshim low memory error", func() {
+		Context("when given an HCS low memory error", func() {
 			var inputError error
 			BeforeEach(func() {
-				inputError = &hcsshim.ContainerError{
+				inputError = &hcs.ContainerError{
 					Err: syscall.Errno(0x5af),
 				}
 			})
@@ -25,10 +26,10 @@ var _ = Describe("HCS Errors", func() {
 				Expect(outputError).To(BeAssignableToTypeOf(&hcs.LowMemoryError{}))
 			})
 		})
-		Context("when given an hcsshim other error", func() {
+		Context("when given an HCS other error", func() {
 			var inputError error
 			BeforeEach(func() {
-				inputError = &hcsshim.ContainerError{
+				inputError = &hcs.ContainerError{
 					Err: syscall.Errno(0x5ae),
 				}
 			})
@@ -50,4 +51,23 @@ var _ = Describe("HCS Errors", func() {
 			})
 		})
 	})
+
+	DescribeTable("Code",
+		func(err error, name string, class errcode.Class) {
+			Expect(errcode.Of(err)).To(Equal(errcode.Code{Name: name, Class: class}))
+		},
+		Entry("a container that does not exist", &hcs.ContainerError{Err: hcs.ErrComputeSystemDoesNotExist}, "container_not_found", errcode.ClassNotFound),
+		Entry("a container operation that timed out", &hcs.ContainerError{Err: hcs.ErrTimeout}, "hcs_timeout", errcode.ClassTimeout),
+		Entry("a container operation that is still pending", &hcs.ContainerError{Err: hcs.ErrVmcomputeOperationPending}, "invalid_container_state", errcode.ClassConflict),
+		Entry("any other container error", &hcs.ContainerError{Err: syscall.Errno(0x5ae)}, "hcs_container_error", errcode.ClassInternal),
+		Entry("a process that does not exist", &hcs.ProcessError{Err: hcs.ErrElementNotFound}, "process_not_found", errcode.ClassNotFound),
+		Entry("a process in an invalid state", &hcs.ProcessError{Err: hcs.ErrInvalidProcessState}, "invalid_process_state", errcode.ClassConflict),
+		Entry("any other process error", &hcs.ProcessError{Err: errors.New("some error")}, "hcs_process_error", errcode.ClassInternal),
+		Entry("a network that does not exist", hcs.NetworkNotFoundError{NetworkName: "some-network"}, "network_not_found", errcode.ClassNotFound),
+		Entry("an endpoint that does not exist", hcs.EndpointNotFoundError{EndpointName: "some-endpoint"}, "endpoint_not_found", errcode.ClassNotFound),
+		Entry("a container that is not found", &hcs.NotFoundError{Id: "some-id"}, "container_not_found", errcode.ClassNotFound),
+		Entry("duplicate containers", &hcs.DuplicateError{Id: "some-id"}, "duplicate_container", errcode.ClassConflict),
+		Entry("low memory", &hcs.LowMemoryError{}, "low_memory", errcode.ClassResource),
+		Entry("signalling that is not supported", &hcs.SignalNotSupportedError{Call: "HcsSignalProcess", Err: hcs.ErrPlatformNotSupported}, "signal_not_supported", errcode.ClassInternal),
+	)
 })
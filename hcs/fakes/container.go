@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
-	"github.com/Microsoft/hcsshim"
 )
 
 type Container struct {
@@ -86,63 +85,63 @@ type Container struct {
 		result1 bool
 		result2 error
 	}
-	StatisticsStub        func() (hcsshim.Statistics, error)
+	StatisticsStub        func() (hcs.Statistics, error)
 	statisticsMutex       sync.RWMutex
 	statisticsArgsForCall []struct{}
 	statisticsReturns     struct {
-		result1 hcsshim.Statistics
+		result1 hcs.Statistics
 		result2 error
 	}
 	statisticsReturnsOnCall map[int]struct {
-		result1 hcsshim.Statistics
+		result1 hcs.Statistics
 		result2 error
 	}
-	ProcessListStub        func() ([]hcsshim.ProcessListItem, error)
+	ProcessListStub        func() ([]hcs.ProcessListItem, error)
 	processListMutex       sync.RWMutex
 	processListArgsForCall []struct{}
 	processListReturns     struct {
-		result1 []hcsshim.ProcessListItem
+		result1 []hcs.ProcessListItem
 		result2 error
 	}
 	processListReturnsOnCall map[int]struct {
-		result1 []hcsshim.ProcessListItem
+		result1 []hcs.ProcessListItem
 		result2 error
 	}
-	MappedVirtualDisksStub        func() (map[int]hcsshim.MappedVirtualDiskController, error)
+	MappedVirtualDisksStub        func() (map[int]hcs.MappedVirtualDiskController, error)
 	mappedVirtualDisksMutex       sync.RWMutex
 	mappedVirtualDisksArgsForCall []struct{}
 	mappedVirtualDisksReturns     struct {
-		result1 map[int]hcsshim.MappedVirtualDiskController
+		result1 map[int]hcs.MappedVirtualDiskController
 		result2 error
 	}
 	mappedVirtualDisksReturnsOnCall map[int]struct {
-		result1 map[int]hcsshim.MappedVirtualDiskController
+		result1 map[int]hcs.MappedVirtualDiskController
 		result2 error
 	}
-	CreateProcessStub        func(c *hcsshim.ProcessConfig) (hcsshim.Process, error)
+	CreateProcessStub        func(c *hcs.ProcessConfig) (hcs.Process, error)
 	createProcessMutex       sync.RWMutex
 	createProcessArgsForCall []struct {
-		c *hcsshim.ProcessConfig
+		c *hcs.ProcessConfig
 	}
 	createProcessReturns struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}
 	createProcessReturnsOnCall map[int]struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}
-	OpenProcessStub        func(pid int) (hcsshim.Process, error)
+	OpenProcessStub        func(pid int) (hcs.Process, error)
 	openProcessMutex       sync.RWMutex
 	openProcessArgsForCall []struct {
 		pid int
 	}
 	openProcessReturns struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}
 	openProcessReturnsOnCall map[int]struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}
 	CloseStub        func() error
@@ -154,10 +153,10 @@ type Container struct {
 	closeReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ModifyStub        func(config *hcsshim.ResourceModificationRequestResponse) error
+	ModifyStub        func(config *hcs.ResourceModificationRequestResponse) error
 	modifyMutex       sync.RWMutex
 	modifyArgsForCall []struct {
-		config *hcsshim.ResourceModificationRequestResponse
+		config *hcs.ResourceModificationRequestResponse
 	}
 	modifyReturns struct {
 		result1 error
@@ -500,7 +499,7 @@ func (fake *Container) HasPendingUpdatesReturnsOnCall(i int, result1 bool, resul
 	}{result1, result2}
 }
 
-func (fake *Container) Statistics() (hcsshim.Statistics, error) {
+func (fake *Container) Statistics() (hcs.Statistics, error) {
 	fake.statisticsMutex.Lock()
 	ret, specificReturn := fake.statisticsReturnsOnCall[len(fake.statisticsArgsForCall)]
 	fake.statisticsArgsForCall = append(fake.statisticsArgsForCall, struct{}{})
@@ -521,29 +520,29 @@ func (fake *Container) StatisticsCallCount() int {
 	return len(fake.statisticsArgsForCall)
 }
 
-func (fake *Container) StatisticsReturns(result1 hcsshim.Statistics, result2 error) {
+func (fake *Container) StatisticsReturns(result1 hcs.Statistics, result2 error) {
 	fake.StatisticsStub = nil
 	fake.statisticsReturns = struct {
-		result1 hcsshim.Statistics
+		result1 hcs.Statistics
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) StatisticsReturnsOnCall(i int, result1 hcsshim.Statistics, result2 error) {
+func (fake *Container) StatisticsReturnsOnCall(i int, result1 hcs.Statistics, result2 error) {
 	fake.StatisticsStub = nil
 	if fake.statisticsReturnsOnCall == nil {
 		fake.statisticsReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.Statistics
+			result1 hcs.Statistics
 			result2 error
 		})
 	}
 	fake.statisticsReturnsOnCall[i] = struct {
-		result1 hcsshim.Statistics
+		result1 hcs.Statistics
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) ProcessList() ([]hcsshim.ProcessListItem, error) {
+func (fake *Container) ProcessList() ([]hcs.ProcessListItem, error) {
 	fake.processListMutex.Lock()
 	ret, specificReturn := fake.processListReturnsOnCall[len(fake.processListArgsForCall)]
 	fake.processListArgsForCall = append(fake.processListArgsForCall, struct{}{})
@@ -564,29 +563,29 @@ func (fake *Container) ProcessListCallCount() int {
 	return len(fake.processListArgsForCall)
 }
 
-func (fake *Container) ProcessListReturns(result1 []hcsshim.ProcessListItem, result2 error) {
+func (fake *Container) ProcessListReturns(result1 []hcs.ProcessListItem, result2 error) {
 	fake.ProcessListStub = nil
 	fake.processListReturns = struct {
-		result1 []hcsshim.ProcessListItem
+		result1 []hcs.ProcessListItem
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) ProcessListReturnsOnCall(i int, result1 []hcsshim.ProcessListItem, result2 error) {
+func (fake *Container) ProcessListReturnsOnCall(i int, result1 []hcs.ProcessListItem, result2 error) {
 	fake.ProcessListStub = nil
 	if fake.processListReturnsOnCall == nil {
 		fake.processListReturnsOnCall = make(map[int]struct {
-			result1 []hcsshim.ProcessListItem
+			result1 []hcs.ProcessListItem
 			result2 error
 		})
 	}
 	fake.processListReturnsOnCall[i] = struct {
-		result1 []hcsshim.ProcessListItem
+		result1 []hcs.ProcessListItem
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) MappedVirtualDisks() (map[int]hcsshim.MappedVirtualDiskController, error) {
+func (fake *Container) MappedVirtualDisks() (map[int]hcs.MappedVirtualDiskController, error) {
 	fake.mappedVirtualDisksMutex.Lock()
 	ret, specificReturn := fake.mappedVirtualDisksReturnsOnCall[len(fake.mappedVirtualDisksArgsForCall)]
 	fake.mappedVirtualDisksArgsForCall = append(fake.mappedVirtualDisksArgsForCall, struct{}{})
@@ -607,33 +606,33 @@ func (fake *Container) MappedVirtualDisksCallCount() int {
 	return len(fake.mappedVirtualDisksArgsForCall)
 }
 
-func (fake *Container) MappedVirtualDisksReturns(result1 map[int]hcsshim.MappedVirtualDiskController, result2 error) {
+func (fake *Container) MappedVirtualDisksReturns(result1 map[int]hcs.MappedVirtualDiskController, result2 error) {
 	fake.MappedVirtualDisksStub = nil
 	fake.mappedVirtualDisksReturns = struct {
-		result1 map[int]hcsshim.MappedVirtualDiskController
+		result1 map[int]hcs.MappedVirtualDiskController
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) MappedVirtualDisksReturnsOnCall(i int, result1 map[int]hcsshim.MappedVirtualDiskController, result2 error) {
+func (fake *Container) MappedVirtualDisksReturnsOnCall(i int, result1 map[int]hcs.MappedVirtualDiskController, result2 error) {
 	fake.MappedVirtualDisksStub = nil
 	if fake.mappedVirtualDisksReturnsOnCall == nil {
 		fake.mappedVirtualDisksReturnsOnCall = make(map[int]struct {
-			result1 map[int]hcsshim.MappedVirtualDiskController
+			result1 map[int]hcs.MappedVirtualDiskController
 			result2 error
 		})
 	}
 	fake.mappedVirtualDisksReturnsOnCall[i] = struct {
-		result1 map[int]hcsshim.MappedVirtualDiskController
+		result1 map[int]hcs.MappedVirtualDiskController
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) CreateProcess(c *hcsshim.ProcessConfig) (hcsshim.Process, error) {
+func (fake *Container) CreateProcess(c *hcs.ProcessConfig) (hcs.Process, error) {
 	fake.createProcessMutex.Lock()
 	ret, specificReturn := fake.createProcessReturnsOnCall[len(fake.createProcessArgsForCall)]
 	fake.createProcessArgsForCall = append(fake.createProcessArgsForCall, struct {
-		c *hcsshim.ProcessConfig
+		c *hcs.ProcessConfig
 	}{c})
 	fake.recordInvocation("CreateProcess", []interface{}{c})
 	fake.createProcessMutex.Unlock()
@@ -652,35 +651,35 @@ func (fake *Container) CreateProcessCallCount() int {
 	return len(fake.createProcessArgsForCall)
 }
 
-func (fake *Container) CreateProcessArgsForCall(i int) *hcsshim.ProcessConfig {
+func (fake *Container) CreateProcessArgsForCall(i int) *hcs.ProcessConfig {
 	fake.createProcessMutex.RLock()
 	defer fake.createProcessMutex.RUnlock()
 	return fake.createProcessArgsForCall[i].c
 }
 
-func (fake *Container) CreateProcessReturns(result1 hcsshim.Process, result2 error) {
+func (fake *Container) CreateProcessReturns(result1 hcs.Process, result2 error) {
 	fake.CreateProcessStub = nil
 	fake.createProcessReturns = struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) CreateProcessReturnsOnCall(i int, result1 hcsshim.Process, result2 error) {
+func (fake *Container) CreateProcessReturnsOnCall(i int, result1 hcs.Process, result2 error) {
 	fake.CreateProcessStub = nil
 	if fake.createProcessReturnsOnCall == nil {
 		fake.createProcessReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.Process
+			result1 hcs.Process
 			result2 error
 		})
 	}
 	fake.createProcessReturnsOnCall[i] = struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) OpenProcess(pid int) (hcsshim.Process, error) {
+func (fake *Container) OpenProcess(pid int) (hcs.Process, error) {
 	fake.openProcessMutex.Lock()
 	ret, specificReturn := fake.openProcessReturnsOnCall[len(fake.openProcessArgsForCall)]
 	fake.openProcessArgsForCall = append(fake.openProcessArgsForCall, struct {
@@ -709,24 +708,24 @@ func (fake *Container) OpenProcessArgsForCall(i int) int {
 	return fake.openProcessArgsForCall[i].pid
 }
 
-func (fake *Container) OpenProcessReturns(result1 hcsshim.Process, result2 error) {
+func (fake *Container) OpenProcessReturns(result1 hcs.Process, result2 error) {
 	fake.OpenProcessStub = nil
 	fake.openProcessReturns = struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Container) OpenProcessReturnsOnCall(i int, result1 hcsshim.Process, result2 error) {
+func (fake *Container) OpenProcessReturnsOnCall(i int, result1 hcs.Process, result2 error) {
 	fake.OpenProcessStub = nil
 	if fake.openProcessReturnsOnCall == nil {
 		fake.openProcessReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.Process
+			result1 hcs.Process
 			result2 error
 		})
 	}
 	fake.openProcessReturnsOnCall[i] = struct {
-		result1 hcsshim.Process
+		result1 hcs.Process
 		result2 error
 	}{result1, result2}
 }
@@ -771,11 +770,11 @@ func (fake *Container) CloseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *Container) Modify(config *hcsshim.ResourceModificationRequestResponse) error {
+func (fake *Container) Modify(config *hcs.ResourceModificationRequestResponse) error {
 	fake.modifyMutex.Lock()
 	ret, specificReturn := fake.modifyReturnsOnCall[len(fake.modifyArgsForCall)]
 	fake.modifyArgsForCall = append(fake.modifyArgsForCall, struct {
-		config *hcsshim.ResourceModificationRequestResponse
+		config *hcs.ResourceModificationRequestResponse
 	}{config})
 	fake.recordInvocation("Modify", []interface{}{config})
 	fake.modifyMutex.Unlock()
@@ -794,7 +793,7 @@ func (fake *Container) ModifyCallCount() int {
 	return len(fake.modifyArgsForCall)
 }
 
-func (fake *Container) ModifyArgsForCall(i int) *hcsshim.ResourceModificationRequestResponse {
+func (fake *Container) ModifyArgsForCall(i int) *hcs.ResourceModificationRequestResponse {
 	fake.modifyMutex.RLock()
 	defer fake.modifyMutex.RUnlock()
 	return fake.modifyArgsForCall[i].config
@@ -0,0 +1,241 @@
+package hcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Microsoft/hcsshim"
+)
+
+// shimErrors are the hcsshim errors that are not Windows system call errors,
+// which compare equal to the errors of this package by value.
+var shimErrors = map[error]error{
+	hcsshim.ErrHandleClose:             ErrHandleClose,
+	hcsshim.ErrAlreadyClosed:           ErrAlreadyClosed,
+	hcsshim.ErrInvalidNotificationType: ErrInvalidNotificationType,
+	hcsshim.ErrInvalidProcessState:     ErrInvalidProcessState,
+	hcsshim.ErrTimeout:                 ErrTimeout,
+	hcsshim.ErrUnexpectedContainerExit: ErrUnexpectedContainerExit,
+	hcsshim.ErrUnexpectedProcessAbort:  ErrUnexpectedProcessAbort,
+	hcsshim.ErrUnexpectedValue:         ErrUnexpectedValue,
+	hcsshim.ErrPlatformNotSupported:    ErrPlatformNotSupported,
+}
+
+// shimError returns err with the hcsshim errors it is or wraps replaced by
+// those of this package. id and pid are the compute system and process the
+// error was returned for, which hcsshim does not export.
+func shimError(id string, pid int, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *hcsshim.ContainerError:
+		cErr := &ContainerError{Id: id, Operation: e.Operation, ExtraInfo: e.ExtraInfo, Err: shimError(id, pid, e.Err)}
+		for _, event := range e.Events {
+			cErr.Events = append(cErr.Events, event.String())
+		}
+		return cErr
+	case *hcsshim.ProcessError:
+		pErr := &ProcessError{Id: id, Pid: pid, Operation: e.Operation, Err: shimError(id, pid, e.Err)}
+		for _, event := range e.Events {
+			pErr.Events = append(pErr.Events, event.String())
+		}
+		return pErr
+	case hcsshim.NetworkNotFoundError:
+		return NetworkNotFoundError{NetworkName: e.NetworkName}
+	case hcsshim.EndpointNotFoundError:
+		return EndpointNotFoundError{EndpointName: e.EndpointName}
+	}
+
+	if shimErr, ok := shimErrors[err]; ok {
+		return shimErr
+	}
+	return err
+}
+
+// convert copies from, a document of this package or of hcsshim, into to, the
+// same document of the other, through the JSON they share. It fails rather
+// than drop a field that to does not have.
+func convert(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(to)
+}
+
+func convertEndpoint(endpoint *HNSEndpoint, call func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)) (*HNSEndpoint, error) {
+	var shimEndpoint hcsshim.HNSEndpoint
+	if err := convert(endpoint, &shimEndpoint); err != nil {
+		return nil, err
+	}
+
+	result, err := call(&shimEndpoint)
+	if err != nil {
+		return nil, shimError("", 0, err)
+	}
+
+	converted := &HNSEndpoint{}
+	if err := convert(result, converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// shimContainer is an hcsshim container with the documents and errors of
+// this package.
+type shimContainer struct {
+	container hcsshim.Container
+	id        string
+}
+
+func (c *shimContainer) error(err error) error {
+	return shimError(c.id, 0, err)
+}
+
+func (c *shimContainer) Start() error {
+	return c.error(c.container.Start())
+}
+
+func (c *shimContainer) Shutdown() error {
+	return c.error(c.container.Shutdown())
+}
+
+func (c *shimContainer) Terminate() error {
+	return c.error(c.container.Terminate())
+}
+
+func (c *shimContainer) Wait() error {
+	return c.error(c.container.Wait())
+}
+
+func (c *shimContainer) WaitTimeout(timeout time.Duration) error {
+	return c.error(c.container.WaitTimeout(timeout))
+}
+
+func (c *shimContainer) Pause() error {
+	return c.error(c.container.Pause())
+}
+
+func (c *shimContainer) Resume() error {
+	return c.error(c.container.Resume())
+}
+
+func (c *shimContainer) HasPendingUpdates() (bool, error) {
+	pending, err := c.container.HasPendingUpdates()
+	return pending, c.error(err)
+}
+
+func (c *shimContainer) Statistics() (Statistics, error) {
+	shimStats, err := c.container.Statistics()
+	if err != nil {
+		return Statistics{}, c.error(err)
+	}
+
+	var stats Statistics
+	err = convert(shimStats, &stats)
+	return stats, err
+}
+
+func (c *shimContainer) ProcessList() ([]ProcessListItem, error) {
+	shimProcesses, err := c.container.ProcessList()
+	if err != nil {
+		return nil, c.error(err)
+	}
+
+	var processes []ProcessListItem
+	err = convert(shimProcesses, &processes)
+	return processes, err
+}
+
+func (c *shimContainer) MappedVirtualDisks() (map[int]MappedVirtualDiskController, error) {
+	shimDisks, err := c.container.MappedVirtualDisks()
+	if err != nil {
+		return nil, c.error(err)
+	}
+
+	var disks map[int]MappedVirtualDiskController
+	err = convert(shimDisks, &disks)
+	return disks, err
+}
+
+func (c *shimContainer) CreateProcess(config *ProcessConfig) (Process, error) {
+	var shimConfig hcsshim.ProcessConfig
+	if err := convert(config, &shimConfig); err != nil {
+		return nil, err
+	}
+
+	p, err := c.container.CreateProcess(&shimConfig)
+	if err != nil {
+		return nil, c.error(err)
+	}
+	return &shimProcess{Process: p, id: c.id}, nil
+}
+
+func (c *shimContainer) OpenProcess(pid int) (Process, error) {
+	p, err := c.container.OpenProcess(pid)
+	if err != nil {
+		return nil, shimError(c.id, pid, err)
+	}
+	return &shimProcess{Process: p, id: c.id}, nil
+}
+
+func (c *shimContainer) Close() error {
+	return c.error(c.container.Close())
+}
+
+func (c *shimContainer) Modify(config *ResourceModificationRequestResponse) error {
+	var shimConfig hcsshim.ResourceModificationRequestResponse
+	if err := convert(config, &shimConfig); err != nil {
+		return err
+	}
+	return c.error(c.container.Modify(&shimConfig))
+}
+
+// shimProcess is an hcsshim process with the errors of this package.
+type shimProcess struct {
+	hcsshim.Process
+	id string
+}
+
+func (p *shimProcess) error(err error) error {
+	return shimError(p.id, p.Pid(), err)
+}
+
+func (p *shimProcess) Kill() error {
+	return p.error(p.Process.Kill())
+}
+
+func (p *shimProcess) Wait() error {
+	return p.error(p.Process.Wait())
+}
+
+func (p *shimProcess) WaitTimeout(timeout time.Duration) error {
+	return p.error(p.Process.WaitTimeout(timeout))
+}
+
+func (p *shimProcess) ExitCode() (int, error) {
+	exitCode, err := p.Process.ExitCode()
+	return exitCode, p.error(err)
+}
+
+func (p *shimProcess) ResizeConsole(width, height uint16) error {
+	return p.error(p.Process.ResizeConsole(width, height))
+}
+
+func (p *shimProcess) Stdio() (io.WriteCloser, io.ReadCloser, io.ReadCloser, error) {
+	stdin, stdout, stderr, err := p.Process.Stdio()
+	return stdin, stdout, stderr, p.error(err)
+}
+
+func (p *shimProcess) CloseStdin() error {
+	return p.error(p.Process.CloseStdin())
+}
+
+func (p *shimProcess) Close() error {
+	return p.error(p.Process.Close())
+}
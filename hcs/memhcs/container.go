@@ -0,0 +1,363 @@
+package memhcs
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+// ExitCodeTerminated is the exit code of the processes that are still running
+// when their compute system stops or that are killed.
+const ExitCodeTerminated = 1
+
+type computeSystem struct {
+	id        string
+	config    hcs.ContainerConfig
+	state     string
+	started   time.Time
+	stopped   chan struct{}
+	pids      []int
+	endpoints []string
+}
+
+func (cs *computeSystem) properties() hcs.ContainerProperties {
+	return hcs.ContainerProperties{
+		ID:         cs.id,
+		Name:       cs.id,
+		State:      cs.state,
+		SystemType: cs.config.SystemType,
+		Owner:      cs.config.Owner,
+		Stopped:    cs.state == StateStopped,
+	}
+}
+
+// invalidStateError is the error HCS returns for an operation the compute
+// system cannot do in its current state.
+func (cs *computeSystem) invalidStateError() error {
+	if cs.state == StateStopped {
+		return hcs.ErrVmcomputeAlreadyStopped
+	}
+	return hcs.ErrVmcomputeOperationInvalidState
+}
+
+// systemHandle is a handle to a compute system. As in HCS, a compute system
+// that has stopped is kept, and reported as stopped, until a handle opened
+// after it stopped is closed, so closing the handle that stopped it does not
+// remove it.
+type systemHandle struct {
+	h             *HCS
+	cs            *computeSystem
+	closed        chan struct{}
+	openedStopped bool
+}
+
+// newSystemHandle must be called with the lock held.
+func newSystemHandle(h *HCS, cs *computeSystem) *systemHandle {
+	return &systemHandle{h: h, cs: cs, closed: make(chan struct{}), openedStopped: cs.state == StateStopped}
+}
+
+// isClosed must be called with the lock held.
+func (c *systemHandle) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *systemHandle) Start() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateCreated {
+		return c.cs.invalidStateError()
+	}
+
+	c.cs.state = StateRunning
+	c.cs.started = time.Now()
+	return nil
+}
+
+// Shutdown stops the compute system synchronously, so it never returns a
+// pending error.
+func (c *systemHandle) Shutdown() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning && c.cs.state != StateCreated {
+		return c.cs.invalidStateError()
+	}
+
+	c.stop()
+	return nil
+}
+
+func (c *systemHandle) Terminate() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state == StateStopped {
+		return c.cs.invalidStateError()
+	}
+
+	c.stop()
+	return nil
+}
+
+// stop must be called with the lock held.
+func (c *systemHandle) stop() {
+	for _, pid := range c.cs.pids {
+		c.h.processes[pid].exit(ExitCodeTerminated)
+	}
+	c.cs.state = StateStopped
+	close(c.cs.stopped)
+}
+
+func (c *systemHandle) Wait() error {
+	return c.WaitTimeout(0)
+}
+
+// WaitTimeout waits for the compute system to stop. A timeout of 0 waits
+// indefinitely.
+func (c *systemHandle) WaitTimeout(timeout time.Duration) error {
+	return wait(c.cs.stopped, c.closed, timeout)
+}
+
+func (c *systemHandle) Pause() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning {
+		return c.cs.invalidStateError()
+	}
+
+	c.cs.state = StatePaused
+	return nil
+}
+
+func (c *systemHandle) Resume() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StatePaused {
+		return c.cs.invalidStateError()
+	}
+
+	c.cs.state = StateRunning
+	return nil
+}
+
+func (c *systemHandle) HasPendingUpdates() (bool, error) {
+	return false, nil
+}
+
+// Statistics reports the uptime of the compute system and an entry for each
+// of its endpoints. No resources are used by its processes.
+func (c *systemHandle) Statistics() (hcs.Statistics, error) {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.Statistics{}, hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning && c.cs.state != StatePaused {
+		return hcs.Statistics{}, c.cs.invalidStateError()
+	}
+
+	now := time.Now()
+	stats := hcs.Statistics{
+		Timestamp:          now,
+		ContainerStartTime: c.cs.started,
+		Uptime100ns:        uint64(now.Sub(c.cs.started) / 100),
+	}
+	for _, endpointId := range c.cs.endpoints {
+		stats.Network = append(stats.Network, hcs.NetworkStats{EndpointId: endpointId})
+	}
+
+	return stats, nil
+}
+
+// ProcessList lists the processes of the compute system that have not exited.
+func (c *systemHandle) ProcessList() ([]hcs.ProcessListItem, error) {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return nil, hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning && c.cs.state != StatePaused {
+		return nil, c.cs.invalidStateError()
+	}
+
+	items := []hcs.ProcessListItem{}
+	for _, pid := range c.cs.pids {
+		p := c.h.processes[pid]
+		if p.hasExited() {
+			continue
+		}
+		items = append(items, hcs.ProcessListItem{
+			CreateTimestamp: p.created,
+			ImageName:       p.image,
+			ProcessId:       uint32(p.pid),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ProcessId < items[j].ProcessId })
+
+	return items, nil
+}
+
+func (c *systemHandle) MappedVirtualDisks() (map[int]hcs.MappedVirtualDiskController, error) {
+	return map[int]hcs.MappedVirtualDiskController{}, nil
+}
+
+func (c *systemHandle) CreateProcess(config *hcs.ProcessConfig) (hcs.Process, error) {
+	c.h.mu.Lock()
+
+	if c.isClosed() {
+		c.h.mu.Unlock()
+		return nil, hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning {
+		c.h.mu.Unlock()
+		return nil, c.cs.invalidStateError()
+	}
+
+	p := newProcess(c.h, c.h.nextPid, c.cs, *config)
+	c.h.nextPid += 4
+	c.h.processes[p.pid] = p
+	c.cs.pids = append(c.cs.pids, p.pid)
+	program := c.h.programs[p.image]
+
+	c.h.mu.Unlock()
+
+	if program != nil {
+		go func() {
+			p.Exit(program(p))
+		}()
+	}
+
+	return newProcessHandle(c.h, p, true), nil
+}
+
+func (c *systemHandle) OpenProcess(pid int) (hcs.Process, error) {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return nil, hcs.ErrAlreadyClosed
+	}
+
+	p, ok := c.h.processes[pid]
+	if !ok || p.system != c.cs {
+		return nil, hcs.ErrElementNotFound
+	}
+
+	return newProcessHandle(c.h, p, false), nil
+}
+
+func (c *systemHandle) Close() error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return nil
+	}
+	close(c.closed)
+
+	if c.openedStopped {
+		c.h.remove(c.cs)
+	}
+	return nil
+}
+
+// Modify applies the memory and processor settings of an update request to
+// the config of the running compute system.
+func (c *systemHandle) Modify(config *hcs.ResourceModificationRequestResponse) error {
+	c.h.mu.Lock()
+	defer c.h.mu.Unlock()
+
+	if c.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if c.cs.state != StateRunning {
+		return c.cs.invalidStateError()
+	}
+	if config.Request != hcs.UpdateRequest {
+		return hcs.ErrInvalidData
+	}
+
+	data, err := json.Marshal(config.Data)
+	if err != nil {
+		return hcs.ErrInvalidData
+	}
+
+	var settings struct {
+		MemoryMaximumInMB *int64
+		ProcessorCount    *uint32
+		ProcessorWeight   *uint64
+		ProcessorMaximum  *int64
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return hcs.ErrInvalidData
+	}
+
+	switch config.Resource {
+	case hcs.MemoryResource:
+		if settings.MemoryMaximumInMB == nil {
+			return hcs.ErrInvalidData
+		}
+		c.cs.config.MemoryMaximumInMB = *settings.MemoryMaximumInMB
+	case hcs.ProcessorResource:
+		if settings.ProcessorCount != nil {
+			c.cs.config.ProcessorCount = *settings.ProcessorCount
+		}
+		if settings.ProcessorWeight != nil {
+			c.cs.config.ProcessorWeight = *settings.ProcessorWeight
+		}
+		if settings.ProcessorMaximum != nil {
+			c.cs.config.ProcessorMaximum = *settings.ProcessorMaximum
+		}
+	default:
+		return hcs.ErrInvalidData
+	}
+
+	return nil
+}
+
+// wait waits for done until closed is closed or timeout passes. A timeout of
+// 0 waits indefinitely.
+func wait(done, closed <-chan struct{}, timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-closed:
+		return hcs.ErrAlreadyClosed
+	case <-expired:
+		return hcs.ErrTimeout
+	}
+}
@@ -0,0 +1,214 @@
+package memhcs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/winc/client"
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/memhcs"
+	"code.cloudfoundry.org/winc/network"
+	netfakes "code.cloudfoundry.org/winc/network/fakes"
+	"code.cloudfoundry.org/winc/network/netrules"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("End to end", func() {
+	const (
+		containerId = "some-container"
+		volumePath  = `\\?\Volume{some-volume}\`
+	)
+
+	var (
+		h          *memhcs.HCS
+		rootDir    string
+		bundlesDir string
+		bundlePath string
+		c          *client.Client
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = ioutil.TempDir("", "memhcs-root")
+		Expect(err).NotTo(HaveOccurred())
+		bundlesDir, err = ioutil.TempDir("", "memhcs-bundles")
+		Expect(err).NotTo(HaveOccurred())
+
+		bundlePath = filepath.Join(bundlesDir, containerId)
+		Expect(os.Mkdir(bundlePath, 0755)).To(Succeed())
+
+		spec := specs.Spec{
+			Version: specs.Version,
+			Process: &specs.Process{
+				Args: []string{"waitfor.exe", "forever"},
+				Cwd:  `C:\`,
+			},
+			Root: &specs.Root{Path: volumePath},
+			Windows: &specs.Windows{
+				LayerFolders: []string{filepath.Join(bundlesDir, "some-layer")},
+			},
+		}
+		config, err := json.Marshal(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0644)).To(Succeed())
+
+		h = memhcs.New()
+		c = client.NewFromRuntime(h.Runtime(rootDir))
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+		Expect(os.RemoveAll(bundlesDir)).To(Succeed())
+	})
+
+	It("creates, starts, execs in, networks and deletes a container", func() {
+		Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(Succeed())
+
+		s, err := c.State(ctx, containerId)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Status).To(Equal("created"))
+
+		config, ok := h.ContainerConfig(containerId)
+		Expect(ok).To(BeTrue())
+		Expect(config.VolumePath).To(Equal(volumePath))
+		Expect(config.Layers).To(HaveLen(1))
+
+		Expect(c.Start(ctx, containerId)).To(Succeed())
+
+		s, err = c.State(ctx, containerId)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Status).To(Equal("running"))
+		volume, mounted := h.MountedVolume(s.Pid)
+		Expect(mounted).To(BeTrue())
+		Expect(volume).To(Equal(volumePath))
+
+		By("exec'ing a process")
+		h.SetProgram("cmd.exe", func(p *memhcs.Process) int {
+			fmt.Fprintf(p.Stdout(), "hello from %s", p.Config().WorkingDirectory)
+			return 7
+		})
+
+		var stdout bytes.Buffer
+		exitCode, err := c.Exec(ctx, containerId, &specs.Process{
+			Args: []string{"cmd.exe", "/c", "echo"},
+			Cwd:  `C:\some-dir`,
+		}, client.ExecOpts{IO: runtime.IO{Stdout: &stdout}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exitCode).To(Equal(7))
+		Expect(stdout.String()).To(Equal(`hello from C:\some-dir`))
+
+		By("bringing the network up")
+		applier := &netfakes.NetRuleApplier{}
+		applier.InReturns(&hcs.NatPolicy{Type: hcs.Nat, Protocol: "TCP", InternalPort: 8080, ExternalPort: 40000}, nil, nil)
+		nm := h.NetworkManager(containerId, network.Config{
+			NetworkName:    "winc-nat",
+			SubnetRange:    "172.30.0.0/22",
+			GatewayAddress: "172.30.0.1",
+		}, applier, &netfakes.Mtu{})
+
+		Expect(nm.CreateHostNATNetwork()).To(Succeed())
+
+		outputs, err := nm.Up(network.UpInputs{Pid: s.Pid, NetIn: []netrules.NetIn{{ContainerPort: 8080}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(outputs.Properties.ContainerIP).To(Equal("172.30.0.2"))
+		Expect(outputs.Properties.MappedPorts).To(Equal(`[{"HostPort":40000,"ContainerPort":8080}]`))
+
+		endpoint, err := h.GetHNSEndpointByName(containerId)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoint.Policies).NotTo(BeEmpty())
+		Expect(h.AttachedEndpoints(containerId)).To(Equal([]string{endpoint.Id}))
+
+		Expect(nm.Down()).To(Succeed())
+		Expect(h.AttachedEndpoints(containerId)).To(BeEmpty())
+
+		By("deleting the container")
+		results, err := c.Delete(ctx, containerId, client.DeleteOpts{Stop: container.StopPolicy{GracePeriod: time.Second}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(Equal([]runtime.DeleteResult{{ID: containerId, StoppedBy: "signal"}}))
+
+		_, err = h.GetContainerProperties(containerId)
+		Expect(err).To(Equal(&hcs.NotFoundError{Id: containerId}))
+
+		_, mounted = h.MountedVolume(s.Pid)
+		Expect(mounted).To(BeFalse())
+	})
+
+	It("shuts down a container whose init process ignores console signals", func() {
+		Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(Succeed())
+		Expect(c.Start(ctx, containerId)).To(Succeed())
+
+		s, err := c.State(ctx, containerId)
+		Expect(err).NotTo(HaveOccurred())
+		initProcess, ok := h.Process(s.Pid)
+		Expect(ok).To(BeTrue())
+		initProcess.IgnoreSignals()
+
+		results, err := c.Delete(ctx, containerId, client.DeleteOpts{Stop: container.StopPolicy{GracePeriod: 10 * time.Millisecond}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].StoppedBy).To(Equal("shutdown"))
+		Expect(initProcess.Signals()).To(ContainElement(hcs.SignalCtrlC))
+
+		_, err = h.GetContainerProperties(containerId)
+		Expect(err).To(Equal(&hcs.NotFoundError{Id: containerId}))
+	})
+
+	It("collects only the orphaned containers winc created under the root", func() {
+		Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(Succeed())
+		config, ok := h.ContainerConfig(containerId)
+		Expect(ok).To(BeTrue())
+		Expect(config.Owner).To(Equal(container.Owner(rootDir)))
+
+		for id, owner := range map[string]string{
+			"orphaned-container":   container.Owner(rootDir),
+			"docker-container":     "docker",
+			"unowned-container":    "",
+			"other-root-container": container.Owner(rootDir + "-other"),
+		} {
+			_, err := h.CreateContainer(id, &hcs.ContainerConfig{SystemType: "Container", Owner: owner})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		output := &bytes.Buffer{}
+		Expect(h.Runtime(rootDir).GC(output, "json", false)).To(Succeed())
+
+		var orphans []runtime.Orphan
+		Expect(json.Unmarshal(output.Bytes(), &orphans)).To(Succeed())
+		Expect(orphans).To(ConsistOf(runtime.Orphan{Kind: "container", ID: "orphaned-container", Reason: "HCS container has no state directory", Result: "removed"}))
+
+		for _, id := range []string{containerId, "docker-container", "unowned-container", "other-root-container"} {
+			_, err := h.GetContainerProperties(id)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("reports a container as stopped once its init process exits", func() {
+		h.SetProgram("waitfor.exe", func(p *memhcs.Process) int {
+			return 5
+		})
+
+		Expect(c.Create(ctx, containerId, bundlePath, client.CreateOpts{})).To(Succeed())
+		Expect(c.Start(ctx, containerId)).To(Succeed())
+
+		Eventually(func() string {
+			s, err := c.State(ctx, containerId)
+			Expect(err).NotTo(HaveOccurred())
+			return s.Status
+		}).Should(Equal("stopped"))
+
+		_, err := c.Delete(ctx, containerId, client.DeleteOpts{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
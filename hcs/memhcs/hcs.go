@@ -0,0 +1,266 @@
+// Package memhcs is an in-memory implementation of the HCS and HNS calls winc
+// makes through hcs.Client. It keeps track of compute systems, their
+// processes, and the networks and endpoints attached to them, moves them
+// through the states HCS would, and returns the errors hcsshim does, so that
+// whole runtime and network flows can be tested without a container host.
+//
+// Processes do nothing until they are killed, unless a Program is set for
+// the image they run.
+package memhcs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+// States of a compute system, as HCS reports them in ContainerProperties.
+const (
+	StateCreated = "Created"
+	StateRunning = "Running"
+	StatePaused  = hcs.PausedState
+	StateStopped = "Stopped"
+)
+
+// firstPid is the pid of the first process, pids then go up by 4 as they do
+// on Windows.
+const firstPid = 1000
+
+type HCS struct {
+	mu         sync.Mutex
+	systems    map[string]*computeSystem
+	processes  map[int]*Process
+	programs   map[string]Program
+	networks   map[string]*hcs.HNSNetwork
+	endpoints  map[string]*hnsEndpoint
+	mounts     map[int]string
+	nextPid    int
+	nextObject int
+}
+
+func New() *HCS {
+	return &HCS{
+		systems:   map[string]*computeSystem{},
+		processes: map[int]*Process{},
+		programs:  map[string]Program{},
+		networks:  map[string]*hcs.HNSNetwork{},
+		endpoints: map[string]*hnsEndpoint{},
+		mounts:    map[int]string{},
+		nextPid:   firstPid,
+	}
+}
+
+// SetProgram makes the processes created with image, the base name of the
+// first argument of their command line such as cmd.exe, run program.
+func (h *HCS) SetProgram(image string, program Program) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.programs[strings.ToLower(image)] = program
+}
+
+// Process returns the process with the given pid, whether or not it has
+// exited.
+func (h *HCS) Process(pid int) (*Process, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.processes[pid]
+	return p, ok
+}
+
+// Processes returns the processes created in the compute system id, in the
+// order they were created.
+func (h *HCS) Processes(id string) []*Process {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var processes []*Process
+	if cs, ok := h.systems[id]; ok {
+		for _, pid := range cs.pids {
+			processes = append(processes, h.processes[pid])
+		}
+	}
+	return processes
+}
+
+// ContainerConfig returns the config the compute system id was created with,
+// as updated by Container.Modify.
+func (h *HCS) ContainerConfig(id string) (hcs.ContainerConfig, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[id]
+	if !ok {
+		return hcs.ContainerConfig{}, false
+	}
+	return cs.config, true
+}
+
+// AttachedEndpoints returns the ids of the endpoints attached to the compute
+// system id.
+func (h *HCS) AttachedEndpoints(id string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[id]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), cs.endpoints...)
+}
+
+func (h *HCS) GetContainers(q hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ids := make([]string, 0, len(h.systems))
+	for id := range h.systems {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	props := []hcs.ContainerProperties{}
+	for _, id := range ids {
+		cs := h.systems[id]
+		if !matches(q.IDs, cs.id) || !matches(q.Names, cs.id) || !matches(q.Types, cs.config.SystemType) || !matches(q.Owners, cs.config.Owner) {
+			continue
+		}
+		props = append(props, cs.properties())
+	}
+
+	return props, nil
+}
+
+func matches(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *HCS) GetContainerProperties(id string) (hcs.ContainerProperties, error) {
+	cps, err := h.GetContainers(hcs.ComputeSystemQuery{IDs: []string{id}})
+	if err != nil {
+		return hcs.ContainerProperties{}, err
+	}
+
+	if len(cps) == 0 {
+		return hcs.ContainerProperties{}, &hcs.NotFoundError{Id: id}
+	}
+
+	return cps[0], nil
+}
+
+func (h *HCS) NameToGuid(name string) (hcs.GUID, error) {
+	return *hcs.NewGUID(name), nil
+}
+
+func (h *HCS) GetLayerMountPath(info hcs.DriverInfo, id string) (string, error) {
+	return filepath.Join(info.HomeDir, id), nil
+}
+
+func (h *HCS) CreateContainer(id string, config *hcs.ContainerConfig) (hcs.Container, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.systems[id]; ok {
+		return nil, fmt.Errorf("a compute system with id %s already exists", id)
+	}
+
+	for _, endpointId := range config.EndpointList {
+		if _, ok := h.endpoints[endpointId]; !ok {
+			return nil, hcs.EndpointNotFoundError{EndpointName: endpointId}
+		}
+	}
+
+	cs := &computeSystem{
+		id:      id,
+		config:  *config,
+		state:   StateCreated,
+		stopped: make(chan struct{}),
+	}
+	cs.endpoints = append(cs.endpoints, config.EndpointList...)
+	h.systems[id] = cs
+
+	return newSystemHandle(h, cs), nil
+}
+
+func (h *HCS) OpenContainer(id string) (hcs.Container, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[id]
+	if !ok {
+		return nil, hcs.ErrComputeSystemDoesNotExist
+	}
+
+	return newSystemHandle(h, cs), nil
+}
+
+func (h *HCS) IsPending(err error) bool {
+	return hcs.IsPending(err)
+}
+
+// SignalProcess delivers signal to the process. Unless the process ignores
+// signals it exits with STATUS_CONTROL_C_EXIT, as a console process without
+// a handler does.
+func (h *HCS) SignalProcess(containerId string, pid int, signal string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[containerId]
+	if !ok {
+		return hcs.ErrComputeSystemDoesNotExist
+	}
+	if cs.state != StateRunning {
+		return cs.invalidStateError()
+	}
+
+	if signal != hcs.SignalCtrlC && signal != hcs.SignalCtrlBreak {
+		return hcs.ErrInvalidData
+	}
+
+	p, ok := h.processes[pid]
+	if !ok || p.system != cs || p.hasExited() {
+		return hcs.ErrElementNotFound
+	}
+
+	p.signals = append(p.signals, signal)
+	if !p.ignoreSignals {
+		p.exit(ExitCodeCtrlC)
+	}
+
+	return nil
+}
+
+// remove forgets the stopped compute system cs and detaches its endpoints.
+func (h *HCS) remove(cs *computeSystem) {
+	if h.systems[cs.id] != cs {
+		return
+	}
+	delete(h.systems, cs.id)
+
+	for _, endpointId := range cs.endpoints {
+		if e, ok := h.endpoints[endpointId]; ok && e.container == cs.id {
+			e.container = ""
+		}
+	}
+	cs.endpoints = nil
+}
+
+// newObjectId returns a GUID for a new network or endpoint.
+func (h *HCS) newObjectId(kind string) string {
+	h.nextObject++
+	guid := hcs.NewGUID(fmt.Sprintf("%s-%d", kind, h.nextObject))
+	return strings.ToUpper(guid.ToString())
+}
@@ -0,0 +1,285 @@
+package memhcs_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/memhcs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HCS", func() {
+	const containerId = "some-container"
+
+	var (
+		h      *memhcs.HCS
+		config *hcs.ContainerConfig
+	)
+
+	BeforeEach(func() {
+		h = memhcs.New()
+		config = &hcs.ContainerConfig{SystemType: "Container", Owner: "winc"}
+	})
+
+	Context("compute systems", func() {
+		It("creates a compute system in the created state", func() {
+			_, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			props, err := h.GetContainerProperties(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props.ID).To(Equal(containerId))
+			Expect(props.State).To(Equal(memhcs.StateCreated))
+			Expect(props.Stopped).To(BeFalse())
+		})
+
+		It("moves the compute system through the states HCS does", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Start()).To(Succeed())
+			Expect(state(h, containerId)).To(Equal(memhcs.StateRunning))
+
+			Expect(c.Pause()).To(Succeed())
+			Expect(state(h, containerId)).To(Equal(hcs.PausedState))
+
+			Expect(c.Resume()).To(Succeed())
+			Expect(state(h, containerId)).To(Equal(memhcs.StateRunning))
+
+			Expect(c.Shutdown()).To(Succeed())
+			Expect(c.Wait()).To(Succeed())
+
+			props, err := h.GetContainerProperties(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props.State).To(Equal(memhcs.StateStopped))
+			Expect(props.Stopped).To(BeTrue())
+		})
+
+		It("returns the errors HCS does for operations the compute system cannot do in its state", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Pause()).To(Equal(hcs.ErrVmcomputeOperationInvalidState))
+
+			Expect(c.Start()).To(Succeed())
+			Expect(c.Start()).To(Equal(hcs.ErrVmcomputeOperationInvalidState))
+
+			Expect(c.Terminate()).To(Succeed())
+			Expect(c.Start()).To(Equal(hcs.ErrVmcomputeAlreadyStopped))
+			Expect(c.Shutdown()).To(Equal(hcs.ErrVmcomputeAlreadyStopped))
+		})
+
+		It("fails to create a compute system with the id of an existing one", func() {
+			_, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = h.CreateContainer(containerId, config)
+			Expect(err).To(MatchError(fmt.Sprintf("a compute system with id %s already exists", containerId)))
+		})
+
+		It("keeps a compute system when the handle that stopped it is closed", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+			Expect(c.Shutdown()).To(Succeed())
+
+			Expect(c.Close()).To(Succeed())
+
+			props, err := h.GetContainerProperties(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props.Stopped).To(BeTrue())
+		})
+
+		It("removes a stopped compute system once a handle opened after it stopped is closed", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+			Expect(c.Shutdown()).To(Succeed())
+			Expect(c.Close()).To(Succeed())
+
+			opened, err := h.OpenContainer(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opened.Terminate()).To(Equal(hcs.ErrVmcomputeAlreadyStopped))
+			Expect(opened.Close()).To(Succeed())
+
+			_, err = h.GetContainerProperties(containerId)
+			Expect(err).To(Equal(&hcs.NotFoundError{Id: containerId}))
+
+			_, err = h.OpenContainer(containerId)
+			Expect(err).To(Equal(hcs.ErrComputeSystemDoesNotExist))
+		})
+
+		It("keeps a running compute system when a handle to it is closed", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+
+			Expect(c.Close()).To(Succeed())
+			Expect(c.Shutdown()).To(Equal(hcs.ErrAlreadyClosed))
+
+			opened, err := h.OpenContainer(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opened.Shutdown()).To(Succeed())
+		})
+
+		It("times out waiting for a compute system that does not stop", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+
+			err = c.WaitTimeout(10 * time.Millisecond)
+			Expect(hcs.IsTimeout(err)).To(BeTrue())
+		})
+
+		It("filters the compute systems it lists", func() {
+			_, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = h.CreateContainer("other-container", &hcs.ContainerConfig{SystemType: "Container", Owner: "someone-else"})
+			Expect(err).NotTo(HaveOccurred())
+
+			all, err := h.GetContainers(hcs.ComputeSystemQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(all).To(HaveLen(2))
+			Expect(all[0].ID).To(Equal("other-container"))
+			Expect(all[1].ID).To(Equal(containerId))
+
+			owned, err := h.GetContainers(hcs.ComputeSystemQuery{Owners: []string{"winc"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(HaveLen(1))
+			Expect(owned[0].ID).To(Equal(containerId))
+		})
+
+		It("updates the memory limit of a running compute system", func() {
+			c, err := h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+
+			Expect(c.Modify(&hcs.ResourceModificationRequestResponse{
+				Resource: hcs.MemoryResource,
+				Data:     struct{ MemoryMaximumInMB int64 }{MemoryMaximumInMB: 512},
+				Request:  hcs.UpdateRequest,
+			})).To(Succeed())
+
+			updated, ok := h.ContainerConfig(containerId)
+			Expect(ok).To(BeTrue())
+			Expect(updated.MemoryMaximumInMB).To(Equal(int64(512)))
+		})
+	})
+
+	Context("processes", func() {
+		var c hcs.Container
+
+		BeforeEach(func() {
+			var err error
+			c, err = h.CreateContainer(containerId, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+		})
+
+		It("runs the program for the image of the process and hands its output to the creator", func() {
+			h.SetProgram("cmd.exe", func(p *memhcs.Process) int {
+				input, _ := ioutil.ReadAll(p.Stdin())
+				fmt.Fprintf(p.Stdout(), "read %s", input)
+				return 3
+			})
+
+			p, err := c.CreateProcess(&hcs.ProcessConfig{
+				CommandLine:      `"C:\Windows\System32\CMD.EXE" /c something`,
+				CreateStdInPipe:  true,
+				CreateStdOutPipe: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			stdin, stdout, stderr, err := p.Stdio()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stderr).To(BeNil())
+
+			_, err = io.WriteString(stdin, "some-input")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.CloseStdin()).To(Succeed())
+
+			output, err := ioutil.ReadAll(stdout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("read some-input"))
+
+			Expect(p.Wait()).To(Succeed())
+			Expect(p.ExitCode()).To(Equal(3))
+		})
+
+		It("keeps a process without a program running until it is killed", func() {
+			p, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "waitfor.exe forever"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = p.ExitCode()
+			Expect(err).To(Equal(hcs.ErrInvalidProcessState))
+
+			list, err := c.ProcessList()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].ProcessId).To(Equal(uint32(p.Pid())))
+			Expect(list[0].ImageName).To(Equal("waitfor.exe"))
+
+			Expect(p.Kill()).To(Succeed())
+			Expect(p.Wait()).To(Succeed())
+			Expect(p.ExitCode()).To(Equal(memhcs.ExitCodeTerminated))
+			Expect(p.Kill()).To(Equal(hcs.ErrElementNotFound))
+
+			list, err = c.ProcessList()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(BeEmpty())
+		})
+
+		It("ends the processes of a compute system when it stops", func() {
+			p, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "waitfor.exe forever"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Shutdown()).To(Succeed())
+
+			Expect(p.WaitTimeout(time.Second)).To(Succeed())
+			Expect(p.ExitCode()).To(Equal(memhcs.ExitCodeTerminated))
+		})
+
+		It("ends a process sent a console signal", func() {
+			p, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "waitfor.exe forever"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(h.SignalProcess(containerId, p.Pid(), hcs.SignalCtrlC)).To(Succeed())
+
+			Expect(p.WaitTimeout(time.Second)).To(Succeed())
+			Expect(p.ExitCode()).To(Equal(memhcs.ExitCodeCtrlC))
+			Expect(h.SignalProcess(containerId, p.Pid(), hcs.SignalCtrlC)).To(Equal(hcs.ErrElementNotFound))
+		})
+
+		It("records the console signals a process that ignores them is sent", func() {
+			p, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "waitfor.exe forever"})
+			Expect(err).NotTo(HaveOccurred())
+
+			process, ok := h.Process(p.Pid())
+			Expect(ok).To(BeTrue())
+			process.IgnoreSignals()
+
+			Expect(h.SignalProcess(containerId, p.Pid(), hcs.SignalCtrlBreak)).To(Succeed())
+
+			Expect(process.Signals()).To(Equal([]string{hcs.SignalCtrlBreak}))
+			_, exited := process.ExitCode()
+			Expect(exited).To(BeFalse())
+		})
+
+		It("does not create processes in a compute system that is not running", func() {
+			Expect(c.Pause()).To(Succeed())
+
+			_, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "cmd.exe"})
+			Expect(err).To(Equal(hcs.ErrVmcomputeOperationInvalidState))
+		})
+	})
+})
+
+func state(h *memhcs.HCS, id string) string {
+	props, err := h.GetContainerProperties(id)
+	Expect(err).NotTo(HaveOccurred())
+	return props.State
+}
@@ -0,0 +1,298 @@
+package memhcs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+type hnsEndpoint struct {
+	hcs.HNSEndpoint
+
+	// container is the compute system the endpoint is hot attached to
+	container string
+}
+
+// CreateNetwork creates network without waiting for networkReady, which
+// checks for an interface on the host.
+func (h *HCS) CreateNetwork(network *hcs.HNSNetwork, networkReady func() (bool, error)) (*hcs.HNSNetwork, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, n := range h.networks {
+		if n.Name == network.Name {
+			return nil, fmt.Errorf("HNS failed with error : a network named %s already exists", network.Name)
+		}
+	}
+
+	for _, subnet := range network.Subnets {
+		if _, _, err := net.ParseCIDR(subnet.AddressPrefix); err != nil {
+			return nil, fmt.Errorf("HNS failed with error : The parameter is incorrect. ")
+		}
+	}
+
+	created := copyNetwork(network)
+	created.Id = h.newObjectId("network")
+	h.networks[created.Id] = created
+
+	return copyNetwork(created), nil
+}
+
+// DeleteNetwork deletes network and the endpoints on it.
+func (h *HCS) DeleteNetwork(network *hcs.HNSNetwork) (*hcs.HNSNetwork, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	deleted, ok := h.networks[network.Id]
+	if !ok {
+		return nil, hcs.NetworkNotFoundError{NetworkName: network.Name}
+	}
+
+	for id, e := range h.endpoints {
+		if e.VirtualNetwork == deleted.Id {
+			h.deleteEndpoint(id)
+		}
+	}
+	delete(h.networks, deleted.Id)
+
+	return copyNetwork(deleted), nil
+}
+
+func (h *HCS) HNSListNetworkRequest() ([]hcs.HNSNetwork, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	networks := []hcs.HNSNetwork{}
+	for _, n := range h.networks {
+		networks = append(networks, *copyNetwork(n))
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+
+	return networks, nil
+}
+
+func (h *HCS) GetHNSNetworkByName(name string) (*hcs.HNSNetwork, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, n := range h.networks {
+		if n.Name == name {
+			return copyNetwork(n), nil
+		}
+	}
+
+	return nil, hcs.NetworkNotFoundError{NetworkName: name}
+}
+
+// CreateEndpoint creates endpoint on its virtual network, with the next free
+// address of the first subnet of the network unless it has one.
+func (h *HCS) CreateEndpoint(e *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	network, ok := h.networks[e.VirtualNetwork]
+	if !ok {
+		return nil, hcs.NetworkNotFoundError{NetworkName: e.VirtualNetwork}
+	}
+
+	created := &hnsEndpoint{HNSEndpoint: *copyEndpoint(e)}
+	created.Id = h.newObjectId("endpoint")
+	created.VirtualNetworkName = network.Name
+	created.MacAddress = fmt.Sprintf("00-15-5D-%02X-%02X-%02X", byte(h.nextObject>>16), byte(h.nextObject>>8), byte(h.nextObject))
+
+	if len(network.Subnets) > 0 {
+		subnet := network.Subnets[0]
+		created.GatewayAddress = subnet.GatewayAddress
+
+		_, ipNet, _ := net.ParseCIDR(subnet.AddressPrefix)
+		ones, _ := ipNet.Mask.Size()
+		created.PrefixLength = uint8(ones)
+
+		if created.IPAddress == nil {
+			ip, err := h.freeAddress(network.Id, ipNet, subnet.GatewayAddress)
+			if err != nil {
+				return nil, err
+			}
+			created.IPAddress = ip
+		}
+	}
+
+	h.endpoints[created.Id] = created
+
+	return copyEndpoint(&created.HNSEndpoint), nil
+}
+
+// freeAddress returns the first address in ipNet that is not the network
+// address, the gateway, or in use by an endpoint on the network.
+func (h *HCS) freeAddress(networkId string, ipNet *net.IPNet, gateway string) (net.IP, error) {
+	base := ipNet.IP.To4()
+	if base == nil {
+		return nil, fmt.Errorf("HNS failed with error : only IPv4 subnets are supported")
+	}
+
+	used := map[string]bool{gateway: true}
+	for _, e := range h.endpoints {
+		if e.VirtualNetwork == networkId && e.IPAddress != nil {
+			used[e.IPAddress.String()] = true
+		}
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	size := uint32(1) << uint(bits-ones)
+	start := binary.BigEndian.Uint32(base)
+
+	// skip the network and broadcast addresses
+	for i := uint32(1); i+1 < size; i++ {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, start+i)
+		if !used[ip.String()] {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("HNS failed with error : no free addresses in %s", ipNet)
+}
+
+// UpdateEndpoint replaces the policies of the endpoint.
+func (h *HCS) UpdateEndpoint(e *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updated, ok := h.endpoints[e.Id]
+	if !ok {
+		return nil, hcs.EndpointNotFoundError{EndpointName: e.Name}
+	}
+
+	updated.Policies = copyEndpoint(e).Policies
+
+	return copyEndpoint(&updated.HNSEndpoint), nil
+}
+
+func (h *HCS) GetHNSEndpointByID(id string) (*hcs.HNSEndpoint, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e, ok := h.endpoints[id]
+	if !ok {
+		return nil, hcs.EndpointNotFoundError{EndpointName: id}
+	}
+
+	return copyEndpoint(&e.HNSEndpoint), nil
+}
+
+func (h *HCS) GetHNSEndpointByName(name string) (*hcs.HNSEndpoint, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, e := range h.endpoints {
+		if e.Name == name {
+			return copyEndpoint(&e.HNSEndpoint), nil
+		}
+	}
+
+	return nil, hcs.EndpointNotFoundError{EndpointName: name}
+}
+
+// DeleteEndpoint deletes the endpoint, detaching it from the compute system
+// it is attached to.
+func (h *HCS) DeleteEndpoint(e *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	deleted, ok := h.endpoints[e.Id]
+	if !ok {
+		return nil, hcs.EndpointNotFoundError{EndpointName: e.Name}
+	}
+	h.deleteEndpoint(deleted.Id)
+
+	return copyEndpoint(&deleted.HNSEndpoint), nil
+}
+
+// deleteEndpoint must be called with the lock held.
+func (h *HCS) deleteEndpoint(id string) {
+	for _, cs := range h.systems {
+		cs.endpoints = without(cs.endpoints, id)
+	}
+	delete(h.endpoints, id)
+}
+
+// HotAttachEndpoint attaches the endpoint to the running compute system
+// without waiting for endpointReady, which checks for an interface on the
+// host.
+func (h *HCS) HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[containerID]
+	if !ok {
+		return hcs.ErrComputeSystemDoesNotExist
+	}
+	if cs.state != StateRunning {
+		return cs.invalidStateError()
+	}
+
+	e, ok := h.endpoints[endpointID]
+	if !ok {
+		return hcs.EndpointNotFoundError{EndpointName: endpointID}
+	}
+	if e.container != "" {
+		return hcs.ErrVmcomputeOperationInvalidState
+	}
+
+	e.container = cs.id
+	cs.endpoints = append(cs.endpoints, e.Id)
+
+	return nil
+}
+
+func (h *HCS) HotDetachEndpoint(containerID string, endpointID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cs, ok := h.systems[containerID]
+	if !ok {
+		return hcs.ErrComputeSystemDoesNotExist
+	}
+
+	e, ok := h.endpoints[endpointID]
+	if !ok || e.container != cs.id {
+		return hcs.ErrElementNotFound
+	}
+
+	e.container = ""
+	cs.endpoints = without(cs.endpoints, e.Id)
+
+	return nil
+}
+
+func without(ids []string, id string) []string {
+	kept := []string{}
+	for _, i := range ids {
+		if i != id {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+// copyNetwork and copyEndpoint keep callers from changing what is stored
+// through the pointers they are given.
+func copyNetwork(n *hcs.HNSNetwork) *hcs.HNSNetwork {
+	c := *n
+	c.Policies = append([]json.RawMessage(nil), n.Policies...)
+	c.Subnets = append([]hcs.Subnet(nil), n.Subnets...)
+	return &c
+}
+
+func copyEndpoint(e *hcs.HNSEndpoint) *hcs.HNSEndpoint {
+	c := *e
+	c.Policies = append([]json.RawMessage(nil), e.Policies...)
+	if e.IPAddress != nil {
+		c.IPAddress = append(net.IP(nil), e.IPAddress...)
+	}
+	return &c
+}
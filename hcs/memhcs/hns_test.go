@@ -0,0 +1,122 @@
+package memhcs_test
+
+import (
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/memhcs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HNS", func() {
+	const containerId = "some-container"
+
+	var (
+		h       *memhcs.HCS
+		network *hcs.HNSNetwork
+	)
+
+	BeforeEach(func() {
+		h = memhcs.New()
+
+		var err error
+		network, err = h.CreateNetwork(&hcs.HNSNetwork{
+			Name:    "some-network",
+			Type:    "nat",
+			Subnets: []hcs.Subnet{{AddressPrefix: "172.30.0.0/22", GatewayAddress: "172.30.0.1"}},
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates networks that can be found by name", func() {
+		found, err := h.GetHNSNetworkByName("some-network")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Id).To(Equal(network.Id))
+		Expect(found.Subnets).To(Equal(network.Subnets))
+
+		_, err = h.GetHNSNetworkByName("other-network")
+		Expect(err).To(Equal(hcs.NetworkNotFoundError{NetworkName: "other-network"}))
+	})
+
+	It("fails to create a network with the name of an existing one", func() {
+		_, err := h.CreateNetwork(&hcs.HNSNetwork{Name: "some-network"}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("gives endpoints the next free address of the network", func() {
+		first, err := h.CreateEndpoint(&hcs.HNSEndpoint{Name: "first", VirtualNetwork: network.Id})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.IPAddress.String()).To(Equal("172.30.0.2"))
+		Expect(first.GatewayAddress).To(Equal("172.30.0.1"))
+		Expect(first.PrefixLength).To(Equal(uint8(22)))
+		Expect(first.VirtualNetworkName).To(Equal("some-network"))
+
+		second, err := h.CreateEndpoint(&hcs.HNSEndpoint{Name: "second", VirtualNetwork: network.Id})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.IPAddress.String()).To(Equal("172.30.0.3"))
+
+		_, err = h.DeleteEndpoint(first)
+		Expect(err).NotTo(HaveOccurred())
+
+		third, err := h.CreateEndpoint(&hcs.HNSEndpoint{Name: "third", VirtualNetwork: network.Id})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third.IPAddress.String()).To(Equal("172.30.0.2"))
+	})
+
+	It("fails to find endpoints that do not exist", func() {
+		_, err := h.GetHNSEndpointByName("some-endpoint")
+		Expect(err).To(Equal(hcs.EndpointNotFoundError{EndpointName: "some-endpoint"}))
+	})
+
+	It("deletes the endpoints of a deleted network", func() {
+		_, err := h.CreateEndpoint(&hcs.HNSEndpoint{Name: "some-endpoint", VirtualNetwork: network.Id})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = h.DeleteNetwork(network)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = h.GetHNSEndpointByName("some-endpoint")
+		Expect(err).To(BeAssignableToTypeOf(hcs.EndpointNotFoundError{}))
+
+		networks, err := h.HNSListNetworkRequest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks).To(BeEmpty())
+	})
+
+	Context("when there is a compute system", func() {
+		var endpoint *hcs.HNSEndpoint
+
+		BeforeEach(func() {
+			c, err := h.CreateContainer(containerId, &hcs.ContainerConfig{SystemType: "Container"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Start()).To(Succeed())
+
+			endpoint, err = h.CreateEndpoint(&hcs.HNSEndpoint{Name: containerId, VirtualNetwork: network.Id})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("attaches and detaches endpoints", func() {
+			Expect(h.HotAttachEndpoint(containerId, endpoint.Id, nil)).To(Succeed())
+			Expect(h.AttachedEndpoints(containerId)).To(Equal([]string{endpoint.Id}))
+
+			Expect(h.HotAttachEndpoint(containerId, endpoint.Id, nil)).To(Equal(hcs.ErrVmcomputeOperationInvalidState))
+
+			Expect(h.HotDetachEndpoint(containerId, endpoint.Id)).To(Succeed())
+			Expect(h.AttachedEndpoints(containerId)).To(BeEmpty())
+
+			Expect(h.HotDetachEndpoint(containerId, endpoint.Id)).To(Equal(hcs.ErrElementNotFound))
+		})
+
+		It("fails to detach endpoints from a compute system that does not exist", func() {
+			Expect(h.HotDetachEndpoint("other-container", endpoint.Id)).To(Equal(hcs.ErrComputeSystemDoesNotExist))
+		})
+
+		It("detaches deleted endpoints", func() {
+			Expect(h.HotAttachEndpoint(containerId, endpoint.Id, nil)).To(Succeed())
+
+			_, err := h.DeleteEndpoint(endpoint)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(h.AttachedEndpoints(containerId)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package memhcs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// errorInvalidParameter is what OpenProcess fails with for a pid that
+	// does not exist
+	errorInvalidParameter = syscall.Errno(0x57)
+	errorInvalidHandle    = syscall.Errno(0x6)
+
+	defaultUser = "User Manager\\ContainerAdministrator"
+)
+
+// MountedVolume returns the volume mounted for the process with the given pid
+// by the mounter of the runtime returned by Runtime.
+func (h *HCS) MountedVolume(pid int) (string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	volume, ok := h.mounts[pid]
+	return volume, ok
+}
+
+// winSyscall is the view the host has of the processes of h, which share the
+// pids of the host as they do in process isolated containers. The handle of a
+// process is its pid.
+type winSyscall struct {
+	h *HCS
+}
+
+func (w *winSyscall) OpenProcess(flags uint32, inherit bool, pid uint32) (winsyscall.Handle, error) {
+	w.h.mu.Lock()
+	defer w.h.mu.Unlock()
+
+	// the processes of a removed compute system are gone
+	p, ok := w.h.processes[int(pid)]
+	if !ok || w.h.systems[p.system.id] != p.system {
+		return 0, errorInvalidParameter
+	}
+	return winsyscall.Handle(pid), nil
+}
+
+func (w *winSyscall) GetProcessStartTime(handle winsyscall.Handle) (winsyscall.Filetime, error) {
+	p, err := w.process(handle)
+	if err != nil {
+		return winsyscall.Filetime{}, err
+	}
+	return winsyscall.NsecToFiletime(p.created.UnixNano()), nil
+}
+
+func (w *winSyscall) CloseHandle(handle winsyscall.Handle) error {
+	_, err := w.process(handle)
+	return err
+}
+
+func (w *winSyscall) GetExitCodeProcess(handle winsyscall.Handle) (uint32, error) {
+	p, err := w.process(handle)
+	if err != nil {
+		return 0, err
+	}
+
+	w.h.mu.Lock()
+	defer w.h.mu.Unlock()
+
+	if !p.hasExited() {
+		return state.STILL_ACTIVE_EXIT_CODE, nil
+	}
+	return uint32(p.exitCode), nil
+}
+
+func (w *winSyscall) GetProcessUser(handle winsyscall.Handle) (string, error) {
+	p, err := w.process(handle)
+	if err != nil {
+		return "", err
+	}
+
+	if p.config.User == "" {
+		return defaultUser, nil
+	}
+	return p.config.User, nil
+}
+
+func (w *winSyscall) process(handle winsyscall.Handle) (*Process, error) {
+	w.h.mu.Lock()
+	defer w.h.mu.Unlock()
+
+	p, ok := w.h.processes[int(handle)]
+	if !ok {
+		return nil, errorInvalidHandle
+	}
+	return p, nil
+}
+
+// mounter records the volumes mounted for processes instead of creating
+// mount points under c:\proc.
+type mounter struct {
+	h *HCS
+}
+
+func (m *mounter) Mount(pid int, volumePath string, logger *logrus.Entry) error {
+	m.h.mu.Lock()
+	defer m.h.mu.Unlock()
+
+	if _, ok := m.h.mounts[pid]; ok {
+		err := fmt.Errorf("mountdir exists: %s", mountPath(pid))
+		logger.Error(err.Error())
+		return err
+	}
+
+	m.h.mounts[pid] = volumePath
+	return nil
+}
+
+func (m *mounter) Unmount(pid int) error {
+	m.h.mu.Lock()
+	defer m.h.mu.Unlock()
+
+	if _, ok := m.h.mounts[pid]; !ok {
+		return fmt.Errorf("error deleting mount point: %s is not a mount point", filepath.Join(mountPath(pid), "root"))
+	}
+
+	delete(m.h.mounts, pid)
+	return nil
+}
+
+func (m *mounter) Mounts() ([]int, error) {
+	m.h.mu.Lock()
+	defer m.h.mu.Unlock()
+
+	var pids []int
+	for pid := range m.h.mounts {
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+
+	return pids, nil
+}
+
+func mountPath(pid int) string {
+	return filepath.Join("c:\\", "proc", strconv.Itoa(pid))
+}
@@ -0,0 +1,13 @@
+package memhcs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemhcs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memhcs Suite")
+}
@@ -0,0 +1,341 @@
+package memhcs
+
+import (
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+// ExitCodeCtrlC is STATUS_CONTROL_C_EXIT, the exit code of a process ended by
+// a console signal.
+const ExitCodeCtrlC = 0xC000013A
+
+// A Program is what the processes created with an image run. The process
+// exits with the code it returns, unless it has already exited.
+type Program func(p *Process) int
+
+// Process is a process in a compute system as the code running in it sees
+// it. Its methods are safe to call from a Program and from tests.
+type Process struct {
+	h       *HCS
+	system  *computeSystem
+	pid     int
+	image   string
+	config  hcs.ProcessConfig
+	created time.Time
+
+	stdin  *io.PipeReader
+	stdout *io.PipeWriter
+	stderr *io.PipeWriter
+
+	// the ends of the pipes handed out by Stdio
+	hostStdin  *io.PipeWriter
+	hostStdout *io.PipeReader
+	hostStderr *io.PipeReader
+
+	exited        chan struct{}
+	exitCode      int
+	signals       []string
+	ignoreSignals bool
+	consoleSize   [2]uint16
+}
+
+func newProcess(h *HCS, pid int, cs *computeSystem, config hcs.ProcessConfig) *Process {
+	p := &Process{
+		h:       h,
+		system:  cs,
+		pid:     pid,
+		image:   imageName(config.CommandLine),
+		config:  config,
+		created: time.Now(),
+		exited:  make(chan struct{}),
+	}
+
+	if config.CreateStdInPipe {
+		p.stdin, p.hostStdin = io.Pipe()
+	}
+	if config.CreateStdOutPipe {
+		p.hostStdout, p.stdout = io.Pipe()
+	}
+	if config.CreateStdErrPipe {
+		p.hostStderr, p.stderr = io.Pipe()
+	}
+
+	return p
+}
+
+// imageName returns the lower cased base name of the first argument of
+// commandLine, whichever separator its path uses.
+func imageName(commandLine string) string {
+	var image string
+	if strings.HasPrefix(commandLine, `"`) {
+		image = strings.SplitN(commandLine[1:], `"`, 2)[0]
+	} else {
+		image = strings.SplitN(commandLine, " ", 2)[0]
+	}
+	return strings.ToLower(path.Base(strings.Replace(image, `\`, "/", -1)))
+}
+
+func (p *Process) Pid() int {
+	return p.pid
+}
+
+func (p *Process) Config() hcs.ProcessConfig {
+	return p.config
+}
+
+// Stdin reads what is written to the stdin pipe of the process, and is empty
+// if it has none.
+func (p *Process) Stdin() io.Reader {
+	if p.stdin == nil {
+		return strings.NewReader("")
+	}
+	return p.stdin
+}
+
+// Stdout writes to the stdout pipe of the process, or nowhere if it has none.
+func (p *Process) Stdout() io.Writer {
+	if p.stdout == nil {
+		return ioutil.Discard
+	}
+	return p.stdout
+}
+
+// Stderr writes to the stderr pipe of the process, or nowhere if it has none.
+func (p *Process) Stderr() io.Writer {
+	if p.stderr == nil {
+		return ioutil.Discard
+	}
+	return p.stderr
+}
+
+// Exit makes the process exit with exitCode, unless it has already exited.
+func (p *Process) Exit(exitCode int) {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	p.exit(exitCode)
+}
+
+// Exited is closed once the process has exited.
+func (p *Process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// ExitCode returns the exit code of the process and whether it has exited.
+func (p *Process) ExitCode() (int, bool) {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	return p.exitCode, p.hasExited()
+}
+
+// IgnoreSignals makes the process record the console signals it is sent
+// instead of exiting.
+func (p *Process) IgnoreSignals() {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	p.ignoreSignals = true
+}
+
+// Signals returns the console signals the process has been sent.
+func (p *Process) Signals() []string {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	return append([]string(nil), p.signals...)
+}
+
+// ConsoleSize returns the width and height of the console of the process as
+// last resized.
+func (p *Process) ConsoleSize() (uint16, uint16) {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	return p.consoleSize[0], p.consoleSize[1]
+}
+
+// hasExited must be called with the lock held.
+func (p *Process) hasExited() bool {
+	select {
+	case <-p.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// exit must be called with the lock held. The output pipes of the process are
+// closed so that whoever reads them sees the end of its output.
+func (p *Process) exit(exitCode int) {
+	if p.hasExited() {
+		return
+	}
+
+	p.exitCode = exitCode
+	close(p.exited)
+
+	if p.stdin != nil {
+		p.stdin.Close()
+	}
+	if p.stdout != nil {
+		p.stdout.Close()
+	}
+	if p.stderr != nil {
+		p.stderr.Close()
+	}
+}
+
+// processHandle is a handle to a Process. Only the handle returned when the process
+// is created has its pipes, which closing it closes. The process is left
+// running.
+type processHandle struct {
+	h      *HCS
+	p      *Process
+	stdio  bool
+	closed chan struct{}
+}
+
+func newProcessHandle(h *HCS, p *Process, stdio bool) *processHandle {
+	return &processHandle{h: h, p: p, stdio: stdio, closed: make(chan struct{})}
+}
+
+// isClosed must be called with the lock held.
+func (p *processHandle) isClosed() bool {
+	select {
+	case <-p.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *processHandle) Pid() int {
+	return p.p.pid
+}
+
+func (p *processHandle) Kill() error {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if p.p.hasExited() {
+		return hcs.ErrElementNotFound
+	}
+
+	p.p.exit(ExitCodeTerminated)
+	return nil
+}
+
+func (p *processHandle) Wait() error {
+	return p.WaitTimeout(0)
+}
+
+// WaitTimeout waits for the process to exit. A timeout of 0 waits
+// indefinitely.
+func (p *processHandle) WaitTimeout(timeout time.Duration) error {
+	return wait(p.p.exited, p.closed, timeout)
+}
+
+func (p *processHandle) ExitCode() (int, error) {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return -1, hcs.ErrAlreadyClosed
+	}
+	if !p.p.hasExited() {
+		return -1, hcs.ErrInvalidProcessState
+	}
+
+	return p.p.exitCode, nil
+}
+
+func (p *processHandle) ResizeConsole(width, height uint16) error {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if !p.p.config.EmulateConsole {
+		return hcs.ErrNotSupported
+	}
+
+	p.p.consoleSize = [2]uint16{width, height}
+	return nil
+}
+
+// Stdio returns the ends of the pipes of the process that were asked for when
+// it was created, and nil for the others or if the handle was opened.
+func (p *processHandle) Stdio() (io.WriteCloser, io.ReadCloser, io.ReadCloser, error) {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return nil, nil, nil, hcs.ErrAlreadyClosed
+	}
+
+	var (
+		stdin          io.WriteCloser
+		stdout, stderr io.ReadCloser
+	)
+	if !p.stdio {
+		return stdin, stdout, stderr, nil
+	}
+	if p.p.hostStdin != nil {
+		stdin = p.p.hostStdin
+	}
+	if p.p.hostStdout != nil {
+		stdout = p.p.hostStdout
+	}
+	if p.p.hostStderr != nil {
+		stderr = p.p.hostStderr
+	}
+
+	return stdin, stdout, stderr, nil
+}
+
+func (p *processHandle) CloseStdin() error {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return hcs.ErrAlreadyClosed
+	}
+	if p.stdio && p.p.hostStdin != nil {
+		p.p.hostStdin.Close()
+	}
+	return nil
+}
+
+func (p *processHandle) Close() error {
+	p.h.mu.Lock()
+	defer p.h.mu.Unlock()
+
+	if p.isClosed() {
+		return nil
+	}
+	close(p.closed)
+
+	if !p.stdio {
+		return nil
+	}
+	if p.p.hostStdin != nil {
+		p.p.hostStdin.Close()
+	}
+	if p.p.hostStdout != nil {
+		p.p.hostStdout.Close()
+	}
+	if p.p.hostStderr != nil {
+		p.p.hostStderr.Close()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package memhcs
+
+import (
+	"context"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/endpoint"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/hook"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	"github.com/sirupsen/logrus"
+)
+
+const lockTimeout = 10 * time.Second
+
+// Runtime returns a runtime whose containers are compute systems of h, with
+// their state kept under rootDir. The volumes of init processes are recorded
+// by h instead of being mounted, see MountedVolume.
+func (h *HCS) Runtime(rootDir string) *runtime.Runtime {
+	return runtime.New(
+		&stateFactory{h: h},
+		&containerFactory{h: h},
+		&mounter{h: h},
+		h,
+		&processWrapper{},
+		&hook.Runner{},
+		rootDir,
+	)
+}
+
+// NetworkManager returns a network manager for the container containerId
+// whose networks and endpoints are kept by h. The net rules and MTU are
+// applied to the host rather than through HNS, so applier and mtu are
+// usually fakes.
+func (h *HCS) NetworkManager(containerId string, config network.Config, applier network.NetRuleApplier, mtu network.Mtu) *network.NetworkManager {
+	endpointManager := endpoint.NewEndpointManager(h, containerId, config)
+	return network.NewNetworkManager(h, applier, endpointManager, containerId, config, mtu)
+}
+
+// withContext returns h with the waits of the containers it creates or opens
+// bound to ctx, as hcs.Client does with its Context.
+func (h *HCS) withContext(ctx context.Context) container.HCSClient {
+	if ctx == nil {
+		return h
+	}
+	return &contextHCS{HCS: h, ctx: ctx}
+}
+
+type contextHCS struct {
+	*HCS
+	ctx context.Context
+}
+
+func (c *contextHCS) CreateContainer(id string, config *hcs.ContainerConfig) (hcs.Container, error) {
+	created, err := c.HCS.CreateContainer(id, config)
+	if err != nil {
+		return nil, err
+	}
+	return hcs.ContainerWithContext(c.ctx, created), nil
+}
+
+func (c *contextHCS) OpenContainer(id string) (hcs.Container, error) {
+	opened, err := c.HCS.OpenContainer(id)
+	if err != nil {
+		return nil, err
+	}
+	return hcs.ContainerWithContext(c.ctx, opened), nil
+}
+
+// stateFactory and containerFactory use h in place of the hcs.Client and
+// winsyscall.WinSyscall the runtime passes them, keeping the context of the
+// client.
+type stateFactory struct {
+	h *HCS
+}
+
+func (f *stateFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, _ *winsyscall.WinSyscall, id, rootDir string) runtime.StateManager {
+	return state.New(logger, f.h.withContext(hcsClient.Context), &winSyscall{h: f.h}, id, rootDir, lockTimeout, "").WithContext(hcsClient.Context)
+}
+
+type containerFactory struct {
+	h *HCS
+}
+
+func (f *containerFactory) NewManager(logger *logrus.Entry, hcsClient *hcs.Client, id string) runtime.ContainerManager {
+	return container.New(logger, f.h.withContext(hcsClient.Context), id)
+}
+
+type processWrapper struct{}
+
+func (w *processWrapper) Wrap(p hcs.Process) runtime.WrappedProcess {
+	return hcsprocess.New(p)
+}
@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// Console control signals HCS can deliver to a process in a Windows container.
+const (
+	SignalCtrlC     = "CtrlC"
+	SignalCtrlBreak = "CtrlBreak"
+)
+
 //go:generate counterfeiter -o fakes/process.go --fake-name Process . Process
 type Process interface {
 	Pid() int
@@ -0,0 +1,183 @@
+package replay
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+// redacted is what hcs.Tracer records in place of the values of environment
+// variables.
+const redacted = "<redacted>"
+
+type container struct {
+	c  *Client
+	id string
+}
+
+func (c *container) Start() error {
+	return c.c.replay("Container.Start", c.id, 0, nil, nil)
+}
+
+func (c *container) Shutdown() error {
+	return c.c.replay("Container.Shutdown", c.id, 0, nil, nil)
+}
+
+func (c *container) Terminate() error {
+	return c.c.replay("Container.Terminate", c.id, 0, nil, nil)
+}
+
+func (c *container) Wait() error {
+	return c.c.replay("Container.Wait", c.id, 0, nil, nil)
+}
+
+func (c *container) WaitTimeout(timeout time.Duration) error {
+	return c.c.replay("Container.WaitTimeout", c.id, 0, args{"timeout": timeout.String()}, nil)
+}
+
+func (c *container) Pause() error {
+	return c.c.replay("Container.Pause", c.id, 0, nil, nil)
+}
+
+func (c *container) Resume() error {
+	return c.c.replay("Container.Resume", c.id, 0, nil, nil)
+}
+
+func (c *container) HasPendingUpdates() (bool, error) {
+	var pending bool
+	err := c.c.replay("Container.HasPendingUpdates", c.id, 0, nil, &pending)
+	return pending, err
+}
+
+func (c *container) Statistics() (hcs.Statistics, error) {
+	var stats hcs.Statistics
+	err := c.c.replay("Container.Statistics", c.id, 0, nil, &stats)
+	return stats, err
+}
+
+func (c *container) ProcessList() ([]hcs.ProcessListItem, error) {
+	var processes []hcs.ProcessListItem
+	err := c.c.replay("Container.ProcessList", c.id, 0, nil, &processes)
+	return processes, err
+}
+
+func (c *container) MappedVirtualDisks() (map[int]hcs.MappedVirtualDiskController, error) {
+	var disks map[int]hcs.MappedVirtualDiskController
+	err := c.c.replay("Container.MappedVirtualDisks", c.id, 0, nil, &disks)
+	return disks, err
+}
+
+// CreateProcess returns the process with the pid recorded for it, which
+// replays the calls made to that pid.
+func (c *container) CreateProcess(config *hcs.ProcessConfig) (hcs.Process, error) {
+	var created hcs.TraceProcess
+	if err := c.c.replay("Container.CreateProcess", c.id, 0, args{"config": redactProcessConfig(config)}, &created); err != nil {
+		return nil, err
+	}
+	return &process{c: c.c, id: c.id, pid: created.Pid}, nil
+}
+
+func (c *container) OpenProcess(pid int) (hcs.Process, error) {
+	if err := c.c.replay("Container.OpenProcess", c.id, 0, args{"pid": pid}, nil); err != nil {
+		return nil, err
+	}
+	return &process{c: c.c, id: c.id, pid: pid}, nil
+}
+
+func (c *container) Close() error {
+	return c.c.replay("Container.Close", c.id, 0, nil, nil)
+}
+
+func (c *container) Modify(config *hcs.ResourceModificationRequestResponse) error {
+	return c.c.replay("Container.Modify", c.id, 0, args{"config": config}, nil)
+}
+
+// redactProcessConfig records config as hcs.Tracer does, so that the process
+// is matched with the one created with the same config.
+func redactProcessConfig(config *hcs.ProcessConfig) *hcs.ProcessConfig {
+	if config == nil || len(config.Environment) == 0 {
+		return config
+	}
+
+	c := *config
+	c.Environment = map[string]string{}
+	for k := range config.Environment {
+		c.Environment[k] = redacted
+	}
+	return &c
+}
+
+// process is a process whose output was not recorded. The pipes it was
+// created with are empty, and discard what is written to them.
+type process struct {
+	c   *Client
+	id  string
+	pid int
+}
+
+func (p *process) Pid() int {
+	return p.pid
+}
+
+func (p *process) Kill() error {
+	return p.c.replay("Process.Kill", p.id, p.pid, nil, nil)
+}
+
+func (p *process) Wait() error {
+	return p.c.replay("Process.Wait", p.id, p.pid, nil, nil)
+}
+
+func (p *process) WaitTimeout(timeout time.Duration) error {
+	return p.c.replay("Process.WaitTimeout", p.id, p.pid, args{"timeout": timeout.String()}, nil)
+}
+
+func (p *process) ExitCode() (int, error) {
+	var exitCode int
+	err := p.c.replay("Process.ExitCode", p.id, p.pid, nil, &exitCode)
+	return exitCode, err
+}
+
+func (p *process) ResizeConsole(width, height uint16) error {
+	return p.c.replay("Process.ResizeConsole", p.id, p.pid, args{"width": width, "height": height}, nil)
+}
+
+func (p *process) Stdio() (io.WriteCloser, io.ReadCloser, io.ReadCloser, error) {
+	var pipes hcs.TraceStdio
+	if err := p.c.replay("Process.Stdio", p.id, p.pid, nil, &pipes); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		stdin          io.WriteCloser
+		stdout, stderr io.ReadCloser
+	)
+	if pipes.Stdin {
+		stdin = nopWriteCloser{ioutil.Discard}
+	}
+	if pipes.Stdout {
+		stdout = ioutil.NopCloser(strings.NewReader(""))
+	}
+	if pipes.Stderr {
+		stderr = ioutil.NopCloser(strings.NewReader(""))
+	}
+	return stdin, stdout, stderr, nil
+}
+
+func (p *process) CloseStdin() error {
+	return p.c.replay("Process.CloseStdin", p.id, p.pid, nil, nil)
+}
+
+func (p *process) Close() error {
+	return p.c.replay("Process.Close", p.id, p.pid, nil, nil)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package replay
+
+import (
+	"fmt"
+)
+
+type InvalidTraceError struct {
+	Line int
+	Err  error
+}
+
+func (e *InvalidTraceError) Error() string {
+	return fmt.Sprintf("invalid trace record on line %d: %s", e.Line, e.Err)
+}
+
+func (e *InvalidTraceError) Unwrap() error {
+	return e.Err
+}
+
+type UnexpectedCallError struct {
+	Method    string
+	Container string
+	Pid       int
+}
+
+func (e *UnexpectedCallError) Error() string {
+	return fmt.Sprintf("no call to %s left in the trace for container '%s' and pid %d", e.Method, e.Container, e.Pid)
+}
+
+type InvalidResultError struct {
+	Method string
+	Err    error
+}
+
+func (e *InvalidResultError) Error() string {
+	return fmt.Sprintf("invalid result recorded for %s: %s", e.Method, e.Err)
+}
+
+func (e *InvalidResultError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,272 @@
+// Package replay plays a trace written by hcs.Tracer, as with winc
+// --hcs-trace, back in place of hcs.Client, so that what HCS and HNS did
+// during an incident can be reproduced in a unit test.
+//
+// Each call is answered with the result and error of a recorded call to the
+// same method, container and process that has not been replayed yet: the
+// first one made with the same arguments, or else the first one. Calls that
+// only wait, such as for a network to be ready, are not made.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+
+	"code.cloudfoundry.org/winc/hcs"
+)
+
+// maxRecordSize is the longest line of a trace that can be read, enough for
+// the properties of hundreds of containers.
+const maxRecordSize = 16 * 1024 * 1024
+
+type Client struct {
+	mu       sync.Mutex
+	records  []hcs.TraceRecord
+	replayed []bool
+}
+
+// New reads a trace from r.
+func New(r io.Reader) (*Client, error) {
+	c := &Client{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var record hcs.TraceRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, &InvalidTraceError{Line: line, Err: err}
+		}
+		c.records = append(c.records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	c.replayed = make([]bool, len(c.records))
+	return c, nil
+}
+
+// Load reads the trace at path.
+func Load(path string) (*Client, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return New(f)
+}
+
+// Remaining returns the recorded calls that have not been replayed. Once the
+// code under test has made every call it made when the trace was recorded
+// there are none left.
+func (c *Client) Remaining() []hcs.TraceRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := []hcs.TraceRecord{}
+	for i, record := range c.records {
+		if !c.replayed[i] {
+			remaining = append(remaining, record)
+		}
+	}
+	return remaining
+}
+
+type args map[string]interface{}
+
+// replay answers a call with the recorded call it matches, decoding its
+// result into result unless that is nil, and returns its error.
+func (c *Client) replay(method, containerId string, pid int, callArgs args, result interface{}) error {
+	record, err := c.next(method, containerId, pid, callArgs)
+	if err != nil {
+		return err
+	}
+
+	if result != nil && len(record.Result) != 0 {
+		if err := json.Unmarshal(record.Result, result); err != nil {
+			return &InvalidResultError{Method: method, Err: err}
+		}
+	}
+
+	return record.Error.Err()
+}
+
+func (c *Client) next(method, containerId string, pid int, callArgs args) (hcs.TraceRecord, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, record := range c.records {
+		if c.replayed[i] || record.Method != method || record.Container != containerId || record.Pid != pid {
+			continue
+		}
+
+		if sameArgs(record.Args, callArgs) {
+			match = i
+			break
+		}
+		if match == -1 {
+			match = i
+		}
+	}
+
+	if match == -1 {
+		return hcs.TraceRecord{}, &UnexpectedCallError{Method: method, Container: containerId, Pid: pid}
+	}
+
+	c.replayed[match] = true
+	return c.records[match], nil
+}
+
+// sameArgs compares arguments as they are recorded, so that values JSON does
+// not tell apart are the same.
+func sameArgs(recorded json.RawMessage, callArgs args) bool {
+	if len(recorded) == 0 || callArgs == nil {
+		return len(recorded) == 0 && callArgs == nil
+	}
+
+	data, err := json.Marshal(callArgs)
+	if err != nil {
+		return false
+	}
+
+	var want, got interface{}
+	if err := json.Unmarshal(recorded, &want); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func (c *Client) GetContainers(q hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error) {
+	var cps []hcs.ContainerProperties
+	err := c.replay("GetContainers", "", 0, args{"query": q}, &cps)
+	return cps, err
+}
+
+// GetContainerProperties queries the recorded containers as hcs.Client does.
+func (c *Client) GetContainerProperties(id string) (hcs.ContainerProperties, error) {
+	cps, err := c.GetContainers(hcs.ComputeSystemQuery{IDs: []string{id}})
+	if err != nil {
+		return hcs.ContainerProperties{}, err
+	}
+
+	if len(cps) == 0 {
+		return hcs.ContainerProperties{}, &hcs.NotFoundError{Id: id}
+	}
+
+	if len(cps) > 1 {
+		return hcs.ContainerProperties{}, &hcs.DuplicateError{Id: id}
+	}
+
+	return cps[0], nil
+}
+
+func (c *Client) NameToGuid(name string) (hcs.GUID, error) {
+	var guid hcs.GUID
+	err := c.replay("NameToGuid", "", 0, args{"name": name}, &guid)
+	return guid, err
+}
+
+func (c *Client) GetLayerMountPath(info hcs.DriverInfo, id string) (string, error) {
+	var path string
+	err := c.replay("GetLayerMountPath", "", 0, args{"info": info, "id": id}, &path)
+	return path, err
+}
+
+func (c *Client) CreateContainer(id string, config *hcs.ContainerConfig) (hcs.Container, error) {
+	if err := c.replay("CreateContainer", id, 0, args{"config": config}, nil); err != nil {
+		return nil, err
+	}
+	return &container{c: c, id: id}, nil
+}
+
+func (c *Client) OpenContainer(id string) (hcs.Container, error) {
+	if err := c.replay("OpenContainer", id, 0, nil, nil); err != nil {
+		return nil, err
+	}
+	return &container{c: c, id: id}, nil
+}
+
+func (c *Client) IsPending(err error) bool {
+	return hcs.IsPending(err)
+}
+
+func (c *Client) SignalProcess(containerId string, pid int, signal string) error {
+	return c.replay("SignalProcess", containerId, pid, args{"signal": signal}, nil)
+}
+
+func (c *Client) CreateEndpoint(endpoint *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	var created *hcs.HNSEndpoint
+	err := c.replay("CreateEndpoint", "", 0, args{"endpoint": endpoint}, &created)
+	return created, err
+}
+
+func (c *Client) UpdateEndpoint(endpoint *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	var updated *hcs.HNSEndpoint
+	err := c.replay("UpdateEndpoint", "", 0, args{"endpoint": endpoint}, &updated)
+	return updated, err
+}
+
+func (c *Client) DeleteEndpoint(endpoint *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
+	var deleted *hcs.HNSEndpoint
+	err := c.replay("DeleteEndpoint", "", 0, args{"endpoint": endpoint}, &deleted)
+	return deleted, err
+}
+
+func (c *Client) CreateNetwork(network *hcs.HNSNetwork, networkReady func() (bool, error)) (*hcs.HNSNetwork, error) {
+	var created *hcs.HNSNetwork
+	err := c.replay("CreateNetwork", "", 0, args{"network": network}, &created)
+	return created, err
+}
+
+func (c *Client) DeleteNetwork(network *hcs.HNSNetwork) (*hcs.HNSNetwork, error) {
+	var deleted *hcs.HNSNetwork
+	err := c.replay("DeleteNetwork", "", 0, args{"network": network}, &deleted)
+	return deleted, err
+}
+
+func (c *Client) HNSListNetworkRequest() ([]hcs.HNSNetwork, error) {
+	var networks []hcs.HNSNetwork
+	err := c.replay("HNSListNetworkRequest", "", 0, nil, &networks)
+	return networks, err
+}
+
+func (c *Client) GetHNSEndpointByID(id string) (*hcs.HNSEndpoint, error) {
+	var endpoint *hcs.HNSEndpoint
+	err := c.replay("GetHNSEndpointByID", "", 0, args{"id": id}, &endpoint)
+	return endpoint, err
+}
+
+func (c *Client) GetHNSEndpointByName(name string) (*hcs.HNSEndpoint, error) {
+	var endpoint *hcs.HNSEndpoint
+	err := c.replay("GetHNSEndpointByName", "", 0, args{"name": name}, &endpoint)
+	return endpoint, err
+}
+
+func (c *Client) GetHNSNetworkByName(name string) (*hcs.HNSNetwork, error) {
+	var network *hcs.HNSNetwork
+	err := c.replay("GetHNSNetworkByName", "", 0, args{"name": name}, &network)
+	return network, err
+}
+
+func (c *Client) HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error {
+	return c.replay("HotAttachEndpoint", containerID, 0, args{"endpointId": endpointID}, nil)
+}
+
+func (c *Client) HotDetachEndpoint(containerID string, endpointID string) error {
+	return c.replay("HotDetachEndpoint", containerID, 0, args{"endpointId": endpointID}, nil)
+}
@@ -0,0 +1,127 @@
+package replay_test
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/replay"
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/endpoint"
+	"code.cloudfoundry.org/winc/runtime/container"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Replay", func() {
+	const containerId = "some-container"
+
+	load := func(lines ...string) *replay.Client {
+		client, err := replay.New(strings.NewReader(strings.Join(lines, "\n")))
+		Expect(err).NotTo(HaveOccurred())
+		return client
+	}
+
+	It("reproduces a stop that fell back to terminating the container", func() {
+		client := load(
+			`{"method":"OpenContainer","container":"some-container","duration":"2ms"}`,
+			`{"method":"GetContainers","args":{"query":{"Ids":["some-container"]}},"duration":"5ms","result":[{"Id":"some-container","State":"Running","Name":"some-container","SystemType":"Container","Owner":"winc"}]}`,
+			`{"method":"Container.Shutdown","container":"some-container","duration":"1ms","error":{"message":"container some-container encountered an error during Shutdown: ...","type":"*hcs.ContainerError","cause":"ErrVmcomputeOperationPending"}}`,
+			`{"method":"Container.WaitTimeout","container":"some-container","args":{"timeout":"1m0s"},"duration":"1m0s","error":{"message":"container some-container encountered an error during WaitTimeout: ...","type":"*hcs.ContainerError","cause":"ErrTimeout"}}`,
+			`{"method":"Container.Terminate","container":"some-container","duration":"30ms"}`,
+			`{"method":"Container.Close","container":"some-container","duration":"1ms"}`,
+		)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "replay")
+		containerManager := container.New(logger, client, containerId)
+
+		stage, err := containerManager.Stop(0, container.StopPolicy{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stage).To(Equal(container.StopStageTerminate))
+
+		Expect(client.Remaining()).To(BeEmpty())
+	})
+
+	It("reproduces deleting the endpoint of a container that is gone", func() {
+		client := load(
+			`{"method":"GetHNSEndpointByName","args":{"name":"some-container"},"duration":"3ms","result":{"ID":"some-endpoint","Name":"some-container"}}`,
+			`{"method":"HotDetachEndpoint","container":"some-container","args":{"endpointId":"some-endpoint"},"duration":"1ms","error":{"message":"A virtual machine or container with the specified identifier does not exist.","type":"hcs.HcsError","cause":"ErrComputeSystemDoesNotExist"}}`,
+			`{"method":"DeleteEndpoint","args":{"endpoint":{"ID":"some-endpoint","Name":"some-container"}},"duration":"4ms","result":{"ID":"some-endpoint","Name":"some-container"}}`,
+		)
+
+		endpointManager := endpoint.NewEndpointManager(client, containerId, network.Config{})
+		Expect(endpointManager.Delete()).To(Succeed())
+
+		Expect(client.Remaining()).To(BeEmpty())
+	})
+
+	It("rebuilds the HNS errors it replays", func() {
+		client := load(
+			`{"method":"GetHNSNetworkByName","args":{"name":"some-network"},"duration":"1ms","error":{"message":"Network some-network not found","type":"hns.NetworkNotFoundError","name":"some-network"}}`,
+		)
+
+		_, err := client.GetHNSNetworkByName("some-network")
+		Expect(err).To(Equal(hcs.NetworkNotFoundError{NetworkName: "some-network"}))
+	})
+
+	It("answers a call with the recorded call made with the same arguments", func() {
+		client := load(
+			`{"method":"GetHNSEndpointByID","args":{"id":"first-endpoint"},"duration":"1ms","result":{"ID":"first-endpoint","Name":"first"}}`,
+			`{"method":"GetHNSEndpointByID","args":{"id":"second-endpoint"},"duration":"1ms","result":{"ID":"second-endpoint","Name":"second"}}`,
+		)
+
+		e, err := client.GetHNSEndpointByID("second-endpoint")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.Name).To(Equal("second"))
+
+		remaining := client.Remaining()
+		Expect(remaining).To(HaveLen(1))
+		Expect(string(remaining[0].Args)).To(MatchJSON(`{"id":"first-endpoint"}`))
+	})
+
+	It("fails calls that are not left in the trace", func() {
+		client := load(
+			`{"method":"OpenContainer","container":"some-container","duration":"2ms"}`,
+		)
+
+		_, err := client.OpenContainer(containerId)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.OpenContainer(containerId)
+		Expect(err).To(Equal(&replay.UnexpectedCallError{Method: "OpenContainer", Container: containerId}))
+	})
+
+	It("hands out empty pipes for the processes it replays", func() {
+		client := load(
+			`{"method":"OpenContainer","container":"some-container","duration":"2ms"}`,
+			`{"method":"Container.CreateProcess","container":"some-container","args":{"config":{"CommandLine":"cmd.exe","Environment":{"PASSWORD":"<redacted>"}}},"duration":"50ms","result":{"pid":42}}`,
+			`{"method":"Process.Stdio","container":"some-container","pid":42,"duration":"0s","result":{"stdin":true,"stdout":true,"stderr":false}}`,
+			`{"method":"Process.ExitCode","container":"some-container","pid":42,"duration":"0s","result":3}`,
+		)
+
+		c, err := client.OpenContainer(containerId)
+		Expect(err).NotTo(HaveOccurred())
+
+		p, err := c.CreateProcess(&hcs.ProcessConfig{CommandLine: "cmd.exe", Environment: map[string]string{"PASSWORD": "other-secret"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Pid()).To(Equal(42))
+
+		stdin, stdout, stderr, err := p.Stdio()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdin.Close()).To(Succeed())
+		Expect(ioutil.ReadAll(stdout)).To(BeEmpty())
+		Expect(stderr).To(BeNil())
+
+		Expect(p.ExitCode()).To(Equal(3))
+		Expect(client.Remaining()).To(BeEmpty())
+	})
+
+	It("reports the line of a record it cannot read", func() {
+		_, err := replay.New(strings.NewReader(`{"method":"OpenContainer"}` + "\n\nnot json\n"))
+		Expect(err).To(BeAssignableToTypeOf(&replay.InvalidTraceError{}))
+		Expect(err.(*replay.InvalidTraceError).Line).To(Equal(3))
+	})
+})
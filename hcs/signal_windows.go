@@ -0,0 +1,97 @@
+package hcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	vmcompute = windows.NewLazySystemDLL("vmcompute.dll")
+	ole32     = windows.NewLazySystemDLL("ole32.dll")
+
+	procHcsOpenComputeSystem  = vmcompute.NewProc("HcsOpenComputeSystem")
+	procHcsCloseComputeSystem = vmcompute.NewProc("HcsCloseComputeSystem")
+	procHcsOpenProcess        = vmcompute.NewProc("HcsOpenProcess")
+	procHcsCloseProcess       = vmcompute.NewProc("HcsCloseProcess")
+	procHcsSignalProcess      = vmcompute.NewProc("HcsSignalProcess")
+	procCoTaskMemFree         = ole32.NewProc("CoTaskMemFree")
+
+	// signalProcs are not exported by the vmcompute.dll of older hosts
+	signalProcs = []*windows.LazyProc{procHcsOpenComputeSystem, procHcsCloseComputeSystem, procHcsOpenProcess, procHcsCloseProcess, procHcsSignalProcess, procCoTaskMemFree}
+)
+
+// SignalProcess delivers the console control signal to the process with the
+// given pid in the container. hcsshim does not expose signals on the
+// processes it returns, so HCS is called directly. A SignalNotSupportedError
+// is returned if the host's HCS cannot signal processes.
+func (c *Client) SignalProcess(containerId string, pid int, signal string) (err error) {
+	defer c.Tracer.trace("SignalProcess", containerId, pid, traceArgs{"signal": signal})(nil, &err)
+
+	for _, proc := range signalProcs {
+		if err := proc.Find(); err != nil {
+			return &SignalNotSupportedError{Call: proc.Name, Err: err}
+		}
+	}
+
+	id, err := syscall.UTF16PtrFromString(containerId)
+	if err != nil {
+		return err
+	}
+
+	options, err := json.Marshal(struct {
+		Signal string `json:"Signal"`
+	}{Signal: signal})
+	if err != nil {
+		return err
+	}
+	optionsPtr, err := syscall.UTF16PtrFromString(string(options))
+	if err != nil {
+		return err
+	}
+
+	var system, process uintptr
+	var result *uint16
+
+	r0, _, _ := procHcsOpenComputeSystem.Call(uintptr(unsafe.Pointer(id)), uintptr(unsafe.Pointer(&system)), uintptr(unsafe.Pointer(&result)))
+	if err := hcsError("HcsOpenComputeSystem", r0, result); err != nil {
+		return err
+	}
+	defer procHcsCloseComputeSystem.Call(system)
+
+	result = nil
+	r0, _, _ = procHcsOpenProcess.Call(system, uintptr(pid), uintptr(unsafe.Pointer(&process)), uintptr(unsafe.Pointer(&result)))
+	if err := hcsError("HcsOpenProcess", r0, result); err != nil {
+		return err
+	}
+	defer procHcsCloseProcess.Call(process)
+
+	result = nil
+	r0, _, _ = procHcsSignalProcess.Call(process, uintptr(unsafe.Pointer(optionsPtr)), uintptr(unsafe.Pointer(&result)))
+	return hcsError("HcsSignalProcess", r0, result)
+}
+
+// hcsError converts the HRESULT and result document returned by an HCS call
+// into an error, freeing the result document.
+func hcsError(operation string, hr uintptr, result *uint16) error {
+	var details string
+	if result != nil {
+		details = windows.UTF16PtrToString(result)
+		procCoTaskMemFree.Call(uintptr(unsafe.Pointer(result)))
+	}
+
+	if int32(hr) >= 0 {
+		return nil
+	}
+
+	if hr&0x1fff0000 == 0x00070000 {
+		hr &= 0xffff
+	}
+	if details != "" {
+		return fmt.Errorf("%s: %s: %s", operation, syscall.Errno(hr).Error(), details)
+	}
+	return fmt.Errorf("%s: %s", operation, syscall.Errno(hr).Error())
+}
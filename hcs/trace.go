@@ -0,0 +1,389 @@
+package hcs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// redacted replaces the values of the environment variables of the processes
+// a Tracer records, as they often hold credentials.
+const redacted = "<redacted>"
+
+// TraceRecord is a call made to HCS or HNS, as written by a Tracer on a line
+// of its own. Calls to a container or process name it with Container and Pid.
+type TraceRecord struct {
+	Time      time.Time       `json:"time"`
+	Method    string          `json:"method"`
+	Container string          `json:"container,omitempty"`
+	Pid       int             `json:"pid,omitempty"`
+	Args      json.RawMessage `json:"args,omitempty"`
+	Duration  string          `json:"duration"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *TraceError     `json:"error,omitempty"`
+}
+
+// TraceError is an error returned by a call a Tracer recorded.
+type TraceError struct {
+	Message string `json:"message"`
+
+	// Type is the Go type of the error.
+	Type string `json:"type"`
+
+	// Cause is the name of the HCS error of this package, such as
+	// ErrTimeout, the error is or wraps.
+	Cause string `json:"cause,omitempty"`
+
+	// Name is the container, network or endpoint of a not found error.
+	Name string `json:"name,omitempty"`
+}
+
+// hcsErrors are the errors the predicates of this package and the callers of
+// a Client compare errors to.
+var hcsErrors = map[string]error{
+	"ErrComputeSystemDoesNotExist":        ErrComputeSystemDoesNotExist,
+	"ErrElementNotFound":                  ErrElementNotFound,
+	"ErrNotSupported":                     ErrNotSupported,
+	"ErrInvalidData":                      ErrInvalidData,
+	"ErrHandleClose":                      ErrHandleClose,
+	"ErrAlreadyClosed":                    ErrAlreadyClosed,
+	"ErrInvalidNotificationType":          ErrInvalidNotificationType,
+	"ErrInvalidProcessState":              ErrInvalidProcessState,
+	"ErrTimeout":                          ErrTimeout,
+	"ErrUnexpectedContainerExit":          ErrUnexpectedContainerExit,
+	"ErrUnexpectedProcessAbort":           ErrUnexpectedProcessAbort,
+	"ErrUnexpectedValue":                  ErrUnexpectedValue,
+	"ErrVmcomputeAlreadyStopped":          ErrVmcomputeAlreadyStopped,
+	"ErrVmcomputeOperationPending":        ErrVmcomputeOperationPending,
+	"ErrVmcomputeOperationInvalidState":   ErrVmcomputeOperationInvalidState,
+	"ErrProcNotFound":                     ErrProcNotFound,
+	"ErrVmcomputeOperationAccessIsDenied": ErrVmcomputeOperationAccessIsDenied,
+	"ErrVmcomputeInvalidJSON":             ErrVmcomputeInvalidJSON,
+	"ErrVmcomputeUnknownMessage":          ErrVmcomputeUnknownMessage,
+	"ErrPlatformNotSupported":             ErrPlatformNotSupported,
+}
+
+var (
+	notFoundErrorType         = fmt.Sprintf("%T", &NotFoundError{})
+	duplicateErrorType        = fmt.Sprintf("%T", &DuplicateError{})
+	lowMemoryErrorType        = fmt.Sprintf("%T", &LowMemoryError{})
+	networkNotFoundErrorType  = fmt.Sprintf("%T", NetworkNotFoundError{})
+	endpointNotFoundErrorType = fmt.Sprintf("%T", EndpointNotFoundError{})
+
+	// traces recorded when the Client returned the errors of hcsshim name
+	// its types
+	shimNetworkNotFoundErrorType  = "hns.NetworkNotFoundError"
+	shimEndpointNotFoundErrorType = "hns.EndpointNotFoundError"
+)
+
+func newTraceError(err error) *TraceError {
+	if err == nil {
+		return nil
+	}
+
+	traceErr := &TraceError{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+
+	switch e := err.(type) {
+	case *NotFoundError:
+		traceErr.Name = e.Id
+	case *DuplicateError:
+		traceErr.Name = e.Id
+	case NetworkNotFoundError:
+		traceErr.Name = e.NetworkName
+	case EndpointNotFoundError:
+		traceErr.Name = e.EndpointName
+	}
+
+	inner := innerError(err)
+	for name, hcsErr := range hcsErrors {
+		if inner == hcsErr {
+			traceErr.Cause = name
+		}
+	}
+
+	return traceErr
+}
+
+// Err returns an error a caller handles as it did the recorded one. The
+// errors of this package and of HNS are rebuilt, and an error caused by an
+// HCS error is that error, which IsNotExist, IsPending and IsTimeout
+// recognise as they do the errors it is wrapped in. Any other error only
+// keeps its message.
+func (e *TraceError) Err() error {
+	if e == nil {
+		return nil
+	}
+
+	if cause, ok := hcsErrors[e.Cause]; ok {
+		return cause
+	}
+
+	switch e.Type {
+	case notFoundErrorType:
+		return &NotFoundError{Id: e.Name}
+	case duplicateErrorType:
+		return &DuplicateError{Id: e.Name}
+	case lowMemoryErrorType:
+		return &LowMemoryError{}
+	case networkNotFoundErrorType, shimNetworkNotFoundErrorType:
+		return NetworkNotFoundError{NetworkName: e.Name}
+	case endpointNotFoundErrorType, shimEndpointNotFoundErrorType:
+		return EndpointNotFoundError{EndpointName: e.Name}
+	}
+
+	return errors.New(e.Message)
+}
+
+// Tracer writes a TraceRecord for each call made through the Clients it is
+// set on, and the containers and processes they return, to w as JSON lines.
+// The environment of the processes created is not recorded.
+type Tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+type traceArgs map[string]interface{}
+
+// trace returns a function to call with the result and error of the call once
+// it returns, which records it. A nil Tracer records nothing.
+func (t *Tracer) trace(method, containerId string, pid int, args traceArgs) func(result interface{}, err *error) {
+	if t == nil {
+		return func(interface{}, *error) {}
+	}
+
+	start := time.Now()
+	return func(result interface{}, err *error) {
+		record := TraceRecord{
+			Time:      start,
+			Method:    method,
+			Container: containerId,
+			Pid:       pid,
+			Duration:  time.Since(start).String(),
+		}
+		if args != nil {
+			record.Args = marshalTrace(args)
+		}
+		if result != nil {
+			record.Result = marshalTrace(result)
+		}
+		if err != nil {
+			record.Error = newTraceError(*err)
+		}
+
+		t.write(record)
+	}
+}
+
+func (t *Tracer) write(record TraceRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		logrus.WithField("method", record.Method).Warnf("failed to marshal hcs trace record: %s", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := t.w.Write(append(line, '\n')); err != nil {
+		logrus.WithField("method", record.Method).Warnf("failed to write hcs trace record: %s", err)
+	}
+}
+
+// marshalTrace keeps a value that cannot be marshalled from losing the rest
+// of its record.
+func marshalTrace(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("cannot be traced: %s", err))
+	}
+	return data
+}
+
+// ContainerWithTracer returns container with the calls made to it, and to
+// the processes it creates or opens, recorded by t. id is the container
+// recorded with them.
+func ContainerWithTracer(t *Tracer, id string, container Container) Container {
+	if t == nil {
+		return container
+	}
+	return &tracingContainer{Container: container, t: t, id: id}
+}
+
+type tracingContainer struct {
+	Container
+	t  *Tracer
+	id string
+}
+
+func (c *tracingContainer) Start() (err error) {
+	defer c.t.trace("Container.Start", c.id, 0, nil)(nil, &err)
+	return c.Container.Start()
+}
+
+func (c *tracingContainer) Shutdown() (err error) {
+	defer c.t.trace("Container.Shutdown", c.id, 0, nil)(nil, &err)
+	return c.Container.Shutdown()
+}
+
+func (c *tracingContainer) Terminate() (err error) {
+	defer c.t.trace("Container.Terminate", c.id, 0, nil)(nil, &err)
+	return c.Container.Terminate()
+}
+
+func (c *tracingContainer) Wait() (err error) {
+	defer c.t.trace("Container.Wait", c.id, 0, nil)(nil, &err)
+	return c.Container.Wait()
+}
+
+func (c *tracingContainer) WaitTimeout(timeout time.Duration) (err error) {
+	defer c.t.trace("Container.WaitTimeout", c.id, 0, traceArgs{"timeout": timeout.String()})(nil, &err)
+	return c.Container.WaitTimeout(timeout)
+}
+
+func (c *tracingContainer) Pause() (err error) {
+	defer c.t.trace("Container.Pause", c.id, 0, nil)(nil, &err)
+	return c.Container.Pause()
+}
+
+func (c *tracingContainer) Resume() (err error) {
+	defer c.t.trace("Container.Resume", c.id, 0, nil)(nil, &err)
+	return c.Container.Resume()
+}
+
+func (c *tracingContainer) HasPendingUpdates() (pending bool, err error) {
+	defer c.t.trace("Container.HasPendingUpdates", c.id, 0, nil)(&pending, &err)
+	return c.Container.HasPendingUpdates()
+}
+
+func (c *tracingContainer) Statistics() (stats Statistics, err error) {
+	defer c.t.trace("Container.Statistics", c.id, 0, nil)(&stats, &err)
+	return c.Container.Statistics()
+}
+
+func (c *tracingContainer) ProcessList() (processes []ProcessListItem, err error) {
+	defer c.t.trace("Container.ProcessList", c.id, 0, nil)(&processes, &err)
+	return c.Container.ProcessList()
+}
+
+func (c *tracingContainer) MappedVirtualDisks() (disks map[int]MappedVirtualDiskController, err error) {
+	defer c.t.trace("Container.MappedVirtualDisks", c.id, 0, nil)(&disks, &err)
+	return c.Container.MappedVirtualDisks()
+}
+
+func (c *tracingContainer) CreateProcess(config *ProcessConfig) (p Process, err error) {
+	var created TraceProcess
+	defer c.t.trace("Container.CreateProcess", c.id, 0, traceArgs{"config": redactProcessConfig(config)})(&created, &err)
+
+	p, err = c.Container.CreateProcess(config)
+	if err != nil {
+		return nil, err
+	}
+	created.Pid = p.Pid()
+	return &tracingProcess{Process: p, t: c.t, id: c.id, pid: created.Pid}, nil
+}
+
+func (c *tracingContainer) OpenProcess(pid int) (p Process, err error) {
+	defer c.t.trace("Container.OpenProcess", c.id, 0, traceArgs{"pid": pid})(nil, &err)
+
+	p, err = c.Container.OpenProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingProcess{Process: p, t: c.t, id: c.id, pid: pid}, nil
+}
+
+func (c *tracingContainer) Close() (err error) {
+	defer c.t.trace("Container.Close", c.id, 0, nil)(nil, &err)
+	return c.Container.Close()
+}
+
+func (c *tracingContainer) Modify(config *ResourceModificationRequestResponse) (err error) {
+	defer c.t.trace("Container.Modify", c.id, 0, traceArgs{"config": config})(nil, &err)
+	return c.Container.Modify(config)
+}
+
+// TraceProcess is the result recorded for Container.CreateProcess.
+type TraceProcess struct {
+	Pid int `json:"pid"`
+}
+
+// TraceStdio is the result recorded for Process.Stdio, which pipes the
+// process has.
+type TraceStdio struct {
+	Stdin  bool `json:"stdin"`
+	Stdout bool `json:"stdout"`
+	Stderr bool `json:"stderr"`
+}
+
+type tracingProcess struct {
+	Process
+	t   *Tracer
+	id  string
+	pid int
+}
+
+func (p *tracingProcess) Kill() (err error) {
+	defer p.t.trace("Process.Kill", p.id, p.pid, nil)(nil, &err)
+	return p.Process.Kill()
+}
+
+func (p *tracingProcess) Wait() (err error) {
+	defer p.t.trace("Process.Wait", p.id, p.pid, nil)(nil, &err)
+	return p.Process.Wait()
+}
+
+func (p *tracingProcess) WaitTimeout(timeout time.Duration) (err error) {
+	defer p.t.trace("Process.WaitTimeout", p.id, p.pid, traceArgs{"timeout": timeout.String()})(nil, &err)
+	return p.Process.WaitTimeout(timeout)
+}
+
+func (p *tracingProcess) ExitCode() (exitCode int, err error) {
+	defer p.t.trace("Process.ExitCode", p.id, p.pid, nil)(&exitCode, &err)
+	return p.Process.ExitCode()
+}
+
+func (p *tracingProcess) ResizeConsole(width, height uint16) (err error) {
+	defer p.t.trace("Process.ResizeConsole", p.id, p.pid, traceArgs{"width": width, "height": height})(nil, &err)
+	return p.Process.ResizeConsole(width, height)
+}
+
+func (p *tracingProcess) Stdio() (stdin io.WriteCloser, stdout, stderr io.ReadCloser, err error) {
+	var pipes TraceStdio
+	defer p.t.trace("Process.Stdio", p.id, p.pid, nil)(&pipes, &err)
+
+	stdin, stdout, stderr, err = p.Process.Stdio()
+	pipes = TraceStdio{Stdin: stdin != nil, Stdout: stdout != nil, Stderr: stderr != nil}
+	return stdin, stdout, stderr, err
+}
+
+func (p *tracingProcess) CloseStdin() (err error) {
+	defer p.t.trace("Process.CloseStdin", p.id, p.pid, nil)(nil, &err)
+	return p.Process.CloseStdin()
+}
+
+func (p *tracingProcess) Close() (err error) {
+	defer p.t.trace("Process.Close", p.id, p.pid, nil)(nil, &err)
+	return p.Process.Close()
+}
+
+func redactProcessConfig(config *ProcessConfig) *ProcessConfig {
+	if config == nil || len(config.Environment) == 0 {
+		return config
+	}
+
+	c := *config
+	c.Environment = map[string]string{}
+	for k := range config.Environment {
+		c.Environment[k] = redacted
+	}
+	return &c
+}
@@ -0,0 +1,125 @@
+package hcs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/hcs/fakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContainerWithTracer", func() {
+	const containerId = "some-container"
+
+	var (
+		fakeContainer *fakes.Container
+		fakeProcess   *fakes.Process
+		trace         *bytes.Buffer
+		container     hcs.Container
+	)
+
+	records := func() []hcs.TraceRecord {
+		var records []hcs.TraceRecord
+		decoder := json.NewDecoder(bytes.NewReader(trace.Bytes()))
+		for decoder.More() {
+			var record hcs.TraceRecord
+			Expect(decoder.Decode(&record)).To(Succeed())
+			records = append(records, record)
+		}
+		return records
+	}
+
+	BeforeEach(func() {
+		fakeContainer = &fakes.Container{}
+		fakeProcess = &fakes.Process{}
+		fakeProcess.PidReturns(42)
+		fakeContainer.CreateProcessReturns(fakeProcess, nil)
+
+		trace = &bytes.Buffer{}
+		container = hcs.ContainerWithTracer(hcs.NewTracer(trace), containerId, fakeContainer)
+	})
+
+	It("records the calls made to the container with their arguments and results", func() {
+		fakeContainer.ProcessListReturns([]hcs.ProcessListItem{{ProcessId: 42, ImageName: "cmd.exe"}}, nil)
+
+		Expect(container.WaitTimeout(time.Second)).To(Succeed())
+		_, err := container.ProcessList()
+		Expect(err).NotTo(HaveOccurred())
+
+		r := records()
+		Expect(r).To(HaveLen(2))
+
+		Expect(r[0].Method).To(Equal("Container.WaitTimeout"))
+		Expect(r[0].Container).To(Equal(containerId))
+		Expect(string(r[0].Args)).To(MatchJSON(`{"timeout": "1s"}`))
+		Expect(r[0].Error).To(BeNil())
+		Expect(r[0].Duration).NotTo(BeEmpty())
+
+		Expect(r[1].Method).To(Equal("Container.ProcessList"))
+		var processes []hcs.ProcessListItem
+		Expect(json.Unmarshal(r[1].Result, &processes)).To(Succeed())
+		Expect(processes[0].ProcessId).To(Equal(uint32(42)))
+		Expect(processes[0].ImageName).To(Equal("cmd.exe"))
+	})
+
+	It("records the calls made to the processes it creates without their environment", func() {
+		p, err := container.CreateProcess(&hcs.ProcessConfig{
+			CommandLine: "cmd.exe",
+			Environment: map[string]string{"PASSWORD": "some-secret"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		config := fakeContainer.CreateProcessArgsForCall(0)
+		Expect(config.Environment).To(Equal(map[string]string{"PASSWORD": "some-secret"}))
+
+		fakeProcess.ExitCodeReturns(3, nil)
+		Expect(p.ExitCode()).To(Equal(3))
+
+		r := records()
+		Expect(r).To(HaveLen(2))
+
+		Expect(r[0].Method).To(Equal("Container.CreateProcess"))
+		Expect(string(r[0].Args)).NotTo(ContainSubstring("some-secret"))
+		Expect(string(r[0].Result)).To(MatchJSON(`{"pid": 42}`))
+
+		Expect(r[1].Method).To(Equal("Process.ExitCode"))
+		Expect(r[1].Container).To(Equal(containerId))
+		Expect(r[1].Pid).To(Equal(42))
+		Expect(string(r[1].Result)).To(MatchJSON(`3`))
+	})
+
+	It("records the HCS error an error is caused by", func() {
+		fakeContainer.ShutdownReturns(&hcs.ContainerError{Operation: "Shutdown", Err: hcs.ErrVmcomputeOperationPending})
+
+		Expect(container.Shutdown()).NotTo(Succeed())
+
+		r := records()
+		Expect(r).To(HaveLen(1))
+		Expect(r[0].Error.Type).To(Equal("*hcs.ContainerError"))
+		Expect(r[0].Error.Cause).To(Equal("ErrVmcomputeOperationPending"))
+		Expect(hcs.IsPending(r[0].Error.Err())).To(BeTrue())
+	})
+
+	It("rebuilds the typed errors it records", func() {
+		fakeContainer.StatisticsReturns(hcs.Statistics{}, &hcs.NotFoundError{Id: containerId})
+		fakeContainer.CloseReturns(errors.New("some-error"))
+
+		_, err := container.Statistics()
+		Expect(err).To(HaveOccurred())
+		Expect(container.Close()).NotTo(Succeed())
+
+		r := records()
+		Expect(r[0].Error.Err()).To(Equal(&hcs.NotFoundError{Id: containerId}))
+		Expect(r[1].Error.Err()).To(MatchError("some-error"))
+	})
+
+	Context("the tracer is nil", func() {
+		It("returns the container", func() {
+			Expect(hcs.ContainerWithTracer(nil, containerId, fakeContainer)).To(BeIdenticalTo(fakeContainer))
+		})
+	})
+})
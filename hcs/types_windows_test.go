@@ -0,0 +1,83 @@
+package hcs_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Types", func() {
+	DescribeTable("marshal to the same JSON fields as their hcsshim counterparts",
+		func(document, shimDocument interface{}) {
+			Expect(jsonShape(reflect.TypeOf(document))).To(Equal(jsonShape(reflect.TypeOf(shimDocument))))
+		},
+		Entry("ComputeSystemQuery", hcs.ComputeSystemQuery{}, hcsshim.ComputeSystemQuery{}),
+		Entry("ContainerConfig", hcs.ContainerConfig{}, hcsshim.ContainerConfig{}),
+		Entry("ContainerProperties", hcs.ContainerProperties{}, hcsshim.ContainerProperties{}),
+		Entry("Statistics", hcs.Statistics{}, hcsshim.Statistics{}),
+		Entry("ProcessListItem", hcs.ProcessListItem{}, hcsshim.ProcessListItem{}),
+		Entry("MappedVirtualDiskController", hcs.MappedVirtualDiskController{}, hcsshim.MappedVirtualDiskController{}),
+		Entry("ResourceModificationRequestResponse", hcs.ResourceModificationRequestResponse{}, hcsshim.ResourceModificationRequestResponse{}),
+		Entry("ProcessConfig", hcs.ProcessConfig{}, hcsshim.ProcessConfig{}),
+		Entry("HNSNetwork", hcs.HNSNetwork{}, hcsshim.HNSNetwork{}),
+		Entry("HNSEndpoint", hcs.HNSEndpoint{}, hcsshim.HNSEndpoint{}),
+		Entry("Policy", hcs.Policy{}, hcsshim.Policy{}),
+		Entry("NatPolicy", hcs.NatPolicy{}, hcsshim.NatPolicy{}),
+		Entry("QosPolicy", hcs.QosPolicy{}, hcsshim.QosPolicy{}),
+		Entry("ACLPolicy", hcs.ACLPolicy{}, hcsshim.ACLPolicy{}),
+		Entry("DriverInfo", hcs.DriverInfo{}, hcsshim.DriverInfo{}),
+		Entry("GUID", hcs.GUID{}, hcsshim.GUID{}),
+	)
+})
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonShape describes the JSON t marshals to: the name, omitempty option and
+// shape of every field of a struct, recursively, and the kind of everything
+// else. Text marshalers marshal to strings.
+func jsonShape(t reflect.Type) interface{} {
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return "json.Marshaler " + t.String()
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return []interface{}{"pointer", jsonShape(t.Elem())}
+	case reflect.Slice:
+		return []interface{}{"slice", jsonShape(t.Elem())}
+	case reflect.Array:
+		return []interface{}{"array", t.Len(), jsonShape(t.Elem())}
+	case reflect.Map:
+		return []interface{}{"map", jsonShape(t.Key()), jsonShape(t.Elem())}
+	case reflect.Struct:
+		fields := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if f.PkgPath != "" || tag == "-" {
+				continue
+			}
+
+			name := strings.Split(tag, ",")[0]
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = []interface{}{strings.Contains(tag, ",omitempty"), jsonShape(f.Type)}
+		}
+		return fields
+	}
+	return t.Kind().String()
+}
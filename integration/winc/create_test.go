@@ -12,6 +12,7 @@ import (
 	"strings"
 	"syscall"
 
+	"code.cloudfoundry.org/winc/runtime/state"
 	acl "github.com/hectane/go-acl"
 	ps "github.com/mitchellh/go-ps"
 	. "github.com/onsi/ginkgo"
@@ -276,42 +277,50 @@ var _ = Describe("Create", func() {
 			})
 
 			Context("when a file is supplied as a mount", func() {
-				var (
-					logFile   string
-					mountFile string
-				)
+				var mountFile string
 
 				BeforeEach(func() {
-					l, err := ioutil.TempFile("", "winc.log")
-					Expect(err).ToNot(HaveOccurred())
-					Expect(l.Close()).To(Succeed())
-					logFile = l.Name()
-
 					m, err := ioutil.TempFile("", "mountfile")
 					Expect(err).ToNot(HaveOccurred())
+					_, err = m.WriteString("file contents")
+					Expect(err).ToNot(HaveOccurred())
 					Expect(m.Close()).To(Succeed())
 					mountFile = m.Name()
+					Expect(acl.Apply(filepath.Dir(mountFile), false, false, acl.GrantName(windows.GENERIC_READ, "Everyone"))).To(Succeed())
 
 					bundleSpec.Mounts = append(bundleSpec.Mounts, specs.Mount{
 						Source:      mountFile,
-						Destination: "C:\\foobar",
+						Destination: "C:\\certs\\foobar",
 					})
 				})
 
 				AfterEach(func() {
-					Expect(os.RemoveAll(logFile)).To(Succeed())
 					Expect(os.RemoveAll(mountFile)).To(Succeed())
 				})
 
-				It("ignores it and logs that it did so", func() {
-					helpers.GenerateBundle(bundleSpec, bundlePath)
-					stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "--debug", "--log", logFile, "create", "-b", bundlePath, containerId))
-					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				It("makes the file available read-only at the destination", func() {
+					helpers.CreateContainer(bundleSpec, bundlePath, containerId)
 
-					contents, err := ioutil.ReadFile(logFile)
-					Expect(err).ToNot(HaveOccurred())
-					Expect(string(contents)).To(ContainSubstring("mount is not a directory, ignoring"))
-					Expect(string(contents)).To(ContainSubstring(fmt.Sprintf(`"mount":"%s"`, strings.Replace(mountFile, `\`, `\\`, -1))))
+					stdOut, _, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "type", "C:\\certs\\foobar"}, false)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(stdOut.String()).To(ContainSubstring("file contents"))
+
+					_, stdErr, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "echo hello > C:\\certs\\foobar"}, false)
+					Expect(err).To(HaveOccurred())
+					Expect(stdErr.String()).To(ContainSubstring("Access is denied"))
+				})
+			})
+
+			Context("when a mount has an unsupported type", func() {
+				BeforeEach(func() {
+					bundleSpec.Mounts[0].Type = "tmpfs"
+				})
+
+				It("errors", func() {
+					helpers.GenerateBundle(bundleSpec, bundlePath)
+					_, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+					Expect(err).To(HaveOccurred())
+					Expect(stdErr.String()).To(ContainSubstring("mount C:\\mountdest has unsupported type tmpfs"))
 				})
 			})
 		})
@@ -353,6 +362,67 @@ var _ = Describe("Create", func() {
 				Expect(grabMemory(int(memLimitMB), 2)).To(ContainSubstring("fatal error: out of memory"))
 			})
 		})
+
+		Context("when the bundle config.json specifies container storage limits", func() {
+			BeforeEach(func() {
+				iops := uint64(500)
+				sandboxSize := uint64(30 * 1024 * 1024 * 1024)
+				bundleSpec.Windows.Resources = &specs.WindowsResources{
+					Storage: &specs.WindowsStorageResources{Iops: &iops, SandboxSize: &sandboxSize},
+				}
+			})
+
+			It("reports them in the state of the container", func() {
+				helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+
+				annotations := helpers.GetContainerState(containerId).Annotations
+				Expect(annotations).To(HaveKeyWithValue(state.StorageIopsAnnotation, "500"))
+				Expect(annotations).To(HaveKeyWithValue(state.SandboxSizeAnnotation, strconv.Itoa(30*1024*1024*1024)))
+			})
+
+			It("expands the system drive of the container", func() {
+				helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+
+				stdOut, stdErr, err := helpers.ExecInContainer(containerId, []string{"powershell.exe", "-Command", "(Get-PSDrive C).Used + (Get-PSDrive C).Free"}, false)
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				size, err := strconv.ParseUint(strings.TrimSpace(stdOut.String()), 10, 64)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(size).To(BeNumerically(">=", uint64(29*1024*1024*1024)))
+			})
+		})
+
+		Context("when the bundle config.json specifies a container cpu count", func() {
+			BeforeEach(func() {
+				count := uint64(1)
+				bundleSpec.Windows.Resources = &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{Count: &count},
+				}
+			})
+
+			It("limits the processors visible in the container", func() {
+				helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+
+				stdOut, stdErr, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "echo %NUMBER_OF_PROCESSORS%"}, false)
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(strings.TrimSpace(stdOut.String())).To(Equal("1"))
+			})
+
+			Context("when it conflicts with a cpu maximum", func() {
+				BeforeEach(func() {
+					maximum := uint16(5000)
+					bundleSpec.Windows.Resources.CPU.Maximum = &maximum
+				})
+
+				It("errors and does not create the container", func() {
+					helpers.GenerateBundle(bundleSpec, bundlePath)
+					stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "create", "-b", bundlePath, containerId))
+					Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdErr.String()).To(ContainSubstring("cpu count, maximum are mutually exclusive"))
+
+					Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+				})
+			})
+		})
 	})
 
 	Context("when the mount source does not exist", func() {
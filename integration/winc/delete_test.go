@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -68,6 +69,32 @@ var _ = Describe("Delete", func() {
 					Expect(helpers.ContainerExists(containerId)).To(BeFalse())
 				})
 			})
+
+			It("reports the stage that stopped the container", func() {
+				cmd := exec.Command(wincBin, "delete", "--grace-period", "5s", "--shutdown-timeout", "30s", containerId)
+				stdOut, stdErr, err := helpers.Execute(cmd)
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+
+				var results []struct {
+					ID        string `json:"id"`
+					StoppedBy string `json:"stopped_by"`
+				}
+				Expect(json.Unmarshal(stdOut.Bytes(), &results)).To(Succeed())
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].ID).To(Equal(containerId))
+				Expect(results[0].StoppedBy).To(BeElementOf("signal", "shutdown", "terminate"))
+			})
+
+			Context("when passed a negative grace period", func() {
+				It("errors without deleting the container", func() {
+					cmd := exec.Command(wincBin, "delete", "--grace-period", "-1s", containerId)
+					stdOut, stdErr, err := helpers.Execute(cmd)
+					Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdErr.String()).To(ContainSubstring("timeout must not be negative: -1s"))
+					Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+				})
+			})
 		})
 	})
 
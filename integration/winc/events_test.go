@@ -13,6 +13,8 @@ import (
 	acl "github.com/hectane/go-acl"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/sys/windows"
 )
@@ -46,10 +48,26 @@ var _ = Describe("Events", func() {
 		})
 
 		Context("when the container has been created", func() {
-			It("exits without error", func() {
-				cmd := exec.Command(wincBin, "events", containerId)
-				stdOut, stdErr, err := helpers.Execute(cmd)
+			It("streams stats events until the container stops", func() {
+				cmd := exec.Command(wincBin, "events", "--interval", "100ms", containerId)
+				session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session.Out).Should(gbytes.Say(`{"type":"stats","id":"` + containerId + `","data":`))
+				Consistently(session).ShouldNot(gexec.Exit())
+
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
 				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(session.Out).To(gbytes.Say(`{"type":"exit","id":"` + containerId + `"}`))
+			})
+
+			It("errors when the interval is not positive", func() {
+				cmd := exec.Command(wincBin, "events", "--interval", "0s", containerId)
+				stdOut, stdErr, err := helpers.Execute(cmd)
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdErr.String()).To(ContainSubstring("duration interval must be greater than 0"))
 			})
 
 			Context("when passed the --stats flag", func() {
@@ -84,6 +102,31 @@ var _ = Describe("Events", func() {
 					Expect(cmd.Wait()).To(Succeed())
 				})
 
+				It("prints the container memory usage and peak to stdout", func() {
+					stats := getStats(containerId)
+					Expect(stats.Data.Memory.Usage.Usage).To(Equal(stats.Data.Memory.Stats.TotalRss))
+					Expect(stats.Data.Memory.Usage.Max).To(BeNumerically(">=", stats.Data.Memory.Usage.Usage))
+					Expect(stats.Data.Memory.Stats.PrivateWorkingSet).To(BeNumerically(">", 0))
+				})
+
+				It("prints the container storage stats to stdout", func() {
+					written := func() uint64 {
+						for _, entry := range getStats(containerId).Data.Blkio.IoServiceBytesRecursive {
+							if entry.Op == "Write" {
+								return entry.Value
+							}
+						}
+						return 0
+					}
+					writtenBefore := written()
+
+					args := []string{"powershell.exe", "-Command", "Set-Content -Path C:\\data.txt -Value ('x' * 10MB)"}
+					stdOut, stdErr, err := helpers.ExecInContainer(containerId, args, false)
+					Expect(err).ToNot(HaveOccurred(), stdOut.String(), stdErr.String())
+
+					Eventually(written).Should(BeNumerically(">", writtenBefore))
+				})
+
 				It("prints the container CPU stats to stdout", func() {
 					cpuUsageBefore := getStats(containerId).Data.CPUStats.CPUUsage.Usage
 					Expect(cpuUsageBefore).To(BeNumerically(">", 0))
@@ -135,8 +178,7 @@ var _ = Describe("Events", func() {
 			stdOut, stdErr, err := helpers.Execute(cmd)
 			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
 
-			Expect(stdErr.String()).To(ContainSubstring("hcsshim::OpenComputeSystem doesntexist"))
-			Expect(stdErr.String()).To(ContainSubstring("the specified identifier does not exist"))
+			Expect(stdErr.String()).To(ContainSubstring("container not found: doesntexist"))
 		})
 	})
 })
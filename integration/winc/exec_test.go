@@ -3,7 +3,9 @@ package main_test
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/winc/runtime/hcsprocess"
 	"github.com/Microsoft/hcsshim"
 	acl "github.com/hectane/go-acl"
 	. "github.com/onsi/ginkgo"
@@ -201,6 +204,58 @@ var _ = Describe("Exec", func() {
 			})
 		})
 
+		Context("when the '--exec-id' flag is provided", func() {
+			It("records the process so it can be addressed by its exec id until it has been waited on", func() {
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--exec-id", "sleeper", containerId, "C:\\tmp\\sleep.exe", "5"))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "state", "--exec-id", "sleeper", containerId))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				var processState struct {
+					Pid    int    `json:"pid"`
+					Status string `json:"status"`
+					Detach bool   `json:"detach"`
+				}
+				Expect(json.Unmarshal(stdOut.Bytes(), &processState)).To(Succeed())
+				Expect(processState.Status).To(Equal("running"))
+				Expect(processState.Detach).To(BeTrue())
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "ps", "--exec-id", "sleeper", "--format", "json", containerId))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(ContainSubstring(`"exec_id":"sleeper"`))
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "wait", "--exec-id", "sleeper", containerId))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdOut.String()).To(MatchJSON(fmt.Sprintf(`{"pid": %d, "exit_code": 0}`, processState.Pid)))
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "state", "--exec-id", "sleeper", containerId))
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdErr.String()).To(ContainSubstring("has no process with exec id sleeper"))
+			})
+
+			It("errors when the exec id is already in use", func() {
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--exec-id", "sleeper", containerId, "C:\\tmp\\sleep.exe", "5"))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--exec-id", "sleeper", containerId, "C:\\tmp\\sleep.exe", "5"))
+				Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+				Expect(stdErr.String()).To(ContainSubstring("already has a process with exec id sleeper"))
+			})
+
+			It("kills the process by its exec id", func() {
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--exec-id", "sleeper", containerId, "C:\\tmp\\sleep.exe", "9999"))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "kill", "--exec-id", "sleeper", containerId, "KILL"))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				Eventually(func() []hcsshim.ProcessListItem {
+					return helpers.ContainerProcesses(containerId, "sleep.exe")
+				}, "10s").Should(BeEmpty())
+				Expect(helpers.GetContainerState(containerId).Status).To(Equal("running"))
+			})
+		})
+
 		Context("when the --detach flag is not passed", func() {
 			It("the process runs in the container and returns the exit code when the process finishes", func() {
 				cmd := exec.Command(wincBin, "exec", containerId, "cmd.exe", "/C", "exit /B 5")
@@ -234,6 +289,71 @@ var _ = Describe("Exec", func() {
 
 		})
 
+		Context("when the '--tty' and '--console-socket' flags are provided", func() {
+			var (
+				consoleSocket string
+				listener      net.Listener
+			)
+
+			BeforeEach(func() {
+				consoleSocket = filepath.Join(bundlePath, "console.sock")
+				var err error
+				listener, err = net.Listen("unix", consoleSocket)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				listener.Close()
+			})
+
+			It("relays the console of the process over the socket", func() {
+				cmd := exec.Command(wincBin, "exec", "--tty", "--console-socket", consoleSocket, containerId, "cmd.exe")
+				session, err := gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
+				Expect(err).ToNot(HaveOccurred())
+
+				conn, err := listener.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				defer conn.Close()
+
+				encoder := json.NewEncoder(conn)
+				Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleResize, Width: 100, Height: 30})).To(Succeed())
+				Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleStdin, Data: []byte("echo hey-winc\r\nexit 3\r\n")})).To(Succeed())
+
+				output := gbytes.NewBuffer()
+				go io.Copy(output, conn)
+				Eventually(output, "10s").Should(gbytes.Say("hey-winc"))
+				Eventually(session, "10s").Should(gexec.Exit(3))
+			})
+
+			Context("when the '--detach' flag is provided", func() {
+				It("returns immediately and leaves relaying the console to the monitor", func() {
+					cmd := exec.Command(wincBin, "exec", "--detach", "--tty", "--console-socket", consoleSocket, containerId, "cmd.exe")
+					stdOut, stdErr, err := helpers.Execute(cmd)
+					Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+					conn, err := listener.Accept()
+					Expect(err).NotTo(HaveOccurred())
+					defer conn.Close()
+
+					encoder := json.NewEncoder(conn)
+					Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleStdin, Data: []byte("echo hey-winc\r\nexit\r\n")})).To(Succeed())
+
+					output := gbytes.NewBuffer()
+					go io.Copy(output, conn)
+					Eventually(output, "10s").Should(gbytes.Say("hey-winc"))
+				})
+			})
+
+			Context("when the process does not use a terminal", func() {
+				It("errors", func() {
+					cmd := exec.Command(wincBin, "exec", "--console-socket", consoleSocket, containerId, "cmd.exe")
+					stdOut, stdErr, err := helpers.Execute(cmd)
+					Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+					Expect(stdErr.String()).To(ContainSubstring("requires process.terminal to be true"))
+				})
+			})
+		})
+
 		Context("when the '--pid-file' flag is provided", func() {
 			var pidFile string
 
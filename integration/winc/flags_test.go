@@ -2,6 +2,7 @@ package main_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,6 +14,8 @@ import (
 	"sync"
 	"syscall"
 
+	"code.cloudfoundry.org/winc/errcode"
+	"code.cloudfoundry.org/winc/hcs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -313,6 +316,105 @@ var _ = Describe("Flags", func() {
 		})
 	})
 
+	Context("when passed '--error-format'", func() {
+		Context("json", func() {
+			It("writes the code, message and details of the error as json", func() {
+				args := []string{"--error-format", "json", "state", "nonexistentcontainer"}
+				_, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).To(HaveOccurred())
+				Expect(helpers.ExitCode(err)).To(Equal(errcode.ExitNotFound))
+
+				var e errcode.Error
+				Expect(json.Unmarshal(stdErr.Bytes(), &e)).To(Succeed())
+				Expect(e.Code).To(Equal("container_not_found"))
+				Expect(e.Message).To(Equal("container not found: nonexistentcontainer"))
+				Expect(e.Details).To(Equal(map[string]interface{}{"id": "nonexistentcontainer"}))
+			})
+
+			It("writes errors without a code as unknown", func() {
+				args := []string{"--error-format", "json", "--log-handle", "999999", "state", "some-container"}
+				_, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).To(HaveOccurred())
+				Expect(helpers.ExitCode(err)).To(Equal(errcode.ExitInternal))
+
+				var e errcode.Error
+				Expect(json.Unmarshal(stdErr.Bytes(), &e)).To(Succeed())
+				Expect(e.Code).To(Equal(errcode.Unknown))
+			})
+		})
+
+		Context("text", func() {
+			It("writes the message of the error and exits with the code of its class", func() {
+				args := []string{"--error-format", "text", "state", "nonexistentcontainer"}
+				_, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).To(HaveOccurred())
+				Expect(helpers.ExitCode(err)).To(Equal(errcode.ExitNotFound))
+				Expect(stdErr.String()).To(Equal("container not found: nonexistentcontainer\n"))
+			})
+		})
+
+		Context("when provided an invalid error format", func() {
+			It("errors", func() {
+				args := []string{"--error-format", "xml", "state", "some-container"}
+				_, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+				Expect(err).To(HaveOccurred())
+				Expect(helpers.ExitCode(err)).To(Equal(errcode.ExitInvalid))
+				Expect(stdErr.String()).To(ContainSubstring("invalid error format xml"))
+			})
+		})
+	})
+
+	Context("when passed '--hcs-trace'", func() {
+		var (
+			containerId string
+			bundlePath  string
+			bundleSpec  specs.Spec
+			tempDir     string
+			traceFile   string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = ioutil.TempDir("", "trace-dir")
+			Expect(err).NotTo(HaveOccurred())
+
+			traceFile = filepath.Join(tempDir, "something", "hcs-trace.jsonl")
+
+			bundlePath, err = ioutil.TempDir("", "winccontainer")
+			Expect(err).To(Succeed())
+
+			containerId = filepath.Base(bundlePath)
+
+			bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+			helpers.GenerateBundle(bundleSpec, bundlePath)
+		})
+
+		AfterEach(func() {
+			helpers.DeleteContainer(containerId)
+			helpers.DeleteVolume(containerId)
+			Expect(os.RemoveAll(bundlePath)).To(Succeed())
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		It("writes a record of every call made to HCS to the trace file", func() {
+			args := []string{"--hcs-trace", traceFile, "create", containerId, "-b", bundlePath}
+			_, _, err := helpers.Execute(exec.Command(wincBin, args...))
+			Expect(err).NotTo(HaveOccurred())
+
+			trace, err := ioutil.ReadFile(traceFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			var methods []string
+			for _, line := range strings.Split(strings.TrimSpace(string(trace)), "\n") {
+				var record hcs.TraceRecord
+				Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+				methods = append(methods, record.Method)
+			}
+			Expect(methods).To(ContainElement("CreateContainer"))
+			Expect(methods).To(ContainElement("Container.Start"))
+		})
+	})
+
 	Context("when passed '--image-store'", func() {
 		var (
 			containerId string
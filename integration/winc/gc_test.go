@@ -0,0 +1,76 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("GC", func() {
+	type orphan struct {
+		Kind   string `json:"kind"`
+		ID     string `json:"id"`
+		Reason string `json:"reason"`
+		Result string `json:"result"`
+	}
+
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+		orphanDir   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+
+		orphanDir = filepath.Join("C:\\ProgramData\\winc", containerId+"-orphan")
+		Expect(os.MkdirAll(orphanDir, 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+		Expect(os.RemoveAll(orphanDir)).To(Succeed())
+	})
+
+	It("reports orphaned state directories without removing them when passed --dry-run", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "gc", "--dry-run", "--format", "json"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var orphans []orphan
+		Expect(json.Unmarshal(stdOut.Bytes(), &orphans)).To(Succeed())
+		Expect(orphans).To(ContainElement(orphan{
+			Kind:   "state",
+			ID:     containerId + "-orphan",
+			Reason: "state directory has no matching HCS container",
+			Result: "would remove",
+		}))
+		for _, o := range orphans {
+			Expect(o.ID).NotTo(Equal(containerId))
+		}
+
+		Expect(orphanDir).To(BeADirectory())
+	})
+
+	It("errors when passed an invalid format", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "gc", "--dry-run", "--format", "yaml"))
+		Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(stdErr.String()).To(ContainSubstring("invalid format yaml"))
+	})
+})
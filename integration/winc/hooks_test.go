@@ -0,0 +1,70 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Hooks", func() {
+	var (
+		containerId string
+		bundlePath  string
+		hooksDir    string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+		hooksDir, err = ioutil.TempDir("", "hooks")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+
+		cmdPath := filepath.Join(os.Getenv("SystemRoot"), "System32", "cmd.exe")
+		stateHook := func(name string) specs.Hook {
+			return specs.Hook{Path: cmdPath, Args: []string{"cmd.exe", "/C", "more > " + filepath.Join(hooksDir, name)}}
+		}
+		bundleSpec.Hooks = &specs.Hooks{
+			Prestart:  []specs.Hook{stateHook("prestart.json")},
+			Poststart: []specs.Hook{stateHook("poststart.json")},
+			Poststop:  []specs.Hook{stateHook("poststop.json")},
+		}
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+		Expect(os.RemoveAll(hooksDir)).To(Succeed())
+	})
+
+	It("runs each hook with the container state on stdin", func() {
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		Expect(readHookState(hooksDir, "prestart.json")).To(ContainSubstring(`"status":"creating"`))
+
+		helpers.StartContainer(containerId)
+		Expect(readHookState(hooksDir, "poststart.json")).To(ContainSubstring(`"status":"running"`))
+
+		helpers.DeleteContainer(containerId)
+		Expect(readHookState(hooksDir, "poststop.json")).To(ContainSubstring(`"status":"stopped"`))
+	})
+})
+
+func readHookState(dir, name string) string {
+	contents, err := ioutil.ReadFile(filepath.Join(dir, name))
+	Expect(err).NotTo(HaveOccurred())
+	return string(contents)
+}
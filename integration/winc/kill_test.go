@@ -0,0 +1,90 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Kill", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	Context("when the container is running", func() {
+		BeforeEach(func() {
+			helpers.StartContainer(containerId)
+		})
+
+		It("kills the init process with SIGKILL", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			Eventually(func() string {
+				return helpers.GetContainerState(containerId).Status
+			}).Should(Equal("stopped"))
+		})
+
+		It("kills every process in the container with --all", func() {
+			_, _, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "waitfor /t 9999 forever"}, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", "--all", containerId, "SIGKILL"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			Eventually(func() string {
+				return helpers.GetContainerState(containerId).Status
+			}).Should(Equal("stopped"))
+		})
+
+		It("shuts the container down with SIGTERM", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			Expect(helpers.GetContainerState(containerId).Status).To(Equal("stopped"))
+		})
+
+		It("errors when passed an unsupported signal", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "HUP"))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("invalid signal: HUP"))
+		})
+	})
+
+	Context("when the container has not been started", func() {
+		It("errors", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "kill", containerId, "KILL"))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cannot kill container " + containerId + " in the created state"))
+		})
+	})
+})
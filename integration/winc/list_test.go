@@ -0,0 +1,86 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("List", func() {
+	type listItem struct {
+		ID       string `json:"id"`
+		Pid      int    `json:"pid"`
+		Status   string `json:"status"`
+		Bundle   string `json:"bundle"`
+		Orphaned string `json:"orphaned"`
+	}
+
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Annotations = map[string]string{"app-guid": containerId}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("lists the container", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--format", "json"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var items []listItem
+		Expect(json.Unmarshal(stdOut.Bytes(), &items)).To(Succeed())
+		Expect(items).To(ContainElement(listItem{ID: containerId, Status: "created", Bundle: bundlePath}))
+	})
+
+	It("lists only the container ids when passed --quiet", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--quiet"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(strings.Fields(stdOut.String())).To(ContainElement(containerId))
+	})
+
+	It("lists only the containers with matching annotations when passed --filter", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--quiet", "--filter", "app-guid="+containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(strings.Fields(stdOut.String())).To(Equal([]string{containerId}))
+
+		stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "list", "--quiet", "--filter", "app-guid=some-other-app"))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(strings.Fields(stdOut.String())).NotTo(ContainElement(containerId))
+	})
+
+	It("errors when passed an invalid filter", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--filter", "app-guid"))
+		Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(stdErr.String()).To(ContainSubstring("invalid filter app-guid"))
+	})
+
+	It("errors when passed an invalid format", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "list", "--format", "yaml"))
+		Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(stdErr.String()).To(ContainSubstring("invalid format yaml"))
+	})
+})
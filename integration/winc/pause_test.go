@@ -0,0 +1,77 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("pauses and resumes the container", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "pause", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(helpers.GetContainerState(containerId).Status).To(Equal("paused"))
+
+		stdOut, stdErr, err = helpers.Execute(exec.Command(wincBin, "resume", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(helpers.GetContainerState(containerId).Status).To(Equal("running"))
+	})
+
+	Context("when the container is paused", func() {
+		BeforeEach(func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "pause", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		})
+
+		It("refuses to exec a process", func() {
+			stdOut, stdErr, err := helpers.ExecInContainer(containerId, []string{"cmd.exe", "/C", "echo hi"}, false)
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cannot exec in container " + containerId + " in the paused state"))
+		})
+
+		It("refuses to pause it again", func() {
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "pause", containerId))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring("cannot pause container " + containerId + " in the paused state"))
+		})
+
+		It("can be deleted", func() {
+			helpers.DeleteContainer(containerId)
+			Expect(helpers.ContainerExists(containerId)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,67 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Ps", func() {
+	type psItem struct {
+		Pid       uint32 `json:"pid"`
+		ImageName string `json:"image_name"`
+		Init      bool   `json:"init"`
+	}
+
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("lists the processes in the container and marks the init process", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "ps", "--format", "json", containerId))
+		Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var items []psItem
+		Expect(json.Unmarshal(stdOut.Bytes(), &items)).To(Succeed())
+
+		pid := uint32(helpers.GetContainerState(containerId).Pid)
+		Expect(items).To(ContainElement(psItem{Pid: pid, ImageName: "cmd.exe", Init: true}))
+
+		imageNames := []string{}
+		for _, item := range items {
+			imageNames = append(imageNames, item.ImageName)
+		}
+		Expect(imageNames).To(ContainElement("waitfor.exe"))
+	})
+})
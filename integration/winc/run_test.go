@@ -2,18 +2,23 @@ package main_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
@@ -153,6 +158,50 @@ var _ = Describe("Run", func() {
 				return helpers.ContainerProcesses(containerId, "cmd.exe")
 			}, "10s").Should(BeEmpty())
 		})
+
+		Context("when the process uses a terminal and the '--console-socket' flag is provided", func() {
+			var (
+				consoleSocket string
+				listener      net.Listener
+			)
+
+			BeforeEach(func() {
+				consoleSocket = filepath.Join(bundlePath, "console.sock")
+				var err error
+				listener, err = net.Listen("unix", consoleSocket)
+				Expect(err).NotTo(HaveOccurred())
+
+				bundleSpec.Process.Terminal = true
+				bundleSpec.Process.Args = []string{"cmd.exe"}
+			})
+
+			AfterEach(func() {
+				listener.Close()
+			})
+
+			It("relays the console from the monitor once winc run has exited", func() {
+				helpers.GenerateBundle(bundleSpec, bundlePath)
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "run", "-b", bundlePath, "--detach", "--console-socket", consoleSocket, containerId))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				// winc run has exited and closed its handles to the process, so
+				// only the monitor can be relaying the console
+				conn, err := listener.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				defer conn.Close()
+
+				output := gbytes.NewBuffer()
+				go io.Copy(output, conn)
+
+				encoder := json.NewEncoder(conn)
+				Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleStdin, Data: []byte("echo hey-winc\r\nexit 3\r\n")})).To(Succeed())
+				Eventually(output, "10s").Should(gbytes.Say("hey-winc"))
+
+				Eventually(func() map[string]string {
+					return helpers.GetContainerState(containerId).Annotations
+				}, "10s").Should(HaveKeyWithValue(state.ExitCodeAnnotation, "3"))
+			})
+		})
 	})
 
 	Context("when the --detach flag is not passed", func() {
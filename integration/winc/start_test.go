@@ -111,7 +111,7 @@ var _ = Describe("Start", func() {
 			_, stdErr, err := helpers.Execute(exec.Command(wincBin, "start", containerId))
 			Expect(err).To(HaveOccurred())
 
-			Expect(strings.TrimSpace(stdErr.String())).To(Equal("cannot start a container in the running state"))
+			Expect(strings.TrimSpace(stdErr.String())).To(Equal(fmt.Sprintf("cannot start container %s in the running state", containerId)))
 		})
 	})
 
@@ -130,7 +130,7 @@ var _ = Describe("Start", func() {
 			_, stdErr, err := helpers.Execute(exec.Command(wincBin, "start", containerId))
 			Expect(err).To(HaveOccurred())
 
-			Expect(strings.TrimSpace(stdErr.String())).To(Equal("cannot start a container in the stopped state"))
+			Expect(strings.TrimSpace(stdErr.String())).To(Equal(fmt.Sprintf("cannot start container %s in the stopped state", containerId)))
 		})
 	})
 
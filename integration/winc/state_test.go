@@ -6,7 +6,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"code.cloudfoundry.org/winc/runtime/state"
 	acl "github.com/hectane/go-acl"
 	ps "github.com/mitchellh/go-ps"
 	. "github.com/onsi/ginkgo"
@@ -56,6 +58,23 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Context("when the spec has annotations", func() {
+		BeforeEach(func() {
+			bundleSpec.Annotations = map[string]string{"app-guid": "some-app-guid"}
+			helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		})
+
+		It("reports them along with when the container was created", func() {
+			annotations := helpers.GetContainerState(containerId).Annotations
+			Expect(annotations).To(HaveKeyWithValue("app-guid", "some-app-guid"))
+			Expect(annotations).To(HaveKey(state.VersionAnnotation))
+
+			created, err := time.Parse(time.RFC3339Nano, annotations[state.CreatedAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
 	Context("the init process has already been started and is still running", func() {
 		BeforeEach(func() {
 			bundleSpec.Process = &specs.Process{
@@ -118,6 +137,29 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Context("the init process has exited with a non-zero exit code", func() {
+		BeforeEach(func() {
+			bundleSpec.Process = &specs.Process{
+				Cwd:  "C:\\",
+				Args: []string{"cmd.exe", "/C", "exit /B 5"},
+			}
+
+			helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+			helpers.StartContainer(containerId)
+		})
+
+		It("reports the exit code and exit time as annotations", func() {
+			Eventually(func() map[string]string {
+				return helpers.GetContainerState(containerId).Annotations
+			}, "10s").Should(HaveKeyWithValue(state.ExitCodeAnnotation, "5"))
+
+			annotations := helpers.GetContainerState(containerId).Annotations
+			exitTime, err := time.Parse(time.RFC3339Nano, annotations[state.ExitTimeAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitTime).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
 	Context("the init process failed to start", func() {
 		BeforeEach(func() {
 			bundleSpec.Process = &specs.Process{
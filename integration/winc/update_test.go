@@ -0,0 +1,80 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/state"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Update", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 9999 forever"},
+		}
+		helpers.CreateContainer(bundleSpec, bundlePath, containerId)
+		helpers.StartContainer(containerId)
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	It("reports which limits HCS accepted and records them in the state", func() {
+		stdOut, stdErr, _ := helpers.Execute(exec.Command(wincBin, "update", "--memory", "1073741824", containerId))
+
+		var result container.UpdateResult
+		Expect(json.Unmarshal(stdOut.Bytes(), &result)).To(Succeed(), stdErr.String())
+
+		annotations := helpers.GetContainerState(containerId).Annotations
+		if result.Accepted.Memory != nil {
+			Expect(annotations).To(HaveKeyWithValue(state.MemoryLimitAnnotation, "1073741824"))
+		} else {
+			Expect(result.Rejected).To(HaveKey("memory.limit"))
+			Expect(stdErr.String()).To(ContainSubstring("rejected resource updates: memory.limit"))
+		}
+	})
+
+	It("rejects storage limits", func() {
+		resourcesFile := filepath.Join(bundlePath, "resources.json")
+		Expect(ioutil.WriteFile(resourcesFile, []byte(`{"storage": {"iops": 100}}`), 0644)).To(Succeed())
+
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", "-r", resourcesFile, containerId))
+		Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+
+		var result container.UpdateResult
+		Expect(json.Unmarshal(stdOut.Bytes(), &result)).To(Succeed())
+		Expect(result.Rejected).To(HaveKey("storage.iops"))
+		Expect(helpers.GetContainerState(containerId).Annotations).NotTo(HaveKey(state.StorageIopsAnnotation))
+	})
+
+	It("errors when no resources are given", func() {
+		stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "update", containerId))
+		Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+		Expect(stdErr.String()).To(ContainSubstring("no resources specified"))
+	})
+})
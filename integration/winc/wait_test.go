@@ -0,0 +1,141 @@
+package main_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"code.cloudfoundry.org/winc/errcode"
+	"code.cloudfoundry.org/winc/runtime/state"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Wait", func() {
+	var (
+		containerId string
+		bundlePath  string
+		bundleSpec  specs.Spec
+	)
+
+	BeforeEach(func() {
+		var err error
+		bundlePath, err = ioutil.TempDir("", "winccontainer")
+		Expect(err).To(Succeed())
+
+		containerId = filepath.Base(bundlePath)
+
+		bundleSpec = helpers.GenerateRuntimeSpec(helpers.CreateVolume(rootfsURI, containerId))
+		bundleSpec.Process = &specs.Process{
+			Cwd:  "C:\\",
+			Args: []string{"cmd.exe", "/C", "waitfor /t 3 forever & exit /B 7"},
+		}
+	})
+
+	AfterEach(func() {
+		failed = failed || CurrentGinkgoTestDescription().Failed
+		helpers.DeleteContainer(containerId)
+		helpers.DeleteVolume(containerId)
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
+	Context("when the container was run detached", func() {
+		BeforeEach(func() {
+			helpers.GenerateBundle(bundleSpec, bundlePath)
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "run", "-b", bundlePath, "--detach", containerId))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+		})
+
+		It("blocks until the init process exits and exits with its exit code", func() {
+			session, err := gexec.Start(exec.Command(wincBin, "wait", containerId), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(session, "1s").ShouldNot(gexec.Exit())
+			Eventually(session, "15s").Should(gexec.Exit(7))
+
+			var exit struct {
+				ExitCode int `json:"exit_code"`
+			}
+			Expect(json.Unmarshal(session.Out.Contents(), &exit)).To(Succeed())
+			Expect(exit.ExitCode).To(Equal(7))
+		})
+
+		Context("when the init process has already exited", func() {
+			BeforeEach(func() {
+				helpers.TheProcessExits(containerId, "cmd.exe")
+				Eventually(func() map[string]string {
+					return helpers.GetContainerState(containerId).Annotations
+				}, "10s").Should(HaveKey(state.ExitCodeAnnotation))
+			})
+
+			It("returns the recorded exit code", func() {
+				session, err := gexec.Start(exec.Command(wincBin, "wait", containerId), GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(session, "15s").Should(gexec.Exit(7))
+			})
+		})
+
+		Context("when the timeout expires first", func() {
+			It("errors", func() {
+				session, err := gexec.Start(exec.Command(wincBin, "wait", "--timeout", "100ms", containerId), GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(session).Should(gexec.Exit(errcode.ExitTimeout))
+				Expect(session.Err).To(gbytes.Say("timed out after 100ms"))
+			})
+
+			It("writes the error to stderr as json when passed '--error-format json'", func() {
+				session, err := gexec.Start(exec.Command(wincBin, "--error-format", "json", "wait", "--timeout", "100ms", containerId), GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(session).Should(gexec.Exit(errcode.ExitTimeout))
+				Expect(session.Out.Contents()).To(BeEmpty())
+
+				var e errcode.Error
+				Expect(json.Unmarshal(session.Err.Contents(), &e)).To(Succeed())
+				Expect(e.Code).To(Equal("wait_timeout"))
+			})
+		})
+	})
+
+	Context("when waiting on an exec'd process", func() {
+		BeforeEach(func() {
+			bundleSpec.Process.Args = []string{"cmd.exe", "/C", "waitfor /t 9999 forever"}
+			helpers.RunContainer(bundleSpec, bundlePath, containerId)
+		})
+
+		It("exits with the exit code of that process", func() {
+			pidFile := filepath.Join(bundlePath, "exec.pid")
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--pid-file", pidFile, containerId, "cmd.exe", "/C", "waitfor /t 2 forever & exit /B 4"))
+			Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+			contents, err := ioutil.ReadFile(pidFile)
+			Expect(err).NotTo(HaveOccurred())
+			pid, err := strconv.Atoi(string(contents))
+			Expect(err).NotTo(HaveOccurred())
+
+			session, err := gexec.Start(exec.Command(wincBin, "wait", "--exec-pid", strconv.Itoa(pid), containerId), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session, "15s").Should(gexec.Exit(4))
+		})
+
+		Context("when the process has already exited", func() {
+			It("exits with the exit code recorded for that process", func() {
+				stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "exec", "--detach", "--exec-id", "short-lived", containerId, "cmd.exe", "/C", "exit /B 4"))
+				Expect(err).NotTo(HaveOccurred(), stdOut.String(), stdErr.String())
+
+				Eventually(func() string {
+					stdOut, _, _ := helpers.Execute(exec.Command(wincBin, "state", "--exec-id", "short-lived", containerId))
+					return stdOut.String()
+				}, "10s").Should(ContainSubstring(`"exit_code": 4`))
+
+				session, err := gexec.Start(exec.Command(wincBin, "wait", "--exec-id", "short-lived", containerId), GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(session, "15s").Should(gexec.Exit(4))
+			})
+		})
+	})
+})
@@ -39,6 +39,8 @@ var (
 )
 
 type wincStats struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
 	Data struct {
 		CPUStats struct {
 			CPUUsage struct {
@@ -48,14 +50,26 @@ type wincStats struct {
 			} `json:"usage"`
 		} `json:"cpu"`
 		Memory struct {
+			Usage struct {
+				Limit uint64 `json:"limit"`
+				Usage uint64 `json:"usage"`
+				Max   uint64 `json:"max"`
+			} `json:"usage"`
 			Stats struct {
-				TotalRss uint64 `json:"total_rss"`
+				TotalRss          uint64 `json:"total_rss"`
+				PrivateWorkingSet uint64 `json:"private_working_set"`
 			} `json:"raw"`
 		} `json:"memory"`
 		Pids struct {
 			Current uint64 `json:"current,omitempty"`
 			Limit   uint64 `json:"limit,omitempty"`
 		} `json:"pids"`
+		Blkio struct {
+			IoServiceBytesRecursive []struct {
+				Op    string `json:"op"`
+				Value uint64 `json:"value"`
+			} `json:"ioServiceBytesRecursive"`
+		} `json:"blkio"`
 	} `json:"data"`
 }
 
@@ -145,5 +159,6 @@ func getStats(containerId string) wincStats {
 	stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, "events", "--stats", containerId))
 	Expect(err).To(Succeed(), stdOut.String(), stdErr.String())
 	Expect(json.Unmarshal(stdOut.Bytes(), &stats)).To(Succeed())
+	Expect(stats.Type).To(Equal("stats"))
 	return stats
 }
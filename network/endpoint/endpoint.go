@@ -5,21 +5,21 @@ import (
 	"fmt"
 	"strings"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netinterface"
-	"github.com/Microsoft/hcsshim"
 	"github.com/sirupsen/logrus"
 )
 
 //go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
-	GetHNSNetworkByName(string) (*hcsshim.HNSNetwork, error)
-	CreateEndpoint(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
-	UpdateEndpoint(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
-	GetHNSEndpointByID(string) (*hcsshim.HNSEndpoint, error)
-	GetHNSEndpointByName(string) (*hcsshim.HNSEndpoint, error)
-	DeleteEndpoint(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	GetHNSNetworkByName(string) (*hcs.HNSNetwork, error)
+	CreateEndpoint(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
+	UpdateEndpoint(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
+	GetHNSEndpointByID(string) (*hcs.HNSEndpoint, error)
+	GetHNSEndpointByName(string) (*hcs.HNSEndpoint, error)
+	DeleteEndpoint(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
 	HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error
 	HotDetachEndpoint(containerID string, endpointID string) error
 }
@@ -38,24 +38,24 @@ func NewEndpointManager(hcsClient HCSClient, containerId string, config network.
 	}
 }
 
-func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
+func (e *EndpointManager) Create() (hcs.HNSEndpoint, error) {
 	network, err := e.hcsClient.GetHNSNetworkByName(e.config.NetworkName)
 	if err != nil {
-		return hcsshim.HNSEndpoint{}, err
+		return hcs.HNSEndpoint{}, err
 	}
 
-	endpoint := &hcsshim.HNSEndpoint{
+	endpoint := &hcs.HNSEndpoint{
 		VirtualNetwork: network.Id,
 		Name:           e.containerId,
 	}
 
 	if e.config.MaximumOutgoingBandwidth != 0 {
-		policy, err := json.Marshal(hcsshim.QosPolicy{
-			Type: hcsshim.QOS,
+		policy, err := json.Marshal(hcs.QosPolicy{
+			Type:                            hcs.QOS,
 			MaximumOutgoingBandwidthInBytes: uint64(e.config.MaximumOutgoingBandwidth),
 		})
 		if err != nil {
-			return hcsshim.HNSEndpoint{}, err
+			return hcs.HNSEndpoint{}, err
 		}
 
 		endpoint.Policies = []json.RawMessage{policy}
@@ -67,7 +67,7 @@ func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
 
 	createdEndpoint, err := e.createEndpoint(endpoint)
 	if err != nil {
-		return hcsshim.HNSEndpoint{}, err
+		return hcs.HNSEndpoint{}, err
 	}
 
 	attachedEndpoint, err := e.attachEndpoint(createdEndpoint)
@@ -76,13 +76,13 @@ func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
 			logrus.Error(fmt.Sprintf("Error deleting endpoint %s: %s", endpoint.Id, err.Error()))
 		}
 
-		return hcsshim.HNSEndpoint{}, err
+		return hcs.HNSEndpoint{}, err
 	}
 
 	return *attachedEndpoint, nil
 }
 
-func (e *EndpointManager) attachEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+func (e *EndpointManager) attachEndpoint(endpoint *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
 	endpointReady := func() (bool, error) {
 		interfaceAlias := fmt.Sprintf("vEthernet (%s)", e.containerId)
 		return netinterface.InterfaceExists(interfaceAlias)
@@ -101,22 +101,22 @@ func (e *EndpointManager) attachEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshi
 	return allocatedEndpoint, nil
 }
 
-func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hcsshim.NatPolicy, acls []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
+func (e *EndpointManager) ApplyPolicies(endpoint hcs.HNSEndpoint, nats []*hcs.NatPolicy, acls []*hcs.ACLPolicy) (hcs.HNSEndpoint, error) {
 	var policies []json.RawMessage
 
 	if len(acls) == 0 {
 		// make sure everything's blocked if no netout rules present
-		acls = []*hcsshim.ACLPolicy{
+		acls = []*hcs.ACLPolicy{
 			{
-				Type:      hcsshim.ACL,
-				Action:    hcsshim.Block,
-				Direction: hcsshim.Out,
+				Type:      hcs.ACL,
+				Action:    hcs.Block,
+				Direction: hcs.Out,
 				Protocol:  uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 			},
 			{
-				Type:      hcsshim.ACL,
-				Action:    hcsshim.Block,
-				Direction: hcsshim.In,
+				Type:      hcs.ACL,
+				Action:    hcs.Block,
+				Direction: hcs.In,
 				Protocol:  uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 			},
 		}
@@ -125,7 +125,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 	for _, acl := range acls {
 		policy, err := json.Marshal(acl)
 		if err != nil {
-			return hcsshim.HNSEndpoint{}, err
+			return hcs.HNSEndpoint{}, err
 		}
 		policies = append(policies, policy)
 	}
@@ -133,7 +133,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 	for _, nat := range nats {
 		policy, err := json.Marshal(nat)
 		if err != nil {
-			return hcsshim.HNSEndpoint{}, err
+			return hcs.HNSEndpoint{}, err
 		}
 		policies = append(policies, policy)
 	}
@@ -142,7 +142,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 
 	updatedEndpoint, err := e.hcsClient.UpdateEndpoint(&endpoint)
 	if err != nil {
-		return hcsshim.HNSEndpoint{}, err
+		return hcs.HNSEndpoint{}, err
 	}
 
 	return *updatedEndpoint, nil
@@ -151,7 +151,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 func (e *EndpointManager) Delete() error {
 	endpoint, err := e.hcsClient.GetHNSEndpointByName(e.containerId)
 	if err != nil {
-		if _, ok := err.(hcsshim.EndpointNotFoundError); ok {
+		if _, ok := err.(hcs.EndpointNotFoundError); ok {
 			return nil
 		}
 
@@ -160,7 +160,7 @@ func (e *EndpointManager) Delete() error {
 
 	var detachErr error
 	err = e.hcsClient.HotDetachEndpoint(e.containerId, endpoint.Id)
-	if err != hcsshim.ErrComputeSystemDoesNotExist {
+	if err != hcs.ErrComputeSystemDoesNotExist {
 		detachErr = err
 	}
 
@@ -181,9 +181,9 @@ func (e *EndpointManager) Delete() error {
 	return nil
 }
 
-func (e *EndpointManager) createEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+func (e *EndpointManager) createEndpoint(endpoint *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
 	var createErr error
-	var createdEndpoint *hcsshim.HNSEndpoint
+	var createdEndpoint *hcs.HNSEndpoint
 	for i := 0; i < 3 && createdEndpoint == nil; i++ {
 		createdEndpoint, createErr = e.hcsClient.CreateEndpoint(endpoint)
 		if createErr != nil {
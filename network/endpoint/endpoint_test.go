@@ -5,10 +5,10 @@ import (
 	"errors"
 	"io/ioutil"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/endpoint"
 	"code.cloudfoundry.org/winc/network/endpoint/fakes"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
@@ -42,9 +42,9 @@ var _ = Describe("EndpointManager", func() {
 
 	Describe("Create", func() {
 		BeforeEach(func() {
-			hcsClient.GetHNSNetworkByNameReturns(&hcsshim.HNSNetwork{Id: networkId, Name: networkName}, nil)
-			hcsClient.CreateEndpointReturns(&hcsshim.HNSEndpoint{Id: endpointId}, nil)
-			hcsClient.GetHNSEndpointByIDReturns(&hcsshim.HNSEndpoint{
+			hcsClient.GetHNSNetworkByNameReturns(&hcs.HNSNetwork{Id: networkId, Name: networkName}, nil)
+			hcsClient.CreateEndpointReturns(&hcs.HNSEndpoint{Id: endpointId}, nil)
+			hcsClient.GetHNSEndpointByIDReturns(&hcs.HNSEndpoint{
 				Id: endpointId,
 			}, nil)
 		})
@@ -80,21 +80,21 @@ var _ = Describe("EndpointManager", func() {
 				endpointToCreate := hcsClient.CreateEndpointArgsForCall(0)
 				requestedPolicies := endpointToCreate.Policies
 				Expect(len(requestedPolicies)).To(Equal(1))
-				var qos hcsshim.QosPolicy
+				var qos hcs.QosPolicy
 				Expect(json.Unmarshal(requestedPolicies[0], &qos)).To(Succeed())
-				Expect(qos.Type).To(Equal(hcsshim.QOS))
+				Expect(qos.Type).To(Equal(hcs.QOS))
 				Expect(qos.MaximumOutgoingBandwidthInBytes).To(Equal(uint64(9988)))
 			})
 		})
 
 		Context("the network does not already exist", func() {
 			BeforeEach(func() {
-				hcsClient.GetHNSNetworkByNameReturns(nil, hcsshim.NetworkNotFoundError{NetworkName: networkName})
+				hcsClient.GetHNSNetworkByNameReturns(nil, hcs.NetworkNotFoundError{NetworkName: networkName})
 			})
 
 			It("returns an error", func() {
 				_, err := endpointManager.Create()
-				Expect(err).To(BeAssignableToTypeOf(hcsshim.NetworkNotFoundError{}))
+				Expect(err).To(BeAssignableToTypeOf(hcs.NetworkNotFoundError{}))
 			})
 		})
 
@@ -103,7 +103,7 @@ var _ = Describe("EndpointManager", func() {
 				BeforeEach(func() {
 					hcsClient.CreateEndpointReturnsOnCall(0, nil, errors.New("HNS failed with error : Unspecified error"))
 					hcsClient.CreateEndpointReturnsOnCall(1, nil, errors.New("HNS failed with error : Unspecified error"))
-					hcsClient.CreateEndpointReturnsOnCall(2, &hcsshim.HNSEndpoint{Id: endpointId}, nil)
+					hcsClient.CreateEndpointReturnsOnCall(2, &hcs.HNSEndpoint{Id: endpointId}, nil)
 				})
 
 				It("retries creating the endpoint", func() {
@@ -171,26 +171,26 @@ var _ = Describe("EndpointManager", func() {
 
 	Describe("ApplyPolicies", func() {
 		var (
-			nat1            *hcsshim.NatPolicy
-			nat2            *hcsshim.NatPolicy
-			acl1            *hcsshim.ACLPolicy
-			acl2            *hcsshim.ACLPolicy
-			endpoint        hcsshim.HNSEndpoint
-			updatedEndpoint hcsshim.HNSEndpoint
+			nat1            *hcs.NatPolicy
+			nat2            *hcs.NatPolicy
+			acl1            *hcs.ACLPolicy
+			acl2            *hcs.ACLPolicy
+			endpoint        hcs.HNSEndpoint
+			updatedEndpoint hcs.HNSEndpoint
 		)
 
 		BeforeEach(func() {
-			nat1 = &hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 111, ExternalPort: 222}
-			nat2 = &hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 333, ExternalPort: 444}
+			nat1 = &hcs.NatPolicy{Type: hcs.Nat, Protocol: "TCP", InternalPort: 111, ExternalPort: 222}
+			nat2 = &hcs.NatPolicy{Type: hcs.Nat, Protocol: "TCP", InternalPort: 333, ExternalPort: 444}
 
-			acl1 = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Allow, LocalPorts: "111"}
-			acl2 = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Allow, LocalPorts: "333"}
+			acl1 = &hcs.ACLPolicy{Type: hcs.ACL, Direction: hcs.In, Action: hcs.Allow, LocalPorts: "111"}
+			acl2 = &hcs.ACLPolicy{Type: hcs.ACL, Direction: hcs.In, Action: hcs.Allow, LocalPorts: "333"}
 
-			endpoint = hcsshim.HNSEndpoint{
+			endpoint = hcs.HNSEndpoint{
 				Id:       endpointId,
 				Policies: []json.RawMessage{[]byte("existing policy")},
 			}
-			updatedEndpoint = hcsshim.HNSEndpoint{
+			updatedEndpoint = hcs.HNSEndpoint{
 				Id:       endpointId,
 				Policies: []json.RawMessage{[]byte("policies marshalled to json")},
 			}
@@ -199,7 +199,7 @@ var _ = Describe("EndpointManager", func() {
 		})
 
 		It("updates the endpoint with the given port mappings", func() {
-			ep, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{nat1, nat2}, []*hcsshim.ACLPolicy{acl1, acl2})
+			ep, err := endpointManager.ApplyPolicies(endpoint, []*hcs.NatPolicy{nat1, nat2}, []*hcs.ACLPolicy{acl1, acl2})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(ep).To(Equal(updatedEndpoint))
 
@@ -209,42 +209,42 @@ var _ = Describe("EndpointManager", func() {
 			Expect(len(endpointToUpdate.Policies)).To(Equal(5))
 			Expect(endpointToUpdate.Policies[0]).To(Equal(json.RawMessage("existing policy")))
 
-			requestedNats := []hcsshim.NatPolicy{}
-			requestedAcls := []hcsshim.ACLPolicy{}
+			requestedNats := []hcs.NatPolicy{}
+			requestedAcls := []hcs.ACLPolicy{}
 
 			for _, pol := range endpointToUpdate.Policies[1:] {
-				p := hcsshim.Policy{}
-				nat := hcsshim.NatPolicy{}
-				acl := hcsshim.ACLPolicy{}
+				p := hcs.Policy{}
+				nat := hcs.NatPolicy{}
+				acl := hcs.ACLPolicy{}
 
 				Expect(json.Unmarshal(pol, &p)).To(Succeed())
 
-				if p.Type == hcsshim.Nat {
+				if p.Type == hcs.Nat {
 					Expect(json.Unmarshal(pol, &nat)).To(Succeed())
 					requestedNats = append(requestedNats, nat)
 				}
 
-				if p.Type == hcsshim.ACL {
+				if p.Type == hcs.ACL {
 					Expect(json.Unmarshal(pol, &acl)).To(Succeed())
 					requestedAcls = append(requestedAcls, acl)
 				}
 			}
-			expectedNats := []hcsshim.NatPolicy{
+			expectedNats := []hcs.NatPolicy{
 				{Type: "NAT", Protocol: "TCP", InternalPort: 111, ExternalPort: 222},
 				{Type: "NAT", Protocol: "TCP", InternalPort: 333, ExternalPort: 444},
 			}
 			Expect(requestedNats).To(ConsistOf(expectedNats))
 
-			expectedAcls := []hcsshim.ACLPolicy{
-				{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Allow, LocalPorts: "111"},
-				{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Allow, LocalPorts: "333"},
+			expectedAcls := []hcs.ACLPolicy{
+				{Type: hcs.ACL, Direction: hcs.In, Action: hcs.Allow, LocalPorts: "111"},
+				{Type: hcs.ACL, Direction: hcs.In, Action: hcs.Allow, LocalPorts: "333"},
 			}
 			Expect(requestedAcls).To(ConsistOf(expectedAcls))
 		})
 
 		Context("no HNS ACLs are provided", func() {
 			It("generates default block all ACL policies", func() {
-				ep, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{nat1, nat2}, []*hcsshim.ACLPolicy{})
+				ep, err := endpointManager.ApplyPolicies(endpoint, []*hcs.NatPolicy{nat1, nat2}, []*hcs.ACLPolicy{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ep).To(Equal(updatedEndpoint))
 
@@ -252,23 +252,23 @@ var _ = Describe("EndpointManager", func() {
 				Expect(len(endpointToUpdate.Policies)).To(Equal(5))
 				Expect(endpointToUpdate.Policies[0]).To(Equal(json.RawMessage("existing policy")))
 
-				requestedAcls := []hcsshim.ACLPolicy{}
+				requestedAcls := []hcs.ACLPolicy{}
 
 				for _, pol := range endpointToUpdate.Policies[1:] {
-					p := hcsshim.Policy{}
-					acl := hcsshim.ACLPolicy{}
+					p := hcs.Policy{}
+					acl := hcs.ACLPolicy{}
 
 					Expect(json.Unmarshal(pol, &p)).To(Succeed())
 
-					if p.Type == hcsshim.ACL {
+					if p.Type == hcs.ACL {
 						Expect(json.Unmarshal(pol, &acl)).To(Succeed())
 						requestedAcls = append(requestedAcls, acl)
 					}
 				}
 
-				expectedAcls := []hcsshim.ACLPolicy{
-					{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Block, Protocol: 256},
-					{Type: hcsshim.ACL, Direction: hcsshim.Out, Action: hcsshim.Block, Protocol: 256},
+				expectedAcls := []hcs.ACLPolicy{
+					{Type: hcs.ACL, Direction: hcs.In, Action: hcs.Block, Protocol: 256},
+					{Type: hcs.ACL, Direction: hcs.Out, Action: hcs.Block, Protocol: 256},
 				}
 				Expect(requestedAcls).To(ConsistOf(expectedAcls))
 			})
@@ -276,7 +276,7 @@ var _ = Describe("EndpointManager", func() {
 
 		Context("no HNS Nat policies are provided", func() {
 			It("still updates the endpoint", func() {
-				ep, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{}, []*hcsshim.ACLPolicy{})
+				ep, err := endpointManager.ApplyPolicies(endpoint, []*hcs.NatPolicy{}, []*hcs.ACLPolicy{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ep).To(Equal(updatedEndpoint))
 
@@ -290,17 +290,17 @@ var _ = Describe("EndpointManager", func() {
 			})
 
 			It("does not retry", func() {
-				_, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{}, []*hcsshim.ACLPolicy{})
+				_, err := endpointManager.ApplyPolicies(endpoint, []*hcs.NatPolicy{}, []*hcs.ACLPolicy{})
 				Expect(err).To(MatchError("cannot update endpoint"))
 			})
 		})
 	})
 
 	Describe("Delete", func() {
-		var endpoint *hcsshim.HNSEndpoint
+		var endpoint *hcs.HNSEndpoint
 
 		BeforeEach(func() {
-			endpoint = &hcsshim.HNSEndpoint{Id: endpointId}
+			endpoint = &hcs.HNSEndpoint{Id: endpointId}
 			hcsClient.GetHNSEndpointByNameReturns(endpoint, nil)
 		})
 
@@ -318,7 +318,7 @@ var _ = Describe("EndpointManager", func() {
 
 		Context("the endpoint doesn't exist", func() {
 			BeforeEach(func() {
-				hcsClient.GetHNSEndpointByNameReturns(nil, hcsshim.EndpointNotFoundError{EndpointName: containerId})
+				hcsClient.GetHNSEndpointByNameReturns(nil, hcs.EndpointNotFoundError{EndpointName: containerId})
 			})
 
 			It("returns immediately without an error", func() {
@@ -342,7 +342,7 @@ var _ = Describe("EndpointManager", func() {
 
 		Context("the container doesn't exist", func() {
 			BeforeEach(func() {
-				hcsClient.HotDetachEndpointReturns(hcsshim.ErrComputeSystemDoesNotExist)
+				hcsClient.HotDetachEndpointReturns(hcs.ErrComputeSystemDoesNotExist)
 			})
 
 			It("still deletes the endpoint", func() {
@@ -4,87 +4,87 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/endpoint"
-	"github.com/Microsoft/hcsshim"
 )
 
 type HCSClient struct {
-	GetHNSNetworkByNameStub        func(string) (*hcsshim.HNSNetwork, error)
+	GetHNSNetworkByNameStub        func(string) (*hcs.HNSNetwork, error)
 	getHNSNetworkByNameMutex       sync.RWMutex
 	getHNSNetworkByNameArgsForCall []struct {
 		arg1 string
 	}
 	getHNSNetworkByNameReturns struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
 	getHNSNetworkByNameReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
-	CreateEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	CreateEndpointStub        func(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
 	createEndpointMutex       sync.RWMutex
 	createEndpointArgsForCall []struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}
 	createEndpointReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	createEndpointReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
-	UpdateEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	UpdateEndpointStub        func(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
 	updateEndpointMutex       sync.RWMutex
 	updateEndpointArgsForCall []struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}
 	updateEndpointReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	updateEndpointReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
-	GetHNSEndpointByIDStub        func(string) (*hcsshim.HNSEndpoint, error)
+	GetHNSEndpointByIDStub        func(string) (*hcs.HNSEndpoint, error)
 	getHNSEndpointByIDMutex       sync.RWMutex
 	getHNSEndpointByIDArgsForCall []struct {
 		arg1 string
 	}
 	getHNSEndpointByIDReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	getHNSEndpointByIDReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
-	GetHNSEndpointByNameStub        func(string) (*hcsshim.HNSEndpoint, error)
+	GetHNSEndpointByNameStub        func(string) (*hcs.HNSEndpoint, error)
 	getHNSEndpointByNameMutex       sync.RWMutex
 	getHNSEndpointByNameArgsForCall []struct {
 		arg1 string
 	}
 	getHNSEndpointByNameReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	getHNSEndpointByNameReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
-	DeleteEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	DeleteEndpointStub        func(*hcs.HNSEndpoint) (*hcs.HNSEndpoint, error)
 	deleteEndpointMutex       sync.RWMutex
 	deleteEndpointArgsForCall []struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}
 	deleteEndpointReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	deleteEndpointReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	HotAttachEndpointStub        func(containerID string, endpointID string, endpointReady func() (bool, error)) error
@@ -116,7 +116,7 @@ type HCSClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcsshim.HNSNetwork, error) {
+func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcs.HNSNetwork, error) {
 	fake.getHNSNetworkByNameMutex.Lock()
 	ret, specificReturn := fake.getHNSNetworkByNameReturnsOnCall[len(fake.getHNSNetworkByNameArgsForCall)]
 	fake.getHNSNetworkByNameArgsForCall = append(fake.getHNSNetworkByNameArgsForCall, struct {
@@ -145,33 +145,33 @@ func (fake *HCSClient) GetHNSNetworkByNameArgsForCall(i int) string {
 	return fake.getHNSNetworkByNameArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcs.HNSNetwork, result2 error) {
 	fake.GetHNSNetworkByNameStub = nil
 	fake.getHNSNetworkByNameReturns = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcs.HNSNetwork, result2 error) {
 	fake.GetHNSNetworkByNameStub = nil
 	if fake.getHNSNetworkByNameReturnsOnCall == nil {
 		fake.getHNSNetworkByNameReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSNetwork
+			result1 *hcs.HNSNetwork
 			result2 error
 		})
 	}
 	fake.getHNSNetworkByNameReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) CreateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) CreateEndpoint(arg1 *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
 	fake.createEndpointMutex.Lock()
 	ret, specificReturn := fake.createEndpointReturnsOnCall[len(fake.createEndpointArgsForCall)]
 	fake.createEndpointArgsForCall = append(fake.createEndpointArgsForCall, struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}{arg1})
 	fake.recordInvocation("CreateEndpoint", []interface{}{arg1})
 	fake.createEndpointMutex.Unlock()
@@ -190,39 +190,39 @@ func (fake *HCSClient) CreateEndpointCallCount() int {
 	return len(fake.createEndpointArgsForCall)
 }
 
-func (fake *HCSClient) CreateEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
+func (fake *HCSClient) CreateEndpointArgsForCall(i int) *hcs.HNSEndpoint {
 	fake.createEndpointMutex.RLock()
 	defer fake.createEndpointMutex.RUnlock()
 	return fake.createEndpointArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) CreateEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) CreateEndpointReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.CreateEndpointStub = nil
 	fake.createEndpointReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) CreateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) CreateEndpointReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.CreateEndpointStub = nil
 	if fake.createEndpointReturnsOnCall == nil {
 		fake.createEndpointReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.createEndpointReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) UpdateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) UpdateEndpoint(arg1 *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
 	fake.updateEndpointMutex.Lock()
 	ret, specificReturn := fake.updateEndpointReturnsOnCall[len(fake.updateEndpointArgsForCall)]
 	fake.updateEndpointArgsForCall = append(fake.updateEndpointArgsForCall, struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}{arg1})
 	fake.recordInvocation("UpdateEndpoint", []interface{}{arg1})
 	fake.updateEndpointMutex.Unlock()
@@ -241,35 +241,35 @@ func (fake *HCSClient) UpdateEndpointCallCount() int {
 	return len(fake.updateEndpointArgsForCall)
 }
 
-func (fake *HCSClient) UpdateEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
+func (fake *HCSClient) UpdateEndpointArgsForCall(i int) *hcs.HNSEndpoint {
 	fake.updateEndpointMutex.RLock()
 	defer fake.updateEndpointMutex.RUnlock()
 	return fake.updateEndpointArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) UpdateEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) UpdateEndpointReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.UpdateEndpointStub = nil
 	fake.updateEndpointReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) UpdateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) UpdateEndpointReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.UpdateEndpointStub = nil
 	if fake.updateEndpointReturnsOnCall == nil {
 		fake.updateEndpointReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.updateEndpointReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSEndpointByID(arg1 string) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) GetHNSEndpointByID(arg1 string) (*hcs.HNSEndpoint, error) {
 	fake.getHNSEndpointByIDMutex.Lock()
 	ret, specificReturn := fake.getHNSEndpointByIDReturnsOnCall[len(fake.getHNSEndpointByIDArgsForCall)]
 	fake.getHNSEndpointByIDArgsForCall = append(fake.getHNSEndpointByIDArgsForCall, struct {
@@ -298,29 +298,29 @@ func (fake *HCSClient) GetHNSEndpointByIDArgsForCall(i int) string {
 	return fake.getHNSEndpointByIDArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetHNSEndpointByIDReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByIDReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByIDStub = nil
 	fake.getHNSEndpointByIDReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSEndpointByIDReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByIDReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByIDStub = nil
 	if fake.getHNSEndpointByIDReturnsOnCall == nil {
 		fake.getHNSEndpointByIDReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.getHNSEndpointByIDReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcs.HNSEndpoint, error) {
 	fake.getHNSEndpointByNameMutex.Lock()
 	ret, specificReturn := fake.getHNSEndpointByNameReturnsOnCall[len(fake.getHNSEndpointByNameArgsForCall)]
 	fake.getHNSEndpointByNameArgsForCall = append(fake.getHNSEndpointByNameArgsForCall, struct {
@@ -349,33 +349,33 @@ func (fake *HCSClient) GetHNSEndpointByNameArgsForCall(i int) string {
 	return fake.getHNSEndpointByNameArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByNameStub = nil
 	fake.getHNSEndpointByNameReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByNameStub = nil
 	if fake.getHNSEndpointByNameReturnsOnCall == nil {
 		fake.getHNSEndpointByNameReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.getHNSEndpointByNameReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) DeleteEndpoint(arg1 *hcs.HNSEndpoint) (*hcs.HNSEndpoint, error) {
 	fake.deleteEndpointMutex.Lock()
 	ret, specificReturn := fake.deleteEndpointReturnsOnCall[len(fake.deleteEndpointArgsForCall)]
 	fake.deleteEndpointArgsForCall = append(fake.deleteEndpointArgsForCall, struct {
-		arg1 *hcsshim.HNSEndpoint
+		arg1 *hcs.HNSEndpoint
 	}{arg1})
 	fake.recordInvocation("DeleteEndpoint", []interface{}{arg1})
 	fake.deleteEndpointMutex.Unlock()
@@ -394,30 +394,30 @@ func (fake *HCSClient) DeleteEndpointCallCount() int {
 	return len(fake.deleteEndpointArgsForCall)
 }
 
-func (fake *HCSClient) DeleteEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
+func (fake *HCSClient) DeleteEndpointArgsForCall(i int) *hcs.HNSEndpoint {
 	fake.deleteEndpointMutex.RLock()
 	defer fake.deleteEndpointMutex.RUnlock()
 	return fake.deleteEndpointArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) DeleteEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) DeleteEndpointReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.DeleteEndpointStub = nil
 	fake.deleteEndpointReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) DeleteEndpointReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.DeleteEndpointStub = nil
 	if fake.deleteEndpointReturnsOnCall == nil {
 		fake.deleteEndpointReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.deleteEndpointReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
@@ -3,7 +3,8 @@ package network
 import (
 	"fmt"
 
-	"github.com/Microsoft/hcsshim"
+	"code.cloudfoundry.org/winc/errcode"
+	"code.cloudfoundry.org/winc/hcs"
 )
 
 type NoNATNetworkError struct {
@@ -14,11 +15,19 @@ func (e *NoNATNetworkError) Error() string {
 	return fmt.Sprintf("could not load nat network: %s", e.Name)
 }
 
+func (e *NoNATNetworkError) Code() errcode.Code {
+	return errcode.Code{Name: "nat_network_not_found", Class: errcode.ClassNotFound}
+}
+
 type SameNATNetworkNameError struct {
 	Name    string
-	Subnets []hcsshim.Subnet
+	Subnets []hcs.Subnet
 }
 
 func (e *SameNATNetworkNameError) Error() string {
 	return fmt.Sprintf("nat network %s exists with subnets %+v", e.Name, e.Subnets)
 }
+
+func (e *SameNATNetworkNameError) Code() errcode.Code {
+	return errcode.Code{Name: "nat_network_exists", Class: errcode.ClassConflict}
+}
@@ -4,20 +4,20 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
-	"github.com/Microsoft/hcsshim"
 )
 
 type EndpointManager struct {
-	CreateStub        func() (hcsshim.HNSEndpoint, error)
+	CreateStub        func() (hcs.HNSEndpoint, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct{}
 	createReturns     struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}
 	createReturnsOnCall map[int]struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}
 	DeleteStub        func() error
@@ -29,26 +29,26 @@ type EndpointManager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ApplyPoliciesStub        func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	ApplyPoliciesStub        func(hcs.HNSEndpoint, []*hcs.NatPolicy, []*hcs.ACLPolicy) (hcs.HNSEndpoint, error)
 	applyPoliciesMutex       sync.RWMutex
 	applyPoliciesArgsForCall []struct {
-		arg1 hcsshim.HNSEndpoint
-		arg2 []*hcsshim.NatPolicy
-		arg3 []*hcsshim.ACLPolicy
+		arg1 hcs.HNSEndpoint
+		arg2 []*hcs.NatPolicy
+		arg3 []*hcs.ACLPolicy
 	}
 	applyPoliciesReturns struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}
 	applyPoliciesReturnsOnCall map[int]struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
+func (fake *EndpointManager) Create() (hcs.HNSEndpoint, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct{}{})
@@ -69,24 +69,24 @@ func (fake *EndpointManager) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *EndpointManager) CreateReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+func (fake *EndpointManager) CreateReturns(result1 hcs.HNSEndpoint, result2 error) {
 	fake.CreateStub = nil
 	fake.createReturns = struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *EndpointManager) CreateReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+func (fake *EndpointManager) CreateReturnsOnCall(i int, result1 hcs.HNSEndpoint, result2 error) {
 	fake.CreateStub = nil
 	if fake.createReturnsOnCall == nil {
 		fake.createReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.HNSEndpoint
+			result1 hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.createReturnsOnCall[i] = struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
@@ -131,23 +131,23 @@ func (fake *EndpointManager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *EndpointManager) ApplyPolicies(arg1 hcsshim.HNSEndpoint, arg2 []*hcsshim.NatPolicy, arg3 []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
-	var arg2Copy []*hcsshim.NatPolicy
+func (fake *EndpointManager) ApplyPolicies(arg1 hcs.HNSEndpoint, arg2 []*hcs.NatPolicy, arg3 []*hcs.ACLPolicy) (hcs.HNSEndpoint, error) {
+	var arg2Copy []*hcs.NatPolicy
 	if arg2 != nil {
-		arg2Copy = make([]*hcsshim.NatPolicy, len(arg2))
+		arg2Copy = make([]*hcs.NatPolicy, len(arg2))
 		copy(arg2Copy, arg2)
 	}
-	var arg3Copy []*hcsshim.ACLPolicy
+	var arg3Copy []*hcs.ACLPolicy
 	if arg3 != nil {
-		arg3Copy = make([]*hcsshim.ACLPolicy, len(arg3))
+		arg3Copy = make([]*hcs.ACLPolicy, len(arg3))
 		copy(arg3Copy, arg3)
 	}
 	fake.applyPoliciesMutex.Lock()
 	ret, specificReturn := fake.applyPoliciesReturnsOnCall[len(fake.applyPoliciesArgsForCall)]
 	fake.applyPoliciesArgsForCall = append(fake.applyPoliciesArgsForCall, struct {
-		arg1 hcsshim.HNSEndpoint
-		arg2 []*hcsshim.NatPolicy
-		arg3 []*hcsshim.ACLPolicy
+		arg1 hcs.HNSEndpoint
+		arg2 []*hcs.NatPolicy
+		arg3 []*hcs.ACLPolicy
 	}{arg1, arg2Copy, arg3Copy})
 	fake.recordInvocation("ApplyPolicies", []interface{}{arg1, arg2Copy, arg3Copy})
 	fake.applyPoliciesMutex.Unlock()
@@ -166,30 +166,30 @@ func (fake *EndpointManager) ApplyPoliciesCallCount() int {
 	return len(fake.applyPoliciesArgsForCall)
 }
 
-func (fake *EndpointManager) ApplyPoliciesArgsForCall(i int) (hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) {
+func (fake *EndpointManager) ApplyPoliciesArgsForCall(i int) (hcs.HNSEndpoint, []*hcs.NatPolicy, []*hcs.ACLPolicy) {
 	fake.applyPoliciesMutex.RLock()
 	defer fake.applyPoliciesMutex.RUnlock()
 	return fake.applyPoliciesArgsForCall[i].arg1, fake.applyPoliciesArgsForCall[i].arg2, fake.applyPoliciesArgsForCall[i].arg3
 }
 
-func (fake *EndpointManager) ApplyPoliciesReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+func (fake *EndpointManager) ApplyPoliciesReturns(result1 hcs.HNSEndpoint, result2 error) {
 	fake.ApplyPoliciesStub = nil
 	fake.applyPoliciesReturns = struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *EndpointManager) ApplyPoliciesReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+func (fake *EndpointManager) ApplyPoliciesReturnsOnCall(i int, result1 hcs.HNSEndpoint, result2 error) {
 	fake.ApplyPoliciesStub = nil
 	if fake.applyPoliciesReturnsOnCall == nil {
 		fake.applyPoliciesReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.HNSEndpoint
+			result1 hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.applyPoliciesReturnsOnCall[i] = struct {
-		result1 hcsshim.HNSEndpoint
+		result1 hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
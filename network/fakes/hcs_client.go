@@ -4,56 +4,56 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
-	"github.com/Microsoft/hcsshim"
 )
 
 type HCSClient struct {
-	GetHNSNetworkByNameStub        func(string) (*hcsshim.HNSNetwork, error)
+	GetHNSNetworkByNameStub        func(string) (*hcs.HNSNetwork, error)
 	getHNSNetworkByNameMutex       sync.RWMutex
 	getHNSNetworkByNameArgsForCall []struct {
 		arg1 string
 	}
 	getHNSNetworkByNameReturns struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
 	getHNSNetworkByNameReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
-	CreateNetworkStub        func(*hcsshim.HNSNetwork, func() (bool, error)) (*hcsshim.HNSNetwork, error)
+	CreateNetworkStub        func(*hcs.HNSNetwork, func() (bool, error)) (*hcs.HNSNetwork, error)
 	createNetworkMutex       sync.RWMutex
 	createNetworkArgsForCall []struct {
-		arg1 *hcsshim.HNSNetwork
+		arg1 *hcs.HNSNetwork
 		arg2 func() (bool, error)
 	}
 	createNetworkReturns struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
 	createNetworkReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
-	DeleteNetworkStub        func(*hcsshim.HNSNetwork) (*hcsshim.HNSNetwork, error)
+	DeleteNetworkStub        func(*hcs.HNSNetwork) (*hcs.HNSNetwork, error)
 	deleteNetworkMutex       sync.RWMutex
 	deleteNetworkArgsForCall []struct {
-		arg1 *hcsshim.HNSNetwork
+		arg1 *hcs.HNSNetwork
 	}
 	deleteNetworkReturns struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
 	deleteNetworkReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcsshim.HNSNetwork, error) {
+func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcs.HNSNetwork, error) {
 	fake.getHNSNetworkByNameMutex.Lock()
 	ret, specificReturn := fake.getHNSNetworkByNameReturnsOnCall[len(fake.getHNSNetworkByNameArgsForCall)]
 	fake.getHNSNetworkByNameArgsForCall = append(fake.getHNSNetworkByNameArgsForCall, struct {
@@ -82,33 +82,33 @@ func (fake *HCSClient) GetHNSNetworkByNameArgsForCall(i int) string {
 	return fake.getHNSNetworkByNameArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcs.HNSNetwork, result2 error) {
 	fake.GetHNSNetworkByNameStub = nil
 	fake.getHNSNetworkByNameReturns = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcs.HNSNetwork, result2 error) {
 	fake.GetHNSNetworkByNameStub = nil
 	if fake.getHNSNetworkByNameReturnsOnCall == nil {
 		fake.getHNSNetworkByNameReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSNetwork
+			result1 *hcs.HNSNetwork
 			result2 error
 		})
 	}
 	fake.getHNSNetworkByNameReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) CreateNetwork(arg1 *hcsshim.HNSNetwork, arg2 func() (bool, error)) (*hcsshim.HNSNetwork, error) {
+func (fake *HCSClient) CreateNetwork(arg1 *hcs.HNSNetwork, arg2 func() (bool, error)) (*hcs.HNSNetwork, error) {
 	fake.createNetworkMutex.Lock()
 	ret, specificReturn := fake.createNetworkReturnsOnCall[len(fake.createNetworkArgsForCall)]
 	fake.createNetworkArgsForCall = append(fake.createNetworkArgsForCall, struct {
-		arg1 *hcsshim.HNSNetwork
+		arg1 *hcs.HNSNetwork
 		arg2 func() (bool, error)
 	}{arg1, arg2})
 	fake.recordInvocation("CreateNetwork", []interface{}{arg1, arg2})
@@ -128,39 +128,39 @@ func (fake *HCSClient) CreateNetworkCallCount() int {
 	return len(fake.createNetworkArgsForCall)
 }
 
-func (fake *HCSClient) CreateNetworkArgsForCall(i int) (*hcsshim.HNSNetwork, func() (bool, error)) {
+func (fake *HCSClient) CreateNetworkArgsForCall(i int) (*hcs.HNSNetwork, func() (bool, error)) {
 	fake.createNetworkMutex.RLock()
 	defer fake.createNetworkMutex.RUnlock()
 	return fake.createNetworkArgsForCall[i].arg1, fake.createNetworkArgsForCall[i].arg2
 }
 
-func (fake *HCSClient) CreateNetworkReturns(result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) CreateNetworkReturns(result1 *hcs.HNSNetwork, result2 error) {
 	fake.CreateNetworkStub = nil
 	fake.createNetworkReturns = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) CreateNetworkReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) CreateNetworkReturnsOnCall(i int, result1 *hcs.HNSNetwork, result2 error) {
 	fake.CreateNetworkStub = nil
 	if fake.createNetworkReturnsOnCall == nil {
 		fake.createNetworkReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSNetwork
+			result1 *hcs.HNSNetwork
 			result2 error
 		})
 	}
 	fake.createNetworkReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteNetwork(arg1 *hcsshim.HNSNetwork) (*hcsshim.HNSNetwork, error) {
+func (fake *HCSClient) DeleteNetwork(arg1 *hcs.HNSNetwork) (*hcs.HNSNetwork, error) {
 	fake.deleteNetworkMutex.Lock()
 	ret, specificReturn := fake.deleteNetworkReturnsOnCall[len(fake.deleteNetworkArgsForCall)]
 	fake.deleteNetworkArgsForCall = append(fake.deleteNetworkArgsForCall, struct {
-		arg1 *hcsshim.HNSNetwork
+		arg1 *hcs.HNSNetwork
 	}{arg1})
 	fake.recordInvocation("DeleteNetwork", []interface{}{arg1})
 	fake.deleteNetworkMutex.Unlock()
@@ -179,30 +179,30 @@ func (fake *HCSClient) DeleteNetworkCallCount() int {
 	return len(fake.deleteNetworkArgsForCall)
 }
 
-func (fake *HCSClient) DeleteNetworkArgsForCall(i int) *hcsshim.HNSNetwork {
+func (fake *HCSClient) DeleteNetworkArgsForCall(i int) *hcs.HNSNetwork {
 	fake.deleteNetworkMutex.RLock()
 	defer fake.deleteNetworkMutex.RUnlock()
 	return fake.deleteNetworkArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) DeleteNetworkReturns(result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) DeleteNetworkReturns(result1 *hcs.HNSNetwork, result2 error) {
 	fake.DeleteNetworkStub = nil
 	fake.deleteNetworkReturns = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteNetworkReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
+func (fake *HCSClient) DeleteNetworkReturnsOnCall(i int, result1 *hcs.HNSNetwork, result2 error) {
 	fake.DeleteNetworkStub = nil
 	if fake.deleteNetworkReturnsOnCall == nil {
 		fake.deleteNetworkReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSNetwork
+			result1 *hcs.HNSNetwork
 			result2 error
 		})
 	}
 	fake.deleteNetworkReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSNetwork
+		result1 *hcs.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
@@ -4,40 +4,40 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/netrules"
-	"github.com/Microsoft/hcsshim"
 )
 
 type NetRuleApplier struct {
-	InStub        func(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)
+	InStub        func(netrules.NetIn, string) (*hcs.NatPolicy, *hcs.ACLPolicy, error)
 	inMutex       sync.RWMutex
 	inArgsForCall []struct {
 		arg1 netrules.NetIn
 		arg2 string
 	}
 	inReturns struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 *hcs.NatPolicy
+		result2 *hcs.ACLPolicy
 		result3 error
 	}
 	inReturnsOnCall map[int]struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 *hcs.NatPolicy
+		result2 *hcs.ACLPolicy
 		result3 error
 	}
-	OutStub        func(netrules.NetOut, string) (*hcsshim.ACLPolicy, error)
+	OutStub        func(netrules.NetOut, string) (*hcs.ACLPolicy, error)
 	outMutex       sync.RWMutex
 	outArgsForCall []struct {
 		arg1 netrules.NetOut
 		arg2 string
 	}
 	outReturns struct {
-		result1 *hcsshim.ACLPolicy
+		result1 *hcs.ACLPolicy
 		result2 error
 	}
 	outReturnsOnCall map[int]struct {
-		result1 *hcsshim.ACLPolicy
+		result1 *hcs.ACLPolicy
 		result2 error
 	}
 	CleanupStub        func() error
@@ -64,7 +64,7 @@ type NetRuleApplier struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *NetRuleApplier) In(arg1 netrules.NetIn, arg2 string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+func (fake *NetRuleApplier) In(arg1 netrules.NetIn, arg2 string) (*hcs.NatPolicy, *hcs.ACLPolicy, error) {
 	fake.inMutex.Lock()
 	ret, specificReturn := fake.inReturnsOnCall[len(fake.inArgsForCall)]
 	fake.inArgsForCall = append(fake.inArgsForCall, struct {
@@ -94,32 +94,32 @@ func (fake *NetRuleApplier) InArgsForCall(i int) (netrules.NetIn, string) {
 	return fake.inArgsForCall[i].arg1, fake.inArgsForCall[i].arg2
 }
 
-func (fake *NetRuleApplier) InReturns(result1 *hcsshim.NatPolicy, result2 *hcsshim.ACLPolicy, result3 error) {
+func (fake *NetRuleApplier) InReturns(result1 *hcs.NatPolicy, result2 *hcs.ACLPolicy, result3 error) {
 	fake.InStub = nil
 	fake.inReturns = struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 *hcs.NatPolicy
+		result2 *hcs.ACLPolicy
 		result3 error
 	}{result1, result2, result3}
 }
 
-func (fake *NetRuleApplier) InReturnsOnCall(i int, result1 *hcsshim.NatPolicy, result2 *hcsshim.ACLPolicy, result3 error) {
+func (fake *NetRuleApplier) InReturnsOnCall(i int, result1 *hcs.NatPolicy, result2 *hcs.ACLPolicy, result3 error) {
 	fake.InStub = nil
 	if fake.inReturnsOnCall == nil {
 		fake.inReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.NatPolicy
-			result2 *hcsshim.ACLPolicy
+			result1 *hcs.NatPolicy
+			result2 *hcs.ACLPolicy
 			result3 error
 		})
 	}
 	fake.inReturnsOnCall[i] = struct {
-		result1 *hcsshim.NatPolicy
-		result2 *hcsshim.ACLPolicy
+		result1 *hcs.NatPolicy
+		result2 *hcs.ACLPolicy
 		result3 error
 	}{result1, result2, result3}
 }
 
-func (fake *NetRuleApplier) Out(arg1 netrules.NetOut, arg2 string) (*hcsshim.ACLPolicy, error) {
+func (fake *NetRuleApplier) Out(arg1 netrules.NetOut, arg2 string) (*hcs.ACLPolicy, error) {
 	fake.outMutex.Lock()
 	ret, specificReturn := fake.outReturnsOnCall[len(fake.outArgsForCall)]
 	fake.outArgsForCall = append(fake.outArgsForCall, struct {
@@ -149,24 +149,24 @@ func (fake *NetRuleApplier) OutArgsForCall(i int) (netrules.NetOut, string) {
 	return fake.outArgsForCall[i].arg1, fake.outArgsForCall[i].arg2
 }
 
-func (fake *NetRuleApplier) OutReturns(result1 *hcsshim.ACLPolicy, result2 error) {
+func (fake *NetRuleApplier) OutReturns(result1 *hcs.ACLPolicy, result2 error) {
 	fake.OutStub = nil
 	fake.outReturns = struct {
-		result1 *hcsshim.ACLPolicy
+		result1 *hcs.ACLPolicy
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *NetRuleApplier) OutReturnsOnCall(i int, result1 *hcsshim.ACLPolicy, result2 error) {
+func (fake *NetRuleApplier) OutReturnsOnCall(i int, result1 *hcs.ACLPolicy, result2 error) {
 	fake.OutStub = nil
 	if fake.outReturnsOnCall == nil {
 		fake.outReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.ACLPolicy
+			result1 *hcs.ACLPolicy
 			result2 error
 		})
 	}
 	fake.outReturnsOnCall[i] = struct {
-		result1 *hcsshim.ACLPolicy
+		result1 *hcs.ACLPolicy
 		result2 error
 	}{result1, result2}
 }
@@ -2,10 +2,10 @@ package mtu
 
 import (
 	"fmt"
+	"syscall"
 
 	"code.cloudfoundry.org/localip"
 	"code.cloudfoundry.org/winc/network/netinterface"
-	"golang.org/x/sys/windows"
 )
 
 //go:generate counterfeiter -o fakes/netinterface.go --fake-name NetInterface . NetInterface
@@ -36,7 +36,7 @@ func (m *Mtu) SetContainer(mtu int) error {
 		if err != nil {
 			return err
 		}
-		retMtu, err := m.netInterface.GetMTU(adapterInfo.Name, windows.AF_INET)
+		retMtu, err := m.netInterface.GetMTU(adapterInfo.Name, syscall.AF_INET)
 		if err != nil {
 			return err
 		}
@@ -44,7 +44,7 @@ func (m *Mtu) SetContainer(mtu int) error {
 	}
 
 	interfaceAlias := fmt.Sprintf("vEthernet (%s)", m.containerId)
-	return m.netInterface.SetMTU(interfaceAlias, uint32(mtu), windows.AF_INET)
+	return m.netInterface.SetMTU(interfaceAlias, uint32(mtu), syscall.AF_INET)
 }
 
 func (m *Mtu) SetNat(mtu int) error {
@@ -57,7 +57,7 @@ func (m *Mtu) SetNat(mtu int) error {
 		if err != nil {
 			return err
 		}
-		retMtu, err := m.netInterface.GetMTU(adapterInfo.Name, windows.AF_INET)
+		retMtu, err := m.netInterface.GetMTU(adapterInfo.Name, syscall.AF_INET)
 		if err != nil {
 			return err
 		}
@@ -65,5 +65,5 @@ func (m *Mtu) SetNat(mtu int) error {
 	}
 
 	interfaceId := fmt.Sprintf("vEthernet (%s)", m.networkName)
-	return m.netInterface.SetMTU(interfaceId, uint32(mtu), windows.AF_INET)
+	return m.netInterface.SetMTU(interfaceId, uint32(mtu), syscall.AF_INET)
 }
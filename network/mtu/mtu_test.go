@@ -2,6 +2,7 @@ package mtu_test
 
 import (
 	"fmt"
+	"syscall"
 
 	"code.cloudfoundry.org/localip"
 	"code.cloudfoundry.org/winc/network/mtu"
@@ -9,7 +10,6 @@ import (
 	"code.cloudfoundry.org/winc/network/netinterface"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"golang.org/x/sys/windows"
 )
 
 var _ = Describe("Mtu", func() {
@@ -34,7 +34,7 @@ var _ = Describe("Mtu", func() {
 			alias, mtu, family := netInterface.SetMTUArgsForCall(0)
 			Expect(alias).To(Equal("vEthernet (containerabc)"))
 			Expect(mtu).To(Equal(uint32(1405)))
-			Expect(family).To(Equal(uint32(windows.AF_INET)))
+			Expect(family).To(Equal(uint32(syscall.AF_INET)))
 		})
 
 		Context("the specified mtu is 0", func() {
@@ -55,13 +55,13 @@ var _ = Describe("Mtu", func() {
 				Expect(netInterface.GetMTUCallCount()).To(Equal(1))
 				alias, family := netInterface.GetMTUArgsForCall(0)
 				Expect(alias).To(Equal(natNetworkName))
-				Expect(family).To(Equal(uint32(windows.AF_INET)))
+				Expect(family).To(Equal(uint32(syscall.AF_INET)))
 
 				Expect(netInterface.SetMTUCallCount()).To(Equal(1))
 				alias, mtu, family := netInterface.SetMTUArgsForCall(0)
 				Expect(alias).To(Equal("vEthernet (containerabc)"))
 				Expect(mtu).To(Equal(uint32(1302)))
-				Expect(family).To(Equal(uint32(windows.AF_INET)))
+				Expect(family).To(Equal(uint32(syscall.AF_INET)))
 			})
 		})
 	})
@@ -74,7 +74,7 @@ var _ = Describe("Mtu", func() {
 			alias, mtu, family := netInterface.SetMTUArgsForCall(0)
 			Expect(alias).To(Equal("vEthernet (my-network)"))
 			Expect(mtu).To(Equal(uint32(1405)))
-			Expect(family).To(Equal(uint32(windows.AF_INET)))
+			Expect(family).To(Equal(uint32(syscall.AF_INET)))
 		})
 
 		Context("the specified mtu is 0", func() {
@@ -95,13 +95,13 @@ var _ = Describe("Mtu", func() {
 				Expect(netInterface.GetMTUCallCount()).To(Equal(1))
 				alias, family := netInterface.GetMTUArgsForCall(0)
 				Expect(alias).To(Equal(networkName))
-				Expect(family).To(Equal(uint32(windows.AF_INET)))
+				Expect(family).To(Equal(uint32(syscall.AF_INET)))
 
 				Expect(netInterface.SetMTUCallCount()).To(Equal(1))
 				alias, mtu, family := netInterface.SetMTUArgsForCall(0)
 				Expect(alias).To(Equal("vEthernet (my-network)"))
 				Expect(mtu).To(Equal(uint32(1302)))
-				Expect(family).To(Equal(uint32(windows.AF_INET)))
+				Expect(family).To(Equal(uint32(syscall.AF_INET)))
 			})
 		})
 	})
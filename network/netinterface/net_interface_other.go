@@ -0,0 +1,31 @@
+// +build !windows
+
+package netinterface
+
+import (
+	"errors"
+)
+
+// network interfaces are looked up through the IP Helper API, which only
+// exists on Windows
+var errNotSupported = errors.New("netinterface: not supported on this platform")
+
+func (n *NetInterface) ByName(name string) (AdapterInfo, error) {
+	return AdapterInfo{}, errNotSupported
+}
+
+func (n *NetInterface) ByIP(ipStr string) (AdapterInfo, error) {
+	return AdapterInfo{}, errNotSupported
+}
+
+func (n *NetInterface) SetMTU(name string, mtu uint32, family uint32) error {
+	return errNotSupported
+}
+
+func (n *NetInterface) GetMTU(name string, family uint32) (uint32, error) {
+	return 0, errNotSupported
+}
+
+func InterfaceExists(name string) (bool, error) {
+	return false, errNotSupported
+}
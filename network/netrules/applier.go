@@ -5,8 +5,8 @@ import (
 	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/firewall"
-	"github.com/Microsoft/hcsshim"
 )
 
 //go:generate counterfeiter -o fakes/netsh_runner.go --fake-name NetShRunner . NetShRunner
@@ -34,7 +34,7 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+func (a *Applier) In(rule NetIn, containerIP string) (*hcs.NatPolicy, *hcs.ACLPolicy, error) {
 	externalPort := rule.HostPort
 
 	if externalPort == 0 {
@@ -45,22 +45,22 @@ func (a *Applier) In(rule NetIn, containerIP string) (*hcsshim.NatPolicy, *hcssh
 		externalPort = uint32(allocatedPort)
 	}
 
-	return &hcsshim.NatPolicy{
-			Type:         hcsshim.Nat,
+	return &hcs.NatPolicy{
+			Type:         hcs.Nat,
 			Protocol:     "TCP",
 			ExternalPort: uint16(externalPort),
 			InternalPort: uint16(rule.ContainerPort),
-		}, &hcsshim.ACLPolicy{
-			Type:           hcsshim.ACL,
-			Action:         hcsshim.Allow,
-			Direction:      hcsshim.In,
+		}, &hcs.ACLPolicy{
+			Type:           hcs.ACL,
+			Action:         hcs.Allow,
+			Direction:      hcs.In,
 			Protocol:       uint16(firewall.NET_FW_IP_PROTOCOL_TCP),
 			LocalAddresses: containerIP,
 			LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
 		}, nil
 }
 
-func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
+func (a *Applier) Out(rule NetOut, containerIP string) (*hcs.ACLPolicy, error) {
 	rAddrs := []string{}
 
 	for _, ipr := range rule.Networks {
@@ -79,10 +79,10 @@ func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, erro
 		}
 	}
 
-	acl := hcsshim.ACLPolicy{
-		Type:            hcsshim.ACL,
-		Action:          hcsshim.Allow,
-		Direction:       hcsshim.Out,
+	acl := hcs.ACLPolicy{
+		Type:            hcs.ACL,
+		Action:          hcs.Allow,
+		Direction:       hcs.Out,
 		LocalAddresses:  containerIP,
 		RemoteAddresses: strings.Join(rAddrs, ","),
 	}
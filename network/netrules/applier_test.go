@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"net"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netrules"
 	"code.cloudfoundry.org/winc/network/netrules/fakes"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -72,18 +72,18 @@ var _ = Describe("Applier", func() {
 			nat, acl, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
-			expectedNat := hcsshim.NatPolicy{
-				Type:         hcsshim.Nat,
+			expectedNat := hcs.NatPolicy{
+				Type:         hcs.Nat,
 				Protocol:     "TCP",
 				InternalPort: 1000,
 				ExternalPort: 2000,
 			}
 			Expect(*nat).To(Equal(expectedNat))
 
-			expectedAcl := hcsshim.ACLPolicy{
-				Type:           hcsshim.ACL,
-				Action:         hcsshim.Allow,
-				Direction:      hcsshim.In,
+			expectedAcl := hcs.ACLPolicy{
+				Type:           hcs.ACL,
+				Action:         hcs.Allow,
+				Direction:      hcs.In,
 				Protocol:       6,
 				LocalAddresses: "5.4.3.2",
 				LocalPorts:     "1000",
@@ -104,18 +104,18 @@ var _ = Describe("Applier", func() {
 				nat, acl, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedNat := hcsshim.NatPolicy{
-					Type:         hcsshim.Nat,
+				expectedNat := hcs.NatPolicy{
+					Type:         hcs.Nat,
 					Protocol:     "TCP",
 					InternalPort: 1000,
 					ExternalPort: 1234,
 				}
 				Expect(*nat).To(Equal(expectedNat))
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:           hcsshim.ACL,
-					Action:         hcsshim.Allow,
-					Direction:      hcsshim.In,
+				expectedAcl := hcs.ACLPolicy{
+					Type:           hcs.ACL,
+					Action:         hcs.Allow,
+					Direction:      hcs.In,
 					Protocol:       6,
 					LocalAddresses: "5.4.3.2",
 					LocalPorts:     "1000",
@@ -172,10 +172,10 @@ var _ = Describe("Applier", func() {
 				acl, err := applier.Out(netOutRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:            hcsshim.ACL,
-					Action:          hcsshim.Allow,
-					Direction:       hcsshim.Out,
+				expectedAcl := hcs.ACLPolicy{
+					Type:            hcs.ACL,
+					Action:          hcs.Allow,
+					Direction:       hcs.Out,
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_UDP),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
@@ -194,10 +194,10 @@ var _ = Describe("Applier", func() {
 				acl, err := applier.Out(netOutRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:            hcsshim.ACL,
-					Action:          hcsshim.Allow,
-					Direction:       hcsshim.Out,
+				expectedAcl := hcs.ACLPolicy{
+					Type:            hcs.ACL,
+					Action:          hcs.Allow,
+					Direction:       hcs.Out,
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_TCP),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
@@ -216,10 +216,10 @@ var _ = Describe("Applier", func() {
 				acl, err := applier.Out(netOutRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:            hcsshim.ACL,
-					Action:          hcsshim.Allow,
-					Direction:       hcsshim.Out,
+				expectedAcl := hcs.ACLPolicy{
+					Type:            hcs.ACL,
+					Action:          hcs.Allow,
+					Direction:       hcs.Out,
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ICMP),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
@@ -237,10 +237,10 @@ var _ = Describe("Applier", func() {
 				acl, err := applier.Out(netOutRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:            hcsshim.ACL,
-					Action:          hcsshim.Allow,
-					Direction:       hcsshim.Out,
+				expectedAcl := hcs.ACLPolicy{
+					Type:            hcs.ACL,
+					Action:          hcs.Allow,
+					Direction:       hcs.Out,
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
@@ -265,10 +265,10 @@ var _ = Describe("Applier", func() {
 				acl, err := applier.Out(netOutRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedAcl := hcsshim.ACLPolicy{
-					Type:            hcsshim.ACL,
-					Action:          hcsshim.Allow,
-					Direction:       hcsshim.Out,
+				expectedAcl := hcs.ACLPolicy{
+					Type:            hcs.ACL,
+					Action:          hcs.Allow,
+					Direction:       hcs.Out,
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "",
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"strconv"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netrules"
-	"github.com/Microsoft/hcsshim"
 )
 
 //go:generate counterfeiter -o fakes/netsh_runner.go --fake-name NetShRunner . NetShRunner
@@ -43,7 +43,7 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
+func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcs.NatPolicy, *hcs.ACLPolicy, error) {
 	externalPort := rule.HostPort
 
 	if externalPort == 0 {
@@ -71,8 +71,8 @@ func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcsshim.NatPolic
 		return nil, nil, err
 	}
 
-	return &hcsshim.NatPolicy{
-		Type:         hcsshim.Nat,
+	return &hcs.NatPolicy{
+		Type:         hcs.Nat,
 		Protocol:     "TCP",
 		InternalPort: uint16(rule.ContainerPort),
 		ExternalPort: uint16(externalPort),
@@ -80,7 +80,7 @@ func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcsshim.NatPolic
 
 }
 
-func (a *Applier) Out(rule netrules.NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
+func (a *Applier) Out(rule netrules.NetOut, containerIP string) (*hcs.ACLPolicy, error) {
 	fr := firewall.Rule{
 		Name:            a.containerId,
 		Action:          firewall.NET_FW_ACTION_ALLOW,
@@ -4,11 +4,11 @@ import (
 	"errors"
 	"net"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netrules"
 	"code.cloudfoundry.org/winc/network/netrules/firewallapplier"
 	"code.cloudfoundry.org/winc/network/netrules/firewallapplier/fakes"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -63,8 +63,8 @@ var _ = Describe("Firewall Applier", func() {
 			nat, _, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
-			expectedNat := hcsshim.NatPolicy{
-				Type:         hcsshim.Nat,
+			expectedNat := hcs.NatPolicy{
+				Type:         hcs.Nat,
 				Protocol:     "TCP",
 				InternalPort: 1000,
 				ExternalPort: 2000,
@@ -107,8 +107,8 @@ var _ = Describe("Firewall Applier", func() {
 				nat, _, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
-				expectedNat := hcsshim.NatPolicy{
-					Type:         hcsshim.Nat,
+				expectedNat := hcs.NatPolicy{
+					Type:         hcs.Nat,
 					Protocol:     "TCP",
 					InternalPort: 1000,
 					ExternalPort: 1234,
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
-	"github.com/Microsoft/hcsshim"
 	"github.com/sirupsen/logrus"
 )
 
@@ -39,7 +38,7 @@ func (nr *Runner) RunContainer(args []string) error {
 	}
 	defer container.Close()
 
-	p, err := container.CreateProcess(&hcsshim.ProcessConfig{
+	p, err := container.CreateProcess(&hcs.ProcessConfig{
 		CommandLine: commandLine,
 	})
 	if err != nil {
@@ -6,10 +6,10 @@ import (
 	"io/ioutil"
 	"time"
 
+	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/network/netsh"
 	"code.cloudfoundry.org/winc/network/netsh/fakes"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
@@ -49,7 +49,7 @@ var _ = Describe("Netsh", func() {
 			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
 
 			Expect(fakeContainer.CreateProcessCallCount()).To(Equal(1))
-			expectedProcessConfig := hcsshim.ProcessConfig{
+			expectedProcessConfig := hcs.ProcessConfig{
 				CommandLine: "netsh some command",
 			}
 			Expect(*fakeContainer.CreateProcessArgsForCall(0)).To(Equal(expectedProcessConfig))
@@ -7,17 +7,16 @@ import (
 	"strconv"
 	"strings"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network/netinterface"
 	"code.cloudfoundry.org/winc/network/netrules"
-
-	"github.com/Microsoft/hcsshim"
 	"github.com/sirupsen/logrus"
 )
 
 //go:generate counterfeiter -o fakes/net_rule_applier.go --fake-name NetRuleApplier . NetRuleApplier
 type NetRuleApplier interface {
-	In(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)
-	Out(netrules.NetOut, string) (*hcsshim.ACLPolicy, error)
+	In(netrules.NetIn, string) (*hcs.NatPolicy, *hcs.ACLPolicy, error)
+	Out(netrules.NetOut, string) (*hcs.ACLPolicy, error)
 	Cleanup() error
 	OpenPort(port uint32) error
 }
@@ -30,16 +29,16 @@ type Mtu interface {
 
 //go:generate counterfeiter -o fakes/endpoint_manager.go --fake-name EndpointManager . EndpointManager
 type EndpointManager interface {
-	Create() (hcsshim.HNSEndpoint, error)
+	Create() (hcs.HNSEndpoint, error)
 	Delete() error
-	ApplyPolicies(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	ApplyPolicies(hcs.HNSEndpoint, []*hcs.NatPolicy, []*hcs.ACLPolicy) (hcs.HNSEndpoint, error)
 }
 
 //go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
-	GetHNSNetworkByName(string) (*hcsshim.HNSNetwork, error)
-	CreateNetwork(*hcsshim.HNSNetwork, func() (bool, error)) (*hcsshim.HNSNetwork, error)
-	DeleteNetwork(*hcsshim.HNSNetwork) (*hcsshim.HNSNetwork, error)
+	GetHNSNetworkByName(string) (*hcs.HNSNetwork, error)
+	CreateNetwork(*hcs.HNSNetwork, func() (bool, error)) (*hcs.HNSNetwork, error)
+	DeleteNetwork(*hcs.HNSNetwork) (*hcs.HNSNetwork, error)
 }
 
 type Config struct {
@@ -99,12 +98,12 @@ func NewNetworkManager(client HCSClient, applier NetRuleApplier, endpointManager
 func (n *NetworkManager) CreateHostNATNetwork() error {
 	existingNetwork, err := n.hcsClient.GetHNSNetworkByName(n.config.NetworkName)
 	if err != nil {
-		if _, isNotExist := err.(hcsshim.NetworkNotFoundError); !isNotExist {
+		if _, isNotExist := err.(hcs.NetworkNotFoundError); !isNotExist {
 			return err
 		}
 	}
 
-	subnets := []hcsshim.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}}
+	subnets := []hcs.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}}
 
 	if existingNetwork != nil {
 		if len(existingNetwork.Subnets) == 1 && subnetsMatch(existingNetwork.Subnets[0], subnets[0]) {
@@ -123,7 +122,7 @@ func (n *NetworkManager) CreateHostNATNetwork() error {
 	// This must be a comma separated value with no spaces
 	dnsSuffix := strings.Join(n.config.DNSSuffix, ",")
 
-	network := &hcsshim.HNSNetwork{
+	network := &hcs.HNSNetwork{
 		Name:      n.config.NetworkName,
 		Type:      "nat",
 		Subnets:   subnets,
@@ -143,14 +142,14 @@ func (n *NetworkManager) CreateHostNATNetwork() error {
 	return n.mtu.SetNat(n.config.MTU)
 }
 
-func subnetsMatch(a, b hcsshim.Subnet) bool {
+func subnetsMatch(a, b hcs.Subnet) bool {
 	return (a.AddressPrefix == b.AddressPrefix) && (a.GatewayAddress == b.GatewayAddress)
 }
 
 func (n *NetworkManager) DeleteHostNATNetwork() error {
 	network, err := n.hcsClient.GetHNSNetworkByName(n.config.NetworkName)
 	if err != nil {
-		if _, ok := err.(hcsshim.NetworkNotFoundError); ok {
+		if _, ok := err.(hcs.NetworkNotFoundError); ok {
 			return nil
 		}
 
@@ -189,8 +188,8 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 	}
 	logrus.Debugf("created endpoint %s", createdEndpoint.Name)
 
-	hnsAcls := []*hcsshim.ACLPolicy{}
-	hnsNats := []*hcsshim.NatPolicy{}
+	hnsAcls := []*hcs.ACLPolicy{}
+	hnsNats := []*hcs.NatPolicy{}
 
 	for _, rule := range inputs.NetIn {
 		nat, acl, err := n.applier.In(rule, createdEndpoint.IPAddress.String())
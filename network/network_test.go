@@ -5,10 +5,10 @@ import (
 	"io/ioutil"
 	"net"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/fakes"
 	"code.cloudfoundry.org/winc/network/netrules"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
@@ -23,7 +23,7 @@ var _ = Describe("NetworkManager", func() {
 		hcsClient       *fakes.HCSClient
 		endpointManager *fakes.EndpointManager
 		mtu             *fakes.Mtu
-		hnsNetwork      *hcsshim.HNSNetwork
+		hnsNetwork      *hcs.HNSNetwork
 		config          network.Config
 	)
 
@@ -46,7 +46,7 @@ var _ = Describe("NetworkManager", func() {
 
 	Describe("CreateHostNATNetwork", func() {
 		BeforeEach(func() {
-			hcsClient.GetHNSNetworkByNameReturns(nil, hcsshim.NetworkNotFoundError{NetworkName: "unit-test-name"})
+			hcsClient.GetHNSNetworkByNameReturns(nil, hcs.NetworkNotFoundError{NetworkName: "unit-test-name"})
 		})
 
 		It("creates the network with the correct values", func() {
@@ -58,7 +58,7 @@ var _ = Describe("NetworkManager", func() {
 			Expect(hcsClient.CreateNetworkCallCount()).To(Equal(1))
 			net, _ := hcsClient.CreateNetworkArgsForCall(0)
 			Expect(net.Name).To(Equal("unit-test-name"))
-			Expect(net.Subnets).To(ConsistOf(hcsshim.Subnet{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}))
+			Expect(net.Subnets).To(ConsistOf(hcs.Subnet{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}))
 			Expect(net.DNSSuffix).To(Equal(""))
 
 			Expect(mtu.SetNatCallCount()).To(Equal(1))
@@ -93,9 +93,9 @@ var _ = Describe("NetworkManager", func() {
 
 		Context("the network already exists with the correct values", func() {
 			BeforeEach(func() {
-				hnsNetwork = &hcsshim.HNSNetwork{
+				hnsNetwork = &hcs.HNSNetwork{
 					Name:    "unit-test-name",
-					Subnets: []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+					Subnets: []hcs.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
 				}
 				hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
 			})
@@ -111,9 +111,9 @@ var _ = Describe("NetworkManager", func() {
 
 		Context("the network already exists with an incorrect address prefix", func() {
 			BeforeEach(func() {
-				hnsNetwork = &hcsshim.HNSNetwork{
+				hnsNetwork = &hcs.HNSNetwork{
 					Name:    "unit-test-name",
-					Subnets: []hcsshim.Subnet{{AddressPrefix: "123.89.0.0/67", GatewayAddress: "123.45.0.1"}},
+					Subnets: []hcs.Subnet{{AddressPrefix: "123.89.0.0/67", GatewayAddress: "123.45.0.1"}},
 				}
 				hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
 			})
@@ -126,9 +126,9 @@ var _ = Describe("NetworkManager", func() {
 
 		Context("the network already exists with an incorrect gateway address", func() {
 			BeforeEach(func() {
-				hnsNetwork = &hcsshim.HNSNetwork{
+				hnsNetwork = &hcs.HNSNetwork{
 					Name:    "unit-test-name",
-					Subnets: []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.67.89"}},
+					Subnets: []hcs.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.67.89"}},
 				}
 				hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
 			})
@@ -175,7 +175,7 @@ var _ = Describe("NetworkManager", func() {
 
 	Describe("DeleteHostNATNetwork", func() {
 		BeforeEach(func() {
-			hnsNetwork = &hcsshim.HNSNetwork{Name: "unit-test-name"}
+			hnsNetwork = &hcs.HNSNetwork{Name: "unit-test-name"}
 			hcsClient.GetHNSNetworkByNameReturnsOnCall(0, hnsNetwork, nil)
 		})
 
@@ -191,7 +191,7 @@ var _ = Describe("NetworkManager", func() {
 
 		Context("the network does not exist", func() {
 			BeforeEach(func() {
-				hcsClient.GetHNSNetworkByNameReturnsOnCall(0, nil, hcsshim.NetworkNotFoundError{NetworkName: "unit-test-name"})
+				hcsClient.GetHNSNetworkByNameReturnsOnCall(0, nil, hcs.NetworkNotFoundError{NetworkName: "unit-test-name"})
 			})
 
 			It("returns success", func() {
@@ -217,20 +217,20 @@ var _ = Describe("NetworkManager", func() {
 	Describe("Up", func() {
 		var (
 			inputs          network.UpInputs
-			createdEndpoint hcsshim.HNSEndpoint
+			createdEndpoint hcs.HNSEndpoint
 			containerIP     net.IP
-			nat1            *hcsshim.NatPolicy
-			nat2            *hcsshim.NatPolicy
-			inAcl1          *hcsshim.ACLPolicy
-			inAcl2          *hcsshim.ACLPolicy
-			outAcl1         *hcsshim.ACLPolicy
-			outAcl2         *hcsshim.ACLPolicy
+			nat1            *hcs.NatPolicy
+			nat2            *hcs.NatPolicy
+			inAcl1          *hcs.ACLPolicy
+			inAcl2          *hcs.ACLPolicy
+			outAcl1         *hcs.ACLPolicy
+			outAcl2         *hcs.ACLPolicy
 		)
 
 		BeforeEach(func() {
 			containerIP = net.ParseIP("111.222.33.44")
 
-			createdEndpoint = hcsshim.HNSEndpoint{
+			createdEndpoint = hcs.HNSEndpoint{
 				IPAddress: containerIP,
 			}
 
@@ -247,45 +247,45 @@ var _ = Describe("NetworkManager", func() {
 				},
 			}
 
-			nat1 = &hcsshim.NatPolicy{
-				Type:         hcsshim.Nat,
+			nat1 = &hcs.NatPolicy{
+				Type:         hcs.Nat,
 				Protocol:     "TCP",
 				ExternalPort: 111,
 				InternalPort: 666,
 			}
 
-			nat2 = &hcsshim.NatPolicy{
-				Type:         hcsshim.Nat,
+			nat2 = &hcs.NatPolicy{
+				Type:         hcs.Nat,
 				Protocol:     "TCP",
 				ExternalPort: 222,
 				InternalPort: 888,
 			}
 
-			inAcl1 = &hcsshim.ACLPolicy{
-				Type:       hcsshim.ACL,
+			inAcl1 = &hcs.ACLPolicy{
+				Type:       hcs.ACL,
 				LocalPorts: "666",
-				Direction:  hcsshim.In,
-				Action:     hcsshim.Allow,
+				Direction:  hcs.In,
+				Action:     hcs.Allow,
 			}
 
-			inAcl2 = &hcsshim.ACLPolicy{
-				Type:       hcsshim.ACL,
+			inAcl2 = &hcs.ACLPolicy{
+				Type:       hcs.ACL,
 				LocalPorts: "888",
-				Direction:  hcsshim.In,
-				Action:     hcsshim.Allow,
+				Direction:  hcs.In,
+				Action:     hcs.Allow,
 			}
 
-			outAcl1 = &hcsshim.ACLPolicy{
-				Type:      hcsshim.ACL,
-				Direction: hcsshim.Out,
-				Action:    hcsshim.Allow,
+			outAcl1 = &hcs.ACLPolicy{
+				Type:      hcs.ACL,
+				Direction: hcs.Out,
+				Action:    hcs.Allow,
 				Protocol:  6,
 			}
 
-			outAcl2 = &hcsshim.ACLPolicy{
-				Type:      hcsshim.ACL,
-				Direction: hcsshim.In,
-				Action:    hcsshim.Allow,
+			outAcl2 = &hcs.ACLPolicy{
+				Type:      hcs.ACL,
+				Direction: hcs.In,
+				Action:    hcs.Allow,
 				Protocol:  17,
 			}
 
@@ -340,15 +340,15 @@ var _ = Describe("NetworkManager", func() {
 			ep, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
 			Expect(ep).To(Equal(createdEndpoint))
 
-			expectedNatPolicies := []hcsshim.NatPolicy{*nat1, *nat2}
-			var receivedNatPolicies []hcsshim.NatPolicy
+			expectedNatPolicies := []hcs.NatPolicy{*nat1, *nat2}
+			var receivedNatPolicies []hcs.NatPolicy
 			for _, v := range nats {
 				receivedNatPolicies = append(receivedNatPolicies, *v)
 			}
 			Expect(receivedNatPolicies).To(Equal(expectedNatPolicies))
 
-			expectedAclPolicies := []hcsshim.ACLPolicy{*inAcl1, *inAcl2, *outAcl1, *outAcl2}
-			var receivedAclPolicies []hcsshim.ACLPolicy
+			expectedAclPolicies := []hcs.ACLPolicy{*inAcl1, *inAcl2, *outAcl1, *outAcl2}
+			var receivedAclPolicies []hcs.ACLPolicy
 			for _, v := range acls {
 				receivedAclPolicies = append(receivedAclPolicies, *v)
 			}
@@ -532,7 +532,7 @@ var _ = Describe("NetworkManager", func() {
 
 		Context("endpoint create fails", func() {
 			BeforeEach(func() {
-				endpointManager.CreateReturns(hcsshim.HNSEndpoint{}, errors.New("couldn't create endpoint"))
+				endpointManager.CreateReturns(hcs.HNSEndpoint{}, errors.New("couldn't create endpoint"))
 			})
 
 			It("cleans up allocated ports", func() {
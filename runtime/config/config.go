@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -20,6 +21,30 @@ const (
 	defaultCwd = "C:\\"
 )
 
+// PipePrefix is the prefix of named pipe paths.
+const PipePrefix = `\\.\pipe\`
+
+// IsolationAnnotation selects the isolation of a container, overriding
+// spec.Windows.HyperV. Its value is ProcessIsolation or HyperVIsolation.
+const (
+	IsolationAnnotation = "winc.isolation"
+	ProcessIsolation    = "process"
+	HyperVIsolation     = "hyperv"
+)
+
+// Isolation returns the isolation a container is created with.
+func Isolation(spec *specs.Spec) string {
+	if isolation, ok := spec.Annotations[IsolationAnnotation]; ok {
+		return isolation
+	}
+
+	if spec.Windows != nil && spec.Windows.HyperV != nil {
+		return HyperVIsolation
+	}
+
+	return ProcessIsolation
+}
+
 func ValidateBundle(logger *logrus.Entry, bundlePath string) (*specs.Spec, error) {
 	logger.Debug("validating bundle")
 
@@ -49,6 +74,10 @@ func ValidateBundle(logger *logrus.Entry, bundlePath string) (*specs.Spec, error
 		return nil, &BundleConfigValidationError{BundlePath: bundlePath, ErrorMessages: msgs}
 	}
 
+	if _, err := CredentialSpec(&spec); err != nil {
+		return nil, err
+	}
+
 	return &spec, nil
 }
 
@@ -64,14 +93,151 @@ func checkAll(spec specs.Spec, v validate.Validator) []string {
 			msgs = append(msgs, "'Spec.Root.Path' should not be empty.")
 		}
 	}
+	if isolation, ok := spec.Annotations[IsolationAnnotation]; ok && isolation != ProcessIsolation && isolation != HyperVIsolation {
+		msgs = append(msgs, fmt.Sprintf("annotation %s must be %s or %s: %s", IsolationAnnotation, ProcessIsolation, HyperVIsolation, isolation))
+	}
+	msgs = append(msgs, checkMounts(spec.Mounts)...)
+	if spec.Windows != nil && spec.Windows.Resources != nil {
+		if spec.Windows.Resources.CPU != nil {
+			msgs = append(msgs, checkCPU(*spec.Windows.Resources.CPU)...)
+		}
+		if spec.Windows.Resources.Storage != nil {
+			msgs = append(msgs, checkStorage(*spec.Windows.Resources.Storage)...)
+		}
+	}
 	return msgs
 }
 
-func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *specs.Process) (*specs.Process, error) {
-	logger.Debug("validating process config")
+type credentialSpec struct {
+	CmsPlugins       []string
+	DomainJoinConfig *struct {
+		Sid                string
+		MachineAccountName string
+		Guid               string
+		DnsTreeName        string
+		DnsName            string
+		NetBiosName        string
+	}
+	ActiveDirectoryConfig *struct {
+		GroupManagedServiceAccounts []struct {
+			Name  string
+			Scope string
+		}
+	}
+}
+
+// CredentialSpec returns the gMSA credential spec of a container as the JSON
+// HCS expects, or "" if it has none. spec.Windows.CredentialSpec is either the
+// credential spec itself, inline as an object or a JSON string, or the path
+// to a file containing it.
+func CredentialSpec(spec *specs.Spec) (string, error) {
+	if spec.Windows == nil || spec.Windows.CredentialSpec == nil {
+		return "", nil
+	}
+
+	var (
+		source  string
+		content []byte
+		err     error
+	)
+
+	switch cs := spec.Windows.CredentialSpec.(type) {
+	case string:
+		source = cs
+		if strings.HasPrefix(strings.TrimSpace(cs), "{") {
+			content = []byte(cs)
+		} else {
+			content, err = ioutil.ReadFile(cs)
+			if err != nil {
+				return "", &MissingCredentialSpecError{CredentialSpec: cs}
+			}
+		}
+	case map[string]interface{}:
+		source = "inline"
+		content, err = json.Marshal(cs)
+		if err != nil {
+			return "", &CredentialSpecInvalidJSONError{CredentialSpec: source, InternalError: err}
+		}
+	default:
+		return "", &CredentialSpecValidationError{ErrorMessages: []string{"credentialSpec must be an object or a path to a JSON file"}}
+	}
+
+	var cs credentialSpec
+	if err := json.Unmarshal(content, &cs); err != nil {
+		return "", &CredentialSpecInvalidJSONError{CredentialSpec: source, InternalError: err}
+	}
+
+	if msgs := checkCredentialSpec(cs); len(msgs) != 0 {
+		return "", &CredentialSpecValidationError{ErrorMessages: msgs}
+	}
+
+	compacted := &bytes.Buffer{}
+	if err := json.Compact(compacted, content); err != nil {
+		return "", &CredentialSpecInvalidJSONError{CredentialSpec: source, InternalError: err}
+	}
+
+	return compacted.String(), nil
+}
 
+func checkCredentialSpec(cs credentialSpec) []string {
 	msgs := []string{}
 
+	hasActiveDirectory := false
+	for _, p := range cs.CmsPlugins {
+		if p == "ActiveDirectory" {
+			hasActiveDirectory = true
+		}
+	}
+	if !hasActiveDirectory {
+		msgs = append(msgs, "CmsPlugins must include ActiveDirectory")
+	}
+
+	if cs.DomainJoinConfig == nil {
+		msgs = append(msgs, "DomainJoinConfig must be set")
+	} else {
+		required := []struct{ name, value string }{
+			{"Sid", cs.DomainJoinConfig.Sid},
+			{"MachineAccountName", cs.DomainJoinConfig.MachineAccountName},
+			{"Guid", cs.DomainJoinConfig.Guid},
+			{"DnsName", cs.DomainJoinConfig.DnsName},
+			{"NetBiosName", cs.DomainJoinConfig.NetBiosName},
+		}
+		for _, f := range required {
+			if f.value == "" {
+				msgs = append(msgs, fmt.Sprintf("DomainJoinConfig.%s must not be empty", f.name))
+			}
+		}
+	}
+
+	if cs.ActiveDirectoryConfig == nil || len(cs.ActiveDirectoryConfig.GroupManagedServiceAccounts) == 0 {
+		msgs = append(msgs, "ActiveDirectoryConfig.GroupManagedServiceAccounts must not be empty")
+	} else {
+		for i, a := range cs.ActiveDirectoryConfig.GroupManagedServiceAccounts {
+			if a.Name == "" || a.Scope == "" {
+				msgs = append(msgs, fmt.Sprintf("ActiveDirectoryConfig.GroupManagedServiceAccounts[%d] must have a Name and Scope", i))
+			}
+		}
+	}
+
+	return msgs
+}
+
+// ValidateProcess reads the process config at processConfig, or starts from
+// an empty process in the default cwd if it is empty, applies overrides to it
+// and validates the result.
+func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *specs.Process) (*specs.Process, error) {
+	spec, err := LoadProcess(processConfig, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateProcessSpec(logger, spec)
+}
+
+// LoadProcess reads the process config at processConfig, or starts from an
+// empty process in the default cwd if it is empty, and applies overrides to
+// it without validating the result.
+func LoadProcess(processConfig string, overrides *specs.Process) (*specs.Process, error) {
 	var spec specs.Process
 
 	if processConfig == "" {
@@ -105,11 +271,26 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 		if overrides.User.Username != "" {
 			spec.User.Username = overrides.User.Username
 		}
+
+		if overrides.Terminal {
+			spec.Terminal = true
+		}
 	}
 
+	return &spec, nil
+}
+
+// ValidateProcessSpec validates process and returns a copy of it with its cwd
+// as a Windows path.
+func ValidateProcessSpec(logger *logrus.Entry, process *specs.Process) (*specs.Process, error) {
+	logger.Debug("validating process config")
+
+	msgs := []string{}
+
+	spec := *process
 	spec.Cwd = toWindowsPath(spec.Cwd)
 
-	if !filepath.IsAbs(spec.Cwd) {
+	if !isAbsWindowsPath(spec.Cwd) {
 		msgs = append(msgs, fmt.Sprintf("cwd %q is not an absolute path", spec.Cwd))
 	}
 
@@ -133,6 +314,147 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 	return &spec, nil
 }
 
+func ValidateResources(logger *logrus.Entry, resourcesConfig string, overrides *specs.WindowsResources) (*specs.WindowsResources, error) {
+	logger.Debug("validating resources config")
+
+	var resources specs.WindowsResources
+
+	if resourcesConfig != "" {
+		content, err := ioutil.ReadFile(resourcesConfig)
+		if err != nil {
+			return nil, &MissingResourcesConfigError{ResourcesConfig: resourcesConfig}
+		}
+		if !utf8.Valid(content) {
+			return nil, &ResourcesConfigInvalidEncodingError{ResourcesConfig: resourcesConfig}
+		}
+		if err = json.Unmarshal(content, &resources); err != nil {
+			return nil, &ResourcesConfigInvalidJSONError{ResourcesConfig: resourcesConfig, InternalError: err}
+		}
+	}
+
+	if overrides != nil {
+		if overrides.Memory != nil && overrides.Memory.Limit != nil {
+			resources.Memory = &specs.WindowsMemoryResources{Limit: overrides.Memory.Limit}
+		}
+
+		if overrides.CPU != nil && overrides.CPU.Shares != nil {
+			if resources.CPU == nil {
+				resources.CPU = &specs.WindowsCPUResources{}
+			}
+			resources.CPU.Shares = overrides.CPU.Shares
+		}
+	}
+
+	msgs := checkResources(resources)
+	if len(msgs) > 0 {
+		for _, m := range msgs {
+			logger.WithField("resourcesConfigError", m).Error("error in resources config")
+		}
+		return nil, &ResourcesConfigValidationError{ErrorMessages: msgs}
+	}
+
+	return &resources, nil
+}
+
+func checkResources(resources specs.WindowsResources) []string {
+	msgs := []string{}
+
+	if resources.Memory == nil && resources.CPU == nil && resources.Storage == nil {
+		return append(msgs, "no resources specified")
+	}
+
+	if resources.CPU != nil {
+		msgs = append(msgs, checkCPU(*resources.CPU)...)
+	}
+	if resources.Storage != nil {
+		msgs = append(msgs, checkStorage(*resources.Storage)...)
+	}
+
+	return msgs
+}
+
+// checkCPU checks the cpu limits are in the ranges HCS accepts. HCS only
+// applies one of count, shares and maximum, so setting more than one of them
+// is rejected rather than having the others silently ignored.
+func checkCPU(cpu specs.WindowsCPUResources) []string {
+	msgs := []string{}
+	set := []string{}
+
+	if cpu.Count != nil {
+		set = append(set, "count")
+		// the processors winc itself may run on can be fewer than those of the
+		// host, so a count beyond the host's is left for HCS to reject
+		if *cpu.Count < 1 {
+			msgs = append(msgs, fmt.Sprintf("cpu count %d must be at least 1", *cpu.Count))
+		}
+	}
+	if cpu.Shares != nil {
+		set = append(set, "shares")
+		if *cpu.Shares < 1 || *cpu.Shares > 10000 {
+			msgs = append(msgs, fmt.Sprintf("cpu shares %d must be between 1 and 10000", *cpu.Shares))
+		}
+	}
+	if cpu.Maximum != nil {
+		set = append(set, "maximum")
+		if *cpu.Maximum < 1 || *cpu.Maximum > 10000 {
+			msgs = append(msgs, fmt.Sprintf("cpu maximum %d must be between 1 and 10000", *cpu.Maximum))
+		}
+	}
+
+	if len(set) > 1 {
+		msgs = append(msgs, fmt.Sprintf("cpu %s are mutually exclusive", strings.Join(set, ", ")))
+	}
+
+	return msgs
+}
+
+// checkMounts checks the mounts are bind mounts HCS can map into the
+// container. A named pipe can only be mapped to another named pipe.
+func checkMounts(mounts []specs.Mount) []string {
+	msgs := []string{}
+	for _, m := range mounts {
+		if m.Type != "" && m.Type != "bind" {
+			msgs = append(msgs, fmt.Sprintf("mount %s has unsupported type %s", m.Destination, m.Type))
+		}
+
+		sourceIsPipe := IsPipe(m.Source)
+		if sourceIsPipe != IsPipe(m.Destination) {
+			msgs = append(msgs, fmt.Sprintf("mount %s must map a named pipe to a named pipe: %s", m.Destination, m.Source))
+		}
+	}
+	return msgs
+}
+
+// IsPipe reports whether a mount source or destination is a named pipe.
+func IsPipe(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), PipePrefix)
+}
+
+// PipeName returns the name of the named pipe at path, stripping PipePrefix in
+// whatever case path spells it. path must be one IsPipe reports as a pipe.
+func PipeName(path string) string {
+	return path[len(PipePrefix):]
+}
+
+// checkStorage checks the storage limits are usable. HCS treats 0 as no limit,
+// so an explicit 0 is rejected rather than silently leaving the disk
+// unconstrained.
+func checkStorage(storage specs.WindowsStorageResources) []string {
+	msgs := []string{}
+
+	if storage.Iops != nil && *storage.Iops == 0 {
+		msgs = append(msgs, "storage iops must be greater than 0")
+	}
+	if storage.Bps != nil && *storage.Bps == 0 {
+		msgs = append(msgs, "storage bps must be greater than 0")
+	}
+	if storage.SandboxSize != nil && *storage.SandboxSize == 0 {
+		msgs = append(msgs, "storage sandboxSize must be greater than 0")
+	}
+
+	return msgs
+}
+
 func envValid(env string) bool {
 	items := strings.Split(env, "=")
 	if len(items) < 2 {
@@ -154,11 +476,3 @@ func checkSemVer(version string) []string {
 
 	return []string{}
 }
-
-func toWindowsPath(input string) string {
-	vol := filepath.VolumeName(input)
-	if vol == "" {
-		input = filepath.Join("C:", input)
-	}
-	return filepath.Clean(input)
-}
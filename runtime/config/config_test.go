@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	goruntime "runtime"
 
 	"golang.org/x/text/encoding/unicode"
 
@@ -79,6 +80,315 @@ var _ = Describe("Config", func() {
 					Expect(spec).To(Equal(&expectedSpec))
 				})
 			})
+
+			Context("when cpu limits are specified", func() {
+				var count uint64
+
+				BeforeEach(func() {
+					count = 1
+					expectedSpec.Windows.Resources = &specs.WindowsResources{
+						CPU: &specs.WindowsCPUResources{Count: &count},
+					}
+				})
+
+				It("does not error", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec).To(Equal(&expectedSpec))
+				})
+
+				Context("when they are out of range", func() {
+					BeforeEach(func() {
+						count = 0
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("cpu count 0 must be at least 1"))
+					})
+				})
+
+				Context("when they are more than the processors winc can run on", func() {
+					BeforeEach(func() {
+						count = uint64(goruntime.NumCPU() + 1)
+					})
+
+					It("leaves them for HCS to check against the processors of the host", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
+
+				Context("when they conflict", func() {
+					BeforeEach(func() {
+						shares, maximum := uint16(100), uint16(5000)
+						expectedSpec.Windows.Resources.CPU.Shares = &shares
+						expectedSpec.Windows.Resources.CPU.Maximum = &maximum
+					})
+
+					It("returns an error describing the conflict", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("cpu count, shares, maximum are mutually exclusive"))
+					})
+				})
+			})
+
+			Context("when storage limits are specified", func() {
+				var iops, bps, sandboxSize uint64
+
+				BeforeEach(func() {
+					iops, bps, sandboxSize = 500, 10*1024*1024, 40*1024*1024*1024
+					expectedSpec.Windows.Resources = &specs.WindowsResources{
+						Storage: &specs.WindowsStorageResources{Iops: &iops, Bps: &bps, SandboxSize: &sandboxSize},
+					}
+				})
+
+				It("does not error", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec).To(Equal(&expectedSpec))
+				})
+
+				Context("when they are 0", func() {
+					BeforeEach(func() {
+						iops, bps, sandboxSize = 0, 0, 0
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("storage iops must be greater than 0"))
+						Expect(err.Error()).To(ContainSubstring("storage bps must be greater than 0"))
+						Expect(err.Error()).To(ContainSubstring("storage sandboxSize must be greater than 0"))
+					})
+				})
+			})
+
+			Context("when mounts are specified", func() {
+				BeforeEach(func() {
+					expectedSpec.Mounts = []specs.Mount{
+						{Source: "C:\\some-dir", Destination: "C:\\dir", Type: "bind"},
+						{Source: "\\\\.\\pipe\\host-agent", Destination: "\\\\.\\pipe\\agent"},
+					}
+				})
+
+				It("does not error", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec).To(Equal(&expectedSpec))
+				})
+
+				Context("when a mount has an unknown type", func() {
+					BeforeEach(func() {
+						expectedSpec.Mounts[0].Type = "tmpfs"
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("mount C:\\dir has unsupported type tmpfs"))
+					})
+				})
+
+				Context("when a named pipe is mapped to a path that is not a named pipe", func() {
+					BeforeEach(func() {
+						expectedSpec.Mounts[1].Destination = "C:\\agent"
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("mount C:\\agent must map a named pipe to a named pipe: \\\\.\\pipe\\host-agent"))
+					})
+				})
+			})
+
+			Context("when a credential spec is specified", func() {
+				const credentialSpecJSON = `{
+					"CmsPlugins": ["ActiveDirectory"],
+					"DomainJoinConfig": {
+						"Sid": "S-1-5-21-1234",
+						"MachineAccountName": "webapp01",
+						"Guid": "2ab3e0d3-8b71-4bf8-8a4a-bd12c1b0a2e3",
+						"DnsTreeName": "contoso.com",
+						"DnsName": "contoso.com",
+						"NetBiosName": "CONTOSO"
+					},
+					"ActiveDirectoryConfig": {
+						"GroupManagedServiceAccounts": [{"Name": "webapp01", "Scope": "contoso.com"}]
+					}
+				}`
+
+				var expectedCredentials string
+
+				BeforeEach(func() {
+					compacted := &bytes.Buffer{}
+					Expect(json.Compact(compacted, []byte(credentialSpecJSON))).To(Succeed())
+					expectedCredentials = compacted.String()
+				})
+
+				Context("inline as an object", func() {
+					BeforeEach(func() {
+						var cs map[string]interface{}
+						Expect(json.Unmarshal([]byte(credentialSpecJSON), &cs)).To(Succeed())
+						expectedSpec.Windows.CredentialSpec = cs
+					})
+
+					It("returns the credential spec as JSON", func() {
+						spec, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).ToNot(HaveOccurred())
+
+						credentials, err := config.CredentialSpec(spec)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(credentials).To(MatchJSON(credentialSpecJSON))
+					})
+				})
+
+				Context("inline as a JSON string", func() {
+					BeforeEach(func() {
+						expectedSpec.Windows.CredentialSpec = credentialSpecJSON
+					})
+
+					It("returns the credential spec as compact JSON", func() {
+						spec, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).ToNot(HaveOccurred())
+
+						credentials, err := config.CredentialSpec(spec)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(credentials).To(Equal(expectedCredentials))
+					})
+				})
+
+				Context("as a path to a JSON file", func() {
+					var credentialSpecFile string
+
+					BeforeEach(func() {
+						credentialSpecFile = filepath.Join(bundlePath, "credspec.json")
+						Expect(ioutil.WriteFile(credentialSpecFile, []byte(credentialSpecJSON), 0644)).To(Succeed())
+						expectedSpec.Windows.CredentialSpec = credentialSpecFile
+					})
+
+					It("returns the contents of the file", func() {
+						spec, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).ToNot(HaveOccurred())
+
+						credentials, err := config.CredentialSpec(spec)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(credentials).To(Equal(expectedCredentials))
+					})
+
+					Context("when the file does not exist", func() {
+						BeforeEach(func() {
+							Expect(os.Remove(credentialSpecFile)).To(Succeed())
+						})
+
+						It("errors", func() {
+							_, err := config.ValidateBundle(logger, bundlePath)
+							Expect(err).To(Equal(&config.MissingCredentialSpecError{CredentialSpec: credentialSpecFile}))
+						})
+					})
+
+					Context("when the file is not valid JSON", func() {
+						BeforeEach(func() {
+							Expect(ioutil.WriteFile(credentialSpecFile, []byte("{"), 0644)).To(Succeed())
+						})
+
+						It("the returned error describes the underlying JSON unmarshal error", func() {
+							_, err := config.ValidateBundle(logger, bundlePath)
+							Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecInvalidJSONError{}))
+							Expect(err.Error()).To(ContainSubstring("unexpected end of JSON input"))
+						})
+					})
+				})
+
+				Context("when it is missing required fields", func() {
+					BeforeEach(func() {
+						expectedSpec.Windows.CredentialSpec = map[string]interface{}{
+							"CmsPlugins": []interface{}{"ActiveDirectory"},
+							"DomainJoinConfig": map[string]interface{}{
+								"Sid":  "S-1-5-21-1234",
+								"Guid": "2ab3e0d3-8b71-4bf8-8a4a-bd12c1b0a2e3",
+							},
+							"ActiveDirectoryConfig": map[string]interface{}{
+								"GroupManagedServiceAccounts": []interface{}{map[string]interface{}{"Name": "webapp01"}},
+							},
+						}
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("DomainJoinConfig.MachineAccountName must not be empty"))
+						Expect(err.Error()).To(ContainSubstring("DomainJoinConfig.DnsName must not be empty"))
+						Expect(err.Error()).To(ContainSubstring("DomainJoinConfig.NetBiosName must not be empty"))
+						Expect(err.Error()).To(ContainSubstring("ActiveDirectoryConfig.GroupManagedServiceAccounts[0] must have a Name and Scope"))
+						Expect(err.Error()).NotTo(ContainSubstring("CmsPlugins"))
+					})
+				})
+
+				Context("when it is not an object or a path", func() {
+					BeforeEach(func() {
+						expectedSpec.Windows.CredentialSpec = []string{"ActiveDirectory"}
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(Equal(&config.CredentialSpecValidationError{
+							ErrorMessages: []string{"credentialSpec must be an object or a path to a JSON file"},
+						}))
+					})
+				})
+			})
+
+			Context("when the isolation annotation is specified", func() {
+				BeforeEach(func() {
+					expectedSpec.Annotations = map[string]string{config.IsolationAnnotation: "hyperv"}
+				})
+
+				It("does not error", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(config.Isolation(spec)).To(Equal(config.HyperVIsolation))
+				})
+
+				Context("when it is not a known isolation", func() {
+					BeforeEach(func() {
+						expectedSpec.Annotations[config.IsolationAnnotation] = "sandboxed"
+					})
+
+					It("returns an error describing what is invalid", func() {
+						_, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("annotation winc.isolation must be process or hyperv: sandboxed"))
+					})
+				})
+			})
+
+			Context("when spec.Windows.HyperV is specified", func() {
+				BeforeEach(func() {
+					expectedSpec.Windows.HyperV = &specs.WindowsHyperV{}
+				})
+
+				It("uses hyperv isolation", func() {
+					spec, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(config.Isolation(spec)).To(Equal(config.HyperVIsolation))
+				})
+
+				Context("when the isolation annotation requests process isolation", func() {
+					BeforeEach(func() {
+						expectedSpec.Annotations = map[string]string{config.IsolationAnnotation: "process"}
+					})
+
+					It("uses process isolation", func() {
+						spec, err := config.ValidateBundle(logger, bundlePath)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(config.Isolation(spec)).To(Equal(config.ProcessIsolation))
+					})
+				})
+			})
 		})
 
 		Context("when provided a nonexistent bundle directory", func() {
@@ -371,6 +681,17 @@ var _ = Describe("Config", func() {
 				})
 			})
 
+			Context("when the overrides request a terminal", func() {
+				BeforeEach(func() {
+					processConfigOverrides = &specs.Process{Terminal: true}
+				})
+
+				It("the process uses a terminal", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(spec.Terminal).To(BeTrue())
+				})
+			})
+
 			Context("when the process config cwd is a unix style path", func() {
 				BeforeEach(func() {
 					processConfigOverrides.Cwd = "/"
@@ -524,4 +845,104 @@ var _ = Describe("Config", func() {
 			})
 		})
 	})
+
+	Context("Resources", func() {
+		var (
+			resources       *specs.WindowsResources
+			err             error
+			resourcesConfig string
+			overrides       *specs.WindowsResources
+		)
+
+		BeforeEach(func() {
+			resourcesConfig = filepath.Join(bundlePath, "resources.json")
+			overrides = nil
+		})
+
+		JustBeforeEach(func() {
+			resources, err = config.ValidateResources(logger, resourcesConfig, overrides)
+		})
+
+		Context("when provided a valid resources config file", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(resourcesConfig, []byte(`{"memory": {"limit": 1024}, "cpu": {"shares": 100}}`), 0666)).To(Succeed())
+			})
+
+			It("returns the resources", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*resources.Memory.Limit).To(Equal(uint64(1024)))
+				Expect(*resources.CPU.Shares).To(Equal(uint16(100)))
+			})
+
+			Context("when overrides are specified", func() {
+				BeforeEach(func() {
+					shares := uint16(200)
+					overrides = &specs.WindowsResources{CPU: &specs.WindowsCPUResources{Shares: &shares}}
+				})
+
+				It("prefers the overrides", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(*resources.Memory.Limit).To(Equal(uint64(1024)))
+					Expect(*resources.CPU.Shares).To(Equal(uint16(200)))
+				})
+			})
+		})
+
+		Context("when the resources config file is not provided", func() {
+			BeforeEach(func() {
+				resourcesConfig = ""
+				limit := uint64(2048)
+				overrides = &specs.WindowsResources{Memory: &specs.WindowsMemoryResources{Limit: &limit}}
+			})
+
+			It("uses the overrides", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*resources.Memory.Limit).To(Equal(uint64(2048)))
+				Expect(resources.CPU).To(BeNil())
+			})
+		})
+
+		Context("when the resources config file does not exist", func() {
+			It("errors", func() {
+				Expect(err).To(MatchError(&config.MissingResourcesConfigError{ResourcesConfig: resourcesConfig}))
+				Expect(resources).To(BeNil())
+			})
+		})
+
+		Context("when the resources config file is not valid JSON", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(resourcesConfig, []byte("{"), 0666)).To(Succeed())
+			})
+
+			It("the returned error describes the underlying JSON unmarshal error", func() {
+				Expect(err).To(BeAssignableToTypeOf(&config.ResourcesConfigInvalidJSONError{}))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("resources config contains invalid JSON: %s: unexpected end of JSON input", resourcesConfig)))
+			})
+		})
+
+		Context("when no resources are specified", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(resourcesConfig, []byte("{}"), 0666)).To(Succeed())
+			})
+
+			It("errors", func() {
+				Expect(err).To(BeAssignableToTypeOf(&config.ResourcesConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("no resources specified"))
+			})
+		})
+
+		Context("when the cpu limits are out of range", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(resourcesConfig, []byte(`{"cpu": {"shares": 0, "maximum": 20000, "count": 0}}`), 0666)).To(Succeed())
+			})
+
+			It("returns an error describing what is invalid", func() {
+				Expect(err).To(BeAssignableToTypeOf(&config.ResourcesConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("cpu shares 0 must be between 1 and 10000"))
+				Expect(err.Error()).To(ContainSubstring("cpu maximum 20000 must be between 1 and 10000"))
+				Expect(err.Error()).To(ContainSubstring("cpu count 0 must be at least 1"))
+				Expect(err.Error()).To(ContainSubstring("cpu count, shares, maximum are mutually exclusive"))
+			})
+		})
+	})
 })
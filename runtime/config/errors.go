@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+
+	"code.cloudfoundry.org/winc/errcode"
 )
 
 type MissingBundleError struct {
@@ -12,6 +14,10 @@ func (e *MissingBundleError) Error() string {
 	return fmt.Sprintf("bundle does not exist: %s", e.BundlePath)
 }
 
+func (e *MissingBundleError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_bundle", Class: errcode.ClassInvalid}
+}
+
 type MissingBundleConfigError struct {
 	BundlePath string
 }
@@ -20,6 +26,10 @@ func (e *MissingBundleConfigError) Error() string {
 	return fmt.Sprintf("bundle %s does not exist: %s", SpecConfig, e.BundlePath)
 }
 
+func (e *MissingBundleConfigError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_bundle_config", Class: errcode.ClassInvalid}
+}
+
 type MissingProcessConfigError struct {
 	ProcessConfig string
 }
@@ -28,6 +38,10 @@ func (e *MissingProcessConfigError) Error() string {
 	return fmt.Sprintf("process config does not exist: %s", e.ProcessConfig)
 }
 
+func (e *MissingProcessConfigError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_process_config", Class: errcode.ClassInvalid}
+}
+
 type BundleConfigInvalidJSONError struct {
 	BundlePath    string
 	InternalError error
@@ -37,6 +51,10 @@ func (e *BundleConfigInvalidJSONError) Error() string {
 	return fmt.Sprintf("bundle %s contains invalid JSON: %s: %s", SpecConfig, e.BundlePath, e.InternalError)
 }
 
+func (e *BundleConfigInvalidJSONError) Code() errcode.Code {
+	return errcode.Code{Name: "bundle_config_invalid_json", Class: errcode.ClassInvalid}
+}
+
 type ProcessConfigInvalidJSONError struct {
 	ProcessConfig string
 	InternalError error
@@ -46,6 +64,10 @@ func (e *ProcessConfigInvalidJSONError) Error() string {
 	return fmt.Sprintf("process config contains invalid JSON: %s: %s", e.ProcessConfig, e.InternalError)
 }
 
+func (e *ProcessConfigInvalidJSONError) Code() errcode.Code {
+	return errcode.Code{Name: "process_config_invalid_json", Class: errcode.ClassInvalid}
+}
+
 type BundleConfigInvalidEncodingError struct {
 	BundlePath string
 }
@@ -54,6 +76,10 @@ func (e *BundleConfigInvalidEncodingError) Error() string {
 	return fmt.Sprintf("bundle %s not encoded in UTF-8: %s", SpecConfig, e.BundlePath)
 }
 
+func (e *BundleConfigInvalidEncodingError) Code() errcode.Code {
+	return errcode.Code{Name: "bundle_config_invalid_encoding", Class: errcode.ClassInvalid}
+}
+
 type ProcessConfigInvalidEncodingError struct {
 	ProcessConfig string
 }
@@ -62,6 +88,10 @@ func (e *ProcessConfigInvalidEncodingError) Error() string {
 	return fmt.Sprintf("process config is not encoded in UTF-8: %s", e.ProcessConfig)
 }
 
+func (e *ProcessConfigInvalidEncodingError) Code() errcode.Code {
+	return errcode.Code{Name: "process_config_invalid_encoding", Class: errcode.ClassInvalid}
+}
+
 type BundleConfigValidationError struct {
 	BundlePath    string
 	ErrorMessages []string
@@ -76,6 +106,10 @@ func (e *BundleConfigValidationError) Error() string {
 	return errorStr
 }
 
+func (e *BundleConfigValidationError) Code() errcode.Code {
+	return errcode.Code{Name: "bundle_config_invalid", Class: errcode.ClassInvalid}
+}
+
 type ProcessConfigValidationError struct {
 	ErrorMessages []string
 }
@@ -88,3 +122,115 @@ func (e *ProcessConfigValidationError) Error() string {
 
 	return errorStr
 }
+
+func (e *ProcessConfigValidationError) Code() errcode.Code {
+	return errcode.Code{Name: "process_config_invalid", Class: errcode.ClassInvalid}
+}
+
+type MissingResourcesConfigError struct {
+	ResourcesConfig string
+}
+
+func (e *MissingResourcesConfigError) Error() string {
+	return fmt.Sprintf("resources config does not exist: %s", e.ResourcesConfig)
+}
+
+func (e *MissingResourcesConfigError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_resources_config", Class: errcode.ClassInvalid}
+}
+
+type ResourcesConfigInvalidJSONError struct {
+	ResourcesConfig string
+	InternalError   error
+}
+
+func (e *ResourcesConfigInvalidJSONError) Error() string {
+	return fmt.Sprintf("resources config contains invalid JSON: %s: %s", e.ResourcesConfig, e.InternalError)
+}
+
+func (e *ResourcesConfigInvalidJSONError) Code() errcode.Code {
+	return errcode.Code{Name: "resources_config_invalid_json", Class: errcode.ClassInvalid}
+}
+
+type ResourcesConfigInvalidEncodingError struct {
+	ResourcesConfig string
+}
+
+func (e *ResourcesConfigInvalidEncodingError) Error() string {
+	return fmt.Sprintf("resources config is not encoded in UTF-8: %s", e.ResourcesConfig)
+}
+
+func (e *ResourcesConfigInvalidEncodingError) Code() errcode.Code {
+	return errcode.Code{Name: "resources_config_invalid_encoding", Class: errcode.ClassInvalid}
+}
+
+type ResourcesConfigValidationError struct {
+	ErrorMessages []string
+}
+
+func (e *ResourcesConfigValidationError) Error() string {
+	errorStr := "resources config is invalid:"
+	for _, m := range e.ErrorMessages {
+		errorStr += "\n\t" + m
+	}
+
+	return errorStr
+}
+
+func (e *ResourcesConfigValidationError) Code() errcode.Code {
+	return errcode.Code{Name: "resources_config_invalid", Class: errcode.ClassInvalid}
+}
+
+type ConsoleSocketError struct {
+	ConsoleSocket string
+}
+
+func (e *ConsoleSocketError) Error() string {
+	return fmt.Sprintf("console socket %s requires process.terminal to be true", e.ConsoleSocket)
+}
+
+func (e *ConsoleSocketError) Code() errcode.Code {
+	return errcode.Code{Name: "console_socket_without_terminal", Class: errcode.ClassInvalid}
+}
+
+type MissingCredentialSpecError struct {
+	CredentialSpec string
+}
+
+func (e *MissingCredentialSpecError) Error() string {
+	return fmt.Sprintf("credential spec does not exist: %s", e.CredentialSpec)
+}
+
+func (e *MissingCredentialSpecError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_credential_spec", Class: errcode.ClassInvalid}
+}
+
+type CredentialSpecInvalidJSONError struct {
+	CredentialSpec string
+	InternalError  error
+}
+
+func (e *CredentialSpecInvalidJSONError) Error() string {
+	return fmt.Sprintf("credential spec contains invalid JSON: %s: %s", e.CredentialSpec, e.InternalError)
+}
+
+func (e *CredentialSpecInvalidJSONError) Code() errcode.Code {
+	return errcode.Code{Name: "credential_spec_invalid_json", Class: errcode.ClassInvalid}
+}
+
+type CredentialSpecValidationError struct {
+	ErrorMessages []string
+}
+
+func (e *CredentialSpecValidationError) Error() string {
+	errorStr := "credential spec is invalid:"
+	for _, m := range e.ErrorMessages {
+		errorStr += "\n\t" + m
+	}
+
+	return errorStr
+}
+
+func (e *CredentialSpecValidationError) Code() errcode.Code {
+	return errcode.Code{Name: "credential_spec_invalid", Class: errcode.ClassInvalid}
+}
@@ -0,0 +1,28 @@
+// +build !windows
+
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// toWindowsPath and isAbsWindowsPath handle the drive letter paths of the
+// container as path/filepath does on Windows.
+func toWindowsPath(input string) string {
+	input = strings.Replace(input, `\`, "/", -1)
+
+	vol := "C:"
+	if len(input) >= 2 && input[1] == ':' {
+		vol, input = input[:2], input[2:]
+	}
+	if input == "" {
+		return vol + "."
+	}
+
+	return vol + strings.Replace(path.Clean(input), "/", `\`, -1)
+}
+
+func isAbsWindowsPath(path string) bool {
+	return len(path) >= 3 && path[1] == ':' && path[2] == '\\'
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"path/filepath"
+)
+
+func toWindowsPath(input string) string {
+	vol := filepath.VolumeName(input)
+	if vol == "" {
+		input = filepath.Join("C:", input)
+	}
+	return filepath.Clean(input)
+}
+
+func isAbsWindowsPath(path string) bool {
+	return filepath.IsAbs(path)
+}
@@ -4,19 +4,40 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/config"
-	"github.com/Microsoft/hcsshim"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-const destroyTimeout = time.Minute
+const (
+	destroyTimeout = time.Minute
+	fileMountDir   = `C:\.winc\mounts`
+)
+
+// Stages of stopping a container, reported by Stop as the one that stopped
+// it.
+const (
+	StopStageAlreadyStopped = "already-stopped"
+	StopStageSignal         = "signal"
+	StopStageShutdown       = "shutdown"
+	StopStageTerminate      = "terminate"
+)
+
+// StopPolicy is how Stop escalates. With a GracePeriod the init process is
+// first sent a console Ctrl-C and given that long to exit. HCS is then asked
+// to shut the container down within ShutdownTimeout, which defaults to a
+// minute, before it is terminated.
+type StopPolicy struct {
+	GracePeriod     time.Duration
+	ShutdownTimeout time.Duration
+}
 
 type Manager struct {
 	logger    *logrus.Entry
@@ -24,6 +45,8 @@ type Manager struct {
 	id        string
 }
 
+// Statistics is the data of a stats event. Its JSON layout follows the one
+// runc uses for types.Stats so that consumers of runc events can read it.
 type Statistics struct {
 	Data struct {
 		CPUStats struct {
@@ -34,26 +57,73 @@ type Statistics struct {
 			} `json:"usage"`
 		} `json:"cpu"`
 		Memory struct {
+			Usage struct {
+				Limit uint64 `json:"limit,omitempty"`
+				Usage uint64 `json:"usage,omitempty"`
+				Max   uint64 `json:"max,omitempty"`
+			} `json:"usage,omitempty"`
 			Raw struct {
-				TotalRss uint64 `json:"total_rss,omitempty"`
+				TotalRss          uint64 `json:"total_rss,omitempty"`
+				PrivateWorkingSet uint64 `json:"private_working_set,omitempty"`
 			} `json:"raw,omitempty"`
 		} `json:"memory,omitempty"`
 		Pids struct {
 			Current uint64 `json:"current,omitempty"`
-			Limit uint64 `json:"limit,omitempty"`
+			Limit   uint64 `json:"limit,omitempty"`
 		} `json:"pids"`
+		Blkio struct {
+			IoServiceBytesRecursive []BlkioEntry `json:"ioServiceBytesRecursive,omitempty"`
+			IoServicedRecursive     []BlkioEntry `json:"ioServicedRecursive,omitempty"`
+		} `json:"blkio"`
+		NetworkInterfaces []NetworkInterface `json:"network_interfaces,omitempty"`
 	} `json:"data,omitempty"`
 }
 
+type BlkioEntry struct {
+	Op    string `json:"op,omitempty"`
+	Value uint64 `json:"value,omitempty"`
+}
+
+// NetworkInterface holds the statistics of a single HNS endpoint attached to
+// the container. Name is the endpoint id.
+type NetworkInterface struct {
+	Name      string
+	RxBytes   uint64
+	RxPackets uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxDropped uint64
+}
+
+// UpdateResult reports which of the resource limits passed to Update were
+// applied by HCS. Rejected maps the name of each refused field to the reason
+// it was refused.
+type UpdateResult struct {
+	Accepted *specs.WindowsResources `json:"accepted"`
+	Rejected map[string]string       `json:"rejected"`
+}
+
+type memorySettings struct {
+	MemoryMaximumInMB int64
+}
+
+type processorSettings struct {
+	ProcessorCount   uint32 `json:",omitempty"`
+	ProcessorWeight  uint64 `json:",omitempty"`
+	ProcessorMaximum int64  `json:",omitempty"`
+}
+
 //go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
-	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
-	GetContainerProperties(string) (hcsshim.ContainerProperties, error)
-	NameToGuid(string) (hcsshim.GUID, error)
-	CreateContainer(string, *hcsshim.ContainerConfig) (hcs.Container, error)
+	GetContainers(hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error)
+	GetContainerProperties(string) (hcs.ContainerProperties, error)
+	NameToGuid(string) (hcs.GUID, error)
+	CreateContainer(string, *hcs.ContainerConfig) (hcs.Container, error)
 	OpenContainer(string) (hcs.Container, error)
 	IsPending(error) bool
-	GetHNSEndpointByName(string) (*hcsshim.HNSEndpoint, error)
+	GetHNSEndpointByName(string) (*hcs.HNSEndpoint, error)
+	SignalProcess(string, int, string) error
 }
 
 func New(logger *logrus.Entry, hcsClient HCSClient, id string) *Manager {
@@ -90,7 +160,16 @@ func (m *Manager) Spec(bundlePath string) (*specs.Spec, error) {
 	return spec, nil
 }
 
-func (m *Manager) Create(spec *specs.Spec) error {
+// Owner returns the owner winc records on the HCS containers it creates with
+// their state under rootDir, which tells them apart from the containers of
+// other tools and other roots.
+func Owner(rootDir string) string {
+	return "winc:" + filepath.Clean(rootDir)
+}
+
+// Create creates and starts the HCS container of spec with owner as its
+// owner. The owner of a sidecar is the container it shares its network with.
+func (m *Manager) Create(spec *specs.Spec, owner string) error {
 	_, err := m.hcsClient.GetContainerProperties(m.id)
 	if err == nil {
 		return &AlreadyExistsError{Id: m.id}
@@ -99,7 +178,7 @@ func (m *Manager) Create(spec *specs.Spec) error {
 		return err
 	}
 
-	layerInfos := []hcsshim.Layer{}
+	layerInfos := []hcs.Layer{}
 	for _, layerPath := range spec.Windows.LayerFolders {
 		layerId := filepath.Base(layerPath)
 		layerGuid, err := m.hcsClient.NameToGuid(layerId)
@@ -107,37 +186,79 @@ func (m *Manager) Create(spec *specs.Spec) error {
 			return err
 		}
 
-		layerInfos = append(layerInfos, hcsshim.Layer{
+		layerInfos = append(layerInfos, hcs.Layer{
 			ID:   layerGuid.ToString(),
 			Path: layerPath,
 		})
 	}
 
-	mappedDirs := []hcsshim.MappedDir{}
-	for _, d := range spec.Mounts {
+	// the links to file mounts are only left in the volume of a container that
+	// was created
+	var links []string
+	created := false
+	defer func() {
+		if !created {
+			m.removeFileMountLinks(links)
+		}
+	}()
+
+	mappedDirs := []hcs.MappedDir{}
+	mappedPipes := []hcs.MappedPipe{}
+	for i, d := range spec.Mounts {
+		if config.IsPipe(d.Source) {
+			mappedPipes = append(mappedPipes, hcs.MappedPipe{
+				HostPath:          d.Source,
+				ContainerPipeName: config.PipeName(d.Destination),
+			})
+			continue
+		}
+
 		fileInfo, err := os.Stat(d.Source)
 		if err != nil {
 			return err
 		}
-		if !fileInfo.IsDir() {
-			logrus.WithField("mount", d.Source).Error("mount is not a directory, ignoring")
-			continue
-		}
 
 		readOnly, err := m.parseMountOptions(d.Options)
 		if err != nil {
 			return err
 		}
 
-		mappedDirs = append(mappedDirs, hcsshim.MappedDir{
-			HostPath:      d.Source,
-			ContainerPath: destToWindowsPath(d.Destination),
-			ReadOnly:      readOnly,
+		if fileInfo.IsDir() {
+			mappedDirs = append(mappedDirs, hcs.MappedDir{
+				HostPath:      d.Source,
+				ContainerPath: destToWindowsPath(d.Destination),
+				ReadOnly:      readOnly,
+			})
+			continue
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return &UnsupportedMountError{Id: m.id, Source: d.Source}
+		}
+
+		if !readOnly {
+			return &ReadWriteFileMountError{Id: m.id, Source: d.Source}
+		}
+
+		// HCS can only map directories, so the parent directory of a file is
+		// mapped read-only and the destination links to the file within it
+		stageDir := filepath.Join(fileMountDir, strconv.Itoa(i))
+		mappedDirs = append(mappedDirs, hcs.MappedDir{
+			HostPath:      filepath.Dir(d.Source),
+			ContainerPath: stageDir,
+			ReadOnly:      true,
 		})
+
+		link, err := linkFileMount(spec.Root.Path, destToWindowsPath(d.Destination), filepath.Join(stageDir, filepath.Base(d.Source)))
+		if err != nil {
+			return err
+		}
+		links = append(links, link)
 	}
 
-	containerConfig := hcsshim.ContainerConfig{
+	containerConfig := hcs.ContainerConfig{
 		SystemType:        "Container",
+		Owner:             owner,
 		HostName:          spec.Hostname,
 		VolumePath:        spec.Root.Path,
 		LayerFolderPath:   "ignored",
@@ -145,6 +266,26 @@ func (m *Manager) Create(spec *specs.Spec) error {
 		MappedDirectories: mappedDirs,
 	}
 
+	if len(mappedPipes) > 0 {
+		containerConfig.MappedPipes = mappedPipes
+	}
+
+	credentials, err := config.CredentialSpec(spec)
+	if err != nil {
+		return err
+	}
+	containerConfig.Credentials = credentials
+
+	if config.Isolation(spec) == config.HyperVIsolation {
+		uvmPath, err := utilityVMPath(spec)
+		if err != nil {
+			return err
+		}
+
+		containerConfig.HvPartition = true
+		containerConfig.HvRuntime = &hcs.HvRuntime{ImagePath: uvmPath}
+	}
+
 	if spec.Windows != nil {
 		if spec.Windows.Resources != nil {
 			if spec.Windows.Resources.Memory != nil {
@@ -154,9 +295,26 @@ func (m *Manager) Create(spec *specs.Spec) error {
 				}
 			}
 			if spec.Windows.Resources.CPU != nil {
+				if spec.Windows.Resources.CPU.Count != nil {
+					containerConfig.ProcessorCount = uint32(*spec.Windows.Resources.CPU.Count)
+				}
 				if spec.Windows.Resources.CPU.Shares != nil {
 					containerConfig.ProcessorWeight = uint64(*spec.Windows.Resources.CPU.Shares)
 				}
+				if spec.Windows.Resources.CPU.Maximum != nil {
+					containerConfig.ProcessorMaximum = int64(*spec.Windows.Resources.CPU.Maximum)
+				}
+			}
+			if spec.Windows.Resources.Storage != nil {
+				if spec.Windows.Resources.Storage.Iops != nil {
+					containerConfig.StorageIOPSMaximum = *spec.Windows.Resources.Storage.Iops
+				}
+				if spec.Windows.Resources.Storage.Bps != nil {
+					containerConfig.StorageBandwidthMaximum = *spec.Windows.Resources.Storage.Bps
+				}
+				if spec.Windows.Resources.Storage.SandboxSize != nil {
+					containerConfig.StorageSandboxSize = *spec.Windows.Resources.Storage.SandboxSize
+				}
 			}
 		}
 
@@ -185,9 +343,28 @@ func (m *Manager) Create(spec *specs.Spec) error {
 		return err
 	}
 
+	created = true
 	return nil
 }
 
+// utilityVMPath returns the utility VM image a Hyper-V container boots. Unless
+// the spec names one, the image of the upper-most layer that ships one is
+// used.
+func utilityVMPath(spec *specs.Spec) (string, error) {
+	if spec.Windows.HyperV != nil && spec.Windows.HyperV.UtilityVMPath != "" {
+		return spec.Windows.HyperV.UtilityVMPath, nil
+	}
+
+	for _, layerPath := range spec.Windows.LayerFolders {
+		uvmPath := filepath.Join(layerPath, "UtilityVM")
+		if fi, err := os.Stat(uvmPath); err == nil && fi.IsDir() {
+			return uvmPath, nil
+		}
+	}
+
+	return "", &MissingUtilityVMError{LayerFolders: spec.Windows.LayerFolders}
+}
+
 func (m *Manager) parseMountOptions(options []string) (bool, error) {
 	hasReadOnly := false
 	hasReadWrite := false
@@ -217,21 +394,36 @@ func (m *Manager) Exec(processSpec *specs.Process, createIOPipes bool) (hcs.Proc
 		return nil, err
 	}
 
+	props, err := m.hcsClient.GetContainerProperties(m.id)
+	if err != nil {
+		return nil, err
+	}
+
+	if props.State == hcs.PausedState {
+		return nil, &InvalidStateError{Id: m.id, Action: "exec in", State: "paused"}
+	}
+
 	env := map[string]string{}
 	for _, e := range processSpec.Env {
 		v := strings.Split(e, "=")
 		env[v[0]] = strings.Join(v[1:], "=")
 	}
 
-	pc := &hcsshim.ProcessConfig{
+	// a console has a single output stream, so a terminal gets no stderr pipe
+	pc := &hcs.ProcessConfig{
 		CommandLine:      makeCmdLine(processSpec.Args),
 		CreateStdInPipe:  createIOPipes,
 		CreateStdOutPipe: createIOPipes,
-		CreateStdErrPipe: createIOPipes,
+		CreateStdErrPipe: createIOPipes && !processSpec.Terminal,
+		EmulateConsole:   processSpec.Terminal,
 		WorkingDirectory: processSpec.Cwd,
 		User:             processSpec.User.Username,
 		Environment:      env,
 	}
+
+	if processSpec.Terminal && processSpec.ConsoleSize != nil {
+		pc.ConsoleSize = [2]uint{processSpec.ConsoleSize.Height, processSpec.ConsoleSize.Width}
+	}
 	p, err := container.CreateProcess(pc)
 	if err != nil {
 		command := ""
@@ -257,29 +449,251 @@ func (m *Manager) Stats() (Statistics, error) {
 
 	containerStats, err := container.Statistics()
 	if err != nil {
-		return stats, err
+		return stats, hcs.CleanError(err)
 	}
 
 	processListItems, err := container.ProcessList()
 	if err != nil {
-		return stats, err
+		return stats, hcs.CleanError(err)
 	}
 
+	stats.Data.Memory.Usage.Usage = containerStats.Memory.UsageCommitBytes
+	stats.Data.Memory.Usage.Max = containerStats.Memory.UsageCommitPeakBytes
 	stats.Data.Memory.Raw.TotalRss = containerStats.Memory.UsageCommitBytes
+	stats.Data.Memory.Raw.PrivateWorkingSet = containerStats.Memory.UsagePrivateWorkingSetBytes
 	stats.Data.CPUStats.CPUUsage.Usage = containerStats.Processor.TotalRuntime100ns * 100
 	stats.Data.CPUStats.CPUUsage.User = containerStats.Processor.RuntimeUser100ns * 100
 	stats.Data.CPUStats.CPUUsage.System = containerStats.Processor.RuntimeKernel100ns * 100
 	stats.Data.Pids.Current = uint64(len(processListItems))
 
+	stats.Data.Blkio.IoServiceBytesRecursive = []BlkioEntry{
+		{Op: "Read", Value: containerStats.Storage.ReadSizeBytes},
+		{Op: "Write", Value: containerStats.Storage.WriteSizeBytes},
+	}
+	stats.Data.Blkio.IoServicedRecursive = []BlkioEntry{
+		{Op: "Read", Value: containerStats.Storage.ReadCountNormalized},
+		{Op: "Write", Value: containerStats.Storage.WriteCountNormalized},
+	}
+
+	for _, network := range containerStats.Network {
+		stats.Data.NetworkInterfaces = append(stats.Data.NetworkInterfaces, NetworkInterface{
+			Name:      network.EndpointId,
+			RxBytes:   network.BytesReceived,
+			RxPackets: network.PacketsReceived,
+			RxDropped: network.DroppedPacketsIncoming,
+			TxBytes:   network.BytesSent,
+			TxPackets: network.PacketsSent,
+			TxDropped: network.DroppedPacketsOutgoing,
+		})
+	}
+
 	return stats, nil
 }
 
+func (m *Manager) ProcessList() ([]hcs.ProcessListItem, error) {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return container.ProcessList()
+}
+
+func (m *Manager) Pause() error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	return container.Pause()
+}
+
+func (m *Manager) Resume() error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	return container.Resume()
+}
+
+// Update asks HCS to apply each of the requested resource limits to the
+// running container one at a time, so that a limit HCS refuses does not
+// prevent the others from being applied. Storage limits are fixed when the
+// sandbox is created and are always rejected.
+func (m *Manager) Update(resources *specs.WindowsResources) (UpdateResult, error) {
+	result := UpdateResult{
+		Accepted: &specs.WindowsResources{},
+		Rejected: map[string]string{},
+	}
+
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return result, err
+	}
+
+	modify := func(field string, resource hcs.ResourceType, settings interface{}) bool {
+		err := container.Modify(&hcs.ResourceModificationRequestResponse{
+			Resource: resource,
+			Data:     settings,
+			Request:  hcs.UpdateRequest,
+		})
+		if err != nil {
+			m.logger.WithField("field", field).Error(err)
+			result.Rejected[field] = err.Error()
+			return false
+		}
+		return true
+	}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil {
+		limit := *resources.Memory.Limit
+		if modify("memory.limit", hcs.MemoryResource, memorySettings{MemoryMaximumInMB: int64(limit / 1024 / 1024)}) {
+			result.Accepted.Memory = &specs.WindowsMemoryResources{Limit: &limit}
+		}
+	}
+
+	if resources.CPU != nil {
+		cpu := &specs.WindowsCPUResources{}
+
+		if resources.CPU.Shares != nil {
+			shares := *resources.CPU.Shares
+			if modify("cpu.shares", hcs.ProcessorResource, processorSettings{ProcessorWeight: uint64(shares)}) {
+				cpu.Shares = &shares
+			}
+		}
+
+		if resources.CPU.Count != nil {
+			count := *resources.CPU.Count
+			if modify("cpu.count", hcs.ProcessorResource, processorSettings{ProcessorCount: uint32(count)}) {
+				cpu.Count = &count
+			}
+		}
+
+		if resources.CPU.Maximum != nil {
+			maximum := *resources.CPU.Maximum
+			if modify("cpu.maximum", hcs.ProcessorResource, processorSettings{ProcessorMaximum: int64(maximum)}) {
+				cpu.Maximum = &maximum
+			}
+		}
+
+		if cpu.Shares != nil || cpu.Count != nil || cpu.Maximum != nil {
+			result.Accepted.CPU = cpu
+		}
+	}
+
+	if resources.Storage != nil {
+		const reason = "storage limits cannot be changed after the container is created"
+		if resources.Storage.Iops != nil {
+			result.Rejected["storage.iops"] = reason
+		}
+		if resources.Storage.Bps != nil {
+			result.Rejected["storage.bps"] = reason
+		}
+		if resources.Storage.SandboxSize != nil {
+			result.Rejected["storage.sandboxSize"] = reason
+		}
+	}
+
+	return result, nil
+}
+
+// Kill delivers signal to the process with the given pid, or to every process
+// in the container when all is set. HCS cannot deliver arbitrary signals, so
+// SIGTERM and SIGINT gracefully shut the whole container down and SIGKILL
+// kills the targeted processes outright.
+func (m *Manager) Kill(pid int, signal syscall.Signal, all bool) error {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return err
+	}
+
+	switch signal {
+	case syscall.SIGTERM, syscall.SIGINT:
+		return m.shutdownContainer(container, destroyTimeout)
+	case syscall.SIGKILL:
+		if !all {
+			return m.killProcess(container, pid)
+		}
+
+		processListItems, err := container.ProcessList()
+		if err != nil {
+			return err
+		}
+
+		var errs []string
+		for _, p := range processListItems {
+			if err := m.killProcess(container, int(p.ProcessId)); err != nil {
+				m.logger.WithField("pid", p.ProcessId).Error(err)
+				errs = append(errs, err.Error())
+			}
+		}
+
+		if len(errs) != 0 {
+			return errors.New(strings.Join(errs, "\n"))
+		}
+
+		return nil
+	default:
+		return &InvalidSignalError{Signal: signal.String()}
+	}
+}
+
+// Wait blocks until the process with the given pid exits and returns its exit
+// code. A timeout of 0 waits indefinitely.
+func (m *Manager) Wait(pid int, timeout time.Duration) (int, error) {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return -1, err
+	}
+
+	p, err := container.OpenProcess(pid)
+	if err != nil {
+		return -1, err
+	}
+	defer p.Close()
+
+	if timeout > 0 {
+		err = p.WaitTimeout(timeout)
+		if hcs.IsTimeout(err) {
+			return -1, &WaitTimeoutError{Id: m.id, Pid: pid, Timeout: timeout}
+		}
+	} else {
+		err = p.Wait()
+	}
+	if err != nil {
+		return -1, err
+	}
+
+	return p.ExitCode()
+}
+
+// OpenProcess opens the process with the given pid. The caller closes it.
+func (m *Manager) OpenProcess(pid int) (hcs.Process, error) {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return container.OpenProcess(pid)
+}
+
+func (m *Manager) killProcess(container hcs.Container, pid int) error {
+	p, err := container.OpenProcess(pid)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	return p.Kill()
+}
+
 func (m *Manager) Delete(force bool) error {
 	container, err := m.hcsClient.OpenContainer(m.id)
 	if err != nil {
 		if force {
 			_, ok := err.(*hcs.NotFoundError)
-			if ok {
+			if ok || hcs.IsNotExist(err) {
 				return nil
 			}
 		}
@@ -301,7 +715,16 @@ func (m *Manager) deleteContainer(container hcs.Container) error {
 			return err
 		}
 	} else {
-		if err := m.shutdownContainer(container); err != nil {
+		// a paused container cannot be shut down gracefully, so resume it
+		// first and fall back to terminating it if that fails
+		if props.State == hcs.PausedState {
+			if err := container.Resume(); err != nil {
+				logrus.Error("hcsContainer.Resume error", err)
+				return m.terminateContainer(container)
+			}
+		}
+
+		if err := m.shutdownContainer(container, destroyTimeout); err != nil {
 			if err := m.terminateContainer(container); err != nil {
 				return err
 			}
@@ -311,10 +734,89 @@ func (m *Manager) deleteContainer(container hcs.Container) error {
 	return nil
 }
 
-func (m *Manager) shutdownContainer(container hcs.Container) error {
+// Stop stops the container following policy and returns the stage that
+// stopped it. pid is the init process, which is only signalled if it is not
+// 0.
+func (m *Manager) Stop(pid int, policy StopPolicy) (string, error) {
+	container, err := m.hcsClient.OpenContainer(m.id)
+	if err != nil {
+		return "", err
+	}
+	defer container.Close()
+
+	props, err := m.hcsClient.GetContainerProperties(m.id)
+	if err != nil {
+		return "", err
+	}
+
+	if props.Stopped {
+		return StopStageAlreadyStopped, nil
+	}
+
+	// a paused container cannot be shut down gracefully, so resume it first
+	// and fall back to terminating it if that fails
+	if props.State == hcs.PausedState {
+		if err := container.Resume(); err != nil {
+			m.logger.WithField("error", err).Warn("failed to resume container, terminating it")
+			return StopStageTerminate, m.terminateContainer(container)
+		}
+	}
+
+	stage := StopStageShutdown
+	if pid != 0 && policy.GracePeriod > 0 && m.signalProcess(container, pid, policy.GracePeriod) {
+		stage = StopStageSignal
+	}
+
+	shutdownTimeout := policy.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = destroyTimeout
+	}
+
+	if err := m.shutdownContainer(container, shutdownTimeout); err != nil {
+		m.logger.WithField("error", err).Warn("failed to shut down container, terminating it")
+		return StopStageTerminate, m.terminateContainer(container)
+	}
+
+	return stage, nil
+}
+
+// signalProcess sends a console Ctrl-C to the process, or a Ctrl-Break if
+// that cannot be delivered, and reports whether it exits within gracePeriod.
+func (m *Manager) signalProcess(container hcs.Container, pid int, gracePeriod time.Duration) bool {
+	logger := m.logger.WithFields(logrus.Fields{"pid": pid, "gracePeriod": gracePeriod})
+
+	p, err := container.OpenProcess(pid)
+	if err != nil {
+		logger.WithField("error", err).Warn("failed to open init process, skipping grace period")
+		return false
+	}
+	defer p.Close()
+
+	if err := m.hcsClient.SignalProcess(m.id, pid, hcs.SignalCtrlC); err != nil {
+		if _, ok := err.(*hcs.SignalNotSupportedError); ok {
+			logger.WithField("error", err).Info("cannot signal init process on this host, skipping grace period")
+			return false
+		}
+
+		logger.WithField("error", err).Debug("failed to send Ctrl-C, sending Ctrl-Break")
+		if err := m.hcsClient.SignalProcess(m.id, pid, hcs.SignalCtrlBreak); err != nil {
+			logger.WithField("error", err).Warn("failed to signal init process, skipping grace period")
+			return false
+		}
+	}
+
+	if err := p.WaitTimeout(gracePeriod); err != nil {
+		logger.WithField("error", err).Info("init process did not exit within the grace period")
+		return false
+	}
+
+	return true
+}
+
+func (m *Manager) shutdownContainer(container hcs.Container, timeout time.Duration) error {
 	if err := container.Shutdown(); err != nil {
 		if m.hcsClient.IsPending(err) {
-			if err := container.WaitTimeout(destroyTimeout); err != nil {
+			if err := container.WaitTimeout(timeout); err != nil {
 				logrus.Error("hcsContainer.WaitTimeout error after Shutdown", err)
 				return err
 			}
@@ -343,12 +845,64 @@ func (m *Manager) terminateContainer(container hcs.Container) error {
 	return nil
 }
 
-func destToWindowsPath(input string) string {
-	vol := filepath.VolumeName(input)
-	if vol == "" {
-		input = filepath.Join("C:", input)
+// ParseSignal accepts a signal name with or without the SIG prefix, or its
+// number, and returns it if winc knows how to deliver it.
+func ParseSignal(signal string) (syscall.Signal, error) {
+	if signal == "" {
+		return syscall.SIGTERM, nil
+	}
+
+	if n, err := strconv.Atoi(signal); err == nil {
+		sig := syscall.Signal(n)
+		for _, s := range supportedSignals {
+			if sig == s {
+				return sig, nil
+			}
+		}
+		return 0, &InvalidSignalError{Signal: signal}
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if sig, ok := supportedSignals[name]; ok {
+		return sig, nil
+	}
+
+	return 0, &InvalidSignalError{Signal: signal}
+}
+
+var supportedSignals = map[string]syscall.Signal{
+	"INT":  syscall.SIGINT,
+	"KILL": syscall.SIGKILL,
+	"TERM": syscall.SIGTERM,
+}
+
+// linkFileMount creates a symlink at dest within the container volume that
+// resolves to target once the volume is the container's C: drive, and returns
+// the path of the symlink on the host.
+func linkFileMount(volumePath, dest, target string) (string, error) {
+	link := filepath.Join(volumePath, strings.TrimPrefix(dest, filepath.VolumeName(dest)))
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		return "", err
+	}
+
+	if fi, err := os.Lstat(link); err == nil {
+		if fi.IsDir() {
+			return "", &FileMountDestinationError{Destination: dest}
+		}
+		if err := os.Remove(link); err != nil {
+			return "", err
+		}
+	}
+
+	return link, os.Symlink(target, link)
+}
+
+func (m *Manager) removeFileMountLinks(links []string) {
+	for _, link := range links {
+		if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+			m.logger.Error(err)
+		}
 	}
-	return filepath.Clean(input)
 }
 
 func makeCmdLine(args []string) string {
@@ -365,7 +919,7 @@ func makeCmdLine(args []string) string {
 		if s != "" {
 			s += " "
 		}
-		s += syscall.EscapeArg(v)
+		s += escapeArg(v)
 	}
 
 	return s
@@ -9,9 +9,9 @@ import (
 
 	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -23,6 +23,7 @@ var _ = Describe("Create", func() {
 		containerVolume = "containervolume"
 		hostName        = "some-hostname"
 		containerId     = "my-container"
+		owner           = "winc:C:\\run\\winc"
 	)
 
 	var (
@@ -64,19 +65,19 @@ var _ = Describe("Create", func() {
 
 	Context("when the specified container does not already exist", func() {
 		var (
-			expectedHcsshimLayers []hcsshim.Layer
+			expectedHcsshimLayers []hcs.Layer
 			fakeContainer         hcsfakes.Container
 		)
 
 		BeforeEach(func() {
 			fakeContainer = hcsfakes.Container{}
-			hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{}, &hcs.NotFoundError{})
+			hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{}, &hcs.NotFoundError{})
 
-			expectedHcsshimLayers = []hcsshim.Layer{}
+			expectedHcsshimLayers = []hcs.Layer{}
 			for i, l := range layerFolders {
-				guid := hcsshim.NewGUID(fmt.Sprintf("layer-%d", i))
+				guid := hcs.NewGUID(fmt.Sprintf("layer-%d", i))
 				hcsClient.NameToGuidReturnsOnCall(i, *guid, nil)
-				expectedHcsshimLayers = append(expectedHcsshimLayers, hcsshim.Layer{
+				expectedHcsshimLayers = append(expectedHcsshimLayers, hcs.Layer{
 					ID:   guid.ToString(),
 					Path: l,
 				})
@@ -87,7 +88,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("creates and starts it", func() {
-			Expect(containerManager.Create(spec)).To(Succeed())
+			Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 			Expect(hcsClient.GetContainerPropertiesCallCount()).To(Equal(1))
 			Expect(hcsClient.GetContainerPropertiesArgsForCall(0)).To(Equal(containerId))
@@ -100,13 +101,14 @@ var _ = Describe("Create", func() {
 			Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 			actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
 			Expect(actualContainerId).To(Equal(containerId))
-			Expect(containerConfig).To(Equal(&hcsshim.ContainerConfig{
+			Expect(containerConfig).To(Equal(&hcs.ContainerConfig{
 				SystemType:        "Container",
+				Owner:             owner,
 				HostName:          hostName,
 				VolumePath:        containerVolume,
 				LayerFolderPath:   "ignored",
 				Layers:            expectedHcsshimLayers,
-				MappedDirectories: []hcsshim.MappedDir{},
+				MappedDirectories: []hcs.MappedDir{},
 			}))
 
 			Expect(fakeContainer.StartCallCount()).To(Equal(1))
@@ -114,7 +116,7 @@ var _ = Describe("Create", func() {
 
 		Context("when mounts are specified in the spec", func() {
 			var (
-				expectedMappedDirs []hcsshim.MappedDir
+				expectedMappedDirs []hcs.MappedDir
 				mount              string
 			)
 
@@ -127,7 +129,7 @@ var _ = Describe("Create", func() {
 					{Source: mount, Destination: "/bar"},
 				}
 
-				expectedMappedDirs = []hcsshim.MappedDir{
+				expectedMappedDirs = []hcs.MappedDir{
 					{HostPath: mount, ContainerPath: "C:\\bar", ReadOnly: true},
 				}
 			})
@@ -144,7 +146,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -161,7 +163,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -178,7 +180,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates the container with the specified mounts", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -193,7 +195,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("errors", func() {
-					err := containerManager.Create(spec)
+					err := containerManager.Create(spec, owner)
 					Expect(err).To(HaveOccurred())
 					Expect(err).To(BeAssignableToTypeOf(&container.InvalidMountOptionsError{}))
 				})
@@ -205,13 +207,16 @@ var _ = Describe("Create", func() {
 				})
 
 				It("errors", func() {
-					err := containerManager.Create(spec)
+					err := containerManager.Create(spec, owner)
 					Expect(os.IsNotExist(err)).To(BeTrue())
 				})
 			})
 
 			Context("when a file is specified as a mount", func() {
-				var mountFile string
+				var (
+					mountFile string
+					volume    string
+				)
 
 				BeforeEach(func() {
 					m, err := ioutil.TempFile("", "mountfile")
@@ -219,23 +224,133 @@ var _ = Describe("Create", func() {
 					Expect(m.Close()).To(Succeed())
 					mountFile = m.Name()
 
+					volume, err = ioutil.TempDir("", "volume")
+					Expect(err).ToNot(HaveOccurred())
+					spec.Root.Path = volume
+
 					spec.Mounts = append(spec.Mounts, specs.Mount{
 						Source:      mountFile,
-						Destination: "foo",
+						Destination: "/etc/certs/foo.pem",
 					})
 				})
 
 				AfterEach(func() {
 					Expect(os.RemoveAll(mountFile)).To(Succeed())
+					Expect(os.RemoveAll(volume)).To(Succeed())
 				})
 
-				It("ignores it", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+				It("maps its parent directory read-only and links the destination to the file", func() {
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
-					actualContainerId, containerConfig := hcsClient.CreateContainerArgsForCall(0)
-					Expect(actualContainerId).To(Equal(containerId))
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.MappedDirectories).To(ConsistOf(append(expectedMappedDirs, hcs.MappedDir{
+						HostPath:      filepath.Dir(mountFile),
+						ContainerPath: "C:\\.winc\\mounts\\1",
+						ReadOnly:      true,
+					})))
+
+					target, err := os.Readlink(filepath.Join(volume, "etc", "certs", "foo.pem"))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(target).To(Equal(filepath.Join("C:\\.winc\\mounts\\1", filepath.Base(mountFile))))
+				})
+
+				Context("when the destination already exists in the volume", func() {
+					BeforeEach(func() {
+						Expect(os.MkdirAll(filepath.Join(volume, "etc", "certs"), 0755)).To(Succeed())
+						Expect(ioutil.WriteFile(filepath.Join(volume, "etc", "certs", "foo.pem"), []byte("old"), 0644)).To(Succeed())
+					})
+
+					It("replaces it with the link", func() {
+						Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+						fi, err := os.Lstat(filepath.Join(volume, "etc", "certs", "foo.pem"))
+						Expect(err).ToNot(HaveOccurred())
+						Expect(fi.Mode() & os.ModeSymlink).To(Equal(os.ModeSymlink))
+					})
+				})
+
+				Context("when the destination is a directory in the volume", func() {
+					BeforeEach(func() {
+						Expect(os.MkdirAll(filepath.Join(volume, "etc", "certs", "foo.pem"), 0755)).To(Succeed())
+					})
+
+					It("errors without creating the container", func() {
+						err := containerManager.Create(spec, owner)
+						Expect(err).To(Equal(&container.FileMountDestinationError{Destination: "C:\\etc\\certs\\foo.pem"}))
+						Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when creating the container fails", func() {
+					BeforeEach(func() {
+						hcsClient.CreateContainerReturns(nil, errors.New("couldn't create"))
+					})
+
+					It("removes the link", func() {
+						Expect(containerManager.Create(spec, owner)).To(MatchError("couldn't create"))
+
+						_, err := os.Lstat(filepath.Join(volume, "etc", "certs", "foo.pem"))
+						Expect(os.IsNotExist(err)).To(BeTrue())
+					})
+				})
+
+				Context("when starting the container fails", func() {
+					BeforeEach(func() {
+						fakeContainer.StartReturns(errors.New("couldn't start"))
+					})
+
+					It("removes the link", func() {
+						Expect(containerManager.Create(spec, owner)).To(MatchError("couldn't start"))
+
+						_, err := os.Lstat(filepath.Join(volume, "etc", "certs", "foo.pem"))
+						Expect(os.IsNotExist(err)).To(BeTrue())
+					})
+				})
+
+				Context("when the mount options specify rw", func() {
+					BeforeEach(func() {
+						spec.Mounts[1].Options = []string{"bind", "rw"}
+					})
+
+					It("errors without creating the container", func() {
+						err := containerManager.Create(spec, owner)
+						Expect(err).To(Equal(&container.ReadWriteFileMountError{Id: containerId, Source: mountFile}))
+						Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when a named pipe is specified as a mount", func() {
+				BeforeEach(func() {
+					spec.Mounts = append(spec.Mounts, specs.Mount{
+						Source:      "\\\\.\\pipe\\host-agent",
+						Destination: "\\\\.\\pipe\\agent",
+					})
+				})
+
+				It("creates the container with the pipe mapped", func() {
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
 					Expect(containerConfig.MappedDirectories).To(ConsistOf(expectedMappedDirs))
+					Expect(containerConfig.MappedPipes).To(Equal([]hcs.MappedPipe{
+						{HostPath: "\\\\.\\pipe\\host-agent", ContainerPipeName: "agent"},
+					}))
+				})
+
+				Context("when the destination spells the pipe prefix in another case", func() {
+					BeforeEach(func() {
+						spec.Mounts[1].Destination = "\\\\.\\PIPE\\agent"
+					})
+
+					It("maps the pipe under its name", func() {
+						Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+						_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+						Expect(containerConfig.MappedPipes[0].ContainerPipeName).To(Equal("agent"))
+					})
 				})
 			})
 		})
@@ -254,7 +369,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the container with the specified memory limits", func() {
-				Expect(containerManager.Create(spec)).To(Succeed())
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -275,7 +390,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("creates the container with the specified cpu limits", func() {
-				Expect(containerManager.Create(spec)).To(Succeed())
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -283,6 +398,215 @@ var _ = Describe("Create", func() {
 			})
 		})
 
+		Context("when cpu count and maximum are specified in the spec", func() {
+			var (
+				count   uint64
+				maximum uint16
+			)
+
+			BeforeEach(func() {
+				count = 2
+				maximum = 5000
+				spec.Windows.Resources = &specs.WindowsResources{
+					CPU: &specs.WindowsCPUResources{
+						Count:   &count,
+						Maximum: &maximum,
+					},
+				}
+			})
+
+			It("creates the container with the specified processor count and maximum", func() {
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig).To(Equal(&hcs.ContainerConfig{
+					SystemType:        "Container",
+					HostName:          hostName,
+					VolumePath:        containerVolume,
+					LayerFolderPath:   "ignored",
+					Layers:            expectedHcsshimLayers,
+					MappedDirectories: []hcs.MappedDir{},
+					ProcessorCount:    2,
+					ProcessorMaximum:  5000,
+				}))
+			})
+		})
+
+		Context("when storage limits are specified in the spec", func() {
+			var iops, bps, sandboxSize uint64
+
+			BeforeEach(func() {
+				iops = 500
+				bps = 10 * 1024 * 1024
+				sandboxSize = 40 * 1024 * 1024 * 1024
+				spec.Windows.Resources = &specs.WindowsResources{
+					Storage: &specs.WindowsStorageResources{
+						Iops:        &iops,
+						Bps:         &bps,
+						SandboxSize: &sandboxSize,
+					},
+				}
+			})
+
+			It("creates the container with the specified storage limits", func() {
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig).To(Equal(&hcs.ContainerConfig{
+					SystemType:              "Container",
+					HostName:                hostName,
+					VolumePath:              containerVolume,
+					LayerFolderPath:         "ignored",
+					Layers:                  expectedHcsshimLayers,
+					MappedDirectories:       []hcs.MappedDir{},
+					StorageIOPSMaximum:      500,
+					StorageBandwidthMaximum: 10 * 1024 * 1024,
+					StorageSandboxSize:      40 * 1024 * 1024 * 1024,
+				}))
+			})
+		})
+
+		Context("when a credential spec is specified in the spec", func() {
+			BeforeEach(func() {
+				spec.Windows.CredentialSpec = map[string]interface{}{
+					"CmsPlugins": []interface{}{"ActiveDirectory"},
+					"DomainJoinConfig": map[string]interface{}{
+						"Sid":                "S-1-5-21-1234",
+						"MachineAccountName": "webapp01",
+						"Guid":               "2ab3e0d3-8b71-4bf8-8a4a-bd12c1b0a2e3",
+						"DnsName":            "contoso.com",
+						"NetBiosName":        "CONTOSO",
+					},
+					"ActiveDirectoryConfig": map[string]interface{}{
+						"GroupManagedServiceAccounts": []interface{}{
+							map[string]interface{}{"Name": "webapp01", "Scope": "contoso.com"},
+						},
+					},
+				}
+			})
+
+			It("creates the container with the credential spec", func() {
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.Credentials).To(MatchJSON(`{
+					"CmsPlugins": ["ActiveDirectory"],
+					"DomainJoinConfig": {
+						"Sid": "S-1-5-21-1234",
+						"MachineAccountName": "webapp01",
+						"Guid": "2ab3e0d3-8b71-4bf8-8a4a-bd12c1b0a2e3",
+						"DnsName": "contoso.com",
+						"NetBiosName": "CONTOSO"
+					},
+					"ActiveDirectoryConfig": {
+						"GroupManagedServiceAccounts": [{"Name": "webapp01", "Scope": "contoso.com"}]
+					}
+				}`))
+			})
+
+			Context("when the credential spec is malformed", func() {
+				BeforeEach(func() {
+					spec.Windows.CredentialSpec = map[string]interface{}{"CmsPlugins": []interface{}{"ActiveDirectory"}}
+				})
+
+				It("errors without creating the container", func() {
+					err := containerManager.Create(spec, owner)
+					Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecValidationError{}))
+					Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when hyperv isolation is specified in the spec", func() {
+			BeforeEach(func() {
+				spec.Windows.HyperV = &specs.WindowsHyperV{UtilityVMPath: "some-uvm-path"}
+			})
+
+			It("creates the container in a utility VM", func() {
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+				Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig).To(Equal(&hcs.ContainerConfig{
+					SystemType:        "Container",
+					HostName:          hostName,
+					VolumePath:        containerVolume,
+					LayerFolderPath:   "ignored",
+					Layers:            expectedHcsshimLayers,
+					MappedDirectories: []hcs.MappedDir{},
+					HvPartition:       true,
+					HvRuntime:         &hcs.HvRuntime{ImagePath: "some-uvm-path"},
+				}))
+			})
+
+			Context("when the utility VM path is not specified", func() {
+				var uvmLayer string
+
+				BeforeEach(func() {
+					var err error
+					uvmLayer, err = ioutil.TempDir("", "uvm-layer")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(os.MkdirAll(filepath.Join(uvmLayer, "UtilityVM"), 0755)).To(Succeed())
+
+					layerFolders[1] = uvmLayer
+					spec.Windows.HyperV.UtilityVMPath = ""
+				})
+
+				AfterEach(func() {
+					Expect(os.RemoveAll(uvmLayer)).To(Succeed())
+				})
+
+				It("uses the utility VM image from the layers", func() {
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+					Expect(containerConfig.HvPartition).To(BeTrue())
+					Expect(containerConfig.HvRuntime).To(Equal(&hcs.HvRuntime{ImagePath: filepath.Join(uvmLayer, "UtilityVM")}))
+				})
+			})
+
+			Context("when no layer contains a utility VM image", func() {
+				BeforeEach(func() {
+					spec.Windows.HyperV.UtilityVMPath = ""
+				})
+
+				It("errors without creating the container", func() {
+					err := containerManager.Create(spec, owner)
+					Expect(err).To(Equal(&container.MissingUtilityVMError{LayerFolders: layerFolders}))
+					Expect(hcsClient.CreateContainerCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when hyperv isolation is requested by annotation", func() {
+			var uvmLayer string
+
+			BeforeEach(func() {
+				var err error
+				uvmLayer, err = ioutil.TempDir("", "uvm-layer")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.MkdirAll(filepath.Join(uvmLayer, "UtilityVM"), 0755)).To(Succeed())
+
+				layerFolders[2] = uvmLayer
+				spec.Annotations = map[string]string{config.IsolationAnnotation: config.HyperVIsolation}
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(uvmLayer)).To(Succeed())
+			})
+
+			It("creates the container in a utility VM", func() {
+				Expect(containerManager.Create(spec, owner)).To(Succeed())
+
+				_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
+				Expect(containerConfig.HvPartition).To(BeTrue())
+				Expect(containerConfig.HvRuntime).To(Equal(&hcs.HvRuntime{ImagePath: filepath.Join(uvmLayer, "UtilityVM")}))
+			})
+		})
+
 		Context("when network settings are specified in the spec", func() {
 			Context("when NetworkSharedContainerName is specified", func() {
 				var (
@@ -295,11 +619,11 @@ var _ = Describe("Create", func() {
 					sharedEndpointId = "some-shared-endpoint-id"
 					spec.Windows.Network = &specs.WindowsNetwork{NetworkSharedContainerName: networkSharedContainerName}
 
-					hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: sharedEndpointId}, nil)
+					hcsClient.GetHNSEndpointByNameReturns(&hcs.HNSEndpoint{Id: sharedEndpointId}, nil)
 				})
 
 				It("creates the container with a NetworkSharedContainerName and EndpointList", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -316,7 +640,7 @@ var _ = Describe("Create", func() {
 					})
 
 					It("returns an error", func() {
-						err := containerManager.Create(spec)
+						err := containerManager.Create(spec, owner)
 						Expect(err).To(MatchError("couldn't get endpoint"))
 					})
 				})
@@ -328,7 +652,7 @@ var _ = Describe("Create", func() {
 				})
 
 				It("creates a container without a NetworkSharedContainerName or EndpointList", func() {
-					Expect(containerManager.Create(spec)).To(Succeed())
+					Expect(containerManager.Create(spec, owner)).To(Succeed())
 
 					Expect(hcsClient.CreateContainerCallCount()).To(Equal(1))
 					_, containerConfig := hcsClient.CreateContainerArgsForCall(0)
@@ -344,7 +668,7 @@ var _ = Describe("Create", func() {
 			})
 
 			It("returns an error", func() {
-				err := containerManager.Create(spec)
+				err := containerManager.Create(spec, owner)
 				Expect(err).To(MatchError("couldn't create"))
 			})
 		})
@@ -352,11 +676,11 @@ var _ = Describe("Create", func() {
 		Context("when container Start fails", func() {
 			BeforeEach(func() {
 				fakeContainer.StartReturns(errors.New("couldn't start"))
-				hcsClient.GetContainerPropertiesReturnsOnCall(1, hcsshim.ContainerProperties{Stopped: true}, nil)
+				hcsClient.GetContainerPropertiesReturnsOnCall(1, hcs.ContainerProperties{Stopped: true}, nil)
 			})
 
 			It("closes but doesn't shutdown or terminate the container", func() {
-				err := containerManager.Create(spec)
+				err := containerManager.Create(spec, owner)
 				Expect(err).To(MatchError("couldn't start"))
 
 				Expect(fakeContainer.CloseCallCount()).To(Equal(1))
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"io/ioutil"
 
+	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
-	"github.com/Microsoft/hcsshim"
 	"github.com/sirupsen/logrus"
 
 	. "github.com/onsi/ginkgo"
@@ -52,7 +52,7 @@ var _ = Describe("Delete", func() {
 
 		Context("when the container was never started", func() {
 			BeforeEach(func() {
-				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{Stopped: true}, nil)
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{Stopped: true}, nil)
 			})
 
 			It("closes the container but skips shutting down and terminating it", func() {
@@ -76,6 +76,33 @@ var _ = Describe("Delete", func() {
 			})
 		})
 
+		Context("when the container is paused", func() {
+			BeforeEach(func() {
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{State: "Paused"}, nil)
+			})
+
+			It("resumes the container before shutting it down", func() {
+				Expect(containerManager.Delete(false)).To(Succeed())
+
+				Expect(fakeContainer.ResumeCallCount()).To(Equal(1))
+				Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+			})
+
+			Context("when resuming the container fails", func() {
+				BeforeEach(func() {
+					fakeContainer.ResumeReturns(errors.New("resume failed"))
+				})
+
+				It("terminates the container", func() {
+					Expect(containerManager.Delete(false)).To(Succeed())
+
+					Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
+					Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+				})
+			})
+		})
+
 		Context("when shutting down the container does not immediately succeed", func() {
 			var shutdownContainerError = errors.New("shutdown container failed")
 
@@ -160,5 +187,15 @@ var _ = Describe("Delete", func() {
 		It("errors", func() {
 			Expect(containerManager.Delete(false)).To(Equal(openContainerError))
 		})
+
+		Context("HCS reports that it does not exist and force is true", func() {
+			BeforeEach(func() {
+				hcsClient.OpenContainerReturns(nil, hcs.ErrComputeSystemDoesNotExist)
+			})
+
+			It("succeeds", func() {
+				Expect(containerManager.Delete(true)).To(Succeed())
+			})
+		})
 	})
 })
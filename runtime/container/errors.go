@@ -1,6 +1,12 @@
 package container
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/errcode"
+)
 
 type AlreadyExistsError struct {
 	Id string
@@ -10,6 +16,10 @@ func (e *AlreadyExistsError) Error() string {
 	return fmt.Sprintf("container with id already exists: %s", e.Id)
 }
 
+func (e *AlreadyExistsError) Code() errcode.Code {
+	return errcode.Code{Name: "container_already_exists", Class: errcode.ClassConflict}
+}
+
 type InvalidIdError struct {
 	Id string
 }
@@ -18,6 +28,10 @@ func (e *InvalidIdError) Error() string {
 	return fmt.Sprintf("container id does not match bundle directory name: %s", e.Id)
 }
 
+func (e *InvalidIdError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_container_id", Class: errcode.ClassInvalid}
+}
+
 type MissingVolumePathError struct {
 	Id string
 }
@@ -26,6 +40,10 @@ func (e *MissingVolumePathError) Error() string {
 	return fmt.Sprintf("could not get volume path for container: %s", e.Id)
 }
 
+func (e *MissingVolumePathError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_volume_path", Class: errcode.ClassInternal}
+}
+
 type CouldNotCreateProcessError struct {
 	Id      string
 	Command string
@@ -35,6 +53,10 @@ func (e *CouldNotCreateProcessError) Error() string {
 	return fmt.Sprintf("could not start command '%s' in container: %s", e.Command, e.Id)
 }
 
+func (e *CouldNotCreateProcessError) Code() errcode.Code {
+	return errcode.Code{Name: "process_not_created", Class: errcode.ClassInternal}
+}
+
 type InvalidMountOptionsError struct {
 	Id      string
 	Options []string
@@ -43,3 +65,110 @@ type InvalidMountOptionsError struct {
 func (e *InvalidMountOptionsError) Error() string {
 	return fmt.Sprintf("invalid mount options for container %s: %+v", e.Id, e.Options)
 }
+
+func (e *InvalidMountOptionsError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_mount_options", Class: errcode.ClassInvalid}
+}
+
+type InvalidSignalError struct {
+	Signal string
+}
+
+func (e *InvalidSignalError) Error() string {
+	return fmt.Sprintf("invalid signal: %s", e.Signal)
+}
+
+func (e *InvalidSignalError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_signal", Class: errcode.ClassInvalid}
+}
+
+type InvalidStateError struct {
+	Id     string
+	Action string
+	State  string
+}
+
+func (e *InvalidStateError) Error() string {
+	return fmt.Sprintf("cannot %s container %s in the %s state", e.Action, e.Id, e.State)
+}
+
+func (e *InvalidStateError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_container_state", Class: errcode.ClassConflict}
+}
+
+type ResourcesRejectedError struct {
+	Id     string
+	Fields []string
+}
+
+func (e *ResourcesRejectedError) Error() string {
+	return fmt.Sprintf("container %s rejected resource updates: %s", e.Id, strings.Join(e.Fields, ", "))
+}
+
+func (e *ResourcesRejectedError) Code() errcode.Code {
+	return errcode.Code{Name: "resources_rejected", Class: errcode.ClassResource}
+}
+
+type WaitTimeoutError struct {
+	Id      string
+	Pid     int
+	Timeout time.Duration
+}
+
+func (e *WaitTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for process %d in container %s", e.Timeout, e.Pid, e.Id)
+}
+
+func (e *WaitTimeoutError) Code() errcode.Code {
+	return errcode.Code{Name: "wait_timeout", Class: errcode.ClassTimeout}
+}
+
+type MissingUtilityVMError struct {
+	LayerFolders []string
+}
+
+func (e *MissingUtilityVMError) Error() string {
+	return fmt.Sprintf("no utility VM image found in layers: %s", strings.Join(e.LayerFolders, ", "))
+}
+
+func (e *MissingUtilityVMError) Code() errcode.Code {
+	return errcode.Code{Name: "missing_utility_vm", Class: errcode.ClassInvalid}
+}
+
+type UnsupportedMountError struct {
+	Id     string
+	Source string
+}
+
+func (e *UnsupportedMountError) Error() string {
+	return fmt.Sprintf("mount source in container %s is not a directory, file or named pipe: %s", e.Id, e.Source)
+}
+
+func (e *UnsupportedMountError) Code() errcode.Code {
+	return errcode.Code{Name: "unsupported_mount", Class: errcode.ClassInvalid}
+}
+
+type ReadWriteFileMountError struct {
+	Id     string
+	Source string
+}
+
+func (e *ReadWriteFileMountError) Error() string {
+	return fmt.Sprintf("file mounts in container %s are read-only, but rw was requested: %s", e.Id, e.Source)
+}
+
+func (e *ReadWriteFileMountError) Code() errcode.Code {
+	return errcode.Code{Name: "read_write_file_mount", Class: errcode.ClassInvalid}
+}
+
+type FileMountDestinationError struct {
+	Destination string
+}
+
+func (e *FileMountDestinationError) Error() string {
+	return fmt.Sprintf("file mount destination is a directory: %s", e.Destination)
+}
+
+func (e *FileMountDestinationError) Code() errcode.Code {
+	return errcode.Code{Name: "file_mount_destination_is_directory", Class: errcode.ClassInvalid}
+}
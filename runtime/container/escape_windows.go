@@ -0,0 +1,9 @@
+package container
+
+import (
+	"syscall"
+)
+
+func escapeArg(s string) string {
+	return syscall.EscapeArg(s)
+}
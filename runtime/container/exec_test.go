@@ -5,11 +5,11 @@ import (
 	"io/ioutil"
 	"syscall"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -38,7 +38,7 @@ var _ = Describe("Exec", func() {
 	})
 
 	Context("when the specified container exists", func() {
-		var expectedProcessConfig *hcsshim.ProcessConfig
+		var expectedProcessConfig *hcs.ProcessConfig
 
 		BeforeEach(func() {
 			hcsClient.OpenContainerReturns(fakeContainer, nil)
@@ -51,7 +51,7 @@ var _ = Describe("Exec", func() {
 				},
 				Env: []string{"a=b", "c=d"},
 			}
-			expectedProcessConfig = &hcsshim.ProcessConfig{
+			expectedProcessConfig = &hcs.ProcessConfig{
 				CommandLine:      `powershell.exe "Write-Host 'hi'"`,
 				CreateStdInPipe:  true,
 				CreateStdErrPipe: true,
@@ -85,13 +85,29 @@ var _ = Describe("Exec", func() {
 			})
 		})
 
+		Context("when the process uses a terminal", func() {
+			BeforeEach(func() {
+				processSpec.Terminal = true
+				processSpec.ConsoleSize = &specs.Box{Height: 40, Width: 120}
+				expectedProcessConfig.CreateStdErrPipe = false
+				expectedProcessConfig.EmulateConsole = true
+				expectedProcessConfig.ConsoleSize = [2]uint{40, 120}
+			})
+
+			It("creates a process with an emulated console and no stderr pipe", func() {
+				_, err := containerManager.Exec(&processSpec, true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeContainer.CreateProcessArgsForCall(0)).To(Equal(expectedProcessConfig))
+			})
+		})
+
 		Context("when a command and arguments contain spaces", func() {
 			It("quotes the argument", func() {
 				commandArgs := []string{"command with spaces.exe", "arg with spaces", "other arg"}
 				processSpec = specs.Process{
 					Args: commandArgs,
 				}
-				expectedProcessConfig = &hcsshim.ProcessConfig{
+				expectedProcessConfig = &hcs.ProcessConfig{
 					CommandLine:      `"command with spaces.exe" "arg with spaces" "other arg"`,
 					CreateStdInPipe:  true,
 					CreateStdErrPipe: true,
@@ -111,7 +127,7 @@ var _ = Describe("Exec", func() {
 				processSpec = specs.Process{
 					Args: commandArgs,
 				}
-				expectedProcessConfig = &hcsshim.ProcessConfig{
+				expectedProcessConfig = &hcs.ProcessConfig{
 					CommandLine:      `command.exe "" ""`,
 					CreateStdInPipe:  true,
 					CreateStdErrPipe: true,
@@ -131,7 +147,7 @@ var _ = Describe("Exec", func() {
 				processSpec = specs.Process{
 					Args: commandArgs,
 				}
-				expectedProcessConfig = &hcsshim.ProcessConfig{
+				expectedProcessConfig = &hcs.ProcessConfig{
 					CommandLine:      `command.exe`,
 					CreateStdInPipe:  true,
 					CreateStdErrPipe: true,
@@ -151,7 +167,7 @@ var _ = Describe("Exec", func() {
 				processSpec = specs.Process{
 					Args: commandArgs,
 				}
-				expectedProcessConfig = &hcsshim.ProcessConfig{
+				expectedProcessConfig = &hcs.ProcessConfig{
 					CommandLine:      `\path\to\command.exe`,
 					CreateStdInPipe:  true,
 					CreateStdErrPipe: true,
@@ -165,6 +181,31 @@ var _ = Describe("Exec", func() {
 			})
 		})
 
+		Context("when the container is paused", func() {
+			BeforeEach(func() {
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{State: "Paused"}, nil)
+			})
+
+			It("refuses to create a process", func() {
+				p, err := containerManager.Exec(&processSpec, true)
+				Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "exec in", State: "paused"}))
+				Expect(p).To(BeNil())
+				Expect(hcsClient.GetContainerPropertiesArgsForCall(0)).To(Equal(containerId))
+				Expect(fakeContainer.CreateProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when getting the container properties fails", func() {
+			BeforeEach(func() {
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{}, errors.New("properties failed"))
+			})
+
+			It("errors", func() {
+				_, err := containerManager.Exec(&processSpec, true)
+				Expect(err).To(MatchError("properties failed"))
+			})
+		})
+
 		Context("when creating a process in the container fails due to low memory", func() {
 			var couldNotCreateProcessError *container.CouldNotCreateProcessError
 
@@ -173,7 +214,7 @@ var _ = Describe("Exec", func() {
 					Id:      containerId,
 					Command: "powershell.exe",
 				}
-				fakeCreateProcessError := &hcsshim.ContainerError{
+				fakeCreateProcessError := &hcs.ContainerError{
 					Err: syscall.Errno(0x5af),
 				}
 				fakeContainer.CreateProcessReturns(nil, fakeCreateProcessError)
@@ -6,54 +6,53 @@ import (
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/container"
-	"github.com/Microsoft/hcsshim"
 )
 
 type HCSClient struct {
-	GetContainersStub        func(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
+	GetContainersStub        func(hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error)
 	getContainersMutex       sync.RWMutex
 	getContainersArgsForCall []struct {
-		arg1 hcsshim.ComputeSystemQuery
+		arg1 hcs.ComputeSystemQuery
 	}
 	getContainersReturns struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}
 	getContainersReturnsOnCall map[int]struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}
-	GetContainerPropertiesStub        func(string) (hcsshim.ContainerProperties, error)
+	GetContainerPropertiesStub        func(string) (hcs.ContainerProperties, error)
 	getContainerPropertiesMutex       sync.RWMutex
 	getContainerPropertiesArgsForCall []struct {
 		arg1 string
 	}
 	getContainerPropertiesReturns struct {
-		result1 hcsshim.ContainerProperties
+		result1 hcs.ContainerProperties
 		result2 error
 	}
 	getContainerPropertiesReturnsOnCall map[int]struct {
-		result1 hcsshim.ContainerProperties
+		result1 hcs.ContainerProperties
 		result2 error
 	}
-	NameToGuidStub        func(string) (hcsshim.GUID, error)
+	NameToGuidStub        func(string) (hcs.GUID, error)
 	nameToGuidMutex       sync.RWMutex
 	nameToGuidArgsForCall []struct {
 		arg1 string
 	}
 	nameToGuidReturns struct {
-		result1 hcsshim.GUID
+		result1 hcs.GUID
 		result2 error
 	}
 	nameToGuidReturnsOnCall map[int]struct {
-		result1 hcsshim.GUID
+		result1 hcs.GUID
 		result2 error
 	}
-	CreateContainerStub        func(string, *hcsshim.ContainerConfig) (hcs.Container, error)
+	CreateContainerStub        func(string, *hcs.ContainerConfig) (hcs.Container, error)
 	createContainerMutex       sync.RWMutex
 	createContainerArgsForCall []struct {
 		arg1 string
-		arg2 *hcsshim.ContainerConfig
+		arg2 *hcs.ContainerConfig
 	}
 	createContainerReturns struct {
 		result1 hcs.Container
@@ -87,28 +86,41 @@ type HCSClient struct {
 	isPendingReturnsOnCall map[int]struct {
 		result1 bool
 	}
-	GetHNSEndpointByNameStub        func(string) (*hcsshim.HNSEndpoint, error)
+	GetHNSEndpointByNameStub        func(string) (*hcs.HNSEndpoint, error)
 	getHNSEndpointByNameMutex       sync.RWMutex
 	getHNSEndpointByNameArgsForCall []struct {
 		arg1 string
 	}
 	getHNSEndpointByNameReturns struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
 	getHNSEndpointByNameReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}
+	SignalProcessStub        func(string, int, string) error
+	signalProcessMutex       sync.RWMutex
+	signalProcessArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 string
+	}
+	signalProcessReturns struct {
+		result1 error
+	}
+	signalProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *HCSClient) GetContainers(arg1 hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error) {
+func (fake *HCSClient) GetContainers(arg1 hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error) {
 	fake.getContainersMutex.Lock()
 	ret, specificReturn := fake.getContainersReturnsOnCall[len(fake.getContainersArgsForCall)]
 	fake.getContainersArgsForCall = append(fake.getContainersArgsForCall, struct {
-		arg1 hcsshim.ComputeSystemQuery
+		arg1 hcs.ComputeSystemQuery
 	}{arg1})
 	fake.recordInvocation("GetContainers", []interface{}{arg1})
 	fake.getContainersMutex.Unlock()
@@ -127,35 +139,35 @@ func (fake *HCSClient) GetContainersCallCount() int {
 	return len(fake.getContainersArgsForCall)
 }
 
-func (fake *HCSClient) GetContainersArgsForCall(i int) hcsshim.ComputeSystemQuery {
+func (fake *HCSClient) GetContainersArgsForCall(i int) hcs.ComputeSystemQuery {
 	fake.getContainersMutex.RLock()
 	defer fake.getContainersMutex.RUnlock()
 	return fake.getContainersArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetContainersReturns(result1 []hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSClient) GetContainersReturns(result1 []hcs.ContainerProperties, result2 error) {
 	fake.GetContainersStub = nil
 	fake.getContainersReturns = struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetContainersReturnsOnCall(i int, result1 []hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSClient) GetContainersReturnsOnCall(i int, result1 []hcs.ContainerProperties, result2 error) {
 	fake.GetContainersStub = nil
 	if fake.getContainersReturnsOnCall == nil {
 		fake.getContainersReturnsOnCall = make(map[int]struct {
-			result1 []hcsshim.ContainerProperties
+			result1 []hcs.ContainerProperties
 			result2 error
 		})
 	}
 	fake.getContainersReturnsOnCall[i] = struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetContainerProperties(arg1 string) (hcsshim.ContainerProperties, error) {
+func (fake *HCSClient) GetContainerProperties(arg1 string) (hcs.ContainerProperties, error) {
 	fake.getContainerPropertiesMutex.Lock()
 	ret, specificReturn := fake.getContainerPropertiesReturnsOnCall[len(fake.getContainerPropertiesArgsForCall)]
 	fake.getContainerPropertiesArgsForCall = append(fake.getContainerPropertiesArgsForCall, struct {
@@ -184,29 +196,29 @@ func (fake *HCSClient) GetContainerPropertiesArgsForCall(i int) string {
 	return fake.getContainerPropertiesArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetContainerPropertiesReturns(result1 hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSClient) GetContainerPropertiesReturns(result1 hcs.ContainerProperties, result2 error) {
 	fake.GetContainerPropertiesStub = nil
 	fake.getContainerPropertiesReturns = struct {
-		result1 hcsshim.ContainerProperties
+		result1 hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetContainerPropertiesReturnsOnCall(i int, result1 hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSClient) GetContainerPropertiesReturnsOnCall(i int, result1 hcs.ContainerProperties, result2 error) {
 	fake.GetContainerPropertiesStub = nil
 	if fake.getContainerPropertiesReturnsOnCall == nil {
 		fake.getContainerPropertiesReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.ContainerProperties
+			result1 hcs.ContainerProperties
 			result2 error
 		})
 	}
 	fake.getContainerPropertiesReturnsOnCall[i] = struct {
-		result1 hcsshim.ContainerProperties
+		result1 hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) NameToGuid(arg1 string) (hcsshim.GUID, error) {
+func (fake *HCSClient) NameToGuid(arg1 string) (hcs.GUID, error) {
 	fake.nameToGuidMutex.Lock()
 	ret, specificReturn := fake.nameToGuidReturnsOnCall[len(fake.nameToGuidArgsForCall)]
 	fake.nameToGuidArgsForCall = append(fake.nameToGuidArgsForCall, struct {
@@ -235,34 +247,34 @@ func (fake *HCSClient) NameToGuidArgsForCall(i int) string {
 	return fake.nameToGuidArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) NameToGuidReturns(result1 hcsshim.GUID, result2 error) {
+func (fake *HCSClient) NameToGuidReturns(result1 hcs.GUID, result2 error) {
 	fake.NameToGuidStub = nil
 	fake.nameToGuidReturns = struct {
-		result1 hcsshim.GUID
+		result1 hcs.GUID
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) NameToGuidReturnsOnCall(i int, result1 hcsshim.GUID, result2 error) {
+func (fake *HCSClient) NameToGuidReturnsOnCall(i int, result1 hcs.GUID, result2 error) {
 	fake.NameToGuidStub = nil
 	if fake.nameToGuidReturnsOnCall == nil {
 		fake.nameToGuidReturnsOnCall = make(map[int]struct {
-			result1 hcsshim.GUID
+			result1 hcs.GUID
 			result2 error
 		})
 	}
 	fake.nameToGuidReturnsOnCall[i] = struct {
-		result1 hcsshim.GUID
+		result1 hcs.GUID
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) CreateContainer(arg1 string, arg2 *hcsshim.ContainerConfig) (hcs.Container, error) {
+func (fake *HCSClient) CreateContainer(arg1 string, arg2 *hcs.ContainerConfig) (hcs.Container, error) {
 	fake.createContainerMutex.Lock()
 	ret, specificReturn := fake.createContainerReturnsOnCall[len(fake.createContainerArgsForCall)]
 	fake.createContainerArgsForCall = append(fake.createContainerArgsForCall, struct {
 		arg1 string
-		arg2 *hcsshim.ContainerConfig
+		arg2 *hcs.ContainerConfig
 	}{arg1, arg2})
 	fake.recordInvocation("CreateContainer", []interface{}{arg1, arg2})
 	fake.createContainerMutex.Unlock()
@@ -281,7 +293,7 @@ func (fake *HCSClient) CreateContainerCallCount() int {
 	return len(fake.createContainerArgsForCall)
 }
 
-func (fake *HCSClient) CreateContainerArgsForCall(i int) (string, *hcsshim.ContainerConfig) {
+func (fake *HCSClient) CreateContainerArgsForCall(i int) (string, *hcs.ContainerConfig) {
 	fake.createContainerMutex.RLock()
 	defer fake.createContainerMutex.RUnlock()
 	return fake.createContainerArgsForCall[i].arg1, fake.createContainerArgsForCall[i].arg2
@@ -408,7 +420,7 @@ func (fake *HCSClient) IsPendingReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
-func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcsshim.HNSEndpoint, error) {
+func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcs.HNSEndpoint, error) {
 	fake.getHNSEndpointByNameMutex.Lock()
 	ret, specificReturn := fake.getHNSEndpointByNameReturnsOnCall[len(fake.getHNSEndpointByNameArgsForCall)]
 	fake.getHNSEndpointByNameArgsForCall = append(fake.getHNSEndpointByNameArgsForCall, struct {
@@ -437,28 +449,78 @@ func (fake *HCSClient) GetHNSEndpointByNameArgsForCall(i int) string {
 	return fake.getHNSEndpointByNameArgsForCall[i].arg1
 }
 
-func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByNameStub = nil
 	fake.getHNSEndpointByNameReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcs.HNSEndpoint, result2 error) {
 	fake.GetHNSEndpointByNameStub = nil
 	if fake.getHNSEndpointByNameReturnsOnCall == nil {
 		fake.getHNSEndpointByNameReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+			result1 *hcs.HNSEndpoint
 			result2 error
 		})
 	}
 	fake.getHNSEndpointByNameReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+		result1 *hcs.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
+func (fake *HCSClient) SignalProcess(arg1 string, arg2 int, arg3 string) error {
+	fake.signalProcessMutex.Lock()
+	ret, specificReturn := fake.signalProcessReturnsOnCall[len(fake.signalProcessArgsForCall)]
+	fake.signalProcessArgsForCall = append(fake.signalProcessArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("SignalProcess", []interface{}{arg1, arg2, arg3})
+	fake.signalProcessMutex.Unlock()
+	if fake.SignalProcessStub != nil {
+		return fake.SignalProcessStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.signalProcessReturns.result1
+}
+
+func (fake *HCSClient) SignalProcessCallCount() int {
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
+	return len(fake.signalProcessArgsForCall)
+}
+
+func (fake *HCSClient) SignalProcessArgsForCall(i int) (string, int, string) {
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
+	return fake.signalProcessArgsForCall[i].arg1, fake.signalProcessArgsForCall[i].arg2, fake.signalProcessArgsForCall[i].arg3
+}
+
+func (fake *HCSClient) SignalProcessReturns(result1 error) {
+	fake.SignalProcessStub = nil
+	fake.signalProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) SignalProcessReturnsOnCall(i int, result1 error) {
+	fake.SignalProcessStub = nil
+	if fake.signalProcessReturnsOnCall == nil {
+		fake.signalProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.signalProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -476,6 +538,8 @@ func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	defer fake.isPendingMutex.RUnlock()
 	fake.getHNSEndpointByNameMutex.RLock()
 	defer fake.getHNSEndpointByNameMutex.RUnlock()
+	fake.signalProcessMutex.RLock()
+	defer fake.signalProcessMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,184 @@
+package container_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"syscall"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Kill", func() {
+	const containerId = "container-to-kill"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "kill")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+	})
+
+	Context("when sent SIGTERM or SIGINT", func() {
+		It("shuts the container down", func() {
+			Expect(containerManager.Kill(99, syscall.SIGTERM, false)).To(Succeed())
+			Expect(containerManager.Kill(99, syscall.SIGINT, true)).To(Succeed())
+
+			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(2))
+			Expect(fakeContainer.OpenProcessCallCount()).To(Equal(0))
+		})
+
+		Context("when shutdown is pending", func() {
+			BeforeEach(func() {
+				fakeContainer.ShutdownReturns(errors.New("pending"))
+				hcsClient.IsPendingReturns(true)
+			})
+
+			It("waits for shutdown to finish", func() {
+				Expect(containerManager.Kill(99, syscall.SIGTERM, false)).To(Succeed())
+				Expect(fakeContainer.WaitTimeoutCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when shutdown fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ShutdownReturns(errors.New("shutdown failed"))
+			})
+
+			It("errors without terminating the container", func() {
+				Expect(containerManager.Kill(99, syscall.SIGTERM, false)).To(MatchError("shutdown failed"))
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when sent SIGKILL", func() {
+		var fakeProcess *hcsfakes.Process
+
+		BeforeEach(func() {
+			fakeProcess = &hcsfakes.Process{}
+			fakeContainer.OpenProcessReturns(fakeProcess, nil)
+		})
+
+		It("kills the given process", func() {
+			Expect(containerManager.Kill(99, syscall.SIGKILL, false)).To(Succeed())
+
+			Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(99))
+			Expect(fakeProcess.KillCallCount()).To(Equal(1))
+			Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
+		})
+
+		Context("when killing the process fails", func() {
+			BeforeEach(func() {
+				fakeProcess.KillReturns(errors.New("kill failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Kill(99, syscall.SIGKILL, false)).To(MatchError("kill failed"))
+			})
+		})
+
+		Context("when all is set", func() {
+			BeforeEach(func() {
+				fakeContainer.ProcessListReturns([]hcs.ProcessListItem{
+					{ProcessId: 4},
+					{ProcessId: 99},
+					{ProcessId: 100},
+				}, nil)
+			})
+
+			It("kills every process in the container", func() {
+				Expect(containerManager.Kill(99, syscall.SIGKILL, true)).To(Succeed())
+
+				Expect(fakeContainer.OpenProcessCallCount()).To(Equal(3))
+				Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(4))
+				Expect(fakeContainer.OpenProcessArgsForCall(1)).To(Equal(99))
+				Expect(fakeContainer.OpenProcessArgsForCall(2)).To(Equal(100))
+				Expect(fakeProcess.KillCallCount()).To(Equal(3))
+			})
+
+			Context("when killing one of the processes fails", func() {
+				BeforeEach(func() {
+					fakeProcess.KillReturnsOnCall(1, errors.New("kill failed"))
+				})
+
+				It("kills the remaining processes and errors", func() {
+					Expect(containerManager.Kill(99, syscall.SIGKILL, true)).To(MatchError("kill failed"))
+					Expect(fakeProcess.KillCallCount()).To(Equal(3))
+				})
+			})
+
+			Context("when listing the processes fails", func() {
+				BeforeEach(func() {
+					fakeContainer.ProcessListReturns(nil, errors.New("process list failed"))
+				})
+
+				It("errors", func() {
+					Expect(containerManager.Kill(99, syscall.SIGKILL, true)).To(MatchError("process list failed"))
+				})
+			})
+		})
+	})
+
+	Context("when sent a signal HCS cannot deliver", func() {
+		It("returns an InvalidSignalError", func() {
+			err := containerManager.Kill(99, syscall.SIGHUP, false)
+			Expect(err).To(BeAssignableToTypeOf(&container.InvalidSignalError{}))
+		})
+	})
+
+	Context("when the container cannot be opened", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+		})
+
+		It("errors", func() {
+			Expect(containerManager.Kill(99, syscall.SIGKILL, false)).To(MatchError("open failed"))
+		})
+	})
+})
+
+var _ = Describe("ParseSignal", func() {
+	DescribeTable("supported signals",
+		func(in string, expected syscall.Signal) {
+			sig, err := container.ParseSignal(in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sig).To(Equal(expected))
+		},
+		Entry("empty defaults to SIGTERM", "", syscall.SIGTERM),
+		Entry("TERM", "TERM", syscall.SIGTERM),
+		Entry("SIGTERM", "SIGTERM", syscall.SIGTERM),
+		Entry("lowercase", "sigint", syscall.SIGINT),
+		Entry("KILL", "KILL", syscall.SIGKILL),
+		Entry("numeric", "9", syscall.SIGKILL),
+	)
+
+	DescribeTable("unsupported signals",
+		func(in string) {
+			_, err := container.ParseSignal(in)
+			Expect(err).To(Equal(&container.InvalidSignalError{Signal: in}))
+		},
+		Entry("unknown name", "SIGBOGUS"),
+		Entry("known but undeliverable", "HUP"),
+		Entry("unknown number", "1"),
+	)
+})
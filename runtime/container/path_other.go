@@ -0,0 +1,24 @@
+// +build !windows
+
+package container
+
+import (
+	"path"
+	"strings"
+)
+
+// destToWindowsPath handles the drive letter paths of the container as
+// path/filepath does on Windows.
+func destToWindowsPath(input string) string {
+	input = strings.Replace(input, `\`, "/", -1)
+
+	vol := "C:"
+	if len(input) >= 2 && input[1] == ':' {
+		vol, input = input[:2], input[2:]
+	}
+	if input == "" {
+		return vol + "."
+	}
+
+	return vol + strings.Replace(path.Clean(input), "/", `\`, -1)
+}
@@ -0,0 +1,13 @@
+package container
+
+import (
+	"path/filepath"
+)
+
+func destToWindowsPath(input string) string {
+	vol := filepath.VolumeName(input)
+	if vol == "" {
+		input = filepath.Join("C:", input)
+	}
+	return filepath.Clean(input)
+}
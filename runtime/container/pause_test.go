@@ -0,0 +1,81 @@
+package container_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	const containerId = "container-to-pause"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "pause")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+	})
+
+	Describe("Pause", func() {
+		It("pauses the container", func() {
+			Expect(containerManager.Pause()).To(Succeed())
+			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+			Expect(fakeContainer.PauseCallCount()).To(Equal(1))
+		})
+
+		Context("when pausing fails", func() {
+			BeforeEach(func() {
+				fakeContainer.PauseReturns(errors.New("pause failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Pause()).To(MatchError("pause failed"))
+			})
+		})
+
+		Context("when the container cannot be opened", func() {
+			BeforeEach(func() {
+				hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Pause()).To(MatchError("open failed"))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		It("resumes the container", func() {
+			Expect(containerManager.Resume()).To(Succeed())
+			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+			Expect(fakeContainer.ResumeCallCount()).To(Equal(1))
+		})
+
+		Context("when resuming fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ResumeReturns(errors.New("resume failed"))
+			})
+
+			It("errors", func() {
+				Expect(containerManager.Resume()).To(MatchError("resume failed"))
+			})
+		})
+	})
+})
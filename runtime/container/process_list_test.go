@@ -0,0 +1,68 @@
+package container_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProcessList", func() {
+	const containerId = "some-ps-container"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "ps")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+	})
+
+	It("returns the processes HCS reports for the container", func() {
+		items := []hcs.ProcessListItem{{ProcessId: 4, ImageName: "smss.exe"}, {ProcessId: 99, ImageName: "cmd.exe"}}
+		fakeContainer.ProcessListReturns(items, nil)
+
+		processes, err := containerManager.ProcessList()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(processes).To(Equal(items))
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+	})
+
+	Context("when listing the processes fails", func() {
+		BeforeEach(func() {
+			fakeContainer.ProcessListReturns(nil, errors.New("process list failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.ProcessList()
+			Expect(err).To(MatchError("process list failed"))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open container failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.ProcessList()
+			Expect(err).To(MatchError("open container failed"))
+		})
+	})
+})
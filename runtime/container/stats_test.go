@@ -4,12 +4,13 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"syscall"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 
-	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
@@ -42,17 +43,36 @@ var _ = Describe("Stats", func() {
 
 	Context("when the specified container exists", func() {
 		BeforeEach(func() {
-			fakeContainer.StatisticsReturns(hcsshim.Statistics{
-				Memory: hcsshim.MemoryStats{
-					UsageCommitBytes: 666,
+			fakeContainer.StatisticsReturns(hcs.Statistics{
+				Memory: hcs.MemoryStats{
+					UsageCommitBytes:            666,
+					UsageCommitPeakBytes:        777,
+					UsagePrivateWorkingSetBytes: 555,
 				},
-				Processor: hcsshim.ProcessorStats{
+				Storage: hcs.StorageStats{
+					ReadCountNormalized:  3,
+					ReadSizeBytes:        300,
+					WriteCountNormalized: 4,
+					WriteSizeBytes:       400,
+				},
+				Network: []hcs.NetworkStats{
+					{
+						EndpointId:             "some-endpoint",
+						BytesReceived:          1000,
+						BytesSent:              2000,
+						PacketsReceived:        10,
+						PacketsSent:            20,
+						DroppedPacketsIncoming: 1,
+						DroppedPacketsOutgoing: 2,
+					},
+				},
+				Processor: hcs.ProcessorStats{
 					TotalRuntime100ns:  123,
 					RuntimeKernel100ns: 101,
 					RuntimeUser100ns:   22,
 				},
 			}, nil)
-			fakeContainer.ProcessListReturns([]hcsshim.ProcessListItem{hcsshim.ProcessListItem{}}, nil)
+			fakeContainer.ProcessListReturns([]hcs.ProcessListItem{hcs.ProcessListItem{}}, nil)
 		})
 
 		It("returns the correct container stats values", func() {
@@ -63,12 +83,34 @@ var _ = Describe("Stats", func() {
 			Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
 
 			expectedStats := container.Statistics{}
+			expectedStats.Data.Memory.Usage.Usage = 666
+			expectedStats.Data.Memory.Usage.Max = 777
 			expectedStats.Data.Memory.Raw.TotalRss = 666
+			expectedStats.Data.Memory.Raw.PrivateWorkingSet = 555
 			expectedStats.Data.CPUStats.CPUUsage.Usage = 12300
 			expectedStats.Data.CPUStats.CPUUsage.System = 10100
 			expectedStats.Data.CPUStats.CPUUsage.User = 2200
 			expectedStats.Data.Pids.Current = 1
 			expectedStats.Data.Pids.Limit = 0
+			expectedStats.Data.Blkio.IoServiceBytesRecursive = []container.BlkioEntry{
+				{Op: "Read", Value: 300},
+				{Op: "Write", Value: 400},
+			}
+			expectedStats.Data.Blkio.IoServicedRecursive = []container.BlkioEntry{
+				{Op: "Read", Value: 3},
+				{Op: "Write", Value: 4},
+			}
+			expectedStats.Data.NetworkInterfaces = []container.NetworkInterface{
+				{
+					Name:      "some-endpoint",
+					RxBytes:   1000,
+					RxPackets: 10,
+					RxDropped: 1,
+					TxBytes:   2000,
+					TxPackets: 20,
+					TxDropped: 2,
+				},
+			}
 			Expect(stats).To(Equal(expectedStats))
 		})
 	})
@@ -90,12 +132,23 @@ var _ = Describe("Stats", func() {
 		var statsError = errors.New("stats failed")
 
 		BeforeEach(func() {
-			fakeContainer.StatisticsReturns(hcsshim.Statistics{}, statsError)
+			fakeContainer.StatisticsReturns(hcs.Statistics{}, statsError)
 		})
 
 		It("errors", func() {
 			_, err := containerManager.Stats()
 			Expect(err).To(Equal(statsError))
 		})
+
+		Context("because the host is low on memory", func() {
+			BeforeEach(func() {
+				fakeContainer.StatisticsReturns(hcs.Statistics{}, &hcs.ContainerError{Err: syscall.Errno(0x5af)})
+			})
+
+			It("returns a LowMemoryError", func() {
+				_, err := containerManager.Stats()
+				Expect(err).To(BeAssignableToTypeOf(&hcs.LowMemoryError{}))
+			})
+		})
 	})
 })
@@ -0,0 +1,294 @@
+package container_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stop", func() {
+	const (
+		containerId = "container-to-stop"
+		pid         = 42
+	)
+
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		fakeProcess      *hcsfakes.Process
+		containerManager *container.Manager
+		policy           container.StopPolicy
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		fakeProcess = &hcsfakes.Process{}
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "stop")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+		fakeContainer.OpenProcessReturns(fakeProcess, nil)
+		policy = container.StopPolicy{GracePeriod: 10 * time.Second, ShutdownTimeout: 30 * time.Second}
+	})
+
+	It("signals the init process and reports that it stopped the container", func() {
+		stage, err := containerManager.Stop(pid, policy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stage).To(Equal(container.StopStageSignal))
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(pid))
+
+		Expect(hcsClient.SignalProcessCallCount()).To(Equal(1))
+		id, signalledPid, signal := hcsClient.SignalProcessArgsForCall(0)
+		Expect(id).To(Equal(containerId))
+		Expect(signalledPid).To(Equal(pid))
+		Expect(signal).To(Equal(hcs.SignalCtrlC))
+
+		Expect(fakeProcess.WaitTimeoutArgsForCall(0)).To(Equal(10 * time.Second))
+		Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+		Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+		Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+		Expect(fakeContainer.CloseCallCount()).To(Equal(1))
+	})
+
+	Context("the container is already stopped", func() {
+		BeforeEach(func() {
+			hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{Stopped: true}, nil)
+		})
+
+		It("does nothing", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageAlreadyStopped))
+
+			Expect(hcsClient.SignalProcessCallCount()).To(Equal(0))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(0))
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("there is no grace period", func() {
+		BeforeEach(func() {
+			policy.GracePeriod = 0
+		})
+
+		It("shuts the container down without signalling the init process", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageShutdown))
+
+			Expect(hcsClient.SignalProcessCallCount()).To(Equal(0))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("there is no init process", func() {
+		It("shuts the container down without signalling", func() {
+			stage, err := containerManager.Stop(0, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageShutdown))
+
+			Expect(fakeContainer.OpenProcessCallCount()).To(Equal(0))
+			Expect(hcsClient.SignalProcessCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("Ctrl-C cannot be delivered", func() {
+		BeforeEach(func() {
+			hcsClient.SignalProcessReturnsOnCall(0, errors.New("no console"))
+		})
+
+		It("sends Ctrl-Break instead", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageSignal))
+
+			Expect(hcsClient.SignalProcessCallCount()).To(Equal(2))
+			_, _, signal := hcsClient.SignalProcessArgsForCall(1)
+			Expect(signal).To(Equal(hcs.SignalCtrlBreak))
+		})
+
+		Context("Ctrl-Break cannot be delivered either", func() {
+			BeforeEach(func() {
+				hcsClient.SignalProcessReturnsOnCall(1, errors.New("no console"))
+			})
+
+			It("shuts the container down without waiting out the grace period", func() {
+				stage, err := containerManager.Stop(pid, policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stage).To(Equal(container.StopStageShutdown))
+
+				Expect(fakeProcess.WaitTimeoutCallCount()).To(Equal(0))
+				Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("the host cannot signal processes", func() {
+		BeforeEach(func() {
+			hcsClient.SignalProcessReturns(&hcs.SignalNotSupportedError{Call: "HcsSignalProcess", Err: errors.New("not found")})
+		})
+
+		It("shuts the container down without trying Ctrl-Break or waiting out the grace period", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageShutdown))
+
+			Expect(hcsClient.SignalProcessCallCount()).To(Equal(1))
+			Expect(fakeProcess.WaitTimeoutCallCount()).To(Equal(0))
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+		})
+
+		Context("shutting the container down fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ShutdownReturns(errors.New("couldn't shut down"))
+			})
+
+			It("terminates it", func() {
+				stage, err := containerManager.Stop(pid, policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stage).To(Equal(container.StopStageTerminate))
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("the init process does not exit within the grace period", func() {
+		BeforeEach(func() {
+			fakeProcess.WaitTimeoutReturns(errors.New("timed out"))
+		})
+
+		It("shuts the container down", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageShutdown))
+
+			Expect(fakeContainer.ShutdownCallCount()).To(Equal(1))
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("shutting down is pending", func() {
+		BeforeEach(func() {
+			policy.GracePeriod = 0
+			fakeContainer.ShutdownReturns(errors.New("pending"))
+			hcsClient.IsPendingReturns(true)
+		})
+
+		It("waits up to the shutdown timeout", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageShutdown))
+
+			Expect(fakeContainer.WaitTimeoutArgsForCall(0)).To(Equal(30 * time.Second))
+		})
+
+		Context("the shutdown timeout is not set", func() {
+			BeforeEach(func() {
+				policy.ShutdownTimeout = 0
+			})
+
+			It("waits up to a minute", func() {
+				_, err := containerManager.Stop(pid, policy)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeContainer.WaitTimeoutArgsForCall(0)).To(Equal(time.Minute))
+			})
+		})
+
+		Context("the container does not shut down within the shutdown timeout", func() {
+			BeforeEach(func() {
+				fakeContainer.WaitTimeoutReturnsOnCall(0, errors.New("timed out"))
+			})
+
+			It("terminates the container", func() {
+				stage, err := containerManager.Stop(pid, policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stage).To(Equal(container.StopStageTerminate))
+
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("shutting down fails", func() {
+		BeforeEach(func() {
+			fakeContainer.ShutdownReturns(errors.New("couldn't shut down"))
+		})
+
+		It("terminates the container", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageTerminate))
+
+			Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+		})
+
+		Context("terminating fails", func() {
+			BeforeEach(func() {
+				fakeContainer.TerminateReturns(errors.New("couldn't terminate"))
+			})
+
+			It("returns the error", func() {
+				stage, err := containerManager.Stop(pid, policy)
+				Expect(err).To(MatchError("couldn't terminate"))
+				Expect(stage).To(Equal(container.StopStageTerminate))
+			})
+		})
+	})
+
+	Context("the container is paused", func() {
+		BeforeEach(func() {
+			hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{State: hcs.PausedState}, nil)
+		})
+
+		It("resumes it before stopping it", func() {
+			stage, err := containerManager.Stop(pid, policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stage).To(Equal(container.StopStageSignal))
+
+			Expect(fakeContainer.ResumeCallCount()).To(Equal(1))
+		})
+
+		Context("resuming fails", func() {
+			BeforeEach(func() {
+				fakeContainer.ResumeReturns(errors.New("couldn't resume"))
+			})
+
+			It("terminates the container", func() {
+				stage, err := containerManager.Stop(pid, policy)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stage).To(Equal(container.StopStageTerminate))
+
+				Expect(hcsClient.SignalProcessCallCount()).To(Equal(0))
+				Expect(fakeContainer.TerminateCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("opening the container fails", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("couldn't open"))
+		})
+
+		It("returns the error", func() {
+			_, err := containerManager.Stop(pid, policy)
+			Expect(err).To(MatchError("couldn't open"))
+		})
+	})
+})
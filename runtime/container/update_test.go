@@ -0,0 +1,117 @@
+package container_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Update", func() {
+	const containerId = "container-to-update"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		containerManager *container.Manager
+		resources        *specs.WindowsResources
+		memoryLimit      uint64
+		shares           uint16
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "update")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+
+		memoryLimit = 512 * 1024 * 1024
+		shares = 5000
+		resources = &specs.WindowsResources{
+			Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+			CPU:    &specs.WindowsCPUResources{Shares: &shares},
+		}
+	})
+
+	It("modifies each requested limit of the container", func() {
+		result, err := containerManager.Update(resources)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.ModifyCallCount()).To(Equal(2))
+
+		memoryRequest := fakeContainer.ModifyArgsForCall(0)
+		Expect(memoryRequest.Resource).To(Equal(hcs.MemoryResource))
+		Expect(memoryRequest.Request).To(Equal(hcs.UpdateRequest))
+		Expect(json.Marshal(memoryRequest.Data)).To(MatchJSON(`{"MemoryMaximumInMB": 512}`))
+
+		cpuRequest := fakeContainer.ModifyArgsForCall(1)
+		Expect(cpuRequest.Resource).To(Equal(hcs.ProcessorResource))
+		Expect(cpuRequest.Request).To(Equal(hcs.UpdateRequest))
+		Expect(json.Marshal(cpuRequest.Data)).To(MatchJSON(`{"ProcessorWeight": 5000}`))
+
+		Expect(result.Accepted).To(Equal(resources))
+		Expect(result.Rejected).To(BeEmpty())
+	})
+
+	Context("when HCS rejects one of the limits", func() {
+		BeforeEach(func() {
+			fakeContainer.ModifyStub = func(request *hcs.ResourceModificationRequestResponse) error {
+				if request.Resource == hcs.ProcessorResource {
+					return errors.New("not supported")
+				}
+				return nil
+			}
+		})
+
+		It("still applies the others and reports the rejected one", func() {
+			result, err := containerManager.Update(resources)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Accepted.Memory).To(Equal(resources.Memory))
+			Expect(result.Accepted.CPU).To(BeNil())
+			Expect(result.Rejected).To(Equal(map[string]string{"cpu.shares": "not supported"}))
+		})
+	})
+
+	Context("when storage limits are requested", func() {
+		BeforeEach(func() {
+			iops := uint64(100)
+			resources = &specs.WindowsResources{
+				Storage: &specs.WindowsStorageResources{Iops: &iops},
+			}
+		})
+
+		It("rejects them without asking HCS", func() {
+			result, err := containerManager.Update(resources)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeContainer.ModifyCallCount()).To(Equal(0))
+			Expect(result.Rejected).To(HaveKey("storage.iops"))
+		})
+	})
+
+	Context("when the container cannot be opened", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.Update(resources)
+			Expect(err).To(MatchError("open failed"))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package container_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/container/fakes"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Wait", func() {
+	const containerId = "container-to-wait-on"
+	var (
+		hcsClient        *fakes.HCSClient
+		fakeContainer    *hcsfakes.Container
+		fakeProcess      *hcsfakes.Process
+		containerManager *container.Manager
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		fakeContainer = &hcsfakes.Container{}
+		fakeProcess = &hcsfakes.Process{}
+		hcsClient.OpenContainerReturns(fakeContainer, nil)
+		fakeContainer.OpenProcessReturns(fakeProcess, nil)
+		fakeProcess.ExitCodeReturns(4, nil)
+
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "wait")
+
+		containerManager = container.New(logger, hcsClient, containerId)
+	})
+
+	It("waits for the process to exit and returns its exit code", func() {
+		exitCode, err := containerManager.Wait(99, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exitCode).To(Equal(4))
+
+		Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+		Expect(fakeContainer.OpenProcessArgsForCall(0)).To(Equal(99))
+		Expect(fakeProcess.WaitCallCount()).To(Equal(1))
+		Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+	})
+
+	Context("when a timeout is given", func() {
+		It("waits for at most the timeout", func() {
+			exitCode, err := containerManager.Wait(99, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitCode).To(Equal(4))
+
+			Expect(fakeProcess.WaitCallCount()).To(Equal(0))
+			Expect(fakeProcess.WaitTimeoutArgsForCall(0)).To(Equal(time.Minute))
+		})
+
+		Context("when the process does not exit in time", func() {
+			BeforeEach(func() {
+				fakeProcess.WaitTimeoutReturns(hcs.ErrTimeout)
+			})
+
+			It("returns a WaitTimeoutError", func() {
+				_, err := containerManager.Wait(99, time.Minute)
+				Expect(err).To(Equal(&container.WaitTimeoutError{Id: containerId, Pid: 99, Timeout: time.Minute}))
+			})
+		})
+	})
+
+	Context("when waiting fails", func() {
+		BeforeEach(func() {
+			fakeProcess.WaitReturns(errors.New("wait failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.Wait(99, 0)
+			Expect(err).To(MatchError("wait failed"))
+			Expect(fakeProcess.CloseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the process cannot be opened", func() {
+		BeforeEach(func() {
+			fakeContainer.OpenProcessReturns(nil, errors.New("open process failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.Wait(99, 0)
+			Expect(err).To(MatchError("open process failed"))
+		})
+	})
+
+	Context("when the container cannot be opened", func() {
+		BeforeEach(func() {
+			hcsClient.OpenContainerReturns(nil, errors.New("open failed"))
+		})
+
+		It("errors", func() {
+			_, err := containerManager.Wait(99, 0)
+			Expect(err).To(MatchError("open failed"))
+		})
+	})
+})
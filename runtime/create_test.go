@@ -5,7 +5,10 @@ import (
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
+	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/hook"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -26,6 +29,7 @@ var _ = Describe("Create", func() {
 		cm               *fakes.ContainerManager
 		processWrapper   *fakes.ProcessWrapper
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
 		spec             *specs.Spec
 	)
@@ -33,6 +37,7 @@ var _ = Describe("Create", func() {
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -43,7 +48,7 @@ var _ = Describe("Create", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 	})
 
 	Context("success", func() {
@@ -52,7 +57,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("loads the spec, creates the container, and intializes the state", func() {
-			Expect(r.Create(containerId, bundlePath)).To(Succeed())
+			Expect(r.Create(containerId, bundlePath, "")).To(Succeed())
 
 			_, c, id := containerFactory.NewManagerArgsForCall(0)
 			Expect(*c).To(Equal(hcs.Client{}))
@@ -65,8 +70,173 @@ var _ = Describe("Create", func() {
 			Expect(rd).To(Equal(rootDir))
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
-			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			createSpec, owner := cm.CreateArgsForCall(0)
+			Expect(createSpec).To(Equal(spec))
+			Expect(owner).To(Equal(container.Owner(rootDir)))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
+			Expect(sm.SetResourcesCallCount()).To(Equal(0))
+			Expect(sm.SetConsoleSocketCallCount()).To(Equal(0))
+			Expect(sm.SetHyperVCallCount()).To(Equal(0))
+		})
+
+		It("holds the lock on the container while creating it", func() {
+			sm.InitializeStub = func(string, *specs.Spec) error {
+				Expect(sm.LockCallCount()).To(Equal(1))
+				Expect(sm.UnlockCallCount()).To(Equal(0))
+				return nil
+			}
+
+			Expect(r.Create(containerId, bundlePath, "")).To(Succeed())
+			Expect(sm.UnlockCallCount()).To(Equal(1))
+		})
+
+		Context("taking the lock fails", func() {
+			BeforeEach(func() {
+				sm.LockReturns(errors.New("timed out"))
+			})
+
+			It("returns the error without creating the container", func() {
+				Expect(r.Create(containerId, bundlePath, "")).To(MatchError("timed out"))
+				Expect(cm.CreateCallCount()).To(Equal(0))
+				Expect(sm.UnlockCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the spec requests hyperv isolation", func() {
+			BeforeEach(func() {
+				spec.Windows = &specs.Windows{HyperV: &specs.WindowsHyperV{}}
+			})
+
+			It("records it in the state", func() {
+				Expect(r.Create(containerId, bundlePath, "")).To(Succeed())
+				Expect(sm.SetHyperVCallCount()).To(Equal(1))
+			})
+
+			Context("recording it fails", func() {
+				BeforeEach(func() {
+					sm.SetHyperVReturns(errors.New("write failed"))
+				})
+
+				It("deletes the container and its state", func() {
+					Expect(r.Create(containerId, bundlePath, "")).To(MatchError("write failed"))
+					Expect(sm.DeleteCallCount()).To(Equal(1))
+					Expect(cm.DeleteCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("a console socket is provided", func() {
+			const consoleSocket = "C:\\console.sock"
+
+			BeforeEach(func() {
+				spec.Process = &specs.Process{Terminal: true}
+			})
+
+			It("records it in the state", func() {
+				Expect(r.Create(containerId, bundlePath, consoleSocket)).To(Succeed())
+				Expect(sm.SetConsoleSocketArgsForCall(0)).To(Equal(consoleSocket))
+			})
+
+			Context("the process does not use a terminal", func() {
+				BeforeEach(func() {
+					spec.Process.Terminal = false
+				})
+
+				It("errors without creating the container", func() {
+					err := r.Create(containerId, bundlePath, consoleSocket)
+					Expect(err).To(Equal(&config.ConsoleSocketError{ConsoleSocket: consoleSocket}))
+					Expect(cm.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("recording it fails", func() {
+				BeforeEach(func() {
+					sm.SetConsoleSocketReturns(errors.New("write failed"))
+				})
+
+				It("deletes the container and its state", func() {
+					Expect(r.Create(containerId, bundlePath, consoleSocket)).To(MatchError("write failed"))
+					Expect(sm.DeleteCallCount()).To(Equal(1))
+					Expect(cm.DeleteCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("the spec sets resource limits", func() {
+			var resources *specs.WindowsResources
+
+			BeforeEach(func() {
+				limit := uint64(1024 * 1024 * 1024)
+				resources = &specs.WindowsResources{Memory: &specs.WindowsMemoryResources{Limit: &limit}}
+				spec.Windows = &specs.Windows{Resources: resources}
+			})
+
+			It("records them in the state", func() {
+				Expect(r.Create(containerId, bundlePath, "")).To(Succeed())
+				Expect(sm.SetResourcesArgsForCall(0)).To(Equal(resources))
+				Expect(hookRunner.RunCallCount()).To(Equal(0))
+			})
+
+			Context("recording them fails", func() {
+				BeforeEach(func() {
+					sm.SetResourcesReturns(errors.New("write failed"))
+				})
+
+				It("deletes the container and its state", func() {
+					Expect(r.Create(containerId, bundlePath, "")).To(MatchError("write failed"))
+					Expect(sm.DeleteCallCount()).To(Equal(1))
+					Expect(cm.DeleteCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Context("the bundle has prestart and createRuntime hooks", func() {
+		var prestart, createRuntime []specs.Hook
+
+		BeforeEach(func() {
+			cm.SpecReturns(spec, nil)
+			sm.StateReturns(&specs.State{ID: containerId, Status: "created", Bundle: bundlePath}, nil)
+
+			prestart = []specs.Hook{{Path: "C:\\hooks\\prestart.exe"}}
+			createRuntime = []specs.Hook{{Path: "C:\\hooks\\create-runtime.exe"}}
+			hookRunner.LoadReturns(hook.Hooks{Prestart: prestart, CreateRuntime: createRuntime}, nil)
+		})
+
+		It("runs them in order with the state of the container being created", func() {
+			Expect(r.Create(containerId, bundlePath, "")).To(Succeed())
+
+			Expect(hookRunner.LoadArgsForCall(0)).To(Equal(bundlePath))
+			hooks, state, _ := hookRunner.RunArgsForCall(0)
+			Expect(hooks).To(Equal(append(prestart, createRuntime...)))
+			Expect(state.ID).To(Equal(containerId))
+			Expect(state.Status).To(Equal("creating"))
+		})
+
+		Context("a hook fails", func() {
+			BeforeEach(func() {
+				hookRunner.RunReturns(errors.New("hook failed"))
+			})
+
+			It("deletes the container and its state", func() {
+				Expect(r.Create(containerId, bundlePath, "")).To(MatchError("hook failed"))
+				Expect(sm.DeleteCallCount()).To(Equal(1))
+				Expect(cm.DeleteCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("loading the hooks fails", func() {
+		BeforeEach(func() {
+			cm.SpecReturns(spec, nil)
+			hookRunner.LoadReturns(hook.Hooks{}, errors.New("bad hooks"))
+		})
+
+		It("returns the error without creating the container", func() {
+			Expect(r.Create(containerId, bundlePath, "")).To(MatchError("bad hooks"))
+			Expect(cm.CreateCallCount()).To(Equal(0))
 		})
 	})
 
@@ -76,7 +246,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("returns the error", func() {
-			err := r.Create(containerId, bundlePath)
+			err := r.Create(containerId, bundlePath, "")
 			Expect(err).To(MatchError("bad spec"))
 		})
 	})
@@ -87,7 +257,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("returns the error", func() {
-			err := r.Create(containerId, bundlePath)
+			err := r.Create(containerId, bundlePath, "")
 			Expect(err).To(MatchError("hcsshim fell over"))
 		})
 	})
@@ -99,7 +269,7 @@ var _ = Describe("Create", func() {
 		})
 
 		It("deletes the container", func() {
-			err := r.Create(containerId, bundlePath)
+			err := r.Create(containerId, bundlePath, "")
 			Expect(err).To(MatchError("state init failed"))
 
 			Expect(cm.DeleteCallCount()).To(Equal(1))
@@ -0,0 +1,14 @@
+// +build !windows
+
+package runtime
+
+import (
+	"os"
+	"time"
+)
+
+// creationTime is the time the state directory was last modified, as other
+// platforms do not record when it was created.
+func creationTime(fi os.FileInfo) time.Time {
+	return fi.ModTime().UTC()
+}
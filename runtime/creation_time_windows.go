@@ -0,0 +1,16 @@
+package runtime
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// creationTime is the time the state directory was created, which is used for
+// containers created before winc recorded it in their state.
+func creationTime(fi os.FileInfo) time.Time {
+	if attrs, ok := fi.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, attrs.CreationTime.Nanoseconds()).UTC()
+	}
+	return fi.ModTime().UTC()
+}
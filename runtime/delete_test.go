@@ -1,18 +1,22 @@
 package runtime_test
 
 import (
-	"strings"
-
-	"github.com/Microsoft/hcsshim"
+	"bytes"
 	"github.com/pkg/errors"
+	"io/ioutil"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/hook"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
 )
 
 var _ = Describe("Delete", func() {
@@ -30,22 +34,35 @@ var _ = Describe("Delete", func() {
 		cm               *fakes.ContainerManager
 		processWrapper   *fakes.ProcessWrapper
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
+		policy           container.StopPolicy
+		results          []runtime.DeleteResult
+		deleteContainer  func(force bool) error
 	)
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
 		cm = &fakes.ContainerManager{}
 		processWrapper = &fakes.ProcessWrapper{}
+		policy = container.StopPolicy{GracePeriod: 5 * time.Second, ShutdownTimeout: time.Minute}
 
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+
+		results = nil
+		deleteContainer = func(force bool) error {
+			var err error
+			results, err = r.Delete(containerId, force, policy)
+			return err
+		}
 	})
 
 	BeforeEach(func() {
@@ -54,7 +71,7 @@ var _ = Describe("Delete", func() {
 	})
 
 	It("unmounts the volume, deletes the state and deletes the container", func() {
-		Expect(r.Delete(containerId, true)).To(Succeed())
+		Expect(deleteContainer(true)).To(Succeed())
 
 		_, c, id := containerFactory.NewManagerArgsForCall(0)
 		Expect(*c).To(Equal(hcs.Client{}))
@@ -71,6 +88,119 @@ var _ = Describe("Delete", func() {
 		Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
 	})
 
+	It("stops the container with the stop policy before deleting it", func() {
+		cm.StopStub = func(int, container.StopPolicy) (string, error) {
+			Expect(cm.DeleteCallCount()).To(Equal(0))
+			return container.StopStageShutdown, nil
+		}
+
+		Expect(deleteContainer(true)).To(Succeed())
+
+		Expect(cm.StopCallCount()).To(Equal(1))
+		pid, p := cm.StopArgsForCall(0)
+		Expect(pid).To(Equal(0))
+		Expect(p).To(Equal(policy))
+	})
+
+	It("returns the stage that stopped the container", func() {
+		cm.StopReturns(container.StopStageShutdown, nil)
+
+		Expect(deleteContainer(true)).To(Succeed())
+		Expect(results).To(Equal([]runtime.DeleteResult{{ID: containerId, StoppedBy: "shutdown"}}))
+	})
+
+	Context("the init process is running", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "running", Bundle: bundlePath, Pid: 99}, nil)
+		})
+
+		It("asks the stop policy to signal the init process", func() {
+			Expect(deleteContainer(true)).To(Succeed())
+
+			pid, _ := cm.StopArgsForCall(0)
+			Expect(pid).To(Equal(99))
+		})
+	})
+
+	Context("stopping the container fails", func() {
+		BeforeEach(func() {
+			cm.StopReturns("", errors.New("couldn't stop"))
+		})
+
+		It("still deletes the container", func() {
+			Expect(deleteContainer(true)).To(Succeed())
+			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
+			Expect(results).To(Equal([]runtime.DeleteResult{{ID: containerId}}))
+		})
+	})
+
+	It("holds the lock on the container while deleting it", func() {
+		cm.DeleteStub = func(bool) error {
+			Expect(sm.LockCallCount()).To(Equal(1))
+			Expect(sm.UnlockCallCount()).To(Equal(0))
+			return nil
+		}
+
+		Expect(deleteContainer(true)).To(Succeed())
+		Expect(sm.UnlockCallCount()).To(Equal(1))
+	})
+
+	Context("taking the lock fails", func() {
+		BeforeEach(func() {
+			sm.LockReturns(errors.New("timed out"))
+		})
+
+		It("returns the error without deleting the container", func() {
+			Expect(deleteContainer(true)).To(MatchError("timed out"))
+			Expect(sm.DeleteCallCount()).To(Equal(0))
+			Expect(cm.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the bundle has poststop hooks", func() {
+		var poststop []specs.Hook
+
+		BeforeEach(func() {
+			poststop = []specs.Hook{{Path: "C:\\hooks\\poststop.exe"}}
+			hookRunner.LoadReturns(hook.Hooks{Poststop: poststop}, nil)
+		})
+
+		It("runs them after deleting the container", func() {
+			Expect(deleteContainer(false)).To(Succeed())
+
+			Expect(hookRunner.LoadArgsForCall(0)).To(Equal(bundlePath))
+			hooks, state, _ := hookRunner.RunArgsForCall(0)
+			Expect(hooks).To(Equal(poststop))
+			Expect(state.Status).To(Equal("stopped"))
+			Expect(state.Pid).To(Equal(99))
+		})
+
+		Context("a poststop hook fails", func() {
+			BeforeEach(func() {
+				hookRunner.RunReturns(&hook.FailedError{Path: "C:\\hooks\\poststop.exe", InternalError: errors.New("exit status 1")})
+			})
+
+			AfterEach(func() {
+				logrus.SetOutput(ioutil.Discard)
+			})
+
+			It("logs which hook failed and why", func() {
+				logOutput := &bytes.Buffer{}
+				logrus.SetOutput(logOutput)
+
+				Expect(deleteContainer(false)).To(Succeed())
+				Expect(logOutput.String()).To(ContainSubstring("poststop hook failed, continuing"))
+				Expect(logOutput.String()).To(ContainSubstring(`hook="C:\\hooks\\poststop.exe"`))
+				Expect(logOutput.String()).To(ContainSubstring("exit status 1"))
+			})
+
+			It("still deletes the container", func() {
+				Expect(deleteContainer(false)).To(Succeed())
+				Expect(cm.DeleteCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	Context("getting state fails", func() {
 		Context("force is true", func() {
 			Context("the error is hcs.NotFoundError", func() {
@@ -79,7 +209,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns success", func() {
-					Expect(r.Delete(containerId, true)).To(Succeed())
+					Expect(deleteContainer(true)).To(Succeed())
 
 					Expect(mounter.UnmountCallCount()).To(Equal(0))
 					Expect(sm.DeleteCallCount()).To(Equal(0))
@@ -93,7 +223,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, true)
+					_, err := r.Delete(containerId, true, policy)
 					Expect(err).To(MatchError("couldn't get state"))
 
 					Expect(mounter.UnmountCallCount()).To(Equal(0))
@@ -110,7 +240,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, false)
+					_, err := r.Delete(containerId, false, policy)
 					Expect(err).To(HaveOccurred())
 					errs := strings.Split(err.Error(), "\n")
 
@@ -120,6 +250,11 @@ var _ = Describe("Delete", func() {
 					Expect(sm.DeleteCallCount()).To(Equal(0))
 					Expect(cm.DeleteCallCount()).To(Equal(0))
 				})
+
+				It("keeps the type of the error", func() {
+					_, err := r.Delete(containerId, false, policy)
+					Expect(err).To(Equal(&hcs.NotFoundError{}))
+				})
 			})
 
 			Context("the error is of unknown type", func() {
@@ -128,7 +263,7 @@ var _ = Describe("Delete", func() {
 				})
 
 				It("returns the error", func() {
-					err := r.Delete(containerId, false)
+					_, err := r.Delete(containerId, false, policy)
 					Expect(err).To(MatchError("couldn't get state"))
 
 					Expect(mounter.UnmountCallCount()).To(Equal(0))
@@ -145,7 +280,7 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("deletes the state and deletes the container", func() {
-			Expect(r.Delete(containerId, true)).To(Succeed())
+			Expect(deleteContainer(true)).To(Succeed())
 
 			Expect(mounter.UnmountCallCount()).To(Equal(0))
 			Expect(sm.DeleteCallCount()).To(Equal(1))
@@ -153,13 +288,26 @@ var _ = Describe("Delete", func() {
 		})
 	})
 
+	Context("the container is hyperv isolated", func() {
+		BeforeEach(func() {
+			sm.HyperVReturns(true, nil)
+		})
+
+		It("deletes the container without unmounting a volume for its pid", func() {
+			Expect(deleteContainer(false)).To(Succeed())
+
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(sm.DeleteCallCount()).To(Equal(1))
+		})
+	})
+
 	Context("unmounting fails", func() {
 		BeforeEach(func() {
 			mounter.UnmountReturns(errors.New("couldn't unmount"))
 		})
 
 		It("deletes the state and deletes the container", func() {
-			err := r.Delete(containerId, true)
+			_, err := r.Delete(containerId, true, policy)
 			Expect(err).To(MatchError("couldn't unmount"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
@@ -174,7 +322,7 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("deletes the container", func() {
-			err := r.Delete(containerId, true)
+			_, err := r.Delete(containerId, true, policy)
 			Expect(err).To(MatchError("couldn't delete state"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
@@ -189,7 +337,7 @@ var _ = Describe("Delete", func() {
 		})
 
 		It("returns an error", func() {
-			err := r.Delete(containerId, true)
+			_, err := r.Delete(containerId, true, policy)
 			Expect(err).To(MatchError("couldn't delete container"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
@@ -210,8 +358,8 @@ var _ = Describe("Delete", func() {
 			sidecarSm = &fakes.StateManager{}
 			sidecarCm = &fakes.ContainerManager{}
 
-			hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{
-				hcsshim.ContainerProperties{ID: sidecarId, Owner: containerId},
+			hcsQuery.GetContainersReturns([]hcs.ContainerProperties{
+				hcs.ContainerProperties{ID: sidecarId, Owner: containerId},
 			}, nil)
 
 			stateFactory.NewManagerReturnsOnCall(0, sidecarSm)
@@ -224,10 +372,10 @@ var _ = Describe("Delete", func() {
 			sidecarSm.StateReturns(sidecarState, nil)
 		})
 		It("deletes the sidecar container", func() {
-			Expect(r.Delete(containerId, true)).To(Succeed())
+			Expect(deleteContainer(true)).To(Succeed())
 
 			Expect(hcsQuery.GetContainersCallCount()).To(Equal(1))
-			query := hcsshim.ComputeSystemQuery{Owners: []string{containerId}}
+			query := hcs.ComputeSystemQuery{Owners: []string{containerId}}
 			Expect(hcsQuery.GetContainersArgsForCall(0)).To(Equal(query))
 
 			_, _, _, sId, _ := stateFactory.NewManagerArgsForCall(0)
@@ -252,7 +400,7 @@ var _ = Describe("Delete", func() {
 				sidecarCm.DeleteReturnsOnCall(0, errors.New("some-sidecar-delete-error"))
 			})
 			It("continues to delete the main container", func() {
-				Expect(r.Delete(containerId, true)).NotTo(Succeed())
+				Expect(deleteContainer(true)).NotTo(Succeed())
 				Expect(mounter.UnmountArgsForCall(1)).To(Equal(99))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
 				Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
@@ -263,7 +411,7 @@ var _ = Describe("Delete", func() {
 				mounter.UnmountReturnsOnCall(0, errors.New("some-sidecar-mount-error"))
 			})
 			It("continues to delete the main container", func() {
-				Expect(r.Delete(containerId, true)).NotTo(Succeed())
+				Expect(deleteContainer(true)).NotTo(Succeed())
 				Expect(mounter.UnmountArgsForCall(1)).To(Equal(99))
 				Expect(sm.DeleteCallCount()).To(Equal(1))
 				Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/winc/errcode"
+)
+
+type NoExitStatusError struct {
+	Id string
+}
+
+func (e *NoExitStatusError) Error() string {
+	return fmt.Sprintf("no exit status recorded for container %s", e.Id)
+}
+
+func (e *NoExitStatusError) Code() errcode.Code {
+	return errcode.Code{Name: "no_exit_status", Class: errcode.ClassNotFound}
+}
+
+type CleanupError struct {
+	Failed int
+}
+
+func (e *CleanupError) Error() string {
+	return fmt.Sprintf("failed to clean up %d orphaned resources", e.Failed)
+}
+
+func (e *CleanupError) Code() errcode.Code {
+	return errcode.Code{Name: "cleanup_failed", Class: errcode.ClassInternal}
+}
+
+type InvalidExecIdError struct {
+	ExecId string
+}
+
+func (e *InvalidExecIdError) Error() string {
+	return fmt.Sprintf("invalid exec id %s: must start with a letter or digit and contain only letters, digits, '_', '.' and '-'", e.ExecId)
+}
+
+func (e *InvalidExecIdError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_exec_id", Class: errcode.ClassInvalid}
+}
+
+type ExecSignalError struct {
+	ExecId string
+	Signal string
+}
+
+func (e *ExecSignalError) Error() string {
+	return fmt.Sprintf("cannot send signal %s to exec'd process %s: only KILL is supported", e.Signal, e.ExecId)
+}
+
+func (e *ExecSignalError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_exec_signal", Class: errcode.ClassInvalid}
+}
+
+type ExecStateError struct {
+	Id     string
+	ExecId string
+	Action string
+	State  string
+}
+
+func (e *ExecStateError) Error() string {
+	return fmt.Sprintf("cannot %s process %s of container %s in the %s state", e.Action, e.ExecId, e.Id, e.State)
+}
+
+func (e *ExecStateError) Code() errcode.Code {
+	return errcode.Code{Name: "invalid_exec_state", Class: errcode.ClassConflict}
+}
@@ -1,7 +1,11 @@
 package runtime_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
@@ -10,6 +14,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 var _ = Describe("Events", func() {
@@ -26,6 +31,7 @@ var _ = Describe("Events", func() {
 		cm               *fakes.ContainerManager
 		processWrapper   *fakes.ProcessWrapper
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
 		output           *gbytes.Buffer
 	)
@@ -33,6 +39,7 @@ var _ = Describe("Events", func() {
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -44,13 +51,15 @@ var _ = Describe("Events", func() {
 
 		output = gbytes.NewBuffer()
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 	})
 
 	Context("show stats is true", func() {
-		var expectedJSON string
-		BeforeEach(func() {
-			expectedJSON = `{
+		It("writes a single stats event to the output", func() {
+			Expect(r.Events(containerId, output, true, time.Millisecond)).To(Succeed())
+			Expect(output.Contents()).To(MatchJSON(`{
+  "type": "stats",
+  "id": "container-for-stats",
   "data": {
     "cpu": {
       "usage": {
@@ -60,46 +69,174 @@ var _ = Describe("Events", func() {
       }
     },
     "memory": {
+      "usage": {},
       "raw": {}
     },
-    "pids": {}
+    "pids": {},
+    "blkio": {}
   }
-}`
-		})
-
-		It("writes the stats to the output", func() {
-			Expect(r.Events(containerId, output, true)).To(Succeed())
-			Expect(string(output.Contents())).To(Equal(expectedJSON))
+}`))
 
 			_, c, id := containerFactory.NewManagerArgsForCall(0)
 			Expect(*c).To(Equal(hcs.Client{}))
 			Expect(id).To(Equal(containerId))
+
+			Expect(cm.StatsCallCount()).To(Equal(1))
+			Expect(sm.StateCallCount()).To(Equal(0))
+		})
+
+		Context("a memory limit has been recorded for the container", func() {
+			BeforeEach(func() {
+				limit := uint64(1024 * 1024 * 1024)
+				sm.ResourcesReturns(&specs.WindowsResources{
+					Memory: &specs.WindowsMemoryResources{Limit: &limit},
+				}, nil)
+			})
+
+			It("reports it as the memory usage limit", func() {
+				Expect(r.Events(containerId, output, true, time.Millisecond)).To(Succeed())
+				Expect(string(output.Contents())).To(ContainSubstring(`"usage":{"limit":1073741824}`))
+			})
 		})
 
-		Context("events is passed a nil io.Writer", func() {
+		Context("reading the recorded resources fails", func() {
+			BeforeEach(func() {
+				sm.ResourcesReturns(nil, errors.New("no state"))
+			})
+
 			It("returns an error", func() {
-				err := r.Events(containerId, nil, true)
-				Expect(err).To(MatchError("provided output is nil"))
+				err := r.Events(containerId, output, true, time.Millisecond)
+				Expect(err).To(MatchError("no state"))
 			})
 		})
-	})
 
-	Context("show stats is false", func() {
-		It("calls cm.Stats but doesn't write anything", func() {
-			Expect(r.Events(containerId, output, false)).To(Succeed())
-			Expect(string(output.Contents())).To(Equal(""))
-			Expect(cm.StatsCallCount()).To(Equal(1))
+		Context("stats fails", func() {
+			BeforeEach(func() {
+				cm.StatsReturns(container.Statistics{}, errors.New("stats failed"))
+			})
+
+			It("returns an error", func() {
+				err := r.Events(containerId, output, true, time.Millisecond)
+				Expect(err).To(MatchError("stats failed"))
+			})
 		})
 	})
 
-	Context("stats fails", func() {
+	Context("show stats is false", func() {
 		BeforeEach(func() {
-			cm.StatsReturns(container.Statistics{}, errors.New("stats failed"))
+			sm.StateReturns(&specs.State{Status: "running"}, nil)
+			sm.StateReturnsOnCall(2, &specs.State{Status: "stopped"}, nil)
+		})
+
+		It("streams stats events until the container stops and then writes an exit event", func() {
+			Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+			events := decodeEvents(output)
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Type).To(Equal("stats"))
+			Expect(events[0].ID).To(Equal(containerId))
+			Expect(events[0].Data).NotTo(BeNil())
+			Expect(events[1].Type).To(Equal("stats"))
+			Expect(events[2].Type).To(Equal("exit"))
+			Expect(events[2].ID).To(Equal(containerId))
+
+			Expect(cm.StatsCallCount()).To(Equal(2))
 		})
 
+		Context("stats fails because the host is low on memory", func() {
+			BeforeEach(func() {
+				cm.StatsReturnsOnCall(0, container.Statistics{}, &hcs.LowMemoryError{})
+			})
+
+			It("writes an oom event and keeps streaming", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+				events := decodeEvents(output)
+				Expect(events).To(HaveLen(3))
+				Expect(events[0].Type).To(Equal("oom"))
+				Expect(events[1].Type).To(Equal("stats"))
+				Expect(events[2].Type).To(Equal("exit"))
+			})
+		})
+
+		Context("the container's memory usage reaches its recorded limit", func() {
+			BeforeEach(func() {
+				limit := uint64(1024)
+				sm.ResourcesReturns(&specs.WindowsResources{
+					Memory: &specs.WindowsMemoryResources{Limit: &limit},
+				}, nil)
+
+				var stats container.Statistics
+				stats.Data.Memory.Raw.TotalRss = 1024
+				cm.StatsReturns(stats, nil)
+			})
+
+			It("writes a single oom event while usage stays at the limit", func() {
+				Expect(r.Events(containerId, output, false, time.Millisecond)).To(Succeed())
+
+				events := decodeEvents(output)
+				Expect(events).To(HaveLen(4))
+				Expect(events[0].Type).To(Equal("stats"))
+				Expect(events[1].Type).To(Equal("oom"))
+				Expect(events[2].Type).To(Equal("stats"))
+				Expect(events[3].Type).To(Equal("exit"))
+			})
+		})
+
+		Context("the context of the runtime is cancelled while streaming", func() {
+			It("stops streaming and returns the error of the context", func() {
+				sm.StateReturnsOnCall(2, &specs.State{Status: "running"}, nil)
+				ctx, cancel := context.WithCancel(context.Background())
+				cm.StatsStub = func() (container.Statistics, error) {
+					cancel()
+					return container.Statistics{}, nil
+				}
+
+				err := r.WithContext(ctx).Events(containerId, output, false, time.Hour)
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(cm.StatsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("stats fails", func() {
+			BeforeEach(func() {
+				cm.StatsReturns(container.Statistics{}, errors.New("stats failed"))
+			})
+
+			It("returns an error", func() {
+				err := r.Events(containerId, output, false, time.Millisecond)
+				Expect(err).To(MatchError("stats failed"))
+			})
+		})
+
+		Context("getting the container state fails", func() {
+			BeforeEach(func() {
+				sm.StateReturnsOnCall(0, nil, errors.New("state failed"))
+			})
+
+			It("returns an error", func() {
+				err := r.Events(containerId, output, false, time.Millisecond)
+				Expect(err).To(MatchError("state failed"))
+				Expect(cm.StatsCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("events is passed a nil io.Writer", func() {
 		It("returns an error", func() {
-			err := r.Events(containerId, nil, true)
-			Expect(err).To(MatchError("stats failed"))
+			err := r.Events(containerId, nil, true, time.Millisecond)
+			Expect(err).To(MatchError("provided output is nil"))
 		})
 	})
 })
+
+func decodeEvents(output *gbytes.Buffer) []runtime.Event {
+	var events []runtime.Event
+	decoder := json.NewDecoder(bytes.NewReader(output.Contents()))
+	for decoder.More() {
+		var event runtime.Event
+		Expect(decoder.Decode(&event)).To(Succeed())
+		events = append(events, event)
+	}
+	return events
+}
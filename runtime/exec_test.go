@@ -1,16 +1,15 @@
 package runtime_test
 
 import (
-	"encoding/json"
 	"errors"
-	"io/ioutil"
 	"os"
-	"path/filepath"
 
 	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
@@ -34,9 +33,9 @@ var _ = Describe("Exec", func() {
 		wrappedProcess   *fakes.WrappedProcess
 		unwrappedProcess *hcsfakes.Process
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
-		processSpecDir   string
-		processSpecFile  string
+		processSpec      *specs.Process
 		io               runtime.IO
 		stdin            *gbytes.Buffer
 		stdout           *gbytes.Buffer
@@ -46,6 +45,7 @@ var _ = Describe("Exec", func() {
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -55,25 +55,17 @@ var _ = Describe("Exec", func() {
 
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
+		sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId})
 
-		var err error
-		processSpecDir, err = ioutil.TempDir("", "runtime.exec")
-		Expect(err).NotTo(HaveOccurred())
-		processSpecFile = filepath.Join(processSpecDir, "process.json")
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
-
-		processSpec := specs.Process{
+		processSpec = &specs.Process{
 			User: specs.User{Username: "some-user"},
 			Cwd:  "c:\\windows",
 			Args: []string{"my", "program"},
 			Env:  []string{"FOO=bar"},
 		}
 
-		c, err := json.Marshal(processSpec)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(ioutil.WriteFile(processSpecFile, c, 0644)).To(Succeed())
-
 		unwrappedProcess = &hcsfakes.Process{}
 
 		stdin = gbytes.NewBuffer()
@@ -82,18 +74,14 @@ var _ = Describe("Exec", func() {
 		io = runtime.IO{Stdin: stdin, Stdout: stdout, Stderr: stderr}
 	})
 
-	AfterEach(func() {
-		Expect(os.RemoveAll(processSpecDir)).To(Succeed())
-	})
-
 	Context("detach is true", func() {
 		BeforeEach(func() {
 			cm.ExecReturns(unwrappedProcess, nil)
 			processWrapper.WrapReturns(wrappedProcess)
 		})
 
-		It("loads the process config, execs the process, and writes the pidfile", func() {
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, true)
+		It("execs the process and writes the pidfile", func() {
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, true)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 
@@ -118,19 +106,74 @@ var _ = Describe("Exec", func() {
 			Expect(wrappedProcess.SetInterruptCallCount()).To(Equal(0))
 			Expect(wrappedProcess.AttachIOCallCount()).To(Equal(0))
 		})
+
+		It("records the process under a generated exec id and keeps the record", func() {
+			_, err := r.Exec(containerId, "", processSpec, pidFile, io, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.AddProcessCallCount()).To(Equal(1))
+			execId, p, spec, detach, _ := sm.AddProcessArgsForCall(0)
+			Expect(execId).To(MatchRegexp(`^[0-9a-f]{16}$`))
+			Expect(p).To(Equal(unwrappedProcess))
+			Expect(spec.Args).To(Equal([]string{"my", "program"}))
+			Expect(detach).To(BeTrue())
+
+			Expect(sm.DeleteProcessCallCount()).To(Equal(0))
+		})
+
+		It("records the process under the exec id that is passed", func() {
+			_, err := r.Exec(containerId, "some-exec", processSpec, pidFile, io, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+			execId, _, _, _, _ := sm.AddProcessArgsForCall(0)
+			Expect(execId).To(Equal("some-exec"))
+		})
+
+		Context("the exec id is already in use", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(&state.Process{ID: "some-exec"}, nil)
+			})
+
+			It("returns an error without execing the process", func() {
+				exitCode, err := r.Exec(containerId, "some-exec", processSpec, pidFile, io, true)
+				Expect(err).To(Equal(&state.ProcessExistsError{Id: containerId, ExecId: "some-exec"}))
+				Expect(exitCode).To(Equal(1))
+				Expect(cm.ExecCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the exec id is invalid", func() {
+			It("returns an error without execing the process", func() {
+				exitCode, err := r.Exec(containerId, "..\\escape", processSpec, pidFile, io, true)
+				Expect(err).To(Equal(&runtime.InvalidExecIdError{ExecId: "..\\escape"}))
+				Expect(exitCode).To(Equal(1))
+				Expect(cm.ExecCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("recording the process fails", func() {
+			BeforeEach(func() {
+				sm.AddProcessReturns(errors.New("couldn't record"))
+			})
+
+			It("returns an error", func() {
+				exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, true)
+				Expect(err).To(MatchError("couldn't record"))
+				Expect(exitCode).To(Equal(1))
+			})
+		})
 	})
 
-	Context("process spec overrides are passed", func() {
+	Context("the cwd of the process is not a windows path", func() {
 		BeforeEach(func() {
 			cm.ExecReturns(unwrappedProcess, nil)
 			processWrapper.WrapReturns(wrappedProcess)
+			processSpec.Cwd = "/some-other-dir"
 		})
 
-		It("uses the values from the overrides", func() {
-			overrides := specs.Process{
-				Cwd: "c:\\some-other-dir",
-			}
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, &overrides, io, true)
+		It("execs the process in the cwd as a windows path without changing the passed process", func() {
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, true)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exitCode).To(Equal(0))
 
@@ -141,28 +184,72 @@ var _ = Describe("Exec", func() {
 			spec, _ := cm.ExecArgsForCall(0)
 			Expect(*spec).To(Equal(specs.Process{
 				User: specs.User{Username: "some-user"},
-				Cwd:  "c:\\some-other-dir",
+				Cwd:  "C:\\some-other-dir",
 				Args: []string{"my", "program"},
 				Env:  []string{"FOO=bar"},
 			}))
+			Expect(processSpec.Cwd).To(Equal("/some-other-dir"))
 		})
 	})
 
-	Context("the process spec is invalid", func() {
+	Context("a console socket is provided", func() {
 		BeforeEach(func() {
-			processSpec := specs.Process{
-				User: specs.User{Username: "some-user"},
-				Cwd:  "c:\\windows",
-				Env:  []string{"FOO=bar"},
-			}
+			cm.ExecReturns(unwrappedProcess, nil)
+			processWrapper.WrapReturns(wrappedProcess)
+			wrappedProcess.AttachConsoleReturns(7, nil)
+			io.ConsoleSocket = "C:\\console.sock"
+		})
 
-			c, err := json.Marshal(processSpec)
+		It("execs a terminal process and relays its console until it exits", func() {
+			processSpec.Terminal = true
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(ioutil.WriteFile(processSpecFile, c, 0644)).To(Succeed())
+			Expect(exitCode).To(Equal(7))
+
+			spec, attach := cm.ExecArgsForCall(0)
+			Expect(spec.Terminal).To(BeTrue())
+			Expect(attach).To(BeTrue())
+
+			Expect(wrappedProcess.AttachConsoleArgsForCall(0)).To(Equal("C:\\console.sock"))
+			Expect(wrappedProcess.AttachIOCallCount()).To(Equal(0))
+		})
+
+		Context("the process is detached", func() {
+			It("records the console socket with the process and returns without relaying it", func() {
+				processSpec.Terminal = true
+				exitCode, err := r.Exec(containerId, "some-exec", processSpec, pidFile, io, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exitCode).To(Equal(0))
+
+				_, attach := cm.ExecArgsForCall(0)
+				Expect(attach).To(BeTrue())
+
+				execId, _, _, _, consoleSocket := sm.AddProcessArgsForCall(0)
+				Expect(execId).To(Equal("some-exec"))
+				Expect(consoleSocket).To(Equal("C:\\console.sock"))
+
+				Expect(wrappedProcess.AttachConsoleCallCount()).To(Equal(0))
+				Expect(sm.DeleteProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the process does not use a terminal", func() {
+			It("returns an error without execing the process", func() {
+				exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
+				Expect(err).To(Equal(&config.ConsoleSocketError{ConsoleSocket: "C:\\console.sock"}))
+				Expect(exitCode).To(Equal(1))
+				Expect(cm.ExecCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("the process spec is invalid", func() {
+		BeforeEach(func() {
+			processSpec.Args = nil
 		})
 
 		It("returns an error", func() {
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, true)
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, true)
 			Expect(err).To(HaveOccurred())
 			Expect(exitCode).To(Equal(1))
 			Expect(err.Error()).To(ContainSubstring("args must not be empty"))
@@ -177,7 +264,7 @@ var _ = Describe("Exec", func() {
 		})
 
 		It("execs the process and waits for it", func() {
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, false)
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exitCode).To(Equal(9))
 
@@ -211,6 +298,44 @@ var _ = Describe("Exec", func() {
 			Expect(se).To(Equal(stderr))
 		})
 
+		It("removes the record of the process once it has exited", func() {
+			_, err := r.Exec(containerId, "some-exec", processSpec, pidFile, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			execId, _, _, detach, _ := sm.AddProcessArgsForCall(0)
+			Expect(execId).To(Equal("some-exec"))
+			Expect(detach).To(BeFalse())
+
+			Expect(sm.DeleteProcessCallCount()).To(Equal(1))
+			Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+		})
+
+		It("releases the lock on the container while waiting for the process", func() {
+			var attachedAtUnlock []int
+			sm.UnlockStub = func() error {
+				attachedAtUnlock = append(attachedAtUnlock, wrappedProcess.AttachIOCallCount())
+				return nil
+			}
+
+			_, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.LockCallCount()).To(Equal(2))
+			Expect(attachedAtUnlock).To(Equal([]int{0, 1}))
+		})
+
+		Context("taking the lock fails", func() {
+			BeforeEach(func() {
+				sm.LockReturns(errors.New("timed out"))
+			})
+
+			It("returns the error without execing the process", func() {
+				_, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
+				Expect(err).To(MatchError("timed out"))
+				Expect(cm.ExecCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("attaching io fails", func() {
 			BeforeEach(func() {
 				cm.ExecReturns(unwrappedProcess, nil)
@@ -218,11 +343,12 @@ var _ = Describe("Exec", func() {
 				wrappedProcess.AttachIOReturns(-1, errors.New("couldn't attach"))
 			})
 
-			It("returns an error", func() {
-				exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, false)
+			It("returns an error and removes the record of the process", func() {
+				exitCode, err := r.Exec(containerId, "some-exec", processSpec, pidFile, io, false)
 				Expect(err).To(HaveOccurred())
 				Expect(exitCode).To(Equal(-1))
 				Expect(err).To(MatchError("couldn't attach"))
+				Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
 			})
 		})
 	})
@@ -233,7 +359,7 @@ var _ = Describe("Exec", func() {
 		})
 
 		It("returns an error", func() {
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, false)
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
 			Expect(err).To(HaveOccurred())
 			Expect(exitCode).To(Equal(1))
 			Expect(err).To(MatchError("couldn't exec"))
@@ -248,7 +374,7 @@ var _ = Describe("Exec", func() {
 		})
 
 		It("returns an error", func() {
-			exitCode, err := r.Exec(containerId, processSpecFile, pidFile, nil, io, false)
+			exitCode, err := r.Exec(containerId, "", processSpec, pidFile, io, false)
 			Expect(err).To(HaveOccurred())
 			Expect(exitCode).To(Equal(1))
 			Expect(err).To(MatchError("couldn't write pidfile"))
@@ -3,6 +3,8 @@ package fakes
 
 import (
 	"sync"
+	"syscall"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
@@ -24,10 +26,11 @@ type ContainerManager struct {
 		result1 *specs.Spec
 		result2 error
 	}
-	CreateStub        func(*specs.Spec) error
+	CreateStub        func(*specs.Spec, string) error
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 *specs.Spec
+		arg2 string
 	}
 	createReturns struct {
 		result1 error
@@ -60,6 +63,88 @@ type ContainerManager struct {
 		result1 container.Statistics
 		result2 error
 	}
+	ProcessListStub        func() ([]hcs.ProcessListItem, error)
+	processListMutex       sync.RWMutex
+	processListArgsForCall []struct{}
+	processListReturns     struct {
+		result1 []hcs.ProcessListItem
+		result2 error
+	}
+	processListReturnsOnCall map[int]struct {
+		result1 []hcs.ProcessListItem
+		result2 error
+	}
+	PauseStub        func() error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct{}
+	pauseReturns     struct {
+		result1 error
+	}
+	pauseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResumeStub        func() error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct{}
+	resumeReturns     struct {
+		result1 error
+	}
+	resumeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStub        func(*specs.WindowsResources) (container.UpdateResult, error)
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 *specs.WindowsResources
+	}
+	updateReturns struct {
+		result1 container.UpdateResult
+		result2 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 container.UpdateResult
+		result2 error
+	}
+	KillStub        func(int, syscall.Signal, bool) error
+	killMutex       sync.RWMutex
+	killArgsForCall []struct {
+		arg1 int
+		arg2 syscall.Signal
+		arg3 bool
+	}
+	killReturns struct {
+		result1 error
+	}
+	killReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WaitStub        func(int, time.Duration) (int, error)
+	waitMutex       sync.RWMutex
+	waitArgsForCall []struct {
+		arg1 int
+		arg2 time.Duration
+	}
+	waitReturns struct {
+		result1 int
+		result2 error
+	}
+	waitReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	OpenProcessStub        func(int) (hcs.Process, error)
+	openProcessMutex       sync.RWMutex
+	openProcessArgsForCall []struct {
+		arg1 int
+	}
+	openProcessReturns struct {
+		result1 hcs.Process
+		result2 error
+	}
+	openProcessReturnsOnCall map[int]struct {
+		result1 hcs.Process
+		result2 error
+	}
 	DeleteStub        func(bool) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
@@ -71,6 +156,20 @@ type ContainerManager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StopStub        func(int, container.StopPolicy) (string, error)
+	stopMutex       sync.RWMutex
+	stopArgsForCall []struct {
+		arg1 int
+		arg2 container.StopPolicy
+	}
+	stopReturns struct {
+		result1 string
+		result2 error
+	}
+	stopReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -126,16 +225,17 @@ func (fake *ContainerManager) SpecReturnsOnCall(i int, result1 *specs.Spec, resu
 	}{result1, result2}
 }
 
-func (fake *ContainerManager) Create(arg1 *specs.Spec) error {
+func (fake *ContainerManager) Create(arg1 *specs.Spec, arg2 string) error {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
 		arg1 *specs.Spec
-	}{arg1})
-	fake.recordInvocation("Create", []interface{}{arg1})
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
 	fake.createMutex.Unlock()
 	if fake.CreateStub != nil {
-		return fake.CreateStub(arg1)
+		return fake.CreateStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -149,10 +249,10 @@ func (fake *ContainerManager) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *ContainerManager) CreateArgsForCall(i int) *specs.Spec {
+func (fake *ContainerManager) CreateArgsForCall(i int) (*specs.Spec, string) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
-	return fake.createArgsForCall[i].arg1
+	return fake.createArgsForCall[i].arg1, fake.createArgsForCall[i].arg2
 }
 
 func (fake *ContainerManager) CreateReturns(result1 error) {
@@ -269,6 +369,333 @@ func (fake *ContainerManager) StatsReturnsOnCall(i int, result1 container.Statis
 	}{result1, result2}
 }
 
+func (fake *ContainerManager) ProcessList() ([]hcs.ProcessListItem, error) {
+	fake.processListMutex.Lock()
+	ret, specificReturn := fake.processListReturnsOnCall[len(fake.processListArgsForCall)]
+	fake.processListArgsForCall = append(fake.processListArgsForCall, struct{}{})
+	fake.recordInvocation("ProcessList", []interface{}{})
+	fake.processListMutex.Unlock()
+	if fake.ProcessListStub != nil {
+		return fake.ProcessListStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processListReturns.result1, fake.processListReturns.result2
+}
+
+func (fake *ContainerManager) ProcessListCallCount() int {
+	fake.processListMutex.RLock()
+	defer fake.processListMutex.RUnlock()
+	return len(fake.processListArgsForCall)
+}
+
+func (fake *ContainerManager) ProcessListReturns(result1 []hcs.ProcessListItem, result2 error) {
+	fake.ProcessListStub = nil
+	fake.processListReturns = struct {
+		result1 []hcs.ProcessListItem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) ProcessListReturnsOnCall(i int, result1 []hcs.ProcessListItem, result2 error) {
+	fake.ProcessListStub = nil
+	if fake.processListReturnsOnCall == nil {
+		fake.processListReturnsOnCall = make(map[int]struct {
+			result1 []hcs.ProcessListItem
+			result2 error
+		})
+	}
+	fake.processListReturnsOnCall[i] = struct {
+		result1 []hcs.ProcessListItem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) Pause() error {
+	fake.pauseMutex.Lock()
+	ret, specificReturn := fake.pauseReturnsOnCall[len(fake.pauseArgsForCall)]
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct{}{})
+	fake.recordInvocation("Pause", []interface{}{})
+	fake.pauseMutex.Unlock()
+	if fake.PauseStub != nil {
+		return fake.PauseStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.pauseReturns.result1
+}
+
+func (fake *ContainerManager) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *ContainerManager) PauseReturns(result1 error) {
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) PauseReturnsOnCall(i int, result1 error) {
+	fake.PauseStub = nil
+	if fake.pauseReturnsOnCall == nil {
+		fake.pauseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pauseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Resume() error {
+	fake.resumeMutex.Lock()
+	ret, specificReturn := fake.resumeReturnsOnCall[len(fake.resumeArgsForCall)]
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct{}{})
+	fake.recordInvocation("Resume", []interface{}{})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.resumeReturns.result1
+}
+
+func (fake *ContainerManager) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *ContainerManager) ResumeReturns(result1 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) ResumeReturnsOnCall(i int, result1 error) {
+	fake.ResumeStub = nil
+	if fake.resumeReturnsOnCall == nil {
+		fake.resumeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resumeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Update(arg1 *specs.WindowsResources) (container.UpdateResult, error) {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 *specs.WindowsResources
+	}{arg1})
+	fake.recordInvocation("Update", []interface{}{arg1})
+	fake.updateMutex.Unlock()
+	if fake.UpdateStub != nil {
+		return fake.UpdateStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.updateReturns.result1, fake.updateReturns.result2
+}
+
+func (fake *ContainerManager) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *ContainerManager) UpdateArgsForCall(i int) *specs.WindowsResources {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return fake.updateArgsForCall[i].arg1
+}
+
+func (fake *ContainerManager) UpdateReturns(result1 container.UpdateResult, result2 error) {
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 container.UpdateResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) UpdateReturnsOnCall(i int, result1 container.UpdateResult, result2 error) {
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 container.UpdateResult
+			result2 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 container.UpdateResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) Kill(arg1 int, arg2 syscall.Signal, arg3 bool) error {
+	fake.killMutex.Lock()
+	ret, specificReturn := fake.killReturnsOnCall[len(fake.killArgsForCall)]
+	fake.killArgsForCall = append(fake.killArgsForCall, struct {
+		arg1 int
+		arg2 syscall.Signal
+		arg3 bool
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Kill", []interface{}{arg1, arg2, arg3})
+	fake.killMutex.Unlock()
+	if fake.KillStub != nil {
+		return fake.KillStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.killReturns.result1
+}
+
+func (fake *ContainerManager) KillCallCount() int {
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	return len(fake.killArgsForCall)
+}
+
+func (fake *ContainerManager) KillArgsForCall(i int) (int, syscall.Signal, bool) {
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	return fake.killArgsForCall[i].arg1, fake.killArgsForCall[i].arg2, fake.killArgsForCall[i].arg3
+}
+
+func (fake *ContainerManager) KillReturns(result1 error) {
+	fake.KillStub = nil
+	fake.killReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) KillReturnsOnCall(i int, result1 error) {
+	fake.KillStub = nil
+	if fake.killReturnsOnCall == nil {
+		fake.killReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.killReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ContainerManager) Wait(arg1 int, arg2 time.Duration) (int, error) {
+	fake.waitMutex.Lock()
+	ret, specificReturn := fake.waitReturnsOnCall[len(fake.waitArgsForCall)]
+	fake.waitArgsForCall = append(fake.waitArgsForCall, struct {
+		arg1 int
+		arg2 time.Duration
+	}{arg1, arg2})
+	fake.recordInvocation("Wait", []interface{}{arg1, arg2})
+	fake.waitMutex.Unlock()
+	if fake.WaitStub != nil {
+		return fake.WaitStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.waitReturns.result1, fake.waitReturns.result2
+}
+
+func (fake *ContainerManager) WaitCallCount() int {
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	return len(fake.waitArgsForCall)
+}
+
+func (fake *ContainerManager) WaitArgsForCall(i int) (int, time.Duration) {
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	return fake.waitArgsForCall[i].arg1, fake.waitArgsForCall[i].arg2
+}
+
+func (fake *ContainerManager) WaitReturns(result1 int, result2 error) {
+	fake.WaitStub = nil
+	fake.waitReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) WaitReturnsOnCall(i int, result1 int, result2 error) {
+	fake.WaitStub = nil
+	if fake.waitReturnsOnCall == nil {
+		fake.waitReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.waitReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) OpenProcess(arg1 int) (hcs.Process, error) {
+	fake.openProcessMutex.Lock()
+	ret, specificReturn := fake.openProcessReturnsOnCall[len(fake.openProcessArgsForCall)]
+	fake.openProcessArgsForCall = append(fake.openProcessArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("OpenProcess", []interface{}{arg1})
+	fake.openProcessMutex.Unlock()
+	if fake.OpenProcessStub != nil {
+		return fake.OpenProcessStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.openProcessReturns.result1, fake.openProcessReturns.result2
+}
+
+func (fake *ContainerManager) OpenProcessCallCount() int {
+	fake.openProcessMutex.RLock()
+	defer fake.openProcessMutex.RUnlock()
+	return len(fake.openProcessArgsForCall)
+}
+
+func (fake *ContainerManager) OpenProcessArgsForCall(i int) int {
+	fake.openProcessMutex.RLock()
+	defer fake.openProcessMutex.RUnlock()
+	return fake.openProcessArgsForCall[i].arg1
+}
+
+func (fake *ContainerManager) OpenProcessReturns(result1 hcs.Process, result2 error) {
+	fake.OpenProcessStub = nil
+	fake.openProcessReturns = struct {
+		result1 hcs.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) OpenProcessReturnsOnCall(i int, result1 hcs.Process, result2 error) {
+	fake.OpenProcessStub = nil
+	if fake.openProcessReturnsOnCall == nil {
+		fake.openProcessReturnsOnCall = make(map[int]struct {
+			result1 hcs.Process
+			result2 error
+		})
+	}
+	fake.openProcessReturnsOnCall[i] = struct {
+		result1 hcs.Process
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ContainerManager) Delete(arg1 bool) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
@@ -317,6 +744,58 @@ func (fake *ContainerManager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ContainerManager) Stop(arg1 int, arg2 container.StopPolicy) (string, error) {
+	fake.stopMutex.Lock()
+	ret, specificReturn := fake.stopReturnsOnCall[len(fake.stopArgsForCall)]
+	fake.stopArgsForCall = append(fake.stopArgsForCall, struct {
+		arg1 int
+		arg2 container.StopPolicy
+	}{arg1, arg2})
+	fake.recordInvocation("Stop", []interface{}{arg1, arg2})
+	fake.stopMutex.Unlock()
+	if fake.StopStub != nil {
+		return fake.StopStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.stopReturns.result1, fake.stopReturns.result2
+}
+
+func (fake *ContainerManager) StopCallCount() int {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return len(fake.stopArgsForCall)
+}
+
+func (fake *ContainerManager) StopArgsForCall(i int) (int, container.StopPolicy) {
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
+	return fake.stopArgsForCall[i].arg1, fake.stopArgsForCall[i].arg2
+}
+
+func (fake *ContainerManager) StopReturns(result1 string, result2 error) {
+	fake.StopStub = nil
+	fake.stopReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) StopReturnsOnCall(i int, result1 string, result2 error) {
+	fake.StopStub = nil
+	if fake.stopReturnsOnCall == nil {
+		fake.stopReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.stopReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ContainerManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -328,8 +807,24 @@ func (fake *ContainerManager) Invocations() map[string][][]interface{} {
 	defer fake.execMutex.RUnlock()
 	fake.statsMutex.RLock()
 	defer fake.statsMutex.RUnlock()
+	fake.processListMutex.RLock()
+	defer fake.processListMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	fake.killMutex.RLock()
+	defer fake.killMutex.RUnlock()
+	fake.waitMutex.RLock()
+	defer fake.waitMutex.RUnlock()
+	fake.openProcessMutex.RLock()
+	defer fake.openProcessMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.stopMutex.RLock()
+	defer fake.stopMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -4,33 +4,33 @@ package fakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
-	"github.com/Microsoft/hcsshim"
 )
 
 type HCSQuery struct {
-	GetContainersStub        func(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
+	GetContainersStub        func(hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error)
 	getContainersMutex       sync.RWMutex
 	getContainersArgsForCall []struct {
-		arg1 hcsshim.ComputeSystemQuery
+		arg1 hcs.ComputeSystemQuery
 	}
 	getContainersReturns struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}
 	getContainersReturnsOnCall map[int]struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *HCSQuery) GetContainers(arg1 hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error) {
+func (fake *HCSQuery) GetContainers(arg1 hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error) {
 	fake.getContainersMutex.Lock()
 	ret, specificReturn := fake.getContainersReturnsOnCall[len(fake.getContainersArgsForCall)]
 	fake.getContainersArgsForCall = append(fake.getContainersArgsForCall, struct {
-		arg1 hcsshim.ComputeSystemQuery
+		arg1 hcs.ComputeSystemQuery
 	}{arg1})
 	fake.recordInvocation("GetContainers", []interface{}{arg1})
 	fake.getContainersMutex.Unlock()
@@ -49,30 +49,30 @@ func (fake *HCSQuery) GetContainersCallCount() int {
 	return len(fake.getContainersArgsForCall)
 }
 
-func (fake *HCSQuery) GetContainersArgsForCall(i int) hcsshim.ComputeSystemQuery {
+func (fake *HCSQuery) GetContainersArgsForCall(i int) hcs.ComputeSystemQuery {
 	fake.getContainersMutex.RLock()
 	defer fake.getContainersMutex.RUnlock()
 	return fake.getContainersArgsForCall[i].arg1
 }
 
-func (fake *HCSQuery) GetContainersReturns(result1 []hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSQuery) GetContainersReturns(result1 []hcs.ContainerProperties, result2 error) {
 	fake.GetContainersStub = nil
 	fake.getContainersReturns = struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSQuery) GetContainersReturnsOnCall(i int, result1 []hcsshim.ContainerProperties, result2 error) {
+func (fake *HCSQuery) GetContainersReturnsOnCall(i int, result1 []hcs.ContainerProperties, result2 error) {
 	fake.GetContainersStub = nil
 	if fake.getContainersReturnsOnCall == nil {
 		fake.getContainersReturnsOnCall = make(map[int]struct {
-			result1 []hcsshim.ContainerProperties
+			result1 []hcs.ContainerProperties
 			result2 error
 		})
 	}
 	fake.getContainersReturnsOnCall[i] = struct {
-		result1 []hcsshim.ContainerProperties
+		result1 []hcs.ContainerProperties
 		result2 error
 	}{result1, result2}
 }
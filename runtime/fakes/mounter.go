@@ -33,6 +33,17 @@ type Mounter struct {
 	unmountReturnsOnCall map[int]struct {
 		result1 error
 	}
+	MountsStub        func() ([]int, error)
+	mountsMutex       sync.RWMutex
+	mountsArgsForCall []struct{}
+	mountsReturns     struct {
+		result1 []int
+		result2 error
+	}
+	mountsReturnsOnCall map[int]struct {
+		result1 []int
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -135,6 +146,49 @@ func (fake *Mounter) UnmountReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Mounter) Mounts() ([]int, error) {
+	fake.mountsMutex.Lock()
+	ret, specificReturn := fake.mountsReturnsOnCall[len(fake.mountsArgsForCall)]
+	fake.mountsArgsForCall = append(fake.mountsArgsForCall, struct{}{})
+	fake.recordInvocation("Mounts", []interface{}{})
+	fake.mountsMutex.Unlock()
+	if fake.MountsStub != nil {
+		return fake.MountsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.mountsReturns.result1, fake.mountsReturns.result2
+}
+
+func (fake *Mounter) MountsCallCount() int {
+	fake.mountsMutex.RLock()
+	defer fake.mountsMutex.RUnlock()
+	return len(fake.mountsArgsForCall)
+}
+
+func (fake *Mounter) MountsReturns(result1 []int, result2 error) {
+	fake.MountsStub = nil
+	fake.mountsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) MountsReturnsOnCall(i int, result1 []int, result2 error) {
+	fake.MountsStub = nil
+	if fake.mountsReturnsOnCall == nil {
+		fake.mountsReturnsOnCall = make(map[int]struct {
+			result1 []int
+			result2 error
+		})
+	}
+	fake.mountsReturnsOnCall[i] = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Mounter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -142,6 +196,8 @@ func (fake *Mounter) Invocations() map[string][][]interface{} {
 	defer fake.mountMutex.RUnlock()
 	fake.unmountMutex.RLock()
 	defer fake.unmountMutex.RUnlock()
+	fake.mountsMutex.RLock()
+	defer fake.mountsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -6,14 +6,16 @@ import (
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/state"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type StateManager struct {
-	InitializeStub        func(string) error
+	InitializeStub        func(string, *specs.Spec) error
 	initializeMutex       sync.RWMutex
 	initializeArgsForCall []struct {
 		arg1 string
+		arg2 *specs.Spec
 	}
 	initializeReturns struct {
 		result1 error
@@ -61,20 +63,213 @@ type StateManager struct {
 		result1 *specs.State
 		result2 error
 	}
+	ProcessUserStub        func(int) (string, error)
+	processUserMutex       sync.RWMutex
+	processUserArgsForCall []struct {
+		arg1 int
+	}
+	processUserReturns struct {
+		result1 string
+		result2 error
+	}
+	processUserReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	SetResourcesStub        func(*specs.WindowsResources) error
+	setResourcesMutex       sync.RWMutex
+	setResourcesArgsForCall []struct {
+		arg1 *specs.WindowsResources
+	}
+	setResourcesReturns struct {
+		result1 error
+	}
+	setResourcesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResourcesStub        func() (*specs.WindowsResources, error)
+	resourcesMutex       sync.RWMutex
+	resourcesArgsForCall []struct{}
+	resourcesReturns     struct {
+		result1 *specs.WindowsResources
+		result2 error
+	}
+	resourcesReturnsOnCall map[int]struct {
+		result1 *specs.WindowsResources
+		result2 error
+	}
+	SetExitedStub        func(int) error
+	setExitedMutex       sync.RWMutex
+	setExitedArgsForCall []struct {
+		arg1 int
+	}
+	setExitedReturns struct {
+		result1 error
+	}
+	setExitedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExitStatusStub        func() (*state.ExitStatus, error)
+	exitStatusMutex       sync.RWMutex
+	exitStatusArgsForCall []struct{}
+	exitStatusReturns     struct {
+		result1 *state.ExitStatus
+		result2 error
+	}
+	exitStatusReturnsOnCall map[int]struct {
+		result1 *state.ExitStatus
+		result2 error
+	}
+	SetConsoleSocketStub        func(string) error
+	setConsoleSocketMutex       sync.RWMutex
+	setConsoleSocketArgsForCall []struct {
+		arg1 string
+	}
+	setConsoleSocketReturns struct {
+		result1 error
+	}
+	setConsoleSocketReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ConsoleSocketStub        func() (string, error)
+	consoleSocketMutex       sync.RWMutex
+	consoleSocketArgsForCall []struct{}
+	consoleSocketReturns     struct {
+		result1 string
+		result2 error
+	}
+	consoleSocketReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	SetHyperVStub        func() error
+	setHyperVMutex       sync.RWMutex
+	setHyperVArgsForCall []struct{}
+	setHyperVReturns     struct {
+		result1 error
+	}
+	setHyperVReturnsOnCall map[int]struct {
+		result1 error
+	}
+	HyperVStub        func() (bool, error)
+	hyperVMutex       sync.RWMutex
+	hyperVArgsForCall []struct{}
+	hyperVReturns     struct {
+		result1 bool
+		result2 error
+	}
+	hyperVReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	LockStub        func() error
+	lockMutex       sync.RWMutex
+	lockArgsForCall []struct{}
+	lockReturns     struct {
+		result1 error
+	}
+	lockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnlockStub        func() error
+	unlockMutex       sync.RWMutex
+	unlockArgsForCall []struct{}
+	unlockReturns     struct {
+		result1 error
+	}
+	unlockReturnsOnCall map[int]struct {
+		result1 error
+	}
+	AddProcessStub        func(string, hcs.Process, *specs.Process, bool, string) error
+	addProcessMutex       sync.RWMutex
+	addProcessArgsForCall []struct {
+		arg1 string
+		arg2 hcs.Process
+		arg3 *specs.Process
+		arg4 bool
+		arg5 string
+	}
+	addProcessReturns struct {
+		result1 error
+	}
+	addProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ProcessStub        func(string) (*state.Process, error)
+	processMutex       sync.RWMutex
+	processArgsForCall []struct {
+		arg1 string
+	}
+	processReturns struct {
+		result1 *state.Process
+		result2 error
+	}
+	processReturnsOnCall map[int]struct {
+		result1 *state.Process
+		result2 error
+	}
+	ProcessesStub        func() ([]state.Process, error)
+	processesMutex       sync.RWMutex
+	processesArgsForCall []struct{}
+	processesReturns     struct {
+		result1 []state.Process
+		result2 error
+	}
+	processesReturnsOnCall map[int]struct {
+		result1 []state.Process
+		result2 error
+	}
+	SetProcessExitedStub        func(string, int) error
+	setProcessExitedMutex       sync.RWMutex
+	setProcessExitedArgsForCall []struct {
+		arg1 string
+		arg2 int
+	}
+	setProcessExitedReturns struct {
+		result1 error
+	}
+	setProcessExitedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ProcessStatusStub        func(*state.Process) (string, error)
+	processStatusMutex       sync.RWMutex
+	processStatusArgsForCall []struct {
+		arg1 *state.Process
+	}
+	processStatusReturns struct {
+		result1 string
+		result2 error
+	}
+	processStatusReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	DeleteProcessStub        func(string) error
+	deleteProcessMutex       sync.RWMutex
+	deleteProcessArgsForCall []struct {
+		arg1 string
+	}
+	deleteProcessReturns struct {
+		result1 error
+	}
+	deleteProcessReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *StateManager) Initialize(arg1 string) error {
+func (fake *StateManager) Initialize(arg1 string, arg2 *specs.Spec) error {
 	fake.initializeMutex.Lock()
 	ret, specificReturn := fake.initializeReturnsOnCall[len(fake.initializeArgsForCall)]
 	fake.initializeArgsForCall = append(fake.initializeArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("Initialize", []interface{}{arg1})
+		arg2 *specs.Spec
+	}{arg1, arg2})
+	fake.recordInvocation("Initialize", []interface{}{arg1, arg2})
 	fake.initializeMutex.Unlock()
 	if fake.InitializeStub != nil {
-		return fake.InitializeStub(arg1)
+		return fake.InitializeStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -88,10 +283,10 @@ func (fake *StateManager) InitializeCallCount() int {
 	return len(fake.initializeArgsForCall)
 }
 
-func (fake *StateManager) InitializeArgsForCall(i int) string {
+func (fake *StateManager) InitializeArgsForCall(i int) (string, *specs.Spec) {
 	fake.initializeMutex.RLock()
 	defer fake.initializeMutex.RUnlock()
-	return fake.initializeArgsForCall[i].arg1
+	return fake.initializeArgsForCall[i].arg1, fake.initializeArgsForCall[i].arg2
 }
 
 func (fake *StateManager) InitializeReturns(result1 error) {
@@ -284,6 +479,787 @@ func (fake *StateManager) StateReturnsOnCall(i int, result1 *specs.State, result
 	}{result1, result2}
 }
 
+func (fake *StateManager) ProcessUser(arg1 int) (string, error) {
+	fake.processUserMutex.Lock()
+	ret, specificReturn := fake.processUserReturnsOnCall[len(fake.processUserArgsForCall)]
+	fake.processUserArgsForCall = append(fake.processUserArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("ProcessUser", []interface{}{arg1})
+	fake.processUserMutex.Unlock()
+	if fake.ProcessUserStub != nil {
+		return fake.ProcessUserStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processUserReturns.result1, fake.processUserReturns.result2
+}
+
+func (fake *StateManager) ProcessUserCallCount() int {
+	fake.processUserMutex.RLock()
+	defer fake.processUserMutex.RUnlock()
+	return len(fake.processUserArgsForCall)
+}
+
+func (fake *StateManager) ProcessUserArgsForCall(i int) int {
+	fake.processUserMutex.RLock()
+	defer fake.processUserMutex.RUnlock()
+	return fake.processUserArgsForCall[i].arg1
+}
+
+func (fake *StateManager) ProcessUserReturns(result1 string, result2 error) {
+	fake.ProcessUserStub = nil
+	fake.processUserReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ProcessUserReturnsOnCall(i int, result1 string, result2 error) {
+	fake.ProcessUserStub = nil
+	if fake.processUserReturnsOnCall == nil {
+		fake.processUserReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.processUserReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) SetResources(arg1 *specs.WindowsResources) error {
+	fake.setResourcesMutex.Lock()
+	ret, specificReturn := fake.setResourcesReturnsOnCall[len(fake.setResourcesArgsForCall)]
+	fake.setResourcesArgsForCall = append(fake.setResourcesArgsForCall, struct {
+		arg1 *specs.WindowsResources
+	}{arg1})
+	fake.recordInvocation("SetResources", []interface{}{arg1})
+	fake.setResourcesMutex.Unlock()
+	if fake.SetResourcesStub != nil {
+		return fake.SetResourcesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setResourcesReturns.result1
+}
+
+func (fake *StateManager) SetResourcesCallCount() int {
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	return len(fake.setResourcesArgsForCall)
+}
+
+func (fake *StateManager) SetResourcesArgsForCall(i int) *specs.WindowsResources {
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	return fake.setResourcesArgsForCall[i].arg1
+}
+
+func (fake *StateManager) SetResourcesReturns(result1 error) {
+	fake.SetResourcesStub = nil
+	fake.setResourcesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetResourcesReturnsOnCall(i int, result1 error) {
+	fake.SetResourcesStub = nil
+	if fake.setResourcesReturnsOnCall == nil {
+		fake.setResourcesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setResourcesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) Resources() (*specs.WindowsResources, error) {
+	fake.resourcesMutex.Lock()
+	ret, specificReturn := fake.resourcesReturnsOnCall[len(fake.resourcesArgsForCall)]
+	fake.resourcesArgsForCall = append(fake.resourcesArgsForCall, struct{}{})
+	fake.recordInvocation("Resources", []interface{}{})
+	fake.resourcesMutex.Unlock()
+	if fake.ResourcesStub != nil {
+		return fake.ResourcesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.resourcesReturns.result1, fake.resourcesReturns.result2
+}
+
+func (fake *StateManager) ResourcesCallCount() int {
+	fake.resourcesMutex.RLock()
+	defer fake.resourcesMutex.RUnlock()
+	return len(fake.resourcesArgsForCall)
+}
+
+func (fake *StateManager) ResourcesReturns(result1 *specs.WindowsResources, result2 error) {
+	fake.ResourcesStub = nil
+	fake.resourcesReturns = struct {
+		result1 *specs.WindowsResources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ResourcesReturnsOnCall(i int, result1 *specs.WindowsResources, result2 error) {
+	fake.ResourcesStub = nil
+	if fake.resourcesReturnsOnCall == nil {
+		fake.resourcesReturnsOnCall = make(map[int]struct {
+			result1 *specs.WindowsResources
+			result2 error
+		})
+	}
+	fake.resourcesReturnsOnCall[i] = struct {
+		result1 *specs.WindowsResources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) SetExited(arg1 int) error {
+	fake.setExitedMutex.Lock()
+	ret, specificReturn := fake.setExitedReturnsOnCall[len(fake.setExitedArgsForCall)]
+	fake.setExitedArgsForCall = append(fake.setExitedArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("SetExited", []interface{}{arg1})
+	fake.setExitedMutex.Unlock()
+	if fake.SetExitedStub != nil {
+		return fake.SetExitedStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setExitedReturns.result1
+}
+
+func (fake *StateManager) SetExitedCallCount() int {
+	fake.setExitedMutex.RLock()
+	defer fake.setExitedMutex.RUnlock()
+	return len(fake.setExitedArgsForCall)
+}
+
+func (fake *StateManager) SetExitedArgsForCall(i int) int {
+	fake.setExitedMutex.RLock()
+	defer fake.setExitedMutex.RUnlock()
+	return fake.setExitedArgsForCall[i].arg1
+}
+
+func (fake *StateManager) SetExitedReturns(result1 error) {
+	fake.SetExitedStub = nil
+	fake.setExitedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetExitedReturnsOnCall(i int, result1 error) {
+	fake.SetExitedStub = nil
+	if fake.setExitedReturnsOnCall == nil {
+		fake.setExitedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setExitedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) ExitStatus() (*state.ExitStatus, error) {
+	fake.exitStatusMutex.Lock()
+	ret, specificReturn := fake.exitStatusReturnsOnCall[len(fake.exitStatusArgsForCall)]
+	fake.exitStatusArgsForCall = append(fake.exitStatusArgsForCall, struct{}{})
+	fake.recordInvocation("ExitStatus", []interface{}{})
+	fake.exitStatusMutex.Unlock()
+	if fake.ExitStatusStub != nil {
+		return fake.ExitStatusStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.exitStatusReturns.result1, fake.exitStatusReturns.result2
+}
+
+func (fake *StateManager) ExitStatusCallCount() int {
+	fake.exitStatusMutex.RLock()
+	defer fake.exitStatusMutex.RUnlock()
+	return len(fake.exitStatusArgsForCall)
+}
+
+func (fake *StateManager) ExitStatusReturns(result1 *state.ExitStatus, result2 error) {
+	fake.ExitStatusStub = nil
+	fake.exitStatusReturns = struct {
+		result1 *state.ExitStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ExitStatusReturnsOnCall(i int, result1 *state.ExitStatus, result2 error) {
+	fake.ExitStatusStub = nil
+	if fake.exitStatusReturnsOnCall == nil {
+		fake.exitStatusReturnsOnCall = make(map[int]struct {
+			result1 *state.ExitStatus
+			result2 error
+		})
+	}
+	fake.exitStatusReturnsOnCall[i] = struct {
+		result1 *state.ExitStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) SetConsoleSocket(arg1 string) error {
+	fake.setConsoleSocketMutex.Lock()
+	ret, specificReturn := fake.setConsoleSocketReturnsOnCall[len(fake.setConsoleSocketArgsForCall)]
+	fake.setConsoleSocketArgsForCall = append(fake.setConsoleSocketArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("SetConsoleSocket", []interface{}{arg1})
+	fake.setConsoleSocketMutex.Unlock()
+	if fake.SetConsoleSocketStub != nil {
+		return fake.SetConsoleSocketStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setConsoleSocketReturns.result1
+}
+
+func (fake *StateManager) SetConsoleSocketCallCount() int {
+	fake.setConsoleSocketMutex.RLock()
+	defer fake.setConsoleSocketMutex.RUnlock()
+	return len(fake.setConsoleSocketArgsForCall)
+}
+
+func (fake *StateManager) SetConsoleSocketArgsForCall(i int) string {
+	fake.setConsoleSocketMutex.RLock()
+	defer fake.setConsoleSocketMutex.RUnlock()
+	return fake.setConsoleSocketArgsForCall[i].arg1
+}
+
+func (fake *StateManager) SetConsoleSocketReturns(result1 error) {
+	fake.SetConsoleSocketStub = nil
+	fake.setConsoleSocketReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetConsoleSocketReturnsOnCall(i int, result1 error) {
+	fake.SetConsoleSocketStub = nil
+	if fake.setConsoleSocketReturnsOnCall == nil {
+		fake.setConsoleSocketReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setConsoleSocketReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) ConsoleSocket() (string, error) {
+	fake.consoleSocketMutex.Lock()
+	ret, specificReturn := fake.consoleSocketReturnsOnCall[len(fake.consoleSocketArgsForCall)]
+	fake.consoleSocketArgsForCall = append(fake.consoleSocketArgsForCall, struct{}{})
+	fake.recordInvocation("ConsoleSocket", []interface{}{})
+	fake.consoleSocketMutex.Unlock()
+	if fake.ConsoleSocketStub != nil {
+		return fake.ConsoleSocketStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.consoleSocketReturns.result1, fake.consoleSocketReturns.result2
+}
+
+func (fake *StateManager) ConsoleSocketCallCount() int {
+	fake.consoleSocketMutex.RLock()
+	defer fake.consoleSocketMutex.RUnlock()
+	return len(fake.consoleSocketArgsForCall)
+}
+
+func (fake *StateManager) ConsoleSocketReturns(result1 string, result2 error) {
+	fake.ConsoleSocketStub = nil
+	fake.consoleSocketReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ConsoleSocketReturnsOnCall(i int, result1 string, result2 error) {
+	fake.ConsoleSocketStub = nil
+	if fake.consoleSocketReturnsOnCall == nil {
+		fake.consoleSocketReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.consoleSocketReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) SetHyperV() error {
+	fake.setHyperVMutex.Lock()
+	ret, specificReturn := fake.setHyperVReturnsOnCall[len(fake.setHyperVArgsForCall)]
+	fake.setHyperVArgsForCall = append(fake.setHyperVArgsForCall, struct{}{})
+	fake.recordInvocation("SetHyperV", []interface{}{})
+	fake.setHyperVMutex.Unlock()
+	if fake.SetHyperVStub != nil {
+		return fake.SetHyperVStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setHyperVReturns.result1
+}
+
+func (fake *StateManager) SetHyperVCallCount() int {
+	fake.setHyperVMutex.RLock()
+	defer fake.setHyperVMutex.RUnlock()
+	return len(fake.setHyperVArgsForCall)
+}
+
+func (fake *StateManager) SetHyperVReturns(result1 error) {
+	fake.SetHyperVStub = nil
+	fake.setHyperVReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetHyperVReturnsOnCall(i int, result1 error) {
+	fake.SetHyperVStub = nil
+	if fake.setHyperVReturnsOnCall == nil {
+		fake.setHyperVReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setHyperVReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) HyperV() (bool, error) {
+	fake.hyperVMutex.Lock()
+	ret, specificReturn := fake.hyperVReturnsOnCall[len(fake.hyperVArgsForCall)]
+	fake.hyperVArgsForCall = append(fake.hyperVArgsForCall, struct{}{})
+	fake.recordInvocation("HyperV", []interface{}{})
+	fake.hyperVMutex.Unlock()
+	if fake.HyperVStub != nil {
+		return fake.HyperVStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.hyperVReturns.result1, fake.hyperVReturns.result2
+}
+
+func (fake *StateManager) HyperVCallCount() int {
+	fake.hyperVMutex.RLock()
+	defer fake.hyperVMutex.RUnlock()
+	return len(fake.hyperVArgsForCall)
+}
+
+func (fake *StateManager) HyperVReturns(result1 bool, result2 error) {
+	fake.HyperVStub = nil
+	fake.hyperVReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) HyperVReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.HyperVStub = nil
+	if fake.hyperVReturnsOnCall == nil {
+		fake.hyperVReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.hyperVReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) Lock() error {
+	fake.lockMutex.Lock()
+	ret, specificReturn := fake.lockReturnsOnCall[len(fake.lockArgsForCall)]
+	fake.lockArgsForCall = append(fake.lockArgsForCall, struct{}{})
+	fake.recordInvocation("Lock", []interface{}{})
+	fake.lockMutex.Unlock()
+	if fake.LockStub != nil {
+		return fake.LockStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.lockReturns.result1
+}
+
+func (fake *StateManager) LockCallCount() int {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	return len(fake.lockArgsForCall)
+}
+
+func (fake *StateManager) LockReturns(result1 error) {
+	fake.LockStub = nil
+	fake.lockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) LockReturnsOnCall(i int, result1 error) {
+	fake.LockStub = nil
+	if fake.lockReturnsOnCall == nil {
+		fake.lockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.lockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) Unlock() error {
+	fake.unlockMutex.Lock()
+	ret, specificReturn := fake.unlockReturnsOnCall[len(fake.unlockArgsForCall)]
+	fake.unlockArgsForCall = append(fake.unlockArgsForCall, struct{}{})
+	fake.recordInvocation("Unlock", []interface{}{})
+	fake.unlockMutex.Unlock()
+	if fake.UnlockStub != nil {
+		return fake.UnlockStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unlockReturns.result1
+}
+
+func (fake *StateManager) UnlockCallCount() int {
+	fake.unlockMutex.RLock()
+	defer fake.unlockMutex.RUnlock()
+	return len(fake.unlockArgsForCall)
+}
+
+func (fake *StateManager) UnlockReturns(result1 error) {
+	fake.UnlockStub = nil
+	fake.unlockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) UnlockReturnsOnCall(i int, result1 error) {
+	fake.UnlockStub = nil
+	if fake.unlockReturnsOnCall == nil {
+		fake.unlockReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unlockReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) AddProcess(arg1 string, arg2 hcs.Process, arg3 *specs.Process, arg4 bool, arg5 string) error {
+	fake.addProcessMutex.Lock()
+	ret, specificReturn := fake.addProcessReturnsOnCall[len(fake.addProcessArgsForCall)]
+	fake.addProcessArgsForCall = append(fake.addProcessArgsForCall, struct {
+		arg1 string
+		arg2 hcs.Process
+		arg3 *specs.Process
+		arg4 bool
+		arg5 string
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("AddProcess", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.addProcessMutex.Unlock()
+	if fake.AddProcessStub != nil {
+		return fake.AddProcessStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.addProcessReturns.result1
+}
+
+func (fake *StateManager) AddProcessCallCount() int {
+	fake.addProcessMutex.RLock()
+	defer fake.addProcessMutex.RUnlock()
+	return len(fake.addProcessArgsForCall)
+}
+
+func (fake *StateManager) AddProcessArgsForCall(i int) (string, hcs.Process, *specs.Process, bool, string) {
+	fake.addProcessMutex.RLock()
+	defer fake.addProcessMutex.RUnlock()
+	return fake.addProcessArgsForCall[i].arg1, fake.addProcessArgsForCall[i].arg2, fake.addProcessArgsForCall[i].arg3, fake.addProcessArgsForCall[i].arg4, fake.addProcessArgsForCall[i].arg5
+}
+
+func (fake *StateManager) AddProcessReturns(result1 error) {
+	fake.AddProcessStub = nil
+	fake.addProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) AddProcessReturnsOnCall(i int, result1 error) {
+	fake.AddProcessStub = nil
+	if fake.addProcessReturnsOnCall == nil {
+		fake.addProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) Process(arg1 string) (*state.Process, error) {
+	fake.processMutex.Lock()
+	ret, specificReturn := fake.processReturnsOnCall[len(fake.processArgsForCall)]
+	fake.processArgsForCall = append(fake.processArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("Process", []interface{}{arg1})
+	fake.processMutex.Unlock()
+	if fake.ProcessStub != nil {
+		return fake.ProcessStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processReturns.result1, fake.processReturns.result2
+}
+
+func (fake *StateManager) ProcessCallCount() int {
+	fake.processMutex.RLock()
+	defer fake.processMutex.RUnlock()
+	return len(fake.processArgsForCall)
+}
+
+func (fake *StateManager) ProcessArgsForCall(i int) string {
+	fake.processMutex.RLock()
+	defer fake.processMutex.RUnlock()
+	return fake.processArgsForCall[i].arg1
+}
+
+func (fake *StateManager) ProcessReturns(result1 *state.Process, result2 error) {
+	fake.ProcessStub = nil
+	fake.processReturns = struct {
+		result1 *state.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ProcessReturnsOnCall(i int, result1 *state.Process, result2 error) {
+	fake.ProcessStub = nil
+	if fake.processReturnsOnCall == nil {
+		fake.processReturnsOnCall = make(map[int]struct {
+			result1 *state.Process
+			result2 error
+		})
+	}
+	fake.processReturnsOnCall[i] = struct {
+		result1 *state.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) Processes() ([]state.Process, error) {
+	fake.processesMutex.Lock()
+	ret, specificReturn := fake.processesReturnsOnCall[len(fake.processesArgsForCall)]
+	fake.processesArgsForCall = append(fake.processesArgsForCall, struct{}{})
+	fake.recordInvocation("Processes", []interface{}{})
+	fake.processesMutex.Unlock()
+	if fake.ProcessesStub != nil {
+		return fake.ProcessesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processesReturns.result1, fake.processesReturns.result2
+}
+
+func (fake *StateManager) ProcessesCallCount() int {
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	return len(fake.processesArgsForCall)
+}
+
+func (fake *StateManager) ProcessesReturns(result1 []state.Process, result2 error) {
+	fake.ProcessesStub = nil
+	fake.processesReturns = struct {
+		result1 []state.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ProcessesReturnsOnCall(i int, result1 []state.Process, result2 error) {
+	fake.ProcessesStub = nil
+	if fake.processesReturnsOnCall == nil {
+		fake.processesReturnsOnCall = make(map[int]struct {
+			result1 []state.Process
+			result2 error
+		})
+	}
+	fake.processesReturnsOnCall[i] = struct {
+		result1 []state.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) SetProcessExited(arg1 string, arg2 int) error {
+	fake.setProcessExitedMutex.Lock()
+	ret, specificReturn := fake.setProcessExitedReturnsOnCall[len(fake.setProcessExitedArgsForCall)]
+	fake.setProcessExitedArgsForCall = append(fake.setProcessExitedArgsForCall, struct {
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("SetProcessExited", []interface{}{arg1, arg2})
+	fake.setProcessExitedMutex.Unlock()
+	if fake.SetProcessExitedStub != nil {
+		return fake.SetProcessExitedStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setProcessExitedReturns.result1
+}
+
+func (fake *StateManager) SetProcessExitedCallCount() int {
+	fake.setProcessExitedMutex.RLock()
+	defer fake.setProcessExitedMutex.RUnlock()
+	return len(fake.setProcessExitedArgsForCall)
+}
+
+func (fake *StateManager) SetProcessExitedArgsForCall(i int) (string, int) {
+	fake.setProcessExitedMutex.RLock()
+	defer fake.setProcessExitedMutex.RUnlock()
+	return fake.setProcessExitedArgsForCall[i].arg1, fake.setProcessExitedArgsForCall[i].arg2
+}
+
+func (fake *StateManager) SetProcessExitedReturns(result1 error) {
+	fake.SetProcessExitedStub = nil
+	fake.setProcessExitedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) SetProcessExitedReturnsOnCall(i int, result1 error) {
+	fake.SetProcessExitedStub = nil
+	if fake.setProcessExitedReturnsOnCall == nil {
+		fake.setProcessExitedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setProcessExitedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) ProcessStatus(arg1 *state.Process) (string, error) {
+	fake.processStatusMutex.Lock()
+	ret, specificReturn := fake.processStatusReturnsOnCall[len(fake.processStatusArgsForCall)]
+	fake.processStatusArgsForCall = append(fake.processStatusArgsForCall, struct {
+		arg1 *state.Process
+	}{arg1})
+	fake.recordInvocation("ProcessStatus", []interface{}{arg1})
+	fake.processStatusMutex.Unlock()
+	if fake.ProcessStatusStub != nil {
+		return fake.ProcessStatusStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.processStatusReturns.result1, fake.processStatusReturns.result2
+}
+
+func (fake *StateManager) ProcessStatusCallCount() int {
+	fake.processStatusMutex.RLock()
+	defer fake.processStatusMutex.RUnlock()
+	return len(fake.processStatusArgsForCall)
+}
+
+func (fake *StateManager) ProcessStatusArgsForCall(i int) *state.Process {
+	fake.processStatusMutex.RLock()
+	defer fake.processStatusMutex.RUnlock()
+	return fake.processStatusArgsForCall[i].arg1
+}
+
+func (fake *StateManager) ProcessStatusReturns(result1 string, result2 error) {
+	fake.ProcessStatusStub = nil
+	fake.processStatusReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) ProcessStatusReturnsOnCall(i int, result1 string, result2 error) {
+	fake.ProcessStatusStub = nil
+	if fake.processStatusReturnsOnCall == nil {
+		fake.processStatusReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.processStatusReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateManager) DeleteProcess(arg1 string) error {
+	fake.deleteProcessMutex.Lock()
+	ret, specificReturn := fake.deleteProcessReturnsOnCall[len(fake.deleteProcessArgsForCall)]
+	fake.deleteProcessArgsForCall = append(fake.deleteProcessArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteProcess", []interface{}{arg1})
+	fake.deleteProcessMutex.Unlock()
+	if fake.DeleteProcessStub != nil {
+		return fake.DeleteProcessStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteProcessReturns.result1
+}
+
+func (fake *StateManager) DeleteProcessCallCount() int {
+	fake.deleteProcessMutex.RLock()
+	defer fake.deleteProcessMutex.RUnlock()
+	return len(fake.deleteProcessArgsForCall)
+}
+
+func (fake *StateManager) DeleteProcessArgsForCall(i int) string {
+	fake.deleteProcessMutex.RLock()
+	defer fake.deleteProcessMutex.RUnlock()
+	return fake.deleteProcessArgsForCall[i].arg1
+}
+
+func (fake *StateManager) DeleteProcessReturns(result1 error) {
+	fake.DeleteProcessStub = nil
+	fake.deleteProcessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) DeleteProcessReturnsOnCall(i int, result1 error) {
+	fake.DeleteProcessStub = nil
+	if fake.deleteProcessReturnsOnCall == nil {
+		fake.deleteProcessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteProcessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *StateManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -297,6 +1273,40 @@ func (fake *StateManager) Invocations() map[string][][]interface{} {
 	defer fake.setSuccessMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
+	fake.processUserMutex.RLock()
+	defer fake.processUserMutex.RUnlock()
+	fake.setResourcesMutex.RLock()
+	defer fake.setResourcesMutex.RUnlock()
+	fake.resourcesMutex.RLock()
+	defer fake.resourcesMutex.RUnlock()
+	fake.setExitedMutex.RLock()
+	defer fake.setExitedMutex.RUnlock()
+	fake.exitStatusMutex.RLock()
+	defer fake.exitStatusMutex.RUnlock()
+	fake.setConsoleSocketMutex.RLock()
+	defer fake.setConsoleSocketMutex.RUnlock()
+	fake.consoleSocketMutex.RLock()
+	defer fake.consoleSocketMutex.RUnlock()
+	fake.setHyperVMutex.RLock()
+	defer fake.setHyperVMutex.RUnlock()
+	fake.hyperVMutex.RLock()
+	defer fake.hyperVMutex.RUnlock()
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	fake.unlockMutex.RLock()
+	defer fake.unlockMutex.RUnlock()
+	fake.addProcessMutex.RLock()
+	defer fake.addProcessMutex.RUnlock()
+	fake.processMutex.RLock()
+	defer fake.processMutex.RUnlock()
+	fake.processesMutex.RLock()
+	defer fake.processesMutex.RUnlock()
+	fake.setProcessExitedMutex.RLock()
+	defer fake.setProcessExitedMutex.RUnlock()
+	fake.processStatusMutex.RLock()
+	defer fake.processStatusMutex.RUnlock()
+	fake.deleteProcessMutex.RLock()
+	defer fake.deleteProcessMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -25,6 +25,19 @@ type WrappedProcess struct {
 		result1 int
 		result2 error
 	}
+	AttachConsoleStub        func(string) (int, error)
+	attachConsoleMutex       sync.RWMutex
+	attachConsoleArgsForCall []struct {
+		arg1 string
+	}
+	attachConsoleReturns struct {
+		result1 int
+		result2 error
+	}
+	attachConsoleReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	SetInterruptStub        func(chan os.Signal)
 	setInterruptMutex       sync.RWMutex
 	setInterruptArgsForCall []struct {
@@ -98,6 +111,57 @@ func (fake *WrappedProcess) AttachIOReturnsOnCall(i int, result1 int, result2 er
 	}{result1, result2}
 }
 
+func (fake *WrappedProcess) AttachConsole(arg1 string) (int, error) {
+	fake.attachConsoleMutex.Lock()
+	ret, specificReturn := fake.attachConsoleReturnsOnCall[len(fake.attachConsoleArgsForCall)]
+	fake.attachConsoleArgsForCall = append(fake.attachConsoleArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("AttachConsole", []interface{}{arg1})
+	fake.attachConsoleMutex.Unlock()
+	if fake.AttachConsoleStub != nil {
+		return fake.AttachConsoleStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.attachConsoleReturns.result1, fake.attachConsoleReturns.result2
+}
+
+func (fake *WrappedProcess) AttachConsoleCallCount() int {
+	fake.attachConsoleMutex.RLock()
+	defer fake.attachConsoleMutex.RUnlock()
+	return len(fake.attachConsoleArgsForCall)
+}
+
+func (fake *WrappedProcess) AttachConsoleArgsForCall(i int) string {
+	fake.attachConsoleMutex.RLock()
+	defer fake.attachConsoleMutex.RUnlock()
+	return fake.attachConsoleArgsForCall[i].arg1
+}
+
+func (fake *WrappedProcess) AttachConsoleReturns(result1 int, result2 error) {
+	fake.AttachConsoleStub = nil
+	fake.attachConsoleReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WrappedProcess) AttachConsoleReturnsOnCall(i int, result1 int, result2 error) {
+	fake.AttachConsoleStub = nil
+	if fake.attachConsoleReturnsOnCall == nil {
+		fake.attachConsoleReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.attachConsoleReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *WrappedProcess) SetInterrupt(arg1 chan os.Signal) {
 	fake.setInterruptMutex.Lock()
 	fake.setInterruptArgsForCall = append(fake.setInterruptArgsForCall, struct {
@@ -175,6 +239,8 @@ func (fake *WrappedProcess) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.attachIOMutex.RLock()
 	defer fake.attachIOMutex.RUnlock()
+	fake.attachConsoleMutex.RLock()
+	defer fake.attachConsoleMutex.RUnlock()
 	fake.setInterruptMutex.RLock()
 	defer fake.setInterruptMutex.RUnlock()
 	fake.writePIDFileMutex.RLock()
@@ -0,0 +1,276 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("GC", func() {
+	var (
+		rootDir           string
+		mounter           *fakes.Mounter
+		stateFactory      *fakes.StateFactory
+		containerFactory  *fakes.ContainerFactory
+		processWrapper    *fakes.ProcessWrapper
+		hcsQuery          *fakes.HCSQuery
+		hookRunner        *fakes.HookRunner
+		r                 *runtime.Runtime
+		output            *gbytes.Buffer
+		stateManagers     map[string]*fakes.StateManager
+		containerManagers map[string]*fakes.ContainerManager
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = ioutil.TempDir("", "gc")
+		Expect(err).NotTo(HaveOccurred())
+
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		containerFactory = &fakes.ContainerFactory{}
+		processWrapper = &fakes.ProcessWrapper{}
+		output = gbytes.NewBuffer()
+
+		stateManagers = map[string]*fakes.StateManager{}
+		containerManagers = map[string]*fakes.ContainerManager{}
+		for _, id := range []string{"container-a", "container-on-disk-only"} {
+			Expect(os.MkdirAll(filepath.Join(rootDir, id), 0755)).To(Succeed())
+		}
+		for _, id := range []string{"container-a", "container-on-disk-only", "container-in-hcs-only", "sidecar-in-hcs-only", "docker-container", "unowned-container", "other-root-container"} {
+			stateManagers[id] = &fakes.StateManager{}
+			containerManagers[id] = &fakes.ContainerManager{}
+		}
+
+		stateManagers["container-a"].StateReturns(&specs.State{Version: specs.Version, ID: "container-a", Status: "running", Pid: 99}, nil)
+
+		stateFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, _ *winsyscall.WinSyscall, id, _ string) runtime.StateManager {
+			return stateManagers[id]
+		}
+		containerFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, id string) runtime.ContainerManager {
+			return containerManagers[id]
+		}
+
+		hcsQuery.GetContainersReturns([]hcs.ContainerProperties{
+			{ID: "container-a", Owner: container.Owner(rootDir)},
+			{ID: "container-in-hcs-only", Owner: container.Owner(rootDir)},
+			{ID: "sidecar-in-hcs-only", Owner: "container-a"},
+			{ID: "docker-container", Owner: "docker"},
+			{ID: "unowned-container"},
+			{ID: "other-root-container", Owner: container.Owner("C:\\other-root")},
+		}, nil)
+
+		mounter.MountsReturns([]int{99, 1234}, nil)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("removes orphaned state directories, HCS containers and mount points", func() {
+		Expect(r.GC(output, "json", false)).To(Succeed())
+
+		var orphans []runtime.Orphan
+		Expect(json.Unmarshal(output.Contents(), &orphans)).To(Succeed())
+		Expect(orphans).To(ConsistOf(
+			runtime.Orphan{Kind: "state", ID: "container-on-disk-only", Reason: "state directory has no matching HCS container", Result: "removed"},
+			runtime.Orphan{Kind: "container", ID: "container-in-hcs-only", Reason: "HCS container has no state directory", Result: "removed"},
+			runtime.Orphan{Kind: "container", ID: "sidecar-in-hcs-only", Reason: "HCS container has no state directory", Result: "removed"},
+			runtime.Orphan{Kind: "mount", ID: "1234", Reason: "mount point has no matching container", Result: "removed"},
+		))
+
+		Expect(stateManagers["container-on-disk-only"].DeleteCallCount()).To(Equal(1))
+		Expect(containerManagers["container-on-disk-only"].DeleteCallCount()).To(Equal(0))
+
+		for _, id := range []string{"container-in-hcs-only", "sidecar-in-hcs-only"} {
+			Expect(stateManagers[id].DeleteCallCount()).To(Equal(1))
+			Expect(containerManagers[id].DeleteCallCount()).To(Equal(1))
+			Expect(containerManagers[id].DeleteArgsForCall(0)).To(BeTrue())
+		}
+
+		Expect(mounter.UnmountCallCount()).To(Equal(1))
+		Expect(mounter.UnmountArgsForCall(0)).To(Equal(1234))
+	})
+
+	It("leaves containers alone that winc did not create under this root", func() {
+		Expect(r.GC(output, "json", false)).To(Succeed())
+
+		for _, id := range []string{"docker-container", "unowned-container", "other-root-container"} {
+			Expect(stateManagers[id].LockCallCount()).To(Equal(0))
+			Expect(containerManagers[id].DeleteCallCount()).To(Equal(0))
+		}
+		Expect(stateManagers["container-a"].DeleteCallCount()).To(Equal(0))
+		Expect(containerManagers["container-a"].DeleteCallCount()).To(Equal(0))
+	})
+
+	It("writes a table by default", func() {
+		Expect(r.GC(output, "table", false)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+		Expect(lines).To(HaveLen(5))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"KIND", "ID", "REASON", "RESULT"}))
+		Expect(strings.Fields(lines[1])[:2]).To(Equal([]string{"container", "container-in-hcs-only"}))
+	})
+
+	Context("dry-run is set", func() {
+		It("reports the orphans without removing them", func() {
+			Expect(r.GC(output, "json", true)).To(Succeed())
+
+			var orphans []runtime.Orphan
+			Expect(json.Unmarshal(output.Contents(), &orphans)).To(Succeed())
+			Expect(orphans).To(HaveLen(4))
+			for _, o := range orphans {
+				Expect(o.Result).To(Equal("would remove"))
+			}
+
+			for id := range stateManagers {
+				Expect(stateManagers[id].DeleteCallCount()).To(Equal(0))
+				Expect(containerManagers[id].DeleteCallCount()).To(Equal(0))
+			}
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("there are no orphans", func() {
+		BeforeEach(func() {
+			Expect(os.RemoveAll(filepath.Join(rootDir, "container-on-disk-only"))).To(Succeed())
+			hcsQuery.GetContainersReturns([]hcs.ContainerProperties{{ID: "container-a", Owner: container.Owner(rootDir)}}, nil)
+			mounter.MountsReturns([]int{99}, nil)
+		})
+
+		It("writes an empty list", func() {
+			Expect(r.GC(output, "json", false)).To(Succeed())
+			Expect(output.Contents()).To(MatchJSON("[]"))
+		})
+	})
+
+	Context("a create finishes before the container is locked", func() {
+		BeforeEach(func() {
+			stateManagers["container-in-hcs-only"].LockStub = func() error {
+				return os.MkdirAll(filepath.Join(rootDir, "container-in-hcs-only"), 0755)
+			}
+		})
+
+		It("keeps the container", func() {
+			Expect(r.GC(output, "json", false)).To(Succeed())
+
+			var orphans []runtime.Orphan
+			Expect(json.Unmarshal(output.Contents(), &orphans)).To(Succeed())
+			Expect(orphans).To(ContainElement(runtime.Orphan{Kind: "container", ID: "container-in-hcs-only", Reason: "HCS container has no state directory", Result: "kept, state was created"}))
+
+			Expect(stateManagers["container-in-hcs-only"].DeleteCallCount()).To(Equal(0))
+			Expect(containerManagers["container-in-hcs-only"].DeleteCallCount()).To(Equal(0))
+			Expect(stateManagers["container-in-hcs-only"].UnlockCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("a create finishes before the state directory is locked", func() {
+		BeforeEach(func() {
+			stateManagers["container-on-disk-only"].LockStub = func() error {
+				hcsQuery.GetContainersReturnsOnCall(1, []hcs.ContainerProperties{{ID: "container-on-disk-only", Owner: container.Owner(rootDir)}}, nil)
+				return nil
+			}
+		})
+
+		It("keeps the state directory", func() {
+			Expect(r.GC(output, "json", false)).To(Succeed())
+
+			var orphans []runtime.Orphan
+			Expect(json.Unmarshal(output.Contents(), &orphans)).To(Succeed())
+			Expect(orphans).To(ContainElement(runtime.Orphan{Kind: "state", ID: "container-on-disk-only", Reason: "state directory has no matching HCS container", Result: "kept, container was created"}))
+
+			Expect(hcsQuery.GetContainersArgsForCall(1)).To(Equal(hcs.ComputeSystemQuery{IDs: []string{"container-on-disk-only"}}))
+			Expect(stateManagers["container-on-disk-only"].DeleteCallCount()).To(Equal(0))
+			Expect(stateManagers["container-on-disk-only"].UnlockCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("a container is hyperv isolated", func() {
+		BeforeEach(func() {
+			stateManagers["container-a"].HyperVReturns(true, nil)
+		})
+
+		It("does not treat the pid of its init process as a live host pid", func() {
+			Expect(r.GC(output, "json", false)).To(Succeed())
+
+			Expect(mounter.UnmountCallCount()).To(Equal(2))
+			Expect([]int{mounter.UnmountArgsForCall(0), mounter.UnmountArgsForCall(1)}).To(ConsistOf(99, 1234))
+		})
+	})
+
+	Context("the state of a container cannot be read", func() {
+		BeforeEach(func() {
+			stateManagers["container-a"].StateReturns(nil, errors.New("couldn't read state"))
+		})
+
+		It("does not remove any mount points", func() {
+			Expect(r.GC(output, "json", false)).To(Succeed())
+
+			Expect(mounter.MountsCallCount()).To(Equal(0))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(containerManagers["container-a"].DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("removing an orphan fails", func() {
+		BeforeEach(func() {
+			mounter.UnmountReturns(errors.New("couldn't unmount"))
+		})
+
+		It("removes the other orphans and returns an error", func() {
+			err := r.GC(output, "json", false)
+			Expect(err).To(Equal(&runtime.CleanupError{Failed: 1}))
+
+			var orphans []runtime.Orphan
+			Expect(json.Unmarshal(output.Contents(), &orphans)).To(Succeed())
+			Expect(orphans).To(ContainElement(runtime.Orphan{Kind: "mount", ID: "1234", Reason: "mount point has no matching container", Result: "couldn't unmount"}))
+			Expect(stateManagers["container-on-disk-only"].DeleteCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("querying HCS fails", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns(nil, errors.New("couldn't query"))
+		})
+
+		It("returns the error without removing anything", func() {
+			Expect(r.GC(output, "json", false)).To(MatchError("couldn't query"))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("listing mount points fails", func() {
+		BeforeEach(func() {
+			mounter.MountsReturns(nil, errors.New("couldn't list mounts"))
+		})
+
+		It("returns the error without removing anything", func() {
+			Expect(r.GC(output, "json", false)).To(MatchError("couldn't list mounts"))
+			Expect(stateManagers["container-on-disk-only"].DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the output is nil", func() {
+		It("errors", func() {
+			Expect(r.GC(nil, "json", false)).To(MatchError("provided output is nil"))
+		})
+	})
+})
@@ -1,15 +1,18 @@
 package hcsprocess
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/signal"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/Microsoft/hcsshim"
+	"code.cloudfoundry.org/winc/hcs"
 )
 
 /*
@@ -20,10 +23,10 @@ import (
 const MAX_GRACEFUL_SHUTDOWN_ALLOWED = 10*time.Second + 1*time.Second
 
 type Process struct {
-	process hcsshim.Process
+	process hcs.Process
 }
 
-func New(p hcsshim.Process) *Process {
+func New(p hcs.Process) *Process {
 	return &Process{process: p}
 }
 
@@ -69,14 +72,15 @@ func (p *Process) AttachIO(attachStdin io.Reader, attachStdout, attachStderr io.
 		_ = stdout.Close()
 	}
 
-	if attachStderr != nil {
+	// terminal processes have no stderr pipe
+	if attachStderr != nil && stderr != nil {
 		wg.Add(1)
 		go func() {
 			_, _ = io.Copy(attachStderr, stderr)
 			_ = stderr.Close()
 			wg.Done()
 		}()
-	} else {
+	} else if stderr != nil {
 		_ = stderr.Close()
 	}
 
@@ -89,6 +93,96 @@ func (p *Process) AttachIO(attachStdin io.Reader, attachStdout, attachStderr io.
 	return p.process.ExitCode()
 }
 
+// ConsoleMessage is sent by the client listening on a console socket to
+// write to the console or to resize it.
+type ConsoleMessage struct {
+	Type   string `json:"type"`
+	Data   []byte `json:"data,omitempty"`
+	Width  uint16 `json:"width,omitempty"`
+	Height uint16 `json:"height,omitempty"`
+}
+
+const (
+	ConsoleStdin  = "stdin"
+	ConsoleResize = "resize"
+)
+
+// AttachConsole connects to the unix socket at consoleSocket and relays the
+// process console over it until the process exits.
+//
+// This is not the runc console socket protocol: Windows cannot pass a handle
+// over a unix socket, so the console is relayed instead of being sent as a
+// file descriptor. The console output is written to the connection as it is
+// produced. The client writes newline-delimited JSON ConsoleMessages to the
+// connection: "stdin" messages are written to the console and "resize"
+// messages resize it. The connection is closed once the process exits.
+//
+// The process may have been opened by winc monitor after the winc that
+// created it has exited. Stdio then asks HCS for new handles to the console
+// pipes rather than returning the ones the creating winc closed.
+func (p *Process) AttachConsole(consoleSocket string) (int, error) {
+	conn, err := net.Dial("unix", consoleSocket)
+	if err != nil {
+		return -1, err
+	}
+	defer conn.Close()
+
+	stdin, stdout, stderr, err := p.process.Stdio()
+	if err != nil {
+		return -1, err
+	}
+	if stderr != nil {
+		_ = stderr.Close()
+	}
+	// a process that was opened rather than created may not have them
+	if stdin == nil || stdout == nil {
+		if stdin != nil {
+			_ = stdin.Close()
+		}
+		if stdout != nil {
+			_ = stdout.Close()
+		}
+		return -1, errors.New("process has no console pipes")
+	}
+
+	go func() {
+		decoder := json.NewDecoder(conn)
+		for {
+			var msg ConsoleMessage
+			if err := decoder.Decode(&msg); err != nil {
+				break
+			}
+
+			switch msg.Type {
+			case ConsoleStdin:
+				if _, err := stdin.Write(msg.Data); err != nil {
+					return
+				}
+			case ConsoleResize:
+				_ = p.process.ResizeConsole(msg.Width, msg.Height)
+			}
+		}
+		_ = stdin.Close()
+		p.process.CloseStdin()
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		_, _ = io.Copy(conn, stdout)
+		_ = stdout.Close()
+		wg.Done()
+	}()
+
+	err = p.process.Wait()
+	waitWithTimeout(&wg, MAX_GRACEFUL_SHUTDOWN_ALLOWED)
+	if err != nil {
+		return -1, err
+	}
+
+	return p.process.ExitCode()
+}
+
 func (p *Process) SetInterrupt(s chan os.Signal) {
 	signal.Notify(s, os.Interrupt)
 	go func() {
@@ -1,8 +1,11 @@
 package hcsprocess_test
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 
@@ -195,6 +198,129 @@ var _ = Describe("Process", func() {
 				Expect(attachedStderr.Contents()).To(Equal([]byte{}))
 			})
 		})
+
+		Context("when the process has no stderr pipe", func() {
+			BeforeEach(func() {
+				fakeProcess.StdioReturns(processStdin, processStdout, nil, nil)
+			})
+
+			It("attaches stdin and stdout", func() {
+				_, err := wrappedProcess.AttachIO(attachedStdin, attachedStdout, attachedStderr)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(processStdin).Should(gbytes.Say("something-on-stdin"))
+				Eventually(attachedStdout).Should(gbytes.Say("something-on-stdout"))
+				Expect(attachedStderr.Contents()).To(Equal([]byte{}))
+			})
+		})
+	})
+
+	Describe("AttachConsole", func() {
+		var (
+			consoleSocket string
+			listener      net.Listener
+			conns         chan net.Conn
+			processStdin  *gbytes.Buffer
+			processStdout *io.PipeReader
+			stdoutWriter  *io.PipeWriter
+		)
+
+		BeforeEach(func() {
+			consoleSocket = filepath.Join(tempDir, "console.sock")
+			var err error
+			listener, err = net.Listen("unix", consoleSocket)
+			Expect(err).NotTo(HaveOccurred())
+
+			conns = make(chan net.Conn, 1)
+			go func() {
+				defer GinkgoRecover()
+				conn, err := listener.Accept()
+				Expect(err).NotTo(HaveOccurred())
+				conns <- conn
+			}()
+
+			processStdin = gbytes.NewBuffer()
+			processStdout, stdoutWriter = io.Pipe()
+			fakeProcess.StdioReturns(processStdin, processStdout, nil, nil)
+		})
+
+		AfterEach(func() {
+			listener.Close()
+		})
+
+		It("relays the console over the socket until the process exits", func() {
+			exited := make(chan struct{})
+			fakeProcess.WaitStub = func() error {
+				<-exited
+				return nil
+			}
+			fakeProcess.ExitCodeReturns(3, nil)
+
+			exitCodes := make(chan int, 1)
+			go func() {
+				defer GinkgoRecover()
+				exitCode, err := wrappedProcess.AttachConsole(consoleSocket)
+				Expect(err).NotTo(HaveOccurred())
+				exitCodes <- exitCode
+			}()
+
+			var conn net.Conn
+			Eventually(conns).Should(Receive(&conn))
+			defer conn.Close()
+
+			encoder := json.NewEncoder(conn)
+			Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleStdin, Data: []byte("something-on-stdin")})).To(Succeed())
+			Eventually(processStdin).Should(gbytes.Say("something-on-stdin"))
+
+			Expect(encoder.Encode(hcsprocess.ConsoleMessage{Type: hcsprocess.ConsoleResize, Width: 120, Height: 40})).To(Succeed())
+			Eventually(fakeProcess.ResizeConsoleCallCount).Should(Equal(1))
+			width, height := fakeProcess.ResizeConsoleArgsForCall(0)
+			Expect(width).To(Equal(uint16(120)))
+			Expect(height).To(Equal(uint16(40)))
+
+			go func() {
+				stdoutWriter.Write([]byte("something-on-stdout"))
+				stdoutWriter.Close()
+			}()
+			output := make([]byte, len("something-on-stdout"))
+			_, err := io.ReadFull(conn, output)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(output)).To(Equal("something-on-stdout"))
+
+			close(exited)
+			Eventually(exitCodes).Should(Receive(Equal(3)))
+		})
+
+		Context("when the console socket cannot be reached", func() {
+			It("returns an error", func() {
+				_, err := wrappedProcess.AttachConsole(filepath.Join(tempDir, "missing.sock"))
+				Expect(err).To(HaveOccurred())
+				Expect(fakeProcess.StdioCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the process has no console pipes", func() {
+			BeforeEach(func() {
+				fakeProcess.StdioReturns(nil, nil, nil, nil)
+			})
+
+			It("returns an error without waiting for the process", func() {
+				_, err := wrappedProcess.AttachConsole(consoleSocket)
+				Expect(err).To(MatchError("process has no console pipes"))
+				Expect(fakeProcess.WaitCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when waiting on the process fails", func() {
+			BeforeEach(func() {
+				stdoutWriter.Close()
+				fakeProcess.WaitReturns(errors.New("wait failed"))
+			})
+
+			It("returns the error", func() {
+				_, err := wrappedProcess.AttachConsole(consoleSocket)
+				Expect(err).To(MatchError("wait failed"))
+			})
+		})
 	})
 
 	Describe("SetInterrupt", func() {
@@ -0,0 +1,37 @@
+package hook
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/winc/errcode"
+)
+
+type FailedError struct {
+	Path          string
+	InternalError error
+	Stderr        string
+}
+
+func (e *FailedError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("hook %s failed: %s", e.Path, e.InternalError)
+	}
+	return fmt.Sprintf("hook %s failed: %s: %s", e.Path, e.InternalError, e.Stderr)
+}
+
+func (e *FailedError) Code() errcode.Code {
+	return errcode.Code{Name: "hook_failed", Class: errcode.ClassInternal}
+}
+
+type TimeoutError struct {
+	Path    string
+	Timeout int
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("hook %s timed out after %d seconds", e.Path, e.Timeout)
+}
+
+func (e *TimeoutError) Code() errcode.Code {
+	return errcode.Code{Name: "hook_timeout", Class: errcode.ClassTimeout}
+}
@@ -0,0 +1,23 @@
+// +build !windows
+
+package hook
+
+import (
+	"errors"
+)
+
+// job is a job object on Windows. Other platforms have none, so only the hook
+// itself is killed on timeout.
+type job struct{}
+
+func newJob(pid int) (*job, error) {
+	return nil, errors.New("job objects are only supported on Windows")
+}
+
+func (j *job) Terminate() error {
+	return nil
+}
+
+func (j *job) Close() error {
+	return nil
+}
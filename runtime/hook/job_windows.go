@@ -0,0 +1,43 @@
+package hook
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// job is a job object holding a hook and the processes it starts, so that
+// they can be killed together.
+type job struct {
+	handle windows.Handle
+}
+
+// newJob assigns the process with pid to a new job object. Processes it
+// starts from then on are added to the job as well.
+func newJob(pid int) (*job, error) {
+	handle, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		windows.CloseHandle(handle)
+		return nil, err
+	}
+	defer windows.CloseHandle(process)
+
+	if err := windows.AssignProcessToJobObject(handle, process); err != nil {
+		windows.CloseHandle(handle)
+		return nil, err
+	}
+
+	return &job{handle: handle}, nil
+}
+
+// Terminate kills every process in the job.
+func (j *job) Terminate() error {
+	return windows.TerminateJobObject(j.handle, 1)
+}
+
+func (j *job) Close() error {
+	return windows.CloseHandle(j.handle)
+}
@@ -0,0 +1,151 @@
+package hook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/winc/runtime/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// Hooks are the lifecycle hooks of a bundle. They are read from the bundle
+// config.json directly because the vendored runtime-spec predates the
+// createRuntime hook.
+type Hooks struct {
+	Prestart      []specs.Hook `json:"prestart,omitempty"`
+	CreateRuntime []specs.Hook `json:"createRuntime,omitempty"`
+	Poststart     []specs.Hook `json:"poststart,omitempty"`
+	Poststop      []specs.Hook `json:"poststop,omitempty"`
+}
+
+type Runner struct{}
+
+// Load reads the hooks of the bundle at bundlePath. A bundle without a
+// config.json has no hooks.
+func (r *Runner) Load(bundlePath string) (Hooks, error) {
+	content, err := ioutil.ReadFile(filepath.Join(bundlePath, config.SpecConfig))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Hooks{}, nil
+		}
+		return Hooks{}, err
+	}
+
+	var spec struct {
+		Hooks *Hooks `json:"hooks"`
+	}
+	if err := json.Unmarshal(content, &spec); err != nil {
+		return Hooks{}, err
+	}
+
+	if spec.Hooks == nil {
+		return Hooks{}, nil
+	}
+	return *spec.Hooks, nil
+}
+
+// Run runs each hook in order with the container state on its stdin, and
+// stops at the first hook that fails or outlives its timeout.
+func (r *Runner) Run(hooks []specs.Hook, state *specs.State, logger *logrus.Entry) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	stateJson, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range hooks {
+		logger.WithField("hook", h.Path).Debug("running hook")
+		if err := run(h, stateJson, logger); err != nil {
+			logger.WithField("hook", h.Path).Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stderrGracePeriod is how long a failed hook's stderr is read for once it
+// has exited, as processes it started may still hold the pipe open.
+const stderrGracePeriod = time.Second
+
+// run runs the hook in a job object, so that a hook that outlives its timeout
+// is killed along with the processes it started.
+func run(h specs.Hook, state []byte, logger *logrus.Entry) error {
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		return &FailedError{Path: h.Path, InternalError: err}
+	}
+
+	cmd := exec.Command(h.Path)
+	if len(h.Args) > 0 {
+		cmd.Args = h.Args
+	}
+	cmd.Env = h.Env
+	cmd.Stdin = bytes.NewReader(state)
+	// stderr is a pipe of our own rather than a buffer, so that cmd.Wait
+	// returns once the hook exits rather than once every process holding the
+	// pipe has
+	cmd.Stderr = stderrW
+
+	err = cmd.Start()
+	stderrW.Close()
+	if err != nil {
+		stderrR.Close()
+		return &FailedError{Path: h.Path, InternalError: err}
+	}
+
+	j, err := newJob(cmd.Process.Pid)
+	if err != nil {
+		logger.WithField("hook", h.Path).Warnf("failed to add hook to a job object, only the hook will be killed on timeout: %s", err)
+	} else {
+		defer j.Close()
+	}
+
+	var stderr bytes.Buffer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(&stderr, stderrR)
+		stderrR.Close()
+		close(copied)
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var timeout <-chan time.Time
+	if h.Timeout != nil && *h.Timeout > 0 {
+		timeout = time.After(time.Duration(*h.Timeout) * time.Second)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-copied:
+			return &FailedError{Path: h.Path, InternalError: err, Stderr: stderr.String()}
+		case <-time.After(stderrGracePeriod):
+			return &FailedError{Path: h.Path, InternalError: err}
+		}
+	case <-timeout:
+		if j == nil || j.Terminate() != nil {
+			cmd.Process.Kill()
+		}
+		<-done
+		return &TimeoutError{Path: h.Path, Timeout: *h.Timeout}
+	}
+}
@@ -0,0 +1,160 @@
+package hook_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/winc/runtime/hook"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		runner *hook.Runner
+		dir    string
+		logger *logrus.Entry
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "hook.test")
+		Expect(err).NotTo(HaveOccurred())
+
+		runner = &hook.Runner{}
+		logger = (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "hook")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("Load", func() {
+		It("reads every lifecycle hook from the bundle config.json", func() {
+			config := `{
+				"hooks": {
+					"prestart": [{"path": "C:\\prestart.exe"}],
+					"createRuntime": [{"path": "C:\\create-runtime.exe", "args": ["create-runtime.exe", "-v"]}],
+					"poststart": [{"path": "C:\\poststart.exe"}],
+					"poststop": [{"path": "C:\\poststop.exe"}]
+				}
+			}`
+			Expect(ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644)).To(Succeed())
+
+			hooks, err := runner.Load(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hooks.Prestart).To(Equal([]specs.Hook{{Path: "C:\\prestart.exe"}}))
+			Expect(hooks.CreateRuntime).To(Equal([]specs.Hook{{Path: "C:\\create-runtime.exe", Args: []string{"create-runtime.exe", "-v"}}}))
+			Expect(hooks.Poststart).To(Equal([]specs.Hook{{Path: "C:\\poststart.exe"}}))
+			Expect(hooks.Poststop).To(Equal([]specs.Hook{{Path: "C:\\poststop.exe"}}))
+		})
+
+		Context("when the bundle has no config.json", func() {
+			It("returns no hooks", func() {
+				hooks, err := runner.Load(dir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hooks).To(Equal(hook.Hooks{}))
+			})
+		})
+
+		Context("when the config.json is invalid", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte("{"), 0644)).To(Succeed())
+			})
+
+			It("errors", func() {
+				_, err := runner.Load(dir)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Run", func() {
+		var (
+			cmdPath string
+			state   *specs.State
+		)
+
+		BeforeEach(func() {
+			cmdPath = filepath.Join(os.Getenv("SystemRoot"), "System32", "cmd.exe")
+			state = &specs.State{ID: "some-container", Status: "creating", Bundle: dir}
+		})
+
+		It("passes the state on stdin", func() {
+			outFile := filepath.Join(dir, "state.json")
+			hooks := []specs.Hook{{Path: cmdPath, Args: []string{"cmd.exe", "/C", "more > " + outFile}}}
+
+			Expect(runner.Run(hooks, state, logger)).To(Succeed())
+
+			contents, err := ioutil.ReadFile(outFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring(`"id":"some-container"`))
+			Expect(string(contents)).To(ContainSubstring(`"status":"creating"`))
+		})
+
+		It("sets the hook environment", func() {
+			outFile := filepath.Join(dir, "env.txt")
+			hooks := []specs.Hook{{
+				Path: cmdPath,
+				Args: []string{"cmd.exe", "/C", "echo %HOOK_VAR% > " + outFile},
+				Env:  []string{"HOOK_VAR=some-value"},
+			}}
+
+			Expect(runner.Run(hooks, state, logger)).To(Succeed())
+
+			contents, err := ioutil.ReadFile(outFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(ContainSubstring("some-value"))
+		})
+
+		Context("when a hook fails", func() {
+			It("returns a FailedError and does not run the remaining hooks", func() {
+				outFile := filepath.Join(dir, "second.txt")
+				hooks := []specs.Hook{
+					{Path: cmdPath, Args: []string{"cmd.exe", "/C", "exit 3"}},
+					{Path: cmdPath, Args: []string{"cmd.exe", "/C", "echo ran > " + outFile}},
+				}
+
+				err := runner.Run(hooks, state, logger)
+				Expect(err).To(BeAssignableToTypeOf(&hook.FailedError{}))
+				Expect(outFile).NotTo(BeAnExistingFile())
+			})
+		})
+
+		Context("when a hook outlives its timeout", func() {
+			It("kills it and returns a TimeoutError", func() {
+				timeout := 1
+				hooks := []specs.Hook{{
+					Path:    cmdPath,
+					Args:    []string{"cmd.exe", "/C", "ping -n 30 127.0.0.1 > nul"},
+					Timeout: &timeout,
+				}}
+
+				err := runner.Run(hooks, state, logger)
+				Expect(err).To(Equal(&hook.TimeoutError{Path: cmdPath, Timeout: 1}))
+			})
+
+			Context("and has started a process that holds its stderr", func() {
+				It("kills both without waiting for the process to exit", func() {
+					timeout := 1
+					hooks := []specs.Hook{{
+						Path:    cmdPath,
+						Args:    []string{"cmd.exe", "/C", "start /B cmd.exe /C ping -n 30 127.0.0.1 & ping -n 30 127.0.0.1 > nul"},
+						Timeout: &timeout,
+					}}
+
+					start := time.Now()
+					err := runner.Run(hooks, state, logger)
+					Expect(err).To(Equal(&hook.TimeoutError{Path: cmdPath, Timeout: 1}))
+					Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+				})
+			})
+		})
+	})
+})
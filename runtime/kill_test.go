@@ -0,0 +1,177 @@
+package runtime_test
+
+import (
+	"errors"
+	"syscall"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Kill", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-kill"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		sm.StateReturns(&specs.State{Status: "running", Pid: 99}, nil)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	It("signals the init process of the container", func() {
+		Expect(r.Kill(containerId, "KILL", false, "")).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		Expect(cm.KillCallCount()).To(Equal(1))
+		pid, sig, all := cm.KillArgsForCall(0)
+		Expect(pid).To(Equal(99))
+		Expect(sig).To(Equal(syscall.SIGKILL))
+		Expect(all).To(BeFalse())
+	})
+
+	It("defaults to SIGTERM", func() {
+		Expect(r.Kill(containerId, "", false, "")).To(Succeed())
+
+		_, sig, _ := cm.KillArgsForCall(0)
+		Expect(sig).To(Equal(syscall.SIGTERM))
+	})
+
+	It("passes through --all", func() {
+		Expect(r.Kill(containerId, "SIGKILL", true, "")).To(Succeed())
+
+		_, _, all := cm.KillArgsForCall(0)
+		Expect(all).To(BeTrue())
+	})
+
+	Context("an exec id is passed", func() {
+		BeforeEach(func() {
+			sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 123}, nil)
+			sm.ProcessStatusReturns("running", nil)
+		})
+
+		It("kills the process started under that exec id", func() {
+			Expect(r.Kill(containerId, "KILL", false, "some-exec")).To(Succeed())
+
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+			Expect(sm.ProcessStatusArgsForCall(0)).To(Equal(&state.Process{ID: "some-exec", PID: 123}))
+
+			pid, sig, all := cm.KillArgsForCall(0)
+			Expect(pid).To(Equal(123))
+			Expect(sig).To(Equal(syscall.SIGKILL))
+			Expect(all).To(BeFalse())
+		})
+
+		Context("the signal is not KILL", func() {
+			It("returns an error without signaling the process", func() {
+				err := r.Kill(containerId, "TERM", false, "some-exec")
+				Expect(err).To(Equal(&runtime.ExecSignalError{ExecId: "some-exec", Signal: "TERM"}))
+				Expect(cm.KillCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the process has exited", func() {
+			BeforeEach(func() {
+				sm.ProcessStatusReturns("stopped", nil)
+			})
+
+			It("returns an error without signaling the process", func() {
+				err := r.Kill(containerId, "KILL", false, "some-exec")
+				Expect(err).To(Equal(&runtime.ExecStateError{Id: containerId, ExecId: "some-exec", Action: "kill", State: "stopped"}))
+				Expect(cm.KillCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("there is no process with that exec id", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"})
+			})
+
+			It("returns an error", func() {
+				err := r.Kill(containerId, "KILL", false, "some-exec")
+				Expect(err).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"}))
+				Expect(cm.KillCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("the signal is invalid", func() {
+		It("returns an InvalidSignalError without signaling the container", func() {
+			err := r.Kill(containerId, "SIGHUP", false, "")
+			Expect(err).To(Equal(&container.InvalidSignalError{Signal: "SIGHUP"}))
+			Expect(cm.KillCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the container is not running", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "created"}, nil)
+		})
+
+		It("returns an InvalidStateError", func() {
+			err := r.Kill(containerId, "KILL", false, "")
+			Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "kill", State: "created"}))
+			Expect(cm.KillCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("getting container state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Kill(containerId, "KILL", false, "")).To(MatchError("couldn't get state"))
+		})
+	})
+
+	Context("signaling the container fails", func() {
+		BeforeEach(func() {
+			cm.KillReturns(errors.New("couldn't kill"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Kill(containerId, "KILL", false, "")).To(MatchError("couldn't kill"))
+		})
+	})
+})
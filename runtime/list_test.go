@@ -0,0 +1,224 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("List", func() {
+	var (
+		rootDir          string
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		containerFactory *fakes.ContainerFactory
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+		output           *gbytes.Buffer
+		stateManagers    map[string]*fakes.StateManager
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = ioutil.TempDir("", "list")
+		Expect(err).NotTo(HaveOccurred())
+
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		containerFactory = &fakes.ContainerFactory{}
+		processWrapper = &fakes.ProcessWrapper{}
+		output = gbytes.NewBuffer()
+
+		stateManagers = map[string]*fakes.StateManager{}
+		for _, id := range []string{"container-a", "container-b", "container-on-disk-only"} {
+			Expect(os.MkdirAll(filepath.Join(rootDir, id), 0755)).To(Succeed())
+			stateManagers[id] = &fakes.StateManager{}
+		}
+		Expect(ioutil.WriteFile(filepath.Join(rootDir, "not-a-container"), nil, 0644)).To(Succeed())
+
+		stateManagers["container-a"].StateReturns(&specs.State{Version: specs.Version, ID: "container-a", Status: "running", Bundle: "bundle-a", Pid: 99, Annotations: map[string]string{
+			"app-guid":              "app-1",
+			state.CreatedAnnotation: "2018-01-02T03:04:05Z",
+		}}, nil)
+		stateManagers["container-b"].StateReturns(&specs.State{Version: specs.Version, ID: "container-b", Status: "created", Bundle: "bundle-b", Annotations: map[string]string{
+			"app-guid": "app-2",
+		}}, nil)
+
+		stateFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, _ *winsyscall.WinSyscall, id, _ string) runtime.StateManager {
+			return stateManagers[id]
+		}
+
+		hcsQuery.GetContainersReturns([]hcs.ContainerProperties{
+			{ID: "container-a", Owner: container.Owner(rootDir)},
+			{ID: "container-b", Owner: "container-a"},
+			{ID: "container-in-hcs-only", Owner: container.Owner(rootDir), Stopped: true},
+			{ID: "sidecar-in-hcs-only", Owner: "container-in-hcs-only"},
+			{ID: "docker-container", Owner: "docker"},
+			{ID: "other-root-container", Owner: container.Owner("C:\\other-root")},
+		}, nil)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	It("queries HCS once and merges the result with the state directories", func() {
+		Expect(r.List(output, "json", false, nil)).To(Succeed())
+
+		Expect(hcsQuery.GetContainersCallCount()).To(Equal(1))
+		Expect(hcsQuery.GetContainersArgsForCall(0)).To(Equal(hcs.ComputeSystemQuery{Types: []string{"Container"}}))
+
+		Expect(stateFactory.NewManagerCallCount()).To(Equal(2))
+		_, c, wc, _, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(rd).To(Equal(rootDir))
+
+		var items []runtime.ContainerListItem
+		Expect(json.Unmarshal(output.Contents(), &items)).To(Succeed())
+		Expect(items).To(HaveLen(5))
+
+		Expect(items[0].ID).To(Equal("container-a"))
+		Expect(items[0].Pid).To(Equal(99))
+		Expect(items[0].Status).To(Equal("running"))
+		Expect(items[0].Bundle).To(Equal("bundle-a"))
+		Expect(items[0].Owner).To(Equal(container.Owner(rootDir)))
+		Expect(items[0].Created).To(Equal(time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)))
+		Expect(items[0].Orphaned).To(BeEmpty())
+		Expect(items[0].Annotations).To(HaveKeyWithValue("app-guid", "app-1"))
+
+		Expect(items[1].ID).To(Equal("container-b"))
+		Expect(items[1].Status).To(Equal("created"))
+		Expect(items[1].Owner).To(Equal("container-a"))
+		Expect(items[1].Created.IsZero()).To(BeFalse())
+		Expect(items[1].Orphaned).To(BeEmpty())
+
+		Expect(items[2].ID).To(Equal("container-in-hcs-only"))
+		Expect(items[2].Status).To(Equal("stopped"))
+		Expect(items[2].Orphaned).To(Equal("HCS container has no state directory"))
+
+		Expect(items[3].ID).To(Equal("container-on-disk-only"))
+		Expect(items[3].Orphaned).To(Equal("state directory has no matching HCS container"))
+
+		Expect(items[4].ID).To(Equal("sidecar-in-hcs-only"))
+		Expect(items[4].Orphaned).To(Equal("HCS container has no state directory"))
+	})
+
+	It("does not list HCS containers winc did not create under this root", func() {
+		Expect(r.List(output, "json", false, nil)).To(Succeed())
+
+		var items []runtime.ContainerListItem
+		Expect(json.Unmarshal(output.Contents(), &items)).To(Succeed())
+		for _, item := range items {
+			Expect(item.ID).NotTo(Equal("docker-container"))
+			Expect(item.ID).NotTo(Equal("other-root-container"))
+		}
+	})
+
+	It("writes a table by default", func() {
+		Expect(r.List(output, "table", false, nil)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(strings.Fields(lines[0])).To(Equal([]string{"ID", "PID", "STATUS", "BUNDLE", "CREATED", "OWNER", "ORPHANED"}))
+		Expect(strings.Fields(lines[1])[:4]).To(Equal([]string{"container-a", "99", "running", "bundle-a"}))
+	})
+
+	It("only writes the ids when quiet", func() {
+		Expect(r.List(output, "table", true, nil)).To(Succeed())
+		Expect(string(output.Contents())).To(Equal("container-a\ncontainer-b\ncontainer-in-hcs-only\ncontainer-on-disk-only\nsidecar-in-hcs-only\n"))
+	})
+
+	Context("filters are provided", func() {
+		It("only lists the containers with matching annotations", func() {
+			Expect(r.List(output, "table", true, map[string]string{"app-guid": "app-2"})).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("container-b\n"))
+		})
+
+		It("requires every filter to match", func() {
+			Expect(r.List(output, "table", true, map[string]string{
+				"app-guid":              "app-1",
+				state.CreatedAnnotation: "2018-01-02T03:04:05Z",
+			})).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("container-a\n"))
+
+			output = gbytes.NewBuffer()
+			Expect(r.List(output, "table", true, map[string]string{
+				"app-guid":              "app-1",
+				state.CreatedAnnotation: "2019-01-02T03:04:05Z",
+			})).To(Succeed())
+			Expect(string(output.Contents())).To(BeEmpty())
+		})
+
+		It("writes an empty list when nothing matches", func() {
+			Expect(r.List(output, "json", false, map[string]string{"app-guid": "unknown"})).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("[]\n"))
+		})
+	})
+
+	Context("reading the state of a container fails", func() {
+		BeforeEach(func() {
+			stateManagers["container-b"].StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("marks the container as orphaned instead of failing", func() {
+			Expect(r.List(output, "json", false, nil)).To(Succeed())
+
+			var items []runtime.ContainerListItem
+			Expect(json.Unmarshal(output.Contents(), &items)).To(Succeed())
+			Expect(items[1].ID).To(Equal("container-b"))
+			Expect(items[1].Status).To(Equal("running"))
+			Expect(items[1].Orphaned).To(Equal("state could not be read: couldn't get state"))
+		})
+	})
+
+	Context("the root directory does not exist", func() {
+		BeforeEach(func() {
+			Expect(os.RemoveAll(rootDir)).To(Succeed())
+			hcsQuery.GetContainersReturns(nil, nil)
+		})
+
+		It("writes an empty list", func() {
+			Expect(r.List(output, "json", false, nil)).To(Succeed())
+			Expect(string(output.Contents())).To(Equal("[]\n"))
+		})
+	})
+
+	Context("querying HCS fails", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns(nil, errors.New("couldn't query"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.List(output, "json", false, nil)).To(MatchError("couldn't query"))
+		})
+	})
+
+	Context("provided output is nil", func() {
+		It("returns an error", func() {
+			Expect(r.List(nil, "json", false, nil)).To(MatchError("provided output is nil"))
+		})
+	})
+})
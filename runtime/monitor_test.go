@@ -0,0 +1,208 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Monitor", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-monitor"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		wrappedProcess   *fakes.WrappedProcess
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		wrappedProcess = &fakes.WrappedProcess{}
+		processWrapper.WrapReturns(wrappedProcess)
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+
+		sm.StateReturns(&specs.State{Status: "running", Pid: 88}, nil)
+		cm.WaitReturns(6, nil)
+	})
+
+	It("waits for the init process and records its exit code", func() {
+		Expect(r.Monitor(containerId, "")).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		pid, timeout := cm.WaitArgsForCall(0)
+		Expect(pid).To(Equal(88))
+		Expect(timeout).To(BeZero())
+		Expect(sm.SetExitedArgsForCall(0)).To(Equal(6))
+	})
+
+	Context("the container is not running", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "stopped", Pid: 88}, nil)
+		})
+
+		It("returns without waiting", func() {
+			Expect(r.Monitor(containerId, "")).To(Succeed())
+			Expect(cm.WaitCallCount()).To(Equal(0))
+			Expect(sm.SetExitedCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the init process has a console socket", func() {
+		var unwrappedProcess *hcsfakes.Process
+
+		BeforeEach(func() {
+			unwrappedProcess = &hcsfakes.Process{}
+			cm.OpenProcessReturns(unwrappedProcess, nil)
+			sm.ConsoleSocketReturns("C:\\console.sock", nil)
+			wrappedProcess.AttachConsoleReturns(4, nil)
+		})
+
+		It("relays the console until the process exits and records its exit code", func() {
+			Expect(r.Monitor(containerId, "")).To(Succeed())
+
+			Expect(cm.OpenProcessArgsForCall(0)).To(Equal(88))
+			Expect(processWrapper.WrapArgsForCall(0)).To(Equal(unwrappedProcess))
+			Expect(wrappedProcess.AttachConsoleArgsForCall(0)).To(Equal("C:\\console.sock"))
+			Expect(unwrappedProcess.CloseCallCount()).To(Equal(1))
+
+			Expect(cm.WaitCallCount()).To(Equal(0))
+			Expect(sm.SetExitedArgsForCall(0)).To(Equal(4))
+		})
+
+		Context("relaying the console fails", func() {
+			BeforeEach(func() {
+				wrappedProcess.AttachConsoleReturns(-1, errors.New("couldn't attach"))
+			})
+
+			It("waits for the process without it", func() {
+				Expect(r.Monitor(containerId, "")).To(Succeed())
+				Expect(cm.WaitArgsForCall(0)).To(Equal(88))
+				Expect(sm.SetExitedArgsForCall(0)).To(Equal(6))
+			})
+		})
+	})
+
+	Context("an exec id is passed", func() {
+		BeforeEach(func() {
+			sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 99, ConsoleSocket: "C:\\exec-console.sock"}, nil)
+			cm.OpenProcessReturns(&hcsfakes.Process{}, nil)
+			wrappedProcess.AttachConsoleReturns(3, nil)
+		})
+
+		It("relays the console of the exec'd process until it exits and records its exit code", func() {
+			Expect(r.Monitor(containerId, "some-exec")).To(Succeed())
+
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+			Expect(cm.OpenProcessArgsForCall(0)).To(Equal(99))
+			Expect(wrappedProcess.AttachConsoleArgsForCall(0)).To(Equal("C:\\exec-console.sock"))
+			Expect(sm.SetExitedCallCount()).To(Equal(0))
+
+			execId, exitCode := sm.SetProcessExitedArgsForCall(0)
+			Expect(execId).To(Equal("some-exec"))
+			Expect(exitCode).To(Equal(3))
+		})
+
+		Context("the process exited before it was recorded", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 99, Exit: &state.ExitStatus{Code: 4}}, nil)
+			})
+
+			It("returns without waiting", func() {
+				Expect(r.Monitor(containerId, "some-exec")).To(Succeed())
+				Expect(cm.OpenProcessCallCount()).To(Equal(0))
+				Expect(cm.WaitCallCount()).To(Equal(0))
+				Expect(sm.SetProcessExitedCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the exit code has already been collected", func() {
+			BeforeEach(func() {
+				sm.SetProcessExitedReturns(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"})
+			})
+
+			It("succeeds", func() {
+				Expect(r.Monitor(containerId, "some-exec")).To(Succeed())
+			})
+		})
+
+		Context("the process is not recorded", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"})
+			})
+
+			It("returns an error", func() {
+				err := r.Monitor(containerId, "some-exec")
+				Expect(err).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"}))
+				Expect(cm.WaitCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("getting the state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Monitor(containerId, "")).To(MatchError("couldn't get state"))
+		})
+	})
+
+	Context("waiting for the init process fails", func() {
+		BeforeEach(func() {
+			cm.WaitReturns(-1, errors.New("couldn't wait"))
+		})
+
+		It("returns an error without recording an exit code", func() {
+			Expect(r.Monitor(containerId, "")).To(MatchError("couldn't wait"))
+			Expect(sm.SetExitedCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("recording the exit code fails", func() {
+		BeforeEach(func() {
+			sm.SetExitedReturns(errors.New("couldn't write state"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Monitor(containerId, "")).To(MatchError("couldn't write state"))
+		})
+	})
+})
@@ -2,21 +2,13 @@ package mount
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
-	"unsafe"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sys/windows"
-)
-
-var (
-	kernel32                = windows.NewLazySystemDLL("kernel32.dll")
-	deleteVolumeMountPointW = kernel32.NewProc("DeleteVolumeMountPointW")
-	setVolumeMountPointW    = kernel32.NewProc("SetVolumeMountPointW")
 )
 
 type Mounter struct{}
@@ -44,54 +36,34 @@ func (m *Mounter) Unmount(pid int) error {
 	return os.RemoveAll(mountPath(pid))
 }
 
-func (m *Mounter) setPoint(mountPoint, volume string) error {
-	if err := setVolumeMountPointW.Find(); err != nil {
-		return err
-	}
-
-	mountPoint = ensureTrailingBackslash(mountPoint)
-	volume = ensureTrailingBackslash(volume)
-
-	mp, err := syscall.UTF16PtrFromString(mountPoint)
+// Mounts returns the pids that have a volume mount point under c:\proc.
+func (m *Mounter) Mounts() ([]int, error) {
+	entries, err := ioutil.ReadDir(procPath())
 	if err != nil {
-		return err
-	}
-
-	vol, err := syscall.UTF16PtrFromString(volume)
-	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	r0, _, err := syscall.Syscall(setVolumeMountPointW.Addr(), 2, uintptr(unsafe.Pointer(mp)), uintptr(unsafe.Pointer(vol)), 0)
-	if int32(r0) == 0 {
-		return fmt.Errorf("error setting mount point: %s", err.Error())
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		pids = append(pids, pid)
 	}
 
-	return nil
+	return pids, nil
 }
 
-func (m *Mounter) deletePoint(mountPoint string) error {
-	if err := deleteVolumeMountPointW.Find(); err != nil {
-		return err
-	}
-
-	mountPoint = ensureTrailingBackslash(mountPoint)
-
-	mp, err := syscall.UTF16PtrFromString(mountPoint)
-	if err != nil {
-		return err
-	}
-
-	r0, _, err := syscall.Syscall(deleteVolumeMountPointW.Addr(), 2, uintptr(unsafe.Pointer(mp)), 0, 0)
-	if int32(r0) == 0 {
-		return fmt.Errorf("error deleting mount point: %s", err.Error())
-	}
-
-	return nil
+func procPath() string {
+	return filepath.Join("c:\\", "proc")
 }
 
 func mountPath(pid int) string {
-	return filepath.Join("c:\\", "proc", strconv.Itoa(pid))
+	return filepath.Join(procPath(), strconv.Itoa(pid))
 }
 
 func rootPath(pid int) string {
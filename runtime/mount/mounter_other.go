@@ -0,0 +1,17 @@
+// +build !windows
+
+package mount
+
+import (
+	"errors"
+)
+
+// setPoint and deletePoint fail, as volume mount points are only supported on
+// Windows.
+func (m *Mounter) setPoint(mountPoint, volume string) error {
+	return errors.New("error setting mount point: not supported on this platform")
+}
+
+func (m *Mounter) deletePoint(mountPoint string) error {
+	return errors.New("error deleting mount point: not supported on this platform")
+}
@@ -60,6 +60,16 @@ var _ = Describe("Mounter", func() {
 		Expect(mounter.Unmount(pid)).To(Succeed())
 	})
 
+	It("lists the pids with a mount point", func() {
+		mounter := &mount.Mounter{}
+
+		Expect(mounter.Mount(pid, volumeGuid, logger)).To(Succeed())
+		Expect(mounter.Mounts()).To(ContainElement(pid))
+
+		Expect(mounter.Unmount(pid)).To(Succeed())
+		Expect(mounter.Mounts()).NotTo(ContainElement(pid))
+	})
+
 	It("mount a volume for a pid that already exist", func() {
 		mounter := &mount.Mounter{}
 
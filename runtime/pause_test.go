@@ -0,0 +1,135 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Pause and Resume", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-pause"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	Describe("Pause", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "running"}, nil)
+		})
+
+		It("pauses the container", func() {
+			Expect(r.Pause(containerId)).To(Succeed())
+
+			_, c, id := containerFactory.NewManagerArgsForCall(0)
+			Expect(*c).To(Equal(hcs.Client{}))
+			Expect(id).To(Equal(containerId))
+
+			_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+			Expect(*c).To(Equal(hcs.Client{}))
+			Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+			Expect(id).To(Equal(containerId))
+			Expect(rd).To(Equal(rootDir))
+
+			Expect(cm.PauseCallCount()).To(Equal(1))
+		})
+
+		Context("the container is not running", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: "paused"}, nil)
+			})
+
+			It("returns an InvalidStateError", func() {
+				err := r.Pause(containerId)
+				Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "pause", State: "paused"}))
+				Expect(cm.PauseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("pausing the container fails", func() {
+			BeforeEach(func() {
+				cm.PauseReturns(errors.New("couldn't pause"))
+			})
+
+			It("returns an error", func() {
+				Expect(r.Pause(containerId)).To(MatchError("couldn't pause"))
+			})
+		})
+
+		Context("getting container state fails", func() {
+			BeforeEach(func() {
+				sm.StateReturns(nil, errors.New("couldn't get state"))
+			})
+
+			It("returns an error", func() {
+				Expect(r.Pause(containerId)).To(MatchError("couldn't get state"))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "paused"}, nil)
+		})
+
+		It("resumes the container", func() {
+			Expect(r.Resume(containerId)).To(Succeed())
+			Expect(cm.ResumeCallCount()).To(Equal(1))
+		})
+
+		Context("the container is not paused", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{Status: "running"}, nil)
+			})
+
+			It("returns an InvalidStateError", func() {
+				err := r.Resume(containerId)
+				Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "resume", State: "running"}))
+				Expect(cm.ResumeCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("resuming the container fails", func() {
+			BeforeEach(func() {
+				cm.ResumeReturns(errors.New("couldn't resume"))
+			})
+
+			It("returns an error", func() {
+				Expect(r.Resume(containerId)).To(MatchError("couldn't resume"))
+			})
+		})
+	})
+})
@@ -0,0 +1,205 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Ps", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-for-ps"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+		output           *gbytes.Buffer
+		created          time.Time
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		output = gbytes.NewBuffer()
+		created = time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		sm.StateReturns(&specs.State{Status: "running", Pid: 99}, nil)
+		sm.ProcessUserStub = func(pid int) (string, error) {
+			if pid == 99 {
+				return "User Manager\\ContainerUser", nil
+			}
+			return "", errors.New("access denied")
+		}
+
+		cm.ProcessListReturns([]hcs.ProcessListItem{
+			{
+				ProcessId:                    4,
+				ImageName:                    "smss.exe",
+				CreateTimestamp:              created,
+				MemoryCommitBytes:            100,
+				MemoryWorkingSetPrivateBytes: 50,
+				KernelTime100ns:              3,
+				UserTime100ns:                4,
+			},
+			{
+				ProcessId:                    99,
+				ImageName:                    "cmd.exe",
+				CreateTimestamp:              created,
+				MemoryCommitBytes:            2048,
+				MemoryWorkingSetPrivateBytes: 1024,
+				KernelTime100ns:              10,
+				UserTime100ns:                20,
+			},
+		}, nil)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	It("writes every process in the container, marking the init process", func() {
+		Expect(r.Ps(containerId, output, "json", "")).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		var processes []runtime.ContainerProcess
+		Expect(json.Unmarshal(output.Contents(), &processes)).To(Succeed())
+		Expect(processes).To(Equal([]runtime.ContainerProcess{
+			{
+				Pid:                          4,
+				ImageName:                    "smss.exe",
+				Created:                      created,
+				MemoryCommitBytes:            100,
+				MemoryWorkingSetPrivateBytes: 50,
+				KernelTime:                   300,
+				UserTime:                     400,
+			},
+			{
+				Pid:                          99,
+				ImageName:                    "cmd.exe",
+				User:                         "User Manager\\ContainerUser",
+				Created:                      created,
+				MemoryCommitBytes:            2048,
+				MemoryWorkingSetPrivateBytes: 1024,
+				KernelTime:                   1000,
+				UserTime:                     2000,
+				Init:                         true,
+			},
+		}))
+	})
+
+	It("writes a table", func() {
+		Expect(r.Ps(containerId, output, "table", "")).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(HavePrefix("PID"))
+		Expect(strings.Fields(lines[2])).To(ContainElement("cmd.exe"))
+		Expect(strings.Fields(lines[2])).To(ContainElement("*"))
+		Expect(strings.Fields(lines[1])).NotTo(ContainElement("*"))
+	})
+
+	Context("processes were started by winc exec", func() {
+		BeforeEach(func() {
+			sm.ProcessesReturns([]state.Process{{ID: "some-exec", PID: 4}}, nil)
+		})
+
+		It("marks them with their exec id", func() {
+			Expect(r.Ps(containerId, output, "json", "")).To(Succeed())
+
+			var processes []runtime.ContainerProcess
+			Expect(json.Unmarshal(output.Contents(), &processes)).To(Succeed())
+			Expect(processes[0].ExecID).To(Equal("some-exec"))
+			Expect(processes[1].ExecID).To(BeEmpty())
+		})
+
+		It("only writes the process with the exec id that is passed", func() {
+			Expect(r.Ps(containerId, output, "json", "some-exec")).To(Succeed())
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+
+			var processes []runtime.ContainerProcess
+			Expect(json.Unmarshal(output.Contents(), &processes)).To(Succeed())
+			Expect(processes).To(HaveLen(1))
+			Expect(processes[0].Pid).To(Equal(uint32(4)))
+		})
+
+		Context("there is no process with the exec id that is passed", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId, ExecId: "other-exec"})
+			})
+
+			It("returns an error", func() {
+				Expect(r.Ps(containerId, output, "json", "other-exec")).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "other-exec"}))
+			})
+		})
+	})
+
+	Context("the container has not been started", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "created"}, nil)
+		})
+
+		It("does not mark any process as init", func() {
+			Expect(r.Ps(containerId, output, "json", "")).To(Succeed())
+
+			var processes []runtime.ContainerProcess
+			Expect(json.Unmarshal(output.Contents(), &processes)).To(Succeed())
+			for _, p := range processes {
+				Expect(p.Init).To(BeFalse())
+			}
+		})
+	})
+
+	Context("listing the processes fails", func() {
+		BeforeEach(func() {
+			cm.ProcessListReturns(nil, errors.New("couldn't list processes"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Ps(containerId, output, "json", "")).To(MatchError("couldn't list processes"))
+		})
+	})
+
+	Context("getting container state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns an error", func() {
+			Expect(r.Ps(containerId, output, "json", "")).To(MatchError("couldn't get state"))
+		})
+	})
+
+	Context("provided output is nil", func() {
+		It("returns an error", func() {
+			Expect(r.Ps(containerId, nil, "json", "")).To(MatchError("provided output is nil"))
+		})
+	})
+})
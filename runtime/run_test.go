@@ -8,6 +8,7 @@ import (
 	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
@@ -34,6 +35,7 @@ var _ = Describe("Run", func() {
 		wrappedProcess   *fakes.WrappedProcess
 		unwrappedProcess *hcsfakes.Process
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
 		spec             *specs.Spec
 		io               runtime.IO
@@ -45,6 +47,7 @@ var _ = Describe("Run", func() {
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -65,7 +68,7 @@ var _ = Describe("Run", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 
 		stdin = gbytes.NewBuffer()
 		stdout = gbytes.NewBuffer()
@@ -100,8 +103,12 @@ var _ = Describe("Run", func() {
 			Expect(rd).To(Equal(rootDir))
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
-			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			createSpec, owner := cm.CreateArgsForCall(0)
+			Expect(createSpec).To(Equal(spec))
+			Expect(owner).To(Equal(container.Owner(rootDir)))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
@@ -120,6 +127,31 @@ var _ = Describe("Run", func() {
 			Expect(wrappedProcess.WritePIDFileArgsForCall(0)).To(Equal(pidFile))
 			Expect(wrappedProcess.SetInterruptCallCount()).To(Equal(0))
 			Expect(wrappedProcess.AttachIOCallCount()).To(Equal(0))
+			Expect(wrappedProcess.AttachConsoleCallCount()).To(Equal(0))
+		})
+
+		Context("a console socket is provided", func() {
+			BeforeEach(func() {
+				spec.Process.Terminal = true
+				io.ConsoleSocket = "C:\\console.sock"
+			})
+
+			It("creates the console pipes and returns without relaying them, which is left to the monitor", func() {
+				exitCode, err := r.Run(containerId, bundlePath, pidFile, io, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exitCode).To(Equal(0))
+
+				Expect(sm.SetConsoleSocketArgsForCall(0)).To(Equal("C:\\console.sock"))
+
+				_, attach := cm.ExecArgsForCall(0)
+				Expect(attach).To(BeTrue())
+
+				Expect(wrappedProcess.AttachConsoleCallCount()).To(Equal(0))
+				Expect(wrappedProcess.AttachIOCallCount()).To(Equal(0))
+				Expect(sm.SetExitedCallCount()).To(Equal(0))
+				Expect(sm.DeleteCallCount()).To(Equal(0))
+				Expect(cm.DeleteCallCount()).To(Equal(0))
+			})
 		})
 	})
 
@@ -154,8 +186,12 @@ var _ = Describe("Run", func() {
 			Expect(rd).To(Equal(rootDir))
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
-			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			createSpec, owner := cm.CreateArgsForCall(0)
+			Expect(createSpec).To(Equal(spec))
+			Expect(owner).To(Equal(container.Owner(rootDir)))
+			bp, initSpec := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(initSpec).To(Equal(spec))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
@@ -188,6 +224,51 @@ var _ = Describe("Run", func() {
 			Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
 		})
 
+		It("releases the lock on the container while waiting for the init process", func() {
+			var attachedAtUnlock []int
+			sm.UnlockStub = func() error {
+				attachedAtUnlock = append(attachedAtUnlock, wrappedProcess.AttachIOCallCount())
+				return nil
+			}
+
+			_, err := r.Run(containerId, bundlePath, pidFile, io, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.LockCallCount()).To(Equal(2))
+			Expect(attachedAtUnlock).To(Equal([]int{0, 1}))
+		})
+
+		Context("a console socket is provided", func() {
+			BeforeEach(func() {
+				spec.Process.Terminal = true
+				io.ConsoleSocket = "C:\\console.sock"
+				wrappedProcess.AttachConsoleReturns(5, nil)
+			})
+
+			It("relays the console of the init process, waits for it, and deletes the container", func() {
+				exitCode, err := r.Run(containerId, bundlePath, pidFile, io, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exitCode).To(Equal(5))
+
+				Expect(wrappedProcess.AttachConsoleArgsForCall(0)).To(Equal("C:\\console.sock"))
+				Expect(wrappedProcess.AttachIOCallCount()).To(Equal(0))
+				Expect(sm.DeleteCallCount()).To(Equal(1))
+				Expect(cm.DeleteCallCount()).To(Equal(1))
+			})
+
+			Context("the process does not use a terminal", func() {
+				BeforeEach(func() {
+					spec.Process.Terminal = false
+				})
+
+				It("returns an error without creating the container", func() {
+					_, err := r.Run(containerId, bundlePath, pidFile, io, false)
+					Expect(err).To(Equal(&config.ConsoleSocketError{ConsoleSocket: "C:\\console.sock"}))
+					Expect(cm.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("attaching io fails", func() {
 			BeforeEach(func() {
 				cm.ExecReturns(unwrappedProcess, nil)
@@ -293,7 +374,7 @@ var _ = Describe("Run", func() {
 		})
 
 		It("returns the error", func() {
-			err := r.Create(containerId, bundlePath)
+			err := r.Create(containerId, bundlePath, "")
 			Expect(err).To(MatchError("bad spec"))
 		})
 	})
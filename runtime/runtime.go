@@ -1,27 +1,44 @@
 package runtime
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/hook"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
-	"github.com/Microsoft/hcsshim"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
 
+// validExecId matches the exec ids winc accepts, which are used as file names
+// in the state directory of the container.
+var validExecId = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
 //go:generate counterfeiter -o fakes/mounter.go --fake-name Mounter . Mounter
 type Mounter interface {
 	Mount(pid int, volumePath string, logger *logrus.Entry) error
 	Unmount(pid int) error
+	Mounts() ([]int, error)
 }
 
 //go:generate counterfeiter -o fakes/state_factory.go --fake-name StateFactory . StateFactory
@@ -31,11 +48,28 @@ type StateFactory interface {
 
 //go:generate counterfeiter -o fakes/state_manager.go --fake-name StateManager . StateManager
 type StateManager interface {
-	Initialize(string) error
+	Initialize(string, *specs.Spec) error
 	Delete() error
 	SetFailure() error
 	SetSuccess(hcs.Process) error
 	State() (*specs.State, error)
+	ProcessUser(int) (string, error)
+	SetResources(*specs.WindowsResources) error
+	Resources() (*specs.WindowsResources, error)
+	SetExited(int) error
+	ExitStatus() (*state.ExitStatus, error)
+	SetConsoleSocket(string) error
+	ConsoleSocket() (string, error)
+	SetHyperV() error
+	HyperV() (bool, error)
+	Lock() error
+	Unlock() error
+	AddProcess(string, hcs.Process, *specs.Process, bool, string) error
+	Process(string) (*state.Process, error)
+	Processes() ([]state.Process, error)
+	SetProcessExited(string, int) error
+	ProcessStatus(*state.Process) (string, error)
+	DeleteProcess(string) error
 }
 
 //go:generate counterfeiter -o fakes/container_factory.go --fake-name ContainerFactory . ContainerFactory
@@ -46,10 +80,18 @@ type ContainerFactory interface {
 //go:generate counterfeiter -o fakes/container_manager.go --fake-name ContainerManager . ContainerManager
 type ContainerManager interface {
 	Spec(string) (*specs.Spec, error)
-	Create(*specs.Spec) error
+	Create(*specs.Spec, string) error
 	Exec(*specs.Process, bool) (hcs.Process, error)
 	Stats() (container.Statistics, error)
+	ProcessList() ([]hcs.ProcessListItem, error)
+	Pause() error
+	Resume() error
+	Update(*specs.WindowsResources) (container.UpdateResult, error)
+	Kill(int, syscall.Signal, bool) error
+	Wait(int, time.Duration) (int, error)
+	OpenProcess(int) (hcs.Process, error)
 	Delete(bool) error
+	Stop(int, container.StopPolicy) (string, error)
 }
 
 //go:generate counterfeiter -o fakes/process_wrapper.go --fake-name ProcessWrapper . ProcessWrapper
@@ -60,19 +102,110 @@ type ProcessWrapper interface {
 //go:generate counterfeiter -o fakes/wrapped_process.go --fake-name WrappedProcess . WrappedProcess
 type WrappedProcess interface {
 	AttachIO(io.Reader, io.Writer, io.Writer) (int, error)
+	AttachConsole(string) (int, error)
 	SetInterrupt(chan os.Signal)
 	WritePIDFile(string) error
 }
 
+//go:generate counterfeiter -o fakes/hook_runner.go --fake-name HookRunner . HookRunner
+type HookRunner interface {
+	Load(bundlePath string) (hook.Hooks, error)
+	Run(hooks []specs.Hook, state *specs.State, logger *logrus.Entry) error
+}
+
 //go:generate counterfeiter -o fakes/hcsquery.go --fake-name HCSQuery . HCSQuery
 type HCSQuery interface {
-	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
+	GetContainers(hcs.ComputeSystemQuery) ([]hcs.ContainerProperties, error)
 }
 
 type IO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+
+	// ConsoleSocket is the path of a unix socket the console of a terminal
+	// process is relayed over instead of Stdin and Stdout.
+	ConsoleSocket string
+}
+
+// Reasons a container is listed as orphaned.
+const (
+	orphanedNoHCSContainer = "state directory has no matching HCS container"
+	orphanedNoState        = "HCS container has no state directory"
+)
+
+// ContainerListItem is a single row of the output of winc list. Its JSON
+// layout matches the one produced by runc list.
+type ContainerListItem struct {
+	Version     string            `json:"ociVersion"`
+	ID          string            `json:"id"`
+	Pid         int               `json:"pid"`
+	Status      string            `json:"status"`
+	Bundle      string            `json:"bundle"`
+	Created     time.Time         `json:"created"`
+	Owner       string            `json:"owner"`
+	Orphaned    string            `json:"orphaned,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Orphan is a single row of the output of winc gc. Kind is "state" for a
+// state directory, "container" for an HCS container and "mount" for the volume
+// mount point of an init process.
+type Orphan struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+	Result string `json:"result"`
+}
+
+// ContainerProcess is a single row of the output of winc ps. Times are in
+// nanoseconds.
+type ContainerProcess struct {
+	Pid                          uint32    `json:"pid"`
+	ImageName                    string    `json:"image_name"`
+	User                         string    `json:"user"`
+	Created                      time.Time `json:"created"`
+	MemoryCommitBytes            uint64    `json:"memory_commit_bytes"`
+	MemoryWorkingSetPrivateBytes uint64    `json:"memory_working_set_private_bytes"`
+	KernelTime                   uint64    `json:"kernel_time"`
+	UserTime                     uint64    `json:"user_time"`
+	Init                         bool      `json:"init"`
+	ExecID                       string    `json:"exec_id,omitempty"`
+}
+
+// DeleteResult is a single record of the output of winc delete. StoppedBy is
+// the stage of the stop policy that stopped the container.
+type DeleteResult struct {
+	ID        string `json:"id"`
+	StoppedBy string `json:"stopped_by,omitempty"`
+}
+
+// ProcessExit is the output of winc wait.
+type ProcessExit struct {
+	Pid      int `json:"pid"`
+	ExitCode int `json:"exit_code"`
+}
+
+// ProcessState is the output of winc state for a process started by winc
+// exec. ExitCode is set once the exit code of the process has been recorded.
+type ProcessState struct {
+	ContainerID string    `json:"container_id"`
+	ExecID      string    `json:"exec_id"`
+	Pid         int       `json:"pid"`
+	Status      string    `json:"status"`
+	Created     time.Time `json:"created"`
+	Args        []string  `json:"args"`
+	User        string    `json:"user,omitempty"`
+	Detach      bool      `json:"detach"`
+	ExitCode    *int      `json:"exit_code,omitempty"`
+}
+
+// Event is a single record of the output of winc events. Its JSON layout
+// matches the envelope produced by runc events.
+type Event struct {
+	Type string      `json:"type"`
+	ID   string      `json:"id"`
+	Data interface{} `json:"data,omitempty"`
 }
 
 type Runtime struct {
@@ -81,51 +214,88 @@ type Runtime struct {
 	mounter          Mounter
 	hcsQuery         HCSQuery
 	processWrapper   ProcessWrapper
+	hookRunner       HookRunner
 	rootDir          string
+	ctx              context.Context
+	tracer           *hcs.Tracer
 }
 
-func New(s StateFactory, c ContainerFactory, m Mounter, h HCSQuery, p ProcessWrapper, rootDir string) *Runtime {
+func New(s StateFactory, c ContainerFactory, m Mounter, h HCSQuery, p ProcessWrapper, hr HookRunner, rootDir string) *Runtime {
 	return &Runtime{
 		stateFactory:     s,
 		containerFactory: c,
 		mounter:          m,
 		hcsQuery:         h,
 		processWrapper:   p,
+		hookRunner:       hr,
 		rootDir:          rootDir,
 	}
 }
 
-func (r *Runtime) Create(containerId, bundlePath string) error {
+// WithContext returns a copy of the runtime whose waits on HCS return once
+// ctx is done.
+func (r *Runtime) WithContext(ctx context.Context) *Runtime {
+	rc := *r
+	rc.ctx = ctx
+	return &rc
+}
+
+// done returns the Done channel of the context of the runtime, or nil if it
+// has none.
+func (r *Runtime) done() <-chan struct{} {
+	if r.ctx == nil {
+		return nil
+	}
+	return r.ctx.Done()
+}
+
+// WithTracer returns a copy of the runtime that records the calls it makes to
+// HCS with t.
+func (r *Runtime) WithTracer(t *hcs.Tracer) *Runtime {
+	rc := *r
+	rc.tracer = t
+	return &rc
+}
+
+func (r *Runtime) Create(containerId, bundlePath, consoleSocket string) error {
 	logger := logrus.WithFields(logrus.Fields{
-		"bundle":      bundlePath,
-		"containerId": containerId,
+		"bundle":        bundlePath,
+		"containerId":   containerId,
+		"consoleSocket": consoleSocket,
 	})
 	logger.Debug("creating container")
 
-	client := hcs.Client{}
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
 	cm := r.containerFactory.NewManager(logger, &client, containerId)
 
 	wsc := winsyscall.WinSyscall{}
 	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
 
-	_, err := r.createContainer(cm, sm, bundlePath)
-	return err
+	return r.withLock(sm, logger, func() error {
+		_, err := r.createContainer(cm, sm, bundlePath, consoleSocket, logger)
+		return err
+	})
 }
 
-func (r *Runtime) Delete(containerId string, force bool) error {
+// Delete stops the container and its sidecars following policy, deletes them
+// and returns how each was stopped, including those that could not be
+// deleted.
+func (r *Runtime) Delete(containerId string, force bool, policy container.StopPolicy) ([]DeleteResult, error) {
 	logger := logrus.WithFields(logrus.Fields{
-		"containerId": containerId,
-		"force":       force,
+		"containerId":     containerId,
+		"force":           force,
+		"gracePeriod":     policy.GracePeriod,
+		"shutdownTimeout": policy.ShutdownTimeout,
 	})
 	logger.Debug("deleting container")
 
-	client := hcs.Client{}
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
 	wsc := winsyscall.WinSyscall{}
 
-	query := hcsshim.ComputeSystemQuery{Owners: []string{containerId}}
+	query := hcs.ComputeSystemQuery{Owners: []string{containerId}}
 	sidecarContainerProperties, err := r.hcsQuery.GetContainers(query)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	containerIdsToDelete := []string{}
@@ -134,301 +304,1533 @@ func (r *Runtime) Delete(containerId string, force bool) error {
 	}
 	containerIdsToDelete = append(containerIdsToDelete, containerId)
 
-	var errors []string
+	var errs []error
+	results := []DeleteResult{}
 	for _, containerIdToDelete := range containerIdsToDelete {
 		cm := r.containerFactory.NewManager(logger, &client, containerIdToDelete)
 
 		sm := r.stateFactory.NewManager(logger, &client, &wsc, containerIdToDelete, r.rootDir)
 
-		if err := r.deleteContainer(cm, sm, force, logger); err != nil {
-			errors = append(errors, err.Error())
+		var stage string
+		err := r.withLock(sm, logger, func() error {
+			var err error
+			stage, err = r.deleteContainer(cm, sm, force, policy, logger)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, err)
 		}
+		results = append(results, DeleteResult{ID: containerIdToDelete, StoppedBy: stage})
 	}
 
-	if len(errors) == 0 {
-		return nil
-	} else {
-		return fmt.Errorf(strings.Join(errors, "\n"))
+	// a single error is returned as is so that its type, and so its error
+	// code, is kept
+	switch len(errs) {
+	case 0:
+		return results, nil
+	case 1:
+		return results, errs[0]
+	default:
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		return results, errors.New(strings.Join(msgs, "\n"))
 	}
 }
 
-func (r *Runtime) Events(containerId string, output io.Writer, showStats bool) error {
+// Stats returns the resource usage of the container along with the memory
+// limit recorded for it.
+func (r *Runtime) Stats(containerId string) (container.Statistics, error) {
+	logger := logrus.WithField("containerId", containerId)
+	logger.Debug("retrieving container stats")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	return r.containerStats(cm, sm)
+}
+
+// Events writes newline-delimited events for the container to output. With
+// showStats a single stats event is written. Otherwise a stats event is
+// written every interval until the container stops, interleaved with an oom
+// event whenever the container runs out of memory and followed by a final
+// exit event.
+func (r *Runtime) Events(containerId string, output io.Writer, showStats bool, interval time.Duration) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"containerId": containerId,
+		"interval":    interval,
 	})
 	logger.Debug("retrieving container events and info")
 
-	client := hcs.Client{}
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
 	cm := r.containerFactory.NewManager(logger, &client, containerId)
 
-	stats, err := cm.Stats()
-	if err != nil {
-		return err
-	}
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	encoder := json.NewEncoder(output)
 
 	if showStats {
-		if output == nil {
-			return errors.New("provided output is nil")
-		}
-		statsJson, err := json.MarshalIndent(stats, "", "  ")
+		stats, err := r.Stats(containerId)
 		if err != nil {
 			return err
 		}
+		return encoder.Encode(Event{Type: "stats", ID: containerId, Data: stats.Data})
+	}
 
-		_, err = output.Write(statsJson)
+	outOfMemory := false
+	for {
+		ociState, err := sm.State()
 		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		if ociState.Status == "stopped" {
+			return encoder.Encode(Event{Type: "exit", ID: containerId})
+		}
+
+		stats, err := r.containerStats(cm, sm)
+		if err != nil {
+			if _, ok := errors.Cause(err).(*hcs.LowMemoryError); !ok {
+				return err
+			}
+			logger.Debug(err)
+			if err := encoder.Encode(Event{Type: "oom", ID: containerId}); err != nil {
+				return err
+			}
+		} else {
+			if err := encoder.Encode(Event{Type: "stats", ID: containerId, Data: stats.Data}); err != nil {
+				return err
+			}
+
+			limit := stats.Data.Memory.Usage.Limit
+			atLimit := limit != 0 && stats.Data.Memory.Raw.TotalRss >= limit
+			if atLimit && !outOfMemory {
+				if err := encoder.Encode(Event{Type: "oom", ID: containerId}); err != nil {
+					return err
+				}
+			}
+			outOfMemory = atLimit
+		}
+
+		select {
+		case <-r.done():
+			return r.ctx.Err()
+		case <-time.After(interval):
+		}
+	}
 }
 
-func (r *Runtime) Exec(containerId, processConfigFile, pidFile string, processOverrides *specs.Process, io IO, detach bool) (int, error) {
+// Exec starts a process in the container and records it under execId, or
+// under a generated exec id if execId is empty. Unless the process is
+// detached, Exec waits for it to exit and removes the record again. The
+// console of a detached process is left for Monitor to relay.
+func (r *Runtime) Exec(containerId, execId string, processSpec *specs.Process, pidFile string, io IO, detach bool) (int, error) {
 	logger := logrus.WithField("containerId", containerId)
 
-	processSpec, err := config.ValidateProcess(logger, processConfigFile, processOverrides)
+	if execId == "" {
+		var err error
+		execId, err = NewExecId()
+		if err != nil {
+			return 1, err
+		}
+	} else if !validExecId.MatchString(execId) {
+		return 1, &InvalidExecIdError{ExecId: execId}
+	}
+
+	processSpec, err := config.ValidateProcessSpec(logger, processSpec)
 	if err != nil {
 		return 1, err
 	}
 
+	if io.ConsoleSocket != "" && !processSpec.Terminal {
+		return 1, &config.ConsoleSocketError{ConsoleSocket: io.ConsoleSocket}
+	}
+
 	logger = logger.WithFields(logrus.Fields{
-		"processConfig": processConfigFile,
+		"execId":        execId,
 		"pidFile":       pidFile,
 		"args":          processSpec.Args,
 		"cwd":           processSpec.Cwd,
 		"user":          processSpec.User.Username,
 		"env":           processSpec.Env,
+		"terminal":      processSpec.Terminal,
+		"consoleSocket": io.ConsoleSocket,
 		"detach":        detach,
 	})
 	logger.Debug("executing process in container")
 
-	client := hcs.Client{}
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
 	cm := r.containerFactory.NewManager(logger, &client, containerId)
 
-	p, err := cm.Exec(processSpec, !detach)
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	useConsole := io.ConsoleSocket != ""
+	var (
+		p              hcs.Process
+		wrappedProcess WrappedProcess
+	)
+	err = r.withLock(sm, logger, func() error {
+		if _, err := sm.Process(execId); err == nil {
+			return &state.ProcessExistsError{Id: containerId, ExecId: execId}
+		} else if _, ok := err.(*state.ProcessNotFoundError); !ok {
+			return err
+		}
+
+		var err error
+		p, err = cm.Exec(processSpec, !detach || useConsole)
+		if err != nil {
+			return err
+		}
+
+		wrappedProcess = r.processWrapper.Wrap(p)
+		if err := wrappedProcess.WritePIDFile(pidFile); err != nil {
+			return err
+		}
+
+		return sm.AddProcess(execId, p, processSpec, detach, io.ConsoleSocket)
+	})
+	if p != nil {
+		defer p.Close()
+	}
 	if err != nil {
 		return 1, err
 	}
-	defer p.Close()
 
-	wrappedProcess := r.processWrapper.Wrap(p)
-	if err := wrappedProcess.WritePIDFile(pidFile); err != nil {
-		return 1, err
+	// the console of a detached process is relayed by Monitor
+	if detach {
+		return 0, nil
 	}
 
-	if !detach {
+	var exitCode int
+	if useConsole {
+		exitCode, err = wrappedProcess.AttachConsole(io.ConsoleSocket)
+	} else {
 		s := make(chan os.Signal, 1)
 		wrappedProcess.SetInterrupt(s)
-		return wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
+		exitCode, err = wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
 	}
+	// the exit code is handed to the caller, or attaching failed and nothing
+	// will collect it, so the record is no longer needed
+	r.collectProcess(sm, execId, logger)
 
-	return 0, nil
+	return exitCode, err
 }
 
-func (r *Runtime) Run(containerId, bundlePath, pidFile string, io IO, detach bool) (int, error) {
+// Kill sends signal to the init process of the container, or to the process
+// started by winc exec under execId if it is not empty. Only KILL can be sent
+// to an exec'd process.
+func (r *Runtime) Kill(containerId, signal string, all bool, execId string) error {
 	logger := logrus.WithFields(logrus.Fields{
-		"bundle":      bundlePath,
 		"containerId": containerId,
-		"pidFile":     pidFile,
-		"detach":      detach,
+		"signal":      signal,
+		"all":         all,
+		"execId":      execId,
 	})
-	logger.Debug("creating container")
-
-	client := hcs.Client{}
-	cm := r.containerFactory.NewManager(logger, &client, containerId)
-
-	wsc := winsyscall.WinSyscall{}
-	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
-
-	spec, err := r.createContainer(cm, sm, bundlePath)
-	if err != nil {
-		return 1, err
-	}
+	logger.Debug("signaling container")
 
-	process, err := r.startProcess(cm, sm, spec, pidFile, detach, logger)
+	sig, err := container.ParseSignal(signal)
 	if err != nil {
-		return 1, err
+		return err
 	}
-	defer process.Close()
 
-	wrappedProcess := r.processWrapper.Wrap(process)
-	if err = wrappedProcess.WritePIDFile(pidFile); err != nil {
-		return 1, err
+	if execId != "" && sig != syscall.SIGKILL {
+		return &ExecSignalError{ExecId: execId, Signal: signal}
 	}
 
-	if !detach {
-		s := make(chan os.Signal, 1)
-		wrappedProcess.SetInterrupt(s)
-
-		exitCode, attachErr := wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
-		deleteErr := r.deleteContainer(cm, sm, false, logger)
-		if attachErr != nil {
-			return exitCode, attachErr
+	return r.transition(containerId, "kill", "running", logger, func(cm ContainerManager, sm StateManager, ociState *specs.State) error {
+		if execId == "" {
+			return cm.Kill(ociState.Pid, sig, all)
 		}
 
-		if deleteErr != nil {
-			return 1, deleteErr
+		proc, err := sm.Process(execId)
+		if err != nil {
+			return err
 		}
 
-		return exitCode, nil
-	}
+		status, err := sm.ProcessStatus(proc)
+		if err != nil {
+			return err
+		}
+		if status != "running" {
+			return &ExecStateError{Id: containerId, ExecId: execId, Action: "kill", State: status}
+		}
 
-	return 0, nil
+		return cm.Kill(proc.PID, sig, false)
+	})
 }
 
-func (r *Runtime) Start(containerId, pidFile string) error {
+// List writes the containers under the root to output. Only the containers
+// whose state has every annotation in filters, with the same value, are
+// listed.
+func (r *Runtime) List(output io.Writer, format string, quiet bool, filters map[string]string) error {
 	logger := logrus.WithFields(logrus.Fields{
-		"containerId": containerId,
-		"pidFile":     pidFile,
+		"root":    r.rootDir,
+		"filters": filters,
 	})
-	logger.Debug("starting process in container")
-
-	client := hcs.Client{}
-	cm := r.containerFactory.NewManager(logger, &client, containerId)
+	logger.Debug("listing containers")
 
-	wsc := winsyscall.WinSyscall{}
-	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
 
-	ociState, err := sm.State()
+	all, err := r.listContainers(logger)
 	if err != nil {
 		return err
 	}
 
-	if ociState.Status != "created" {
-		return fmt.Errorf("cannot start a container in the %s state", ociState.Status)
+	var items []ContainerListItem
+	for _, item := range all {
+		if matchesFilters(item, filters) {
+			items = append(items, item)
+		}
 	}
 
-	spec, err := cm.Spec(ociState.Bundle)
-	if err != nil {
-		return err
+	if quiet {
+		for _, item := range items {
+			fmt.Fprintln(output, item.ID)
+		}
+		return nil
 	}
 
-	/*
-	* When IO is attached to the process (detach=false), it is seen that
-	* hcsshim will keep a handle to the process open, and therefore the
-	* statemanager can do OpenProcess() to collect information about the process.
-	 */
-	bDetach := false
-	process, err := r.startProcess(cm, sm, spec, pidFile, bDetach, logger)
-	if err != nil {
-		return err
+	if format == "json" {
+		if items == nil {
+			items = []ContainerListItem{}
+		}
+		return json.NewEncoder(output).Encode(items)
 	}
-	defer process.Close()
 
-	wrappedProcess := r.processWrapper.Wrap(process)
-	err = wrappedProcess.WritePIDFile(pidFile)
-	if err != nil {
-		return err
+	w := tabwriter.NewWriter(output, 12, 1, 3, ' ', 0)
+	fmt.Fprint(w, "ID\tPID\tSTATUS\tBUNDLE\tCREATED\tOWNER\tORPHANED\n")
+	for _, item := range items {
+		created := ""
+		if !item.Created.IsZero() {
+			created = item.Created.Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", item.ID, item.Pid, item.Status, item.Bundle, created, item.Owner, item.Orphaned)
 	}
-
-	return nil
+	return w.Flush()
 }
 
-func (r *Runtime) State(containerId string, output io.Writer) error {
+// GC removes the state directories, HCS containers and volume mount points
+// left behind by a create or delete that did not finish, and writes what it
+// found to output. With dryRun nothing is removed.
+func (r *Runtime) GC(output io.Writer, format string, dryRun bool) error {
 	logger := logrus.WithFields(logrus.Fields{
-		"containerId": containerId,
+		"root":   r.rootDir,
+		"dryRun": dryRun,
 	})
-	logger.Debug("retrieving state of container")
-
-	client := hcs.Client{}
-	wsc := winsyscall.WinSyscall{}
-	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+	logger.Debug("collecting orphaned containers")
 
 	if output == nil {
 		return errors.New("provided output is nil")
 	}
 
-	state, err := sm.State()
+	items, err := r.listContainers(logger)
 	if err != nil {
 		return err
 	}
 
-	stateJson, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	wsc := winsyscall.WinSyscall{}
 
-	_, err = output.Write(stateJson)
-	return err
-}
+	var orphans []Orphan
+	livePids := map[int]bool{}
+	pidsKnown := true
+	for _, item := range items {
+		switch item.Orphaned {
+		case "":
+			if item.Pid == 0 {
+				continue
+			}
 
-func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, bundlePath string) (*specs.Spec, error) {
-	spec, err := cm.Spec(bundlePath)
-	if err != nil {
-		return nil, err
+			// the init process of a Hyper-V container runs in the utility VM and
+			// has no mount point on the host
+			sm := r.stateFactory.NewManager(logger.WithField("containerId", item.ID), &client, &wsc, item.ID, r.rootDir)
+			hyperV, err := sm.HyperV()
+			if err != nil {
+				logger.WithField("containerId", item.ID).Error(err)
+				pidsKnown = false
+				continue
+			}
+			if !hyperV {
+				livePids[item.Pid] = true
+			}
+		case orphanedNoHCSContainer:
+			orphans = append(orphans, Orphan{Kind: "state", ID: item.ID, Reason: item.Orphaned})
+		case orphanedNoState:
+			orphans = append(orphans, Orphan{Kind: "container", ID: item.ID, Reason: item.Orphaned})
+		default:
+			pidsKnown = false
+		}
 	}
 
-	if err := cm.Create(spec); err != nil {
-		return nil, err
-	}
+	if pidsKnown {
+		pids, err := r.mounter.Mounts()
+		if err != nil {
+			return err
+		}
 
-	if err := sm.Initialize(bundlePath); err != nil {
-		cm.Delete(false)
-		return nil, err
+		for _, pid := range pids {
+			if !livePids[pid] {
+				orphans = append(orphans, Orphan{Kind: "mount", ID: strconv.Itoa(pid), Reason: "mount point has no matching container"})
+			}
+		}
+	} else {
+		logger.Warn("the state of some containers could not be read, skipping mount points")
 	}
 
-	return spec, nil
-}
+	failed := 0
+	for i, o := range orphans {
+		if dryRun {
+			orphans[i].Result = "would remove"
+			continue
+		}
 
-func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bool, logger *logrus.Entry) error {
-	var errs []string
+		var (
+			errs []string
+			kept string
+		)
+		switch o.Kind {
+		case "state":
+			containerLogger := logger.WithField("containerId", o.ID)
+			sm := r.stateFactory.NewManager(containerLogger, &client, &wsc, o.ID, r.rootDir)
+			err := r.withLock(sm, containerLogger, func() error {
+				// a create that held the lock may have created the container
+				// since the containers were listed
+				created, err := r.hcsQuery.GetContainers(hcs.ComputeSystemQuery{IDs: []string{o.ID}})
+				if err != nil {
+					return err
+				}
+				for _, cp := range created {
+					if cp.ID == o.ID {
+						kept = "kept, container was created"
+						return nil
+					}
+				}
+
+				return sm.Delete()
+			})
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		case "container":
+			containerLogger := logger.WithField("containerId", o.ID)
+			cm := r.containerFactory.NewManager(containerLogger, &client, o.ID)
+			sm := r.stateFactory.NewManager(containerLogger, &client, &wsc, o.ID, r.rootDir)
+			if err := sm.Lock(); err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
 
-	ociState, err := sm.State()
-	if err != nil {
-		logger.Error(err)
+			// a create that held the lock may have finished since the containers
+			// were listed
+			if _, err := os.Stat(filepath.Join(r.rootDir, o.ID)); !os.IsNotExist(err) {
+				r.unlock(sm, containerLogger)
+				if err != nil {
+					errs = append(errs, err.Error())
+					break
+				}
+				kept = "kept, state was created"
+				break
+			}
 
-		if _, ok := err.(*hcs.NotFoundError); ok {
-			if force {
-				return nil
+			errs = r.destroyContainer(cm, sm, 0, true, containerLogger)
+			r.unlock(sm, containerLogger)
+		case "mount":
+			pid, _ := strconv.Atoi(o.ID)
+			if err := r.mounter.Unmount(pid); err != nil {
+				errs = append(errs, err.Error())
 			}
-			return err
 		}
 
-		errs = append(errs, err.Error())
-	} else if ociState.Pid != 0 {
-		if err := r.mounter.Unmount(ociState.Pid); err != nil {
-			logger.Error(err)
-			errs = append(errs, err.Error())
+		if len(errs) != 0 {
+			failed++
+			orphans[i].Result = strings.Join(errs, "; ")
+			continue
 		}
+		if kept != "" {
+			orphans[i].Result = kept
+			continue
+		}
+		orphans[i].Result = "removed"
 	}
 
-	if err := sm.Delete(); err != nil {
-		logger.Error(err)
-		errs = append(errs, err.Error())
-	}
-
-	if err := cm.Delete(force); err != nil {
-		logger.Error(err)
-		errs = append(errs, err.Error())
+	if format == "json" {
+		if orphans == nil {
+			orphans = []Orphan{}
+		}
+		if err := json.NewEncoder(output).Encode(orphans); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(output, 12, 1, 3, ' ', 0)
+		fmt.Fprint(w, "KIND\tID\tREASON\tRESULT\n")
+		for _, o := range orphans {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", o.Kind, o.ID, o.Reason, o.Result)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
 	}
 
-	if len(errs) != 0 {
-		return errors.New(strings.Join(errs, "\n"))
+	if failed != 0 {
+		return &CleanupError{Failed: failed}
 	}
 
 	return nil
 }
 
-func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, spec *specs.Spec, pidFile string, detach bool, logger *logrus.Entry) (hcs.Process, error) {
-	process, err := cm.Exec(spec.Process, !detach)
+// Ps writes the processes running in the container to output, or only the
+// process started by winc exec under execId if it is not empty.
+func (r *Runtime) Ps(containerId string, output io.Writer, format, execId string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"execId":      execId,
+	})
+	logger.Debug("listing processes in container")
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(); err != nil {
+		return err
+	}
+	defer r.unlock(sm, logger)
+
+	ociState, err := sm.State()
 	if err != nil {
-		if cErr, ok := errors.Cause(err).(*container.CouldNotCreateProcessError); ok {
-			if sErr := sm.SetFailure(); sErr != nil {
-				logger.Error(sErr)
-			}
-			return nil, cErr
-		}
-		return nil, err
+		return err
 	}
 
-	if err := sm.SetSuccess(process); err != nil {
-		return nil, err
+	records, err := sm.Processes()
+	if err != nil {
+		return err
 	}
 
-	if err := r.mounter.Mount(process.Pid(), spec.Root.Path, logger); err != nil {
-		return nil, err
+	execIds := map[int]string{}
+	for _, record := range records {
+		execIds[record.PID] = record.ID
 	}
 
-	return process, nil
+	if execId != "" {
+		if _, err := sm.Process(execId); err != nil {
+			return err
+		}
+	}
+
+	processListItems, err := cm.ProcessList()
+	if err != nil {
+		return err
+	}
+
+	processes := []ContainerProcess{}
+	for _, p := range processListItems {
+		if execId != "" && execIds[int(p.ProcessId)] != execId {
+			continue
+		}
+
+		user, err := sm.ProcessUser(int(p.ProcessId))
+		if err != nil {
+			logger.WithField("pid", p.ProcessId).Debug(err)
+		}
+
+		processes = append(processes, ContainerProcess{
+			Pid:                          p.ProcessId,
+			ImageName:                    p.ImageName,
+			User:                         user,
+			Created:                      p.CreateTimestamp,
+			MemoryCommitBytes:            p.MemoryCommitBytes,
+			MemoryWorkingSetPrivateBytes: p.MemoryWorkingSetPrivateBytes,
+			KernelTime:                   p.KernelTime100ns * 100,
+			UserTime:                     p.UserTime100ns * 100,
+			Init:                         ociState.Pid != 0 && int(p.ProcessId) == ociState.Pid,
+			ExecID:                       execIds[int(p.ProcessId)],
+		})
+	}
+
+	if format == "json" {
+		return json.NewEncoder(output).Encode(processes)
+	}
+
+	w := tabwriter.NewWriter(output, 12, 1, 3, ' ', 0)
+	fmt.Fprint(w, "PID\tIMAGE\tUSER\tCREATED\tCOMMIT\tPRIVATE WS\tKERNEL\tUSER TIME\tINIT\tEXEC ID\n")
+	for _, p := range processes {
+		initMarker := ""
+		if p.Init {
+			initMarker = "*"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n", p.Pid, p.ImageName, p.User, p.Created.Format(time.RFC3339), p.MemoryCommitBytes, p.MemoryWorkingSetPrivateBytes, time.Duration(p.KernelTime), time.Duration(p.UserTime), initMarker, p.ExecID)
+	}
+	return w.Flush()
+}
+
+// Monitor waits for the init process of a running container, or the process
+// started by winc exec under execId if it is not empty, to exit. Until then
+// the console of the process is relayed over its console socket, if it has
+// one. The exit code is recorded in the container's state, or in the record
+// of the exec'd process. Monitor returns immediately if the container is not
+// running or the exec'd process has already exited.
+func (r *Runtime) Monitor(containerId, execId string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"execId":      execId,
+	})
+	logger.Debug("monitoring container")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	var (
+		pid           int
+		consoleSocket string
+	)
+	err := r.withLock(sm, logger, func() error {
+		ociState, err := sm.State()
+		if err != nil || ociState.Status != "running" {
+			return err
+		}
+
+		if execId != "" {
+			proc, err := sm.Process(execId)
+			if err != nil || proc.Exit != nil {
+				return err
+			}
+			pid, consoleSocket = proc.PID, proc.ConsoleSocket
+			return nil
+		}
+
+		pid = ociState.Pid
+		consoleSocket, err = sm.ConsoleSocket()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if pid == 0 {
+		return nil
+	}
+
+	exitCode, err := r.waitForExit(cm, pid, consoleSocket, logger)
+	if err != nil {
+		return err
+	}
+
+	return r.withLock(sm, logger, func() error {
+		if execId == "" {
+			return sm.SetExited(exitCode)
+		}
+
+		// winc wait may already have collected the exit code and removed the
+		// record
+		err := sm.SetProcessExited(execId, exitCode)
+		if _, ok := err.(*state.ProcessNotFoundError); ok {
+			return nil
+		}
+		return err
+	})
+}
+
+func (r *Runtime) Pause(containerId string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+	})
+	logger.Debug("pausing container")
+
+	return r.transition(containerId, "pause", "running", logger, func(cm ContainerManager, _ StateManager, _ *specs.State) error {
+		return cm.Pause()
+	})
+}
+
+func (r *Runtime) Resume(containerId string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+	})
+	logger.Debug("resuming container")
+
+	return r.transition(containerId, "resume", "paused", logger, func(cm ContainerManager, _ StateManager, _ *specs.State) error {
+		return cm.Resume()
+	})
+}
+
+// Update applies the resource limits read from resourcesFile, with any set
+// fields of overrides taking precedence, to a container that has not
+// stopped. It writes which limits HCS accepted and which it rejected to
+// output and records the accepted ones in the container state.
+func (r *Runtime) Update(containerId, resourcesFile string, overrides *specs.WindowsResources, output io.Writer) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"resources":   resourcesFile,
+	})
+	logger.Debug("updating container resources")
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	resources, err := config.ValidateResources(logger, resourcesFile, overrides)
+	if err != nil {
+		return err
+	}
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(); err != nil {
+		return err
+	}
+	defer r.unlock(sm, logger)
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status == "stopped" {
+		return &container.InvalidStateError{Id: containerId, Action: "update", State: ociState.Status}
+	}
+
+	result, err := cm.Update(resources)
+	if err != nil {
+		return err
+	}
+
+	if err := sm.SetResources(result.Accepted); err != nil {
+		return err
+	}
+
+	resultJson, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := output.Write(resultJson); err != nil {
+		return err
+	}
+
+	if len(result.Rejected) != 0 {
+		var fields []string
+		for field := range result.Rejected {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return &container.ResourcesRejectedError{Id: containerId, Fields: fields}
+	}
+
+	return nil
+}
+
+func (r *Runtime) Run(containerId, bundlePath, pidFile string, io IO, detach bool) (int, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"bundle":        bundlePath,
+		"containerId":   containerId,
+		"pidFile":       pidFile,
+		"consoleSocket": io.ConsoleSocket,
+		"detach":        detach,
+	})
+	logger.Debug("creating container")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	useConsole := io.ConsoleSocket != ""
+	var (
+		process        hcs.Process
+		wrappedProcess WrappedProcess
+	)
+	err := r.withLock(sm, logger, func() error {
+		spec, err := r.createContainer(cm, sm, bundlePath, io.ConsoleSocket, logger)
+		if err != nil {
+			return err
+		}
+
+		process, err = r.startProcess(cm, sm, spec, bundlePath, pidFile, detach && !useConsole, logger)
+		if err != nil {
+			return err
+		}
+
+		wrappedProcess = r.processWrapper.Wrap(process)
+		return wrappedProcess.WritePIDFile(pidFile)
+	})
+	if process != nil {
+		defer process.Close()
+	}
+	if err != nil {
+		return 1, err
+	}
+
+	if !detach {
+		s := make(chan os.Signal, 1)
+		wrappedProcess.SetInterrupt(s)
+
+		var exitCode int
+		var attachErr error
+		if useConsole {
+			exitCode, attachErr = wrappedProcess.AttachConsole(io.ConsoleSocket)
+		} else {
+			exitCode, attachErr = wrappedProcess.AttachIO(io.Stdin, io.Stdout, io.Stderr)
+		}
+		deleteErr := r.withLock(sm, logger, func() error {
+			_, err := r.deleteContainer(cm, sm, false, container.StopPolicy{}, logger)
+			return err
+		})
+		if attachErr != nil {
+			return exitCode, attachErr
+		}
+
+		if deleteErr != nil {
+			return 1, deleteErr
+		}
+
+		return exitCode, nil
+	}
+
+	return 0, nil
+}
+
+func (r *Runtime) Start(containerId, pidFile string) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"pidFile":     pidFile,
+	})
+	logger.Debug("starting process in container")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	var process hcs.Process
+	err := r.withLock(sm, logger, func() error {
+		ociState, err := sm.State()
+		if err != nil {
+			return err
+		}
+
+		if ociState.Status != "created" {
+			return &container.InvalidStateError{Id: containerId, Action: "start", State: ociState.Status}
+		}
+
+		spec, err := cm.Spec(ociState.Bundle)
+		if err != nil {
+			return err
+		}
+
+		/*
+		* When IO is attached to the process (detach=false), it is seen that
+		* hcsshim will keep a handle to the process open, and therefore the
+		* statemanager can do OpenProcess() to collect information about the process.
+		 */
+		bDetach := false
+		process, err = r.startProcess(cm, sm, spec, ociState.Bundle, pidFile, bDetach, logger)
+		if err != nil {
+			return err
+		}
+
+		return r.processWrapper.Wrap(process).WritePIDFile(pidFile)
+	})
+	if process != nil {
+		process.Close()
+	}
+	return err
+}
+
+// State returns the state of the container.
+func (r *Runtime) State(containerId string) (*specs.State, error) {
+	logger := logrus.WithField("containerId", containerId)
+	logger.Debug("retrieving state of container")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	var ociState *specs.State
+	err := r.withLock(sm, logger, func() error {
+		var err error
+		ociState, err = sm.State()
+		return err
+	})
+	return ociState, err
+}
+
+// ProcessState returns the state of the process started by winc exec under
+// execId.
+func (r *Runtime) ProcessState(containerId, execId string) (*ProcessState, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"execId":      execId,
+	})
+	logger.Debug("retrieving state of process")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	var processState *ProcessState
+	err := r.withLock(sm, logger, func() error {
+		var err error
+		processState, err = processStateOf(sm, containerId, execId)
+		return err
+	})
+	return processState, err
+}
+
+// Wait blocks until the init process of the container, or the exec'd process
+// with pid execPid or exec id execId, exits and returns its exit code. If the
+// process has already exited the exit code winc recorded for
+// it is used. The record of an exec'd process is removed once its exit code
+// has been collected.
+func (r *Runtime) Wait(containerId string, execPid int, execId string, timeout time.Duration) (ProcessExit, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+		"execPid":     execPid,
+		"execId":      execId,
+		"timeout":     timeout,
+	})
+	logger.Debug("waiting for process in container")
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	var (
+		ociState *specs.State
+		record   *state.Process
+	)
+	err := r.withLock(sm, logger, func() error {
+		var err error
+		ociState, err = sm.State()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case execId != "":
+			record, err = sm.Process(execId)
+		case execPid != 0 && execPid != ociState.Pid:
+			record, err = processByPid(sm, execPid)
+		}
+		return err
+	})
+	if err != nil {
+		return ProcessExit{}, err
+	}
+
+	if ociState.Status == "created" {
+		return ProcessExit{}, &container.InvalidStateError{Id: containerId, Action: "wait on", State: ociState.Status}
+	}
+
+	pid := execPid
+	if record != nil {
+		pid = record.PID
+	}
+	if pid == 0 {
+		pid = ociState.Pid
+	}
+	isInit := pid == ociState.Pid
+
+	var exitCode int
+	switch {
+	case isInit && ociState.Status == "stopped":
+		exitCode, err = recordedExitCode(containerId, sm)
+	case record != nil && record.Exit != nil:
+		exitCode = record.Exit.Code
+		r.collectProcess(sm, record.ID, logger)
+	default:
+		exitCode, err = cm.Wait(pid, timeout)
+		if err != nil {
+			if _, ok := err.(*container.WaitTimeoutError); ok {
+				return ProcessExit{}, err
+			}
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return ProcessExit{}, err
+			}
+
+			logger.WithField("error", err).Debug("falling back to recorded exit code")
+			if isInit {
+				exitCode, err = recordedExitCode(containerId, sm)
+			} else if record != nil {
+				exitCode, err = r.recordedProcessExitCode(sm, record.ID, err, logger)
+			}
+		} else if isInit {
+			err := r.withLock(sm, logger, func() error {
+				return sm.SetExited(exitCode)
+			})
+			if err != nil {
+				logger.Error(err)
+			}
+		} else if record != nil {
+			r.collectProcess(sm, record.ID, logger)
+		}
+	}
+	if err != nil {
+		return ProcessExit{}, err
+	}
+
+	return ProcessExit{Pid: pid, ExitCode: exitCode}, nil
+}
+
+// recordedProcessExitCode returns the exit code winc monitor recorded for the
+// process with exec id execId and removes its record, or waitErr if no exit
+// code has been recorded.
+func (r *Runtime) recordedProcessExitCode(sm StateManager, execId string, waitErr error, logger *logrus.Entry) (int, error) {
+	var record *state.Process
+	err := r.withLock(sm, logger, func() error {
+		var err error
+		record, err = sm.Process(execId)
+		return err
+	})
+	if err != nil {
+		return 1, err
+	}
+
+	if record.Exit == nil {
+		return 1, waitErr
+	}
+
+	r.collectProcess(sm, execId, logger)
+	return record.Exit.Code, nil
+}
+
+// collectProcess removes the record of the process with exec id execId once
+// its exit code has been handed to the caller.
+func (r *Runtime) collectProcess(sm StateManager, execId string, logger *logrus.Entry) {
+	if err := r.withLock(sm, logger, func() error { return sm.DeleteProcess(execId) }); err != nil {
+		logger.Error(err)
+	}
+}
+
+// waitForExit waits for the process with pid to exit and returns its exit
+// code, relaying its console over consoleSocket until then if it is not
+// empty. If the console cannot be relayed the process is waited on without it.
+func (r *Runtime) waitForExit(cm ContainerManager, pid int, consoleSocket string, logger *logrus.Entry) (int, error) {
+	if consoleSocket != "" {
+		exitCode, err := r.relayConsole(cm, pid, consoleSocket)
+		if err == nil {
+			return exitCode, nil
+		}
+		logger.WithField("error", err).Warn("failed to relay console, waiting for the process without it")
+	}
+
+	return cm.Wait(pid, 0)
+}
+
+func (r *Runtime) relayConsole(cm ContainerManager, pid int, consoleSocket string) (int, error) {
+	p, err := cm.OpenProcess(pid)
+	if err != nil {
+		return -1, err
+	}
+	defer p.Close()
+
+	return r.processWrapper.Wrap(p).AttachConsole(consoleSocket)
+}
+
+// transition runs action against the container once its state has been
+// checked against the one the action requires.
+func (r *Runtime) transition(containerId, name, requiredStatus string, logger *logrus.Entry, action func(ContainerManager, StateManager, *specs.State) error) error {
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	if err := sm.Lock(); err != nil {
+		return err
+	}
+	defer r.unlock(sm, logger)
+
+	ociState, err := sm.State()
+	if err != nil {
+		return err
+	}
+
+	if ociState.Status != requiredStatus {
+		return &container.InvalidStateError{Id: containerId, Action: name, State: ociState.Status}
+	}
+
+	return action(cm, sm, ociState)
+}
+
+// withLock runs action holding the lock on the container. Entry points hold
+// the lock while they read or change a container, but not while they block on
+// a process in it.
+func (r *Runtime) withLock(sm StateManager, logger *logrus.Entry, action func() error) error {
+	if err := sm.Lock(); err != nil {
+		return err
+	}
+	defer r.unlock(sm, logger)
+
+	return action()
+}
+
+func (r *Runtime) unlock(sm StateManager, logger *logrus.Entry) {
+	if err := sm.Unlock(); err != nil {
+		logger.Error(err)
+	}
+}
+
+func (r *Runtime) listContainers(logger *logrus.Entry) ([]ContainerListItem, error) {
+	query := hcs.ComputeSystemQuery{Types: []string{"Container"}}
+	containerProperties, err := r.hcsQuery.GetContainers(query)
+	if err != nil {
+		return nil, err
+	}
+
+	hcsContainers := map[string]hcs.ContainerProperties{}
+	for _, cp := range containerProperties {
+		hcsContainers[cp.ID] = cp
+	}
+
+	entries, err := ioutil.ReadDir(r.rootDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	client := hcs.Client{Context: r.ctx, Tracer: r.tracer}
+	wsc := winsyscall.WinSyscall{}
+
+	var items []ContainerListItem
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		containerId := entry.Name()
+		item := ContainerListItem{ID: containerId, Created: creationTime(entry)}
+
+		cp, inHCS := hcsContainers[containerId]
+		delete(hcsContainers, containerId)
+		if !inHCS {
+			item.Status = "stopped"
+			item.Orphaned = orphanedNoHCSContainer
+			items = append(items, item)
+			continue
+		}
+		item.Owner = cp.Owner
+
+		sm := r.stateFactory.NewManager(logger.WithField("containerId", containerId), &client, &wsc, containerId, r.rootDir)
+		ociState, err := sm.State()
+		if err != nil {
+			logger.WithField("containerId", containerId).Error(err)
+			item.Status = hcsStatus(cp)
+			item.Orphaned = fmt.Sprintf("state could not be read: %s", err.Error())
+			items = append(items, item)
+			continue
+		}
+
+		item.Version = ociState.Version
+		item.Pid = ociState.Pid
+		item.Status = ociState.Status
+		item.Bundle = ociState.Bundle
+		item.Annotations = ociState.Annotations
+		if created, err := time.Parse(time.RFC3339Nano, ociState.Annotations[state.CreatedAnnotation]); err == nil {
+			item.Created = created
+		}
+		items = append(items, item)
+	}
+
+	// an HCS container without a state directory is only listed if winc
+	// created it under this root. A sidecar is owned by the container it
+	// shares its network with, so it belongs to this root if that container
+	// does.
+	owner := container.Owner(r.rootDir)
+	ownedIds := map[string]bool{}
+	for _, item := range items {
+		ownedIds[item.ID] = true
+	}
+	for id, cp := range hcsContainers {
+		if cp.Owner == owner {
+			ownedIds[id] = true
+		}
+	}
+
+	for id, cp := range hcsContainers {
+		if cp.Owner != owner && !ownedIds[cp.Owner] {
+			logger.WithFields(logrus.Fields{"containerId": id, "owner": cp.Owner}).Debug("skipping HCS container not created by winc under this root")
+			continue
+		}
+
+		items = append(items, ContainerListItem{
+			ID:       id,
+			Status:   hcsStatus(cp),
+			Owner:    cp.Owner,
+			Orphaned: orphanedNoState,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	return items, nil
+}
+
+// containerStats returns the statistics HCS reports for the container along
+// with the memory limit recorded for it.
+func (r *Runtime) containerStats(cm ContainerManager, sm StateManager) (container.Statistics, error) {
+	stats, err := cm.Stats()
+	if err != nil {
+		return stats, err
+	}
+
+	resources, err := sm.Resources()
+	if err != nil {
+		return stats, err
+	}
+	if resources != nil && resources.Memory != nil && resources.Memory.Limit != nil {
+		stats.Data.Memory.Usage.Limit = *resources.Memory.Limit
+	}
+
+	return stats, nil
+}
+
+// processStateOf returns the state of the process started by winc exec under
+// execId.
+func processStateOf(sm StateManager, containerId, execId string) (*ProcessState, error) {
+	proc, err := sm.Process(execId)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := sm.ProcessStatus(proc)
+	if err != nil {
+		return nil, err
+	}
+
+	processState := &ProcessState{
+		ContainerID: containerId,
+		ExecID:      proc.ID,
+		Pid:         proc.PID,
+		Status:      status,
+		Created:     proc.Created,
+		Args:        proc.Args,
+		User:        proc.User,
+		Detach:      proc.Detach,
+	}
+	if proc.Exit != nil {
+		processState.ExitCode = &proc.Exit.Code
+	}
+
+	return processState, nil
+}
+
+// NewExecId returns a random exec id for a process started by winc exec
+// without one.
+func NewExecId() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// processByPid returns the record of the process started by winc exec with
+// pid, or nil if winc did not start it.
+func processByPid(sm StateManager, pid int) (*state.Process, error) {
+	records, err := sm.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		if records[i].PID == pid {
+			return &records[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func recordedExitCode(containerId string, sm StateManager) (int, error) {
+	exit, err := sm.ExitStatus()
+	if err != nil {
+		return 1, err
+	}
+
+	if exit == nil {
+		return 1, &NoExitStatusError{Id: containerId}
+	}
+
+	return exit.Code, nil
+}
+
+func hcsStatus(cp hcs.ContainerProperties) string {
+	if cp.Stopped {
+		return "stopped"
+	}
+	return "running"
+}
+
+// matchesFilters reports whether the annotations of item have every key in
+// filters with the same value.
+func matchesFilters(item ContainerListItem, filters map[string]string) bool {
+	for k, v := range filters {
+		if value, ok := item.Annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, bundlePath, consoleSocket string, logger *logrus.Entry) (*specs.Spec, error) {
+	spec, err := cm.Spec(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if consoleSocket != "" && (spec.Process == nil || !spec.Process.Terminal) {
+		return nil, &config.ConsoleSocketError{ConsoleSocket: consoleSocket}
+	}
+
+	hooks, err := r.hookRunner.Load(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cm.Create(spec, container.Owner(r.rootDir)); err != nil {
+		return nil, err
+	}
+
+	if err := sm.Initialize(bundlePath, spec); err != nil {
+		cm.Delete(false)
+		return nil, err
+	}
+
+	if config.Isolation(spec) == config.HyperVIsolation {
+		if err := sm.SetHyperV(); err != nil {
+			sm.Delete()
+			cm.Delete(false)
+			return nil, err
+		}
+	}
+
+	if spec.Windows != nil && spec.Windows.Resources != nil {
+		if err := sm.SetResources(spec.Windows.Resources); err != nil {
+			sm.Delete()
+			cm.Delete(false)
+			return nil, err
+		}
+	}
+
+	if consoleSocket != "" {
+		if err := sm.SetConsoleSocket(consoleSocket); err != nil {
+			sm.Delete()
+			cm.Delete(false)
+			return nil, err
+		}
+	}
+
+	if len(hooks.Prestart) != 0 || len(hooks.CreateRuntime) != 0 {
+		ociState, err := sm.State()
+		if err != nil {
+			sm.Delete()
+			cm.Delete(false)
+			return nil, err
+		}
+		ociState.Status = "creating"
+
+		if err := r.hookRunner.Run(append(hooks.Prestart, hooks.CreateRuntime...), ociState, logger); err != nil {
+			sm.Delete()
+			cm.Delete(false)
+			return nil, err
+		}
+	}
+
+	return spec, nil
+}
+
+// deleteContainer stops the container following policy before destroying it,
+// and returns the stage of the policy that stopped it.
+func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bool, policy container.StopPolicy, logger *logrus.Entry) (string, error) {
+	var errs []string
+
+	ociState, err := sm.State()
+	if err != nil {
+		logger.Error(err)
+
+		if _, ok := err.(*hcs.NotFoundError); ok {
+			if force {
+				return "", nil
+			}
+			return "", err
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	pid := 0
+	if ociState != nil {
+		pid = ociState.Pid
+	}
+
+	// the volume is only mounted for an init process running on the host
+	mountPid := pid
+	if hyperV, err := sm.HyperV(); err != nil || hyperV {
+		mountPid = 0
+	}
+
+	// only a running init process can be asked to exit
+	signalPid := 0
+	if ociState != nil && ociState.Status == "running" {
+		signalPid = pid
+	}
+
+	// failing to stop the container is not fatal, deleting it terminates it
+	stage, err := cm.Stop(signalPid, policy)
+	if err != nil {
+		logger.WithField("error", err).Warn("failed to stop container")
+	} else {
+		logger.WithField("stage", stage).Info("container stopped")
+	}
+
+	errs = append(errs, r.destroyContainer(cm, sm, mountPid, force, logger)...)
+
+	if ociState != nil {
+		r.runPoststopHooks(ociState, logger)
+	}
+
+	if len(errs) != 0 {
+		return stage, errors.New(strings.Join(errs, "\n"))
+	}
+
+	return stage, nil
+}
+
+// destroyContainer unmounts the volume of the init process, if it has one, and
+// removes the state and HCS container, returning any errors it hit.
+func (r *Runtime) destroyContainer(cm ContainerManager, sm StateManager, pid int, force bool, logger *logrus.Entry) []string {
+	var errs []string
+
+	if pid != 0 {
+		if err := r.mounter.Unmount(pid); err != nil {
+			logger.Error(err)
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if err := sm.Delete(); err != nil {
+		logger.Error(err)
+		errs = append(errs, err.Error())
+	}
+
+	if err := cm.Delete(force); err != nil {
+		logger.Error(err)
+		errs = append(errs, err.Error())
+	}
+
+	return errs
+}
+
+func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, spec *specs.Spec, bundlePath, pidFile string, detach bool, logger *logrus.Entry) (hcs.Process, error) {
+	process, err := cm.Exec(spec.Process, !detach)
+	if err != nil {
+		if cErr, ok := errors.Cause(err).(*container.CouldNotCreateProcessError); ok {
+			if sErr := sm.SetFailure(); sErr != nil {
+				logger.Error(sErr)
+			}
+			return nil, cErr
+		}
+		return nil, err
+	}
+
+	if err := sm.SetSuccess(process); err != nil {
+		return nil, err
+	}
+
+	// the pid of a process in a Hyper-V container belongs to the utility VM, so
+	// there is no host process to mount the volume for
+	if config.Isolation(spec) != config.HyperVIsolation {
+		if err := r.mounter.Mount(process.Pid(), spec.Root.Path, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	r.runPoststartHooks(sm, bundlePath, logger)
+
+	return process, nil
+}
+
+// runPoststartHooks runs the poststart hooks of the container's bundle. Per
+// the OCI spec a failing poststart hook does not fail the start.
+func (r *Runtime) runPoststartHooks(sm StateManager, bundlePath string, logger *logrus.Entry) {
+	hooks, err := r.hookRunner.Load(bundlePath)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if len(hooks.Poststart) == 0 {
+		return
+	}
+
+	ociState, err := sm.State()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if err := r.hookRunner.Run(hooks.Poststart, ociState, logger); err != nil {
+		hookLogger(logger, err).Warn("poststart hook failed, continuing")
+	}
+}
+
+// runPoststopHooks runs the poststop hooks of a deleted container's bundle.
+// Per the OCI spec a failing poststop hook does not fail the delete.
+func (r *Runtime) runPoststopHooks(ociState *specs.State, logger *logrus.Entry) {
+	hooks, err := r.hookRunner.Load(ociState.Bundle)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	stopped := *ociState
+	stopped.Status = "stopped"
+	if err := r.hookRunner.Run(hooks.Poststop, &stopped, logger); err != nil {
+		hookLogger(logger, err).Warn("poststop hook failed, continuing")
+	}
+}
+
+// hookLogger returns logger with the error a hook failed with and, if err
+// names it, the path of that hook.
+func hookLogger(logger *logrus.Entry, err error) *logrus.Entry {
+	logger = logger.WithError(err)
+
+	var (
+		failed   *hook.FailedError
+		timedOut *hook.TimeoutError
+	)
+	switch {
+	case errors.As(err, &failed):
+		return logger.WithField("hook", failed.Path)
+	case errors.As(err, &timedOut):
+		return logger.WithField("hook", timedOut.Path)
+	}
+	return logger
 }
@@ -1,16 +1,22 @@
 package runtime_test
 
 import (
+	"bytes"
+	"io/ioutil"
+
 	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/hook"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 var _ = Describe("Start", func() {
@@ -30,6 +36,7 @@ var _ = Describe("Start", func() {
 		wrappedProcess   *fakes.WrappedProcess
 		unwrappedProcess *hcsfakes.Process
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
 		spec             *specs.Spec
 	)
@@ -37,6 +44,7 @@ var _ = Describe("Start", func() {
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -57,7 +65,7 @@ var _ = Describe("Start", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 	})
 
 	Context("starting the container succeeds", func() {
@@ -106,6 +114,31 @@ var _ = Describe("Start", func() {
 			Expect(processWrapper.WrapArgsForCall(0)).To(Equal(unwrappedProcess))
 
 			Expect(wrappedProcess.WritePIDFileArgsForCall(0)).To(Equal(pidFile))
+			Expect(wrappedProcess.AttachConsoleCallCount()).To(Equal(0))
+		})
+
+		Context("the container is hyperv isolated", func() {
+			BeforeEach(func() {
+				spec.Annotations = map[string]string{config.IsolationAnnotation: config.HyperVIsolation}
+			})
+
+			It("does not mount the volume for the pid, which belongs to the utility VM", func() {
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+				Expect(mounter.MountCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the container was created with a console socket", func() {
+			BeforeEach(func() {
+				sm.ConsoleSocketReturns("C:\\console.sock", nil)
+			})
+
+			It("returns without relaying the console, which is left to the monitor", func() {
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+				Expect(wrappedProcess.AttachConsoleCallCount()).To(Equal(0))
+				Expect(sm.SetExitedCallCount()).To(Equal(0))
+				Expect(unwrappedProcess.CloseCallCount()).To(Equal(1))
+			})
 		})
 	})
 
@@ -115,9 +148,9 @@ var _ = Describe("Start", func() {
 			sm.StateReturns(state, nil)
 		})
 
-		It("returns an error", func() {
+		It("returns an InvalidStateError", func() {
 			err := r.Start(containerId, pidFile)
-			Expect(err).To(MatchError("cannot start a container in the running state"))
+			Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "start", State: "running"}))
 		})
 	})
 
@@ -235,4 +268,54 @@ var _ = Describe("Start", func() {
 			Expect(err).To(MatchError("couldn't write pidfile"))
 		})
 	})
+
+	Context("the bundle has poststart hooks", func() {
+		var poststart []specs.Hook
+
+		BeforeEach(func() {
+			state := &specs.State{Status: "created", Bundle: bundlePath}
+			sm.StateReturns(state, nil)
+
+			cm.SpecReturns(spec, nil)
+			cm.ExecReturns(unwrappedProcess, nil)
+			processWrapper.WrapReturns(wrappedProcess)
+
+			poststart = []specs.Hook{{Path: "C:\\hooks\\poststart.exe"}}
+			hookRunner.LoadReturns(hook.Hooks{Poststart: poststart}, nil)
+		})
+
+		It("runs them with the state of the started container", func() {
+			Expect(r.Start(containerId, pidFile)).To(Succeed())
+
+			Expect(hookRunner.LoadArgsForCall(0)).To(Equal(bundlePath))
+			hooks, state, _ := hookRunner.RunArgsForCall(0)
+			Expect(hooks).To(Equal(poststart))
+			Expect(state.Bundle).To(Equal(bundlePath))
+		})
+
+		Context("a poststart hook fails", func() {
+			BeforeEach(func() {
+				hookRunner.RunReturns(&hook.FailedError{Path: "C:\\hooks\\poststart.exe", InternalError: errors.New("exit status 1")})
+			})
+
+			AfterEach(func() {
+				logrus.SetOutput(ioutil.Discard)
+			})
+
+			It("logs which hook failed and why", func() {
+				logOutput := &bytes.Buffer{}
+				logrus.SetOutput(logOutput)
+
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+				Expect(logOutput.String()).To(ContainSubstring("poststart hook failed, continuing"))
+				Expect(logOutput.String()).To(ContainSubstring(`hook="C:\\hooks\\poststart.exe"`))
+				Expect(logOutput.String()).To(ContainSubstring("exit status 1"))
+			})
+
+			It("still starts the container", func() {
+				Expect(r.Start(containerId, pidFile)).To(Succeed())
+				Expect(wrappedProcess.WritePIDFileCallCount()).To(Equal(1))
+			})
+		})
+	})
 })
@@ -0,0 +1,47 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/winc/errcode"
+)
+
+type LockTimeoutError struct {
+	Id      string
+	Timeout time.Duration
+}
+
+func (e *LockTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for the lock on container %s", e.Timeout, e.Id)
+}
+
+func (e *LockTimeoutError) Code() errcode.Code {
+	return errcode.Code{Name: "lock_timeout", Class: errcode.ClassTimeout}
+}
+
+type ProcessExistsError struct {
+	Id     string
+	ExecId string
+}
+
+func (e *ProcessExistsError) Error() string {
+	return fmt.Sprintf("container %s already has a process with exec id %s", e.Id, e.ExecId)
+}
+
+func (e *ProcessExistsError) Code() errcode.Code {
+	return errcode.Code{Name: "exec_id_in_use", Class: errcode.ClassConflict}
+}
+
+type ProcessNotFoundError struct {
+	Id     string
+	ExecId string
+}
+
+func (e *ProcessNotFoundError) Error() string {
+	return fmt.Sprintf("container %s has no process with exec id %s", e.Id, e.ExecId)
+}
+
+func (e *ProcessNotFoundError) Code() errcode.Code {
+	return errcode.Code{Name: "exec_not_found", Class: errcode.ClassNotFound}
+}
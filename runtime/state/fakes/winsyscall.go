@@ -3,13 +3,13 @@ package fakes
 
 import (
 	"sync"
-	"syscall"
 
 	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
 )
 
 type WinSyscall struct {
-	OpenProcessStub        func(uint32, bool, uint32) (syscall.Handle, error)
+	OpenProcessStub        func(uint32, bool, uint32) (winsyscall.Handle, error)
 	openProcessMutex       sync.RWMutex
 	openProcessArgsForCall []struct {
 		arg1 uint32
@@ -17,30 +17,30 @@ type WinSyscall struct {
 		arg3 uint32
 	}
 	openProcessReturns struct {
-		result1 syscall.Handle
+		result1 winsyscall.Handle
 		result2 error
 	}
 	openProcessReturnsOnCall map[int]struct {
-		result1 syscall.Handle
+		result1 winsyscall.Handle
 		result2 error
 	}
-	GetProcessStartTimeStub        func(syscall.Handle) (syscall.Filetime, error)
+	GetProcessStartTimeStub        func(winsyscall.Handle) (winsyscall.Filetime, error)
 	getProcessStartTimeMutex       sync.RWMutex
 	getProcessStartTimeArgsForCall []struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}
 	getProcessStartTimeReturns struct {
-		result1 syscall.Filetime
+		result1 winsyscall.Filetime
 		result2 error
 	}
 	getProcessStartTimeReturnsOnCall map[int]struct {
-		result1 syscall.Filetime
+		result1 winsyscall.Filetime
 		result2 error
 	}
-	CloseHandleStub        func(syscall.Handle) error
+	CloseHandleStub        func(winsyscall.Handle) error
 	closeHandleMutex       sync.RWMutex
 	closeHandleArgsForCall []struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}
 	closeHandleReturns struct {
 		result1 error
@@ -48,10 +48,10 @@ type WinSyscall struct {
 	closeHandleReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetExitCodeProcessStub        func(syscall.Handle) (uint32, error)
+	GetExitCodeProcessStub        func(winsyscall.Handle) (uint32, error)
 	getExitCodeProcessMutex       sync.RWMutex
 	getExitCodeProcessArgsForCall []struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}
 	getExitCodeProcessReturns struct {
 		result1 uint32
@@ -61,11 +61,24 @@ type WinSyscall struct {
 		result1 uint32
 		result2 error
 	}
+	GetProcessUserStub        func(winsyscall.Handle) (string, error)
+	getProcessUserMutex       sync.RWMutex
+	getProcessUserArgsForCall []struct {
+		arg1 winsyscall.Handle
+	}
+	getProcessUserReturns struct {
+		result1 string
+		result2 error
+	}
+	getProcessUserReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *WinSyscall) OpenProcess(arg1 uint32, arg2 bool, arg3 uint32) (syscall.Handle, error) {
+func (fake *WinSyscall) OpenProcess(arg1 uint32, arg2 bool, arg3 uint32) (winsyscall.Handle, error) {
 	fake.openProcessMutex.Lock()
 	ret, specificReturn := fake.openProcessReturnsOnCall[len(fake.openProcessArgsForCall)]
 	fake.openProcessArgsForCall = append(fake.openProcessArgsForCall, struct {
@@ -96,33 +109,33 @@ func (fake *WinSyscall) OpenProcessArgsForCall(i int) (uint32, bool, uint32) {
 	return fake.openProcessArgsForCall[i].arg1, fake.openProcessArgsForCall[i].arg2, fake.openProcessArgsForCall[i].arg3
 }
 
-func (fake *WinSyscall) OpenProcessReturns(result1 syscall.Handle, result2 error) {
+func (fake *WinSyscall) OpenProcessReturns(result1 winsyscall.Handle, result2 error) {
 	fake.OpenProcessStub = nil
 	fake.openProcessReturns = struct {
-		result1 syscall.Handle
+		result1 winsyscall.Handle
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *WinSyscall) OpenProcessReturnsOnCall(i int, result1 syscall.Handle, result2 error) {
+func (fake *WinSyscall) OpenProcessReturnsOnCall(i int, result1 winsyscall.Handle, result2 error) {
 	fake.OpenProcessStub = nil
 	if fake.openProcessReturnsOnCall == nil {
 		fake.openProcessReturnsOnCall = make(map[int]struct {
-			result1 syscall.Handle
+			result1 winsyscall.Handle
 			result2 error
 		})
 	}
 	fake.openProcessReturnsOnCall[i] = struct {
-		result1 syscall.Handle
+		result1 winsyscall.Handle
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *WinSyscall) GetProcessStartTime(arg1 syscall.Handle) (syscall.Filetime, error) {
+func (fake *WinSyscall) GetProcessStartTime(arg1 winsyscall.Handle) (winsyscall.Filetime, error) {
 	fake.getProcessStartTimeMutex.Lock()
 	ret, specificReturn := fake.getProcessStartTimeReturnsOnCall[len(fake.getProcessStartTimeArgsForCall)]
 	fake.getProcessStartTimeArgsForCall = append(fake.getProcessStartTimeArgsForCall, struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}{arg1})
 	fake.recordInvocation("GetProcessStartTime", []interface{}{arg1})
 	fake.getProcessStartTimeMutex.Unlock()
@@ -141,39 +154,39 @@ func (fake *WinSyscall) GetProcessStartTimeCallCount() int {
 	return len(fake.getProcessStartTimeArgsForCall)
 }
 
-func (fake *WinSyscall) GetProcessStartTimeArgsForCall(i int) syscall.Handle {
+func (fake *WinSyscall) GetProcessStartTimeArgsForCall(i int) winsyscall.Handle {
 	fake.getProcessStartTimeMutex.RLock()
 	defer fake.getProcessStartTimeMutex.RUnlock()
 	return fake.getProcessStartTimeArgsForCall[i].arg1
 }
 
-func (fake *WinSyscall) GetProcessStartTimeReturns(result1 syscall.Filetime, result2 error) {
+func (fake *WinSyscall) GetProcessStartTimeReturns(result1 winsyscall.Filetime, result2 error) {
 	fake.GetProcessStartTimeStub = nil
 	fake.getProcessStartTimeReturns = struct {
-		result1 syscall.Filetime
+		result1 winsyscall.Filetime
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *WinSyscall) GetProcessStartTimeReturnsOnCall(i int, result1 syscall.Filetime, result2 error) {
+func (fake *WinSyscall) GetProcessStartTimeReturnsOnCall(i int, result1 winsyscall.Filetime, result2 error) {
 	fake.GetProcessStartTimeStub = nil
 	if fake.getProcessStartTimeReturnsOnCall == nil {
 		fake.getProcessStartTimeReturnsOnCall = make(map[int]struct {
-			result1 syscall.Filetime
+			result1 winsyscall.Filetime
 			result2 error
 		})
 	}
 	fake.getProcessStartTimeReturnsOnCall[i] = struct {
-		result1 syscall.Filetime
+		result1 winsyscall.Filetime
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *WinSyscall) CloseHandle(arg1 syscall.Handle) error {
+func (fake *WinSyscall) CloseHandle(arg1 winsyscall.Handle) error {
 	fake.closeHandleMutex.Lock()
 	ret, specificReturn := fake.closeHandleReturnsOnCall[len(fake.closeHandleArgsForCall)]
 	fake.closeHandleArgsForCall = append(fake.closeHandleArgsForCall, struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}{arg1})
 	fake.recordInvocation("CloseHandle", []interface{}{arg1})
 	fake.closeHandleMutex.Unlock()
@@ -192,7 +205,7 @@ func (fake *WinSyscall) CloseHandleCallCount() int {
 	return len(fake.closeHandleArgsForCall)
 }
 
-func (fake *WinSyscall) CloseHandleArgsForCall(i int) syscall.Handle {
+func (fake *WinSyscall) CloseHandleArgsForCall(i int) winsyscall.Handle {
 	fake.closeHandleMutex.RLock()
 	defer fake.closeHandleMutex.RUnlock()
 	return fake.closeHandleArgsForCall[i].arg1
@@ -217,11 +230,11 @@ func (fake *WinSyscall) CloseHandleReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *WinSyscall) GetExitCodeProcess(arg1 syscall.Handle) (uint32, error) {
+func (fake *WinSyscall) GetExitCodeProcess(arg1 winsyscall.Handle) (uint32, error) {
 	fake.getExitCodeProcessMutex.Lock()
 	ret, specificReturn := fake.getExitCodeProcessReturnsOnCall[len(fake.getExitCodeProcessArgsForCall)]
 	fake.getExitCodeProcessArgsForCall = append(fake.getExitCodeProcessArgsForCall, struct {
-		arg1 syscall.Handle
+		arg1 winsyscall.Handle
 	}{arg1})
 	fake.recordInvocation("GetExitCodeProcess", []interface{}{arg1})
 	fake.getExitCodeProcessMutex.Unlock()
@@ -240,7 +253,7 @@ func (fake *WinSyscall) GetExitCodeProcessCallCount() int {
 	return len(fake.getExitCodeProcessArgsForCall)
 }
 
-func (fake *WinSyscall) GetExitCodeProcessArgsForCall(i int) syscall.Handle {
+func (fake *WinSyscall) GetExitCodeProcessArgsForCall(i int) winsyscall.Handle {
 	fake.getExitCodeProcessMutex.RLock()
 	defer fake.getExitCodeProcessMutex.RUnlock()
 	return fake.getExitCodeProcessArgsForCall[i].arg1
@@ -268,6 +281,57 @@ func (fake *WinSyscall) GetExitCodeProcessReturnsOnCall(i int, result1 uint32, r
 	}{result1, result2}
 }
 
+func (fake *WinSyscall) GetProcessUser(arg1 winsyscall.Handle) (string, error) {
+	fake.getProcessUserMutex.Lock()
+	ret, specificReturn := fake.getProcessUserReturnsOnCall[len(fake.getProcessUserArgsForCall)]
+	fake.getProcessUserArgsForCall = append(fake.getProcessUserArgsForCall, struct {
+		arg1 winsyscall.Handle
+	}{arg1})
+	fake.recordInvocation("GetProcessUser", []interface{}{arg1})
+	fake.getProcessUserMutex.Unlock()
+	if fake.GetProcessUserStub != nil {
+		return fake.GetProcessUserStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getProcessUserReturns.result1, fake.getProcessUserReturns.result2
+}
+
+func (fake *WinSyscall) GetProcessUserCallCount() int {
+	fake.getProcessUserMutex.RLock()
+	defer fake.getProcessUserMutex.RUnlock()
+	return len(fake.getProcessUserArgsForCall)
+}
+
+func (fake *WinSyscall) GetProcessUserArgsForCall(i int) winsyscall.Handle {
+	fake.getProcessUserMutex.RLock()
+	defer fake.getProcessUserMutex.RUnlock()
+	return fake.getProcessUserArgsForCall[i].arg1
+}
+
+func (fake *WinSyscall) GetProcessUserReturns(result1 string, result2 error) {
+	fake.GetProcessUserStub = nil
+	fake.getProcessUserReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *WinSyscall) GetProcessUserReturnsOnCall(i int, result1 string, result2 error) {
+	fake.GetProcessUserStub = nil
+	if fake.getProcessUserReturnsOnCall == nil {
+		fake.getProcessUserReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getProcessUserReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *WinSyscall) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -279,6 +343,8 @@ func (fake *WinSyscall) Invocations() map[string][][]interface{} {
 	defer fake.closeHandleMutex.RUnlock()
 	fake.getExitCodeProcessMutex.RLock()
 	defer fake.getExitCodeProcessMutex.RUnlock()
+	fake.getProcessUserMutex.RLock()
+	defer fake.getProcessUserMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,12 @@
+package state
+
+import (
+	"path/filepath"
+	"time"
+)
+
+const lockRetryInterval = 10 * time.Millisecond
+
+func (m *Manager) lockPath() string {
+	return filepath.Join(m.rootDir, m.containerId+".lock")
+}
@@ -0,0 +1,98 @@
+// +build !windows
+
+package state
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+type lockHandle = *os.File
+
+// Lock takes an exclusive lock on the container, waiting up to the lock
+// timeout, or until the context of the manager is done, for another winc
+// holding it to finish. The lock file lives next to the state directory
+// rather than in it so that it outlives a delete.
+func (m *Manager) Lock() error {
+	if m.lock != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.rootDir, 0755); err != nil {
+		return err
+	}
+
+	var done <-chan struct{}
+	if m.ctx != nil {
+		done = m.ctx.Done()
+	}
+
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		f, err := os.OpenFile(m.lockPath(), os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+
+		err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			var removed bool
+			removed, err = lockFileRemoved(f, m.lockPath())
+			if err == nil && !removed {
+				m.lock = f
+				return nil
+			}
+		}
+		f.Close()
+
+		// a lock file removed by its holder before it was locked here is
+		// opened again
+		if err != nil && err != syscall.EWOULDBLOCK {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return &LockTimeoutError{Id: m.containerId, Timeout: m.lockTimeout}
+		}
+
+		select {
+		case <-done:
+			return m.ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// Unlock releases the lock taken by Lock. The lock file is removed once the
+// container has been deleted, before the lock is released.
+func (m *Manager) Unlock() error {
+	if m.lock == nil {
+		return nil
+	}
+
+	f := m.lock
+	m.lock = nil
+
+	if _, err := os.Stat(m.stateDir()); os.IsNotExist(err) {
+		os.Remove(m.lockPath())
+	}
+
+	return f.Close()
+}
+
+func lockFileRemoved(f *os.File, path string) (bool, error) {
+	locked, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	current, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return !os.SameFile(locked, current), nil
+}
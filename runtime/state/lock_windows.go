@@ -0,0 +1,87 @@
+package state
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+type lockHandle = windows.Handle
+
+// Lock takes an exclusive lock on the container, waiting up to the lock
+// timeout, or until the context of the manager is done, for another winc
+// holding it to finish. The lock file lives next to
+// the state directory rather than in it so that it outlives a delete.
+func (m *Manager) Lock() error {
+	if m.lock != 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.rootDir, 0755); err != nil {
+		return err
+	}
+
+	path, err := windows.UTF16PtrFromString(m.lockPath())
+	if err != nil {
+		return err
+	}
+
+	var done <-chan struct{}
+	if m.ctx != nil {
+		done = m.ctx.Done()
+	}
+
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		// the lock file is not opened for sharing delete, so it cannot be
+		// removed while anyone is waiting on it
+		h, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE, nil, windows.OPEN_ALWAYS, windows.FILE_ATTRIBUTE_NORMAL, 0)
+		if err == nil {
+			ol := windows.Overlapped{}
+			err = windows.LockFileEx(h, windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+			if err == nil {
+				m.lock = h
+				return nil
+			}
+			windows.CloseHandle(h)
+		}
+
+		if err != windows.ERROR_LOCK_VIOLATION && err != windows.ERROR_ACCESS_DENIED && err != windows.ERROR_SHARING_VIOLATION {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return &LockTimeoutError{Id: m.containerId, Timeout: m.lockTimeout}
+		}
+
+		select {
+		case <-done:
+			return m.ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// Unlock releases the lock taken by Lock. The lock file is removed once the
+// container has been deleted and nobody else is waiting on it.
+func (m *Manager) Unlock() error {
+	if m.lock == 0 {
+		return nil
+	}
+
+	h := m.lock
+	m.lock = 0
+
+	ol := windows.Overlapped{}
+	unlockErr := windows.UnlockFileEx(h, 0, 1, 0, &ol)
+	if err := windows.CloseHandle(h); err != nil && unlockErr == nil {
+		unlockErr = err
+	}
+
+	if _, err := os.Stat(m.stateDir()); os.IsNotExist(err) {
+		os.Remove(m.lockPath())
+	}
+
+	return unlockErr
+}
@@ -0,0 +1,178 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const processDir = "processes"
+
+// Process is the record winc keeps of a process started by winc exec until
+// its exit code has been collected. Exit is set once winc monitor has seen the
+// process exit.
+type Process struct {
+	ID            string              `json:"id"`
+	PID           int                 `json:"pid"`
+	StartTime     winsyscall.Filetime `json:"start_time"`
+	Created       time.Time           `json:"created"`
+	Args          []string            `json:"args"`
+	User          string              `json:"user,omitempty"`
+	Detach        bool                `json:"detach"`
+	ConsoleSocket string              `json:"console_socket,omitempty"`
+	Exit          *ExitStatus         `json:"exit,omitempty"`
+}
+
+// AddProcess records the process proc, started by winc exec from spec, under
+// the exec id id. consoleSocket is the socket its console is relayed over, if
+// it has one.
+func (m *Manager) AddProcess(id string, proc hcs.Process, spec *specs.Process, detach bool, consoleSocket string) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(m.processFile(id)); err == nil {
+		return &ProcessExistsError{Id: m.containerId, ExecId: id}
+	}
+
+	record := Process{
+		ID:            id,
+		PID:           proc.Pid(),
+		Created:       time.Now().UTC(),
+		Args:          spec.Args,
+		User:          spec.User.Username,
+		Detach:        detach,
+		ConsoleSocket: consoleSocket,
+	}
+
+	// the pid of a process in a Hyper-V container belongs to the utility VM, so
+	// it cannot be opened on the host
+	if !state.HyperV {
+		h, err := m.sc.OpenProcess(winsyscall.PROCESS_QUERY_INFORMATION, false, uint32(record.PID))
+		switch {
+		case err == nil:
+			defer m.sc.CloseHandle(h)
+
+			record.StartTime, err = m.sc.GetProcessStartTime(h)
+			if err != nil {
+				return fmt.Errorf("GetProcessStartTime: %s", err.Error())
+			}
+		case processGone(err):
+			// a short-lived process may already have exited, which leaves a
+			// completed record rather than a failed exec
+			record.Exit = m.exitStatus(proc)
+		default:
+			return fmt.Errorf("OpenProcess: %s", err.Error())
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(m.stateDir(), processDir), 0755); err != nil {
+		return err
+	}
+
+	return writeJSON(m.processFile(id), record)
+}
+
+// exitStatus returns how proc exited, or nil if HCS cannot tell.
+func (m *Manager) exitStatus(proc hcs.Process) *ExitStatus {
+	exitCode, err := proc.ExitCode()
+	if err != nil {
+		m.logger.WithField("error", err).Warn("failed to get the exit code of an exited process")
+		return nil
+	}
+
+	return &ExitStatus{Code: exitCode, Time: time.Now().UTC()}
+}
+
+// Process returns the record of the process with exec id id.
+func (m *Manager) Process(id string) (*Process, error) {
+	var record Process
+	if err := readJSON(m.processFile(id), &record); err != nil {
+		if os.IsNotExist(err) {
+			return nil, &ProcessNotFoundError{Id: m.containerId, ExecId: id}
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Processes returns the records of every process started by winc exec whose
+// exit code has not been collected, ordered by exec id.
+func (m *Manager) Processes() ([]Process, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(m.stateDir(), processDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []Process
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		record, err := m.Process(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+
+	return records, nil
+}
+
+// SetProcessExited records the exit code of the process with exec id id along
+// with the current time.
+func (m *Manager) SetProcessExited(id string, exitCode int) error {
+	record, err := m.Process(id)
+	if err != nil {
+		return err
+	}
+
+	record.Exit = &ExitStatus{Code: exitCode, Time: time.Now().UTC()}
+	return writeJSON(m.processFile(id), record)
+}
+
+// ProcessStatus reports whether the process of record is "running" or
+// "stopped".
+func (m *Manager) ProcessStatus(record *Process) (string, error) {
+	if record.Exit != nil {
+		return "stopped", nil
+	}
+
+	state, err := m.loadState()
+	if err != nil {
+		return "", err
+	}
+
+	if state.HyperV {
+		return m.hyperVProcessStatus(record.PID)
+	}
+
+	return m.processStatus(record.PID, record.StartTime)
+}
+
+// DeleteProcess removes the record of the process with exec id id once its
+// exit code has been collected.
+func (m *Manager) DeleteProcess(id string) error {
+	err := os.Remove(m.processFile(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) processFile(id string) string {
+	return filepath.Join(m.stateDir(), processDir, id+".json")
+}
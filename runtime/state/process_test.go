@@ -0,0 +1,325 @@
+package state_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/state/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Processes", func() {
+	const (
+		containerId = "some-container"
+		bundlePath  = "some/path/some-container"
+	)
+
+	var (
+		hcsClient   *fakes.HCSClient
+		sc          *fakes.WinSyscall
+		sm          *state.Manager
+		rootDir     string
+		proc        *hcsfakes.Process
+		processSpec *specs.Process
+		startTime   winsyscall.Filetime
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = ioutil.TempDir("", "processes.root")
+		Expect(err).ToNot(HaveOccurred())
+
+		hcsClient = &fakes.HCSClient{}
+		sc = &fakes.WinSyscall{}
+		logger := (&logrus.Logger{
+			Out: ioutil.Discard,
+		}).WithField("test", "processes")
+
+		sm = state.New(logger, hcsClient, sc, containerId, rootDir, time.Second, "1.2.3")
+		Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+		proc = &hcsfakes.Process{}
+		proc.PidReturns(888)
+		processSpec = &specs.Process{
+			Args: []string{"cmd.exe", "/c", "ver"},
+			User: specs.User{Username: "vcap"},
+		}
+
+		startTime = winsyscall.Filetime{HighDateTime: 444, LowDateTime: 555}
+		sc.OpenProcessReturns(winsyscall.Handle(0xbeef), nil)
+		sc.GetProcessStartTimeReturns(startTime, nil)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	Describe("AddProcess", func() {
+		It("records the process under <rootDir>/<containerId>/processes/<execId>.json", func() {
+			before := time.Now().UTC()
+			Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+			Expect(filepath.Join(rootDir, containerId, "processes", "some-exec.json")).To(BeAnExistingFile())
+
+			record, err := sm.Process("some-exec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.ID).To(Equal("some-exec"))
+			Expect(record.PID).To(Equal(888))
+			Expect(record.StartTime).To(Equal(startTime))
+			Expect(record.Created).To(BeTemporally(">=", before))
+			Expect(record.Args).To(Equal([]string{"cmd.exe", "/c", "ver"}))
+			Expect(record.User).To(Equal("vcap"))
+			Expect(record.Detach).To(BeTrue())
+
+			_, _, openedPid := sc.OpenProcessArgsForCall(0)
+			Expect(openedPid).To(Equal(uint32(888)))
+			Expect(sc.CloseHandleArgsForCall(0)).To(Equal(winsyscall.Handle(0xbeef)))
+		})
+
+		It("records the console socket of the process", func() {
+			Expect(sm.AddProcess("some-exec", proc, processSpec, true, "C:\\console.sock")).To(Succeed())
+
+			record, err := sm.Process("some-exec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.ConsoleSocket).To(Equal("C:\\console.sock"))
+		})
+
+		Context("a process is already recorded under the exec id", func() {
+			BeforeEach(func() {
+				Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				err := sm.AddProcess("some-exec", proc, processSpec, false, "")
+				Expect(err).To(Equal(&state.ProcessExistsError{Id: containerId, ExecId: "some-exec"}))
+			})
+		})
+
+		Context("the container is hyperv isolated", func() {
+			BeforeEach(func() {
+				Expect(sm.SetHyperV()).To(Succeed())
+			})
+
+			It("records the process without opening it", func() {
+				Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+
+				record, err := sm.Process("some-exec")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(record.PID).To(Equal(888))
+				Expect(record.StartTime).To(Equal(winsyscall.Filetime{}))
+			})
+		})
+
+		Context("the process has already exited", func() {
+			BeforeEach(func() {
+				sc.OpenProcessReturns(0, syscall.Errno(0x57))
+				proc.ExitCodeReturns(4, nil)
+			})
+
+			It("records the process as exited", func() {
+				Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+
+				record, err := sm.Process("some-exec")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(record.PID).To(Equal(888))
+				Expect(record.StartTime).To(Equal(winsyscall.Filetime{}))
+				Expect(record.Exit.Code).To(Equal(4))
+
+				status, err := sm.ProcessStatus(record)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal("stopped"))
+			})
+
+			Context("its exit code cannot be read", func() {
+				BeforeEach(func() {
+					proc.ExitCodeReturns(-1, errors.New("couldn't get exit code"))
+				})
+
+				It("records the process without an exit code", func() {
+					Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+
+					record, err := sm.Process("some-exec")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(record.Exit).To(BeNil())
+				})
+			})
+		})
+
+		Context("opening the process fails", func() {
+			BeforeEach(func() {
+				sc.OpenProcessReturns(0, errors.New("access denied"))
+			})
+
+			It("returns an error without recording the process", func() {
+				err := sm.AddProcess("some-exec", proc, processSpec, true, "")
+				Expect(err).To(MatchError("OpenProcess: access denied"))
+
+				_, err = sm.Process("some-exec")
+				Expect(err).To(BeAssignableToTypeOf(&state.ProcessNotFoundError{}))
+			})
+		})
+
+		Context("getting the start time of the process fails", func() {
+			BeforeEach(func() {
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{}, errors.New("couldn't get start time"))
+			})
+
+			It("returns an error without recording the process", func() {
+				err := sm.AddProcess("some-exec", proc, processSpec, true, "")
+				Expect(err).To(MatchError("GetProcessStartTime: couldn't get start time"))
+
+				_, err = sm.Process("some-exec")
+				Expect(err).To(BeAssignableToTypeOf(&state.ProcessNotFoundError{}))
+			})
+		})
+	})
+
+	Describe("Process", func() {
+		Context("no process is recorded under the exec id", func() {
+			It("returns a ProcessNotFoundError", func() {
+				_, err := sm.Process("some-exec")
+				Expect(err).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"}))
+			})
+		})
+	})
+
+	Describe("Processes", func() {
+		It("returns every recorded process ordered by exec id", func() {
+			Expect(sm.AddProcess("b-exec", proc, processSpec, true, "")).To(Succeed())
+			Expect(sm.AddProcess("a-exec", proc, processSpec, false, "")).To(Succeed())
+
+			records, err := sm.Processes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[0].ID).To(Equal("a-exec"))
+			Expect(records[1].ID).To(Equal("b-exec"))
+		})
+
+		It("returns nothing when no process has been recorded", func() {
+			records, err := sm.Processes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+
+	Describe("ProcessStatus", func() {
+		var record *state.Process
+
+		BeforeEach(func() {
+			Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+
+			var err error
+			record, err = sm.Process("some-exec")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports a process that is still running as running", func() {
+			sc.GetExitCodeProcessReturns(state.STILL_ACTIVE_EXIT_CODE, nil)
+
+			status, err := sm.ProcessStatus(record)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal("running"))
+		})
+
+		It("reports a process that has exited as stopped", func() {
+			sc.GetExitCodeProcessReturns(0, nil)
+
+			status, err := sm.ProcessStatus(record)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal("stopped"))
+		})
+
+		It("reports a process whose pid has been reused as stopped", func() {
+			sc.GetExitCodeProcessReturns(state.STILL_ACTIVE_EXIT_CODE, nil)
+			sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 1, LowDateTime: 2}, nil)
+
+			status, err := sm.ProcessStatus(record)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal("stopped"))
+		})
+
+		It("reports a process whose exit code has been recorded as stopped", func() {
+			Expect(sm.SetProcessExited("some-exec", 3)).To(Succeed())
+			record, err := sm.Process("some-exec")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, err := sm.ProcessStatus(record)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal("stopped"))
+			Expect(sc.GetExitCodeProcessCallCount()).To(Equal(0))
+		})
+
+		Context("the container is hyperv isolated", func() {
+			var fakeContainer *hcsfakes.Container
+
+			BeforeEach(func() {
+				Expect(sm.SetHyperV()).To(Succeed())
+
+				fakeContainer = &hcsfakes.Container{}
+				hcsClient.OpenContainerReturns(fakeContainer, nil)
+				fakeContainer.ProcessListReturns([]hcs.ProcessListItem{{ProcessId: 888}}, nil)
+			})
+
+			It("reports whether the process is in the process list of the container", func() {
+				status, err := sm.ProcessStatus(record)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal("running"))
+
+				fakeContainer.ProcessListReturns(nil, nil)
+				status, err = sm.ProcessStatus(record)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal("stopped"))
+			})
+		})
+	})
+
+	Describe("SetProcessExited", func() {
+		It("records the exit code of the process", func() {
+			Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+			before := time.Now().UTC()
+			Expect(sm.SetProcessExited("some-exec", 3)).To(Succeed())
+
+			record, err := sm.Process("some-exec")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.PID).To(Equal(888))
+			Expect(record.Exit.Code).To(Equal(3))
+			Expect(record.Exit.Time).To(BeTemporally(">=", before))
+		})
+
+		Context("no process is recorded under the exec id", func() {
+			It("returns a ProcessNotFoundError", func() {
+				err := sm.SetProcessExited("some-exec", 3)
+				Expect(err).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"}))
+			})
+		})
+	})
+
+	Describe("DeleteProcess", func() {
+		BeforeEach(func() {
+			Expect(sm.AddProcess("some-exec", proc, processSpec, true, "")).To(Succeed())
+		})
+
+		It("removes the record of the process", func() {
+			Expect(sm.DeleteProcess("some-exec")).To(Succeed())
+
+			_, err := sm.Process("some-exec")
+			Expect(err).To(BeAssignableToTypeOf(&state.ProcessNotFoundError{}))
+		})
+
+		It("succeeds when the record has already been removed", func() {
+			Expect(sm.DeleteProcess("some-exec")).To(Succeed())
+			Expect(sm.DeleteProcess("some-exec")).To(Succeed())
+		})
+	})
+})
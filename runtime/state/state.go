@@ -1,15 +1,18 @@
 package state
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"syscall"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
-	"github.com/Microsoft/hcsshim"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
@@ -17,50 +20,122 @@ import (
 const stateFile = "state.json"
 const STILL_ACTIVE_EXIT_CODE = uint32(259)
 
+// Annotations used by State to report the resource limits in effect for the
+// container.
+const (
+	MemoryLimitAnnotation = "winc.resources.memory.limit"
+	CPUCountAnnotation    = "winc.resources.cpu.count"
+	CPUSharesAnnotation   = "winc.resources.cpu.shares"
+	CPUMaximumAnnotation  = "winc.resources.cpu.maximum"
+	StorageIopsAnnotation = "winc.resources.storage.iops"
+	StorageBpsAnnotation  = "winc.resources.storage.bps"
+	SandboxSizeAnnotation = "winc.resources.storage.sandboxSize"
+)
+
+// Annotations used by State to report how the init process exited, once winc
+// has recorded it.
+const (
+	ExitCodeAnnotation = "winc.exit.code"
+	ExitTimeAnnotation = "winc.exit.time"
+)
+
+// Annotations used by State to report when and by which version of winc the
+// container was created, and the container it shares its network with.
+const (
+	CreatedAnnotation = "winc.created"
+	OwnerAnnotation   = "winc.owner"
+	VersionAnnotation = "winc.version"
+)
+
 type Manager struct {
 	logger      *logrus.Entry
 	hcsClient   HCSClient
 	sc          WinSyscall
 	containerId string
 	rootDir     string
+	lockTimeout time.Duration
+	version     string
+	lock        lockHandle
+	ctx         context.Context
 }
 
 type State struct {
-	Bundle     string           `json:"bundle"`
-	PID        int              `json:"pid"`
-	StartTime  syscall.Filetime `json:"start_time"`
-	ExecFailed bool             `json:"exec_failed"`
+	Bundle        string                  `json:"bundle"`
+	PID           int                     `json:"pid"`
+	StartTime     winsyscall.Filetime     `json:"start_time"`
+	ExecFailed    bool                    `json:"exec_failed"`
+	Resources     *specs.WindowsResources `json:"resources,omitempty"`
+	ConsoleSocket string                  `json:"console_socket,omitempty"`
+	Exit          *ExitStatus             `json:"exit,omitempty"`
+	HyperV        bool                    `json:"hyperv,omitempty"`
+	Annotations   map[string]string       `json:"annotations,omitempty"`
+	Created       time.Time               `json:"created"`
+	Owner         string                  `json:"owner,omitempty"`
+	WincVersion   string                  `json:"winc_version,omitempty"`
+}
+
+// ExitStatus is the exit code of the init process and the time winc saw it
+// exit.
+type ExitStatus struct {
+	Code int       `json:"code"`
+	Time time.Time `json:"time"`
 }
 
 //go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
 type HCSClient interface {
-	GetContainerProperties(string) (hcsshim.ContainerProperties, error)
+	GetContainerProperties(string) (hcs.ContainerProperties, error)
+	OpenContainer(string) (hcs.Container, error)
 }
 
 //go:generate counterfeiter -o fakes/winsyscall.go --fake-name WinSyscall . WinSyscall
 type WinSyscall interface {
-	OpenProcess(uint32, bool, uint32) (syscall.Handle, error)
-	GetProcessStartTime(syscall.Handle) (syscall.Filetime, error)
-	CloseHandle(syscall.Handle) error
-	GetExitCodeProcess(syscall.Handle) (uint32, error)
+	OpenProcess(uint32, bool, uint32) (winsyscall.Handle, error)
+	GetProcessStartTime(winsyscall.Handle) (winsyscall.Filetime, error)
+	CloseHandle(winsyscall.Handle) error
+	GetExitCodeProcess(winsyscall.Handle) (uint32, error)
+	GetProcessUser(winsyscall.Handle) (string, error)
 }
 
-func New(logger *logrus.Entry, hcsClient HCSClient, winSyscall WinSyscall, id, rootDir string) *Manager {
+func New(logger *logrus.Entry, hcsClient HCSClient, winSyscall WinSyscall, id, rootDir string, lockTimeout time.Duration, version string) *Manager {
 	return &Manager{
 		logger:      logger,
 		hcsClient:   hcsClient,
 		sc:          winSyscall,
 		containerId: id,
 		rootDir:     rootDir,
+		lockTimeout: lockTimeout,
+		version:     version,
 	}
 }
 
-func (m *Manager) Initialize(bundlePath string) error {
+// WithContext returns the manager with Lock giving up waiting for the lock
+// once ctx is done.
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	m.ctx = ctx
+	return m
+}
+
+// Initialize creates the state of the container, recording the annotations
+// of its spec, the time it was created, its owner and the version of winc
+// that created it.
+func (m *Manager) Initialize(bundlePath string, spec *specs.Spec) error {
 	if err := os.MkdirAll(m.stateDir(), 0755); err != nil {
 		return err
 	}
 
-	state := State{Bundle: bundlePath}
+	state := State{
+		Bundle:      bundlePath,
+		Created:     time.Now().UTC(),
+		WincVersion: m.version,
+	}
+	if spec != nil {
+		if len(spec.Annotations) > 0 {
+			state.Annotations = spec.Annotations
+		}
+		if spec.Windows != nil && spec.Windows.Network != nil {
+			state.Owner = spec.Windows.Network.NetworkSharedContainerName
+		}
+	}
 	return m.writeState(state)
 }
 
@@ -75,7 +150,7 @@ func (m *Manager) SetFailure() error {
 	}
 
 	state.PID = 0
-	state.StartTime = syscall.Filetime{}
+	state.StartTime = winsyscall.Filetime{}
 	state.ExecFailed = true
 	return m.writeState(state)
 }
@@ -93,12 +168,19 @@ func (m *Manager) SetSuccess(proc hcs.Process) error {
 	// https://blogs.msdn.microsoft.com/oldnewthing/20110107-00/?p=11803
 
 	state.PID = proc.Pid()
+
+	// the pid of a process in a Hyper-V container belongs to the utility VM, so
+	// it cannot be opened on the host
+	if state.HyperV {
+		return m.writeState(state)
+	}
+
 	/* Uncomment the following line under test conditions to make sure hcsshim/Windows
 	* is not closing the handle to the already finished process.
 	 */
 	// time.Sleep(10 * time.Second)
 
-	h, err := m.sc.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
+	h, err := m.sc.OpenProcess(winsyscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
 	if err != nil {
 		retErr := fmt.Errorf("OpenProcess: %s", err.Error())
 		m.logger.Error(retErr)
@@ -138,6 +220,8 @@ func (m *Manager) State() (*specs.State, error) {
 	var status string
 	if cp.Stopped {
 		status = "stopped"
+	} else if cp.State == hcs.PausedState {
+		status = "paused"
 	} else {
 		status, err = m.userProgramStatus(state)
 		if err != nil {
@@ -147,16 +231,169 @@ func (m *Manager) State() (*specs.State, error) {
 	}
 
 	return &specs.State{
-		Version: specs.Version,
-		ID:      m.containerId,
-		Status:  status,
-		Bundle:  state.Bundle,
-		Pid:     state.PID,
+		Version:     specs.Version,
+		ID:          m.containerId,
+		Status:      status,
+		Bundle:      state.Bundle,
+		Pid:         state.PID,
+		Annotations: stateAnnotations(state),
 	}, nil
 }
 
+// SetResources records the resource limits in effect for the container. Only
+// the fields set in resources are changed; the rest keep their recorded value.
+func (m *Manager) SetResources(resources *specs.WindowsResources) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	if state.Resources == nil {
+		state.Resources = &specs.WindowsResources{}
+	}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil {
+		state.Resources.Memory = &specs.WindowsMemoryResources{Limit: resources.Memory.Limit}
+	}
+
+	if resources.CPU != nil {
+		if state.Resources.CPU == nil {
+			state.Resources.CPU = &specs.WindowsCPUResources{}
+		}
+		if resources.CPU.Count != nil {
+			state.Resources.CPU.Count = resources.CPU.Count
+		}
+		if resources.CPU.Shares != nil {
+			state.Resources.CPU.Shares = resources.CPU.Shares
+		}
+		if resources.CPU.Maximum != nil {
+			state.Resources.CPU.Maximum = resources.CPU.Maximum
+		}
+	}
+
+	if resources.Storage != nil {
+		if state.Resources.Storage == nil {
+			state.Resources.Storage = &specs.WindowsStorageResources{}
+		}
+		if resources.Storage.Iops != nil {
+			state.Resources.Storage.Iops = resources.Storage.Iops
+		}
+		if resources.Storage.Bps != nil {
+			state.Resources.Storage.Bps = resources.Storage.Bps
+		}
+		if resources.Storage.SandboxSize != nil {
+			state.Resources.Storage.SandboxSize = resources.Storage.SandboxSize
+		}
+	}
+
+	return m.writeState(state)
+}
+
+// Resources returns the resource limits recorded for the container, or nil if
+// none have been recorded.
+func (m *Manager) Resources() (*specs.WindowsResources, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Resources, nil
+}
+
+// SetExited records the exit code of the init process along with the current
+// time.
+func (m *Manager) SetExited(exitCode int) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.Exit = &ExitStatus{Code: exitCode, Time: time.Now().UTC()}
+	return m.writeState(state)
+}
+
+// ExitStatus returns how the init process exited, or nil if winc has not
+// recorded it.
+func (m *Manager) ExitStatus() (*ExitStatus, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Exit, nil
+}
+
+// SetHyperV records that the container is Hyper-V isolated.
+func (m *Manager) SetHyperV() error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.HyperV = true
+	return m.writeState(state)
+}
+
+// HyperV reports whether the container is Hyper-V isolated, in which case the
+// pid of its init process belongs to the utility VM rather than the host.
+func (m *Manager) HyperV() (bool, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return false, err
+	}
+
+	return state.HyperV, nil
+}
+
+// SetConsoleSocket records the socket that winc start hands the console of
+// the init process to.
+func (m *Manager) SetConsoleSocket(consoleSocket string) error {
+	state, err := m.loadState()
+	if err != nil {
+		return err
+	}
+
+	state.ConsoleSocket = consoleSocket
+	return m.writeState(state)
+}
+
+// ConsoleSocket returns the console socket recorded for the container, or ""
+// if the init process does not use a terminal.
+func (m *Manager) ConsoleSocket() (string, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return "", err
+	}
+
+	return state.ConsoleSocket, nil
+}
+
+func (m *Manager) ProcessUser(pid int) (string, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return "", err
+	}
+
+	if state.HyperV {
+		return "", nil
+	}
+
+	h, err := m.sc.OpenProcess(winsyscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", fmt.Errorf("OpenProcess: %s", err.Error())
+	}
+	defer m.sc.CloseHandle(h)
+
+	user, err := m.sc.GetProcessUser(h)
+	if err != nil {
+		return "", fmt.Errorf("GetProcessUser: %s", err.Error())
+	}
+
+	return user, nil
+}
+
 func (m *Manager) userProgramStatus(state State) (string, error) {
-	if state.ExecFailed {
+	if state.ExecFailed || state.Exit != nil {
 		return "stopped", nil
 	}
 
@@ -164,17 +401,24 @@ func (m *Manager) userProgramStatus(state State) (string, error) {
 		return "", fmt.Errorf("invalid state: PID %d, start time %+v", state.PID, state.StartTime)
 	}
 
-	if (state.PID == 0) && (state.StartTime == syscall.Filetime{}) {
+	if (state.PID == 0) && (state.StartTime == winsyscall.Filetime{}) {
 		return "created", nil
 	}
 
-	h, err := m.sc.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(state.PID))
+	if state.HyperV {
+		return m.hyperVProcessStatus(state.PID)
+	}
+
+	return m.processStatus(state.PID, state.StartTime)
+}
+
+// processStatus reports whether the process with the given pid is still the
+// one that was started at startTime.
+func (m *Manager) processStatus(pid int, startTime winsyscall.Filetime) (string, error) {
+	h, err := m.sc.OpenProcess(winsyscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
 	if err != nil {
-		if errno, ok := err.(syscall.Errno); ok {
-			// 0x57 is ERROR_INVALID_PARAMETER, which is returned if the process doesn't exist
-			if errno == 0x57 {
-				return "stopped", nil
-			}
+		if processGone(err) {
+			return "stopped", nil
 		}
 		return "", fmt.Errorf("OpenProcess: %s", err.Error())
 	}
@@ -194,16 +438,116 @@ func (m *Manager) userProgramStatus(state State) (string, error) {
 		return "", fmt.Errorf("GetProcessStartTime: %s", err.Error())
 	}
 
-	if creationTime == state.StartTime {
+	if creationTime == startTime {
 		return "running", err
 	}
 
 	return "stopped", nil
 }
 
+// processGone reports whether OpenProcess failed because the process does not
+// exist any more.
+func processGone(err error) bool {
+	// 0x57 is ERROR_INVALID_PARAMETER, which is returned if the process doesn't exist
+	errno, ok := err.(syscall.Errno)
+	return ok && errno == 0x57
+}
+
+// hyperVProcessStatus reports whether a process in a Hyper-V container is
+// still in its process list, as its pid cannot be opened on the host.
+func (m *Manager) hyperVProcessStatus(pid int) (string, error) {
+	container, err := m.hcsClient.OpenContainer(m.containerId)
+	if err != nil {
+		return "", err
+	}
+	defer container.Close()
+
+	processes, err := container.ProcessList()
+	if err != nil {
+		return "", fmt.Errorf("ProcessList: %s", err.Error())
+	}
+
+	for _, p := range processes {
+		if int(p.ProcessId) == pid {
+			return "running", nil
+		}
+	}
+
+	return "stopped", nil
+}
+
+// stateAnnotations returns the annotations of the spec of the container
+// together with the ones winc reports, which take precedence.
+func stateAnnotations(state State) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range state.Annotations {
+		annotations[k] = v
+	}
+	for k, v := range resourceAnnotations(state.Resources) {
+		annotations[k] = v
+	}
+
+	if !state.Created.IsZero() {
+		annotations[CreatedAnnotation] = state.Created.Format(time.RFC3339Nano)
+	}
+	if state.Owner != "" {
+		annotations[OwnerAnnotation] = state.Owner
+	}
+	if state.WincVersion != "" {
+		annotations[VersionAnnotation] = state.WincVersion
+	}
+
+	if state.Exit != nil {
+		annotations[ExitCodeAnnotation] = strconv.Itoa(state.Exit.Code)
+		annotations[ExitTimeAnnotation] = state.Exit.Time.Format(time.RFC3339Nano)
+	}
+
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+func resourceAnnotations(resources *specs.WindowsResources) map[string]string {
+	if resources == nil {
+		return nil
+	}
+
+	annotations := map[string]string{}
+	set := func(key string, value *uint64) {
+		if value != nil {
+			annotations[key] = strconv.FormatUint(*value, 10)
+		}
+	}
+	set16 := func(key string, value *uint16) {
+		if value != nil {
+			annotations[key] = strconv.FormatUint(uint64(*value), 10)
+		}
+	}
+
+	if resources.Memory != nil {
+		set(MemoryLimitAnnotation, resources.Memory.Limit)
+	}
+	if resources.CPU != nil {
+		set(CPUCountAnnotation, resources.CPU.Count)
+		set16(CPUSharesAnnotation, resources.CPU.Shares)
+		set16(CPUMaximumAnnotation, resources.CPU.Maximum)
+	}
+	if resources.Storage != nil {
+		set(StorageIopsAnnotation, resources.Storage.Iops)
+		set(StorageBpsAnnotation, resources.Storage.Bps)
+		set(SandboxSizeAnnotation, resources.Storage.SandboxSize)
+	}
+
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
 func stateValid(state State) bool {
-	return (state.PID == 0 && state.StartTime == syscall.Filetime{}) ||
-		(state.PID != 0 && state.StartTime != syscall.Filetime{})
+	return (state.PID == 0 && state.StartTime == winsyscall.Filetime{}) ||
+		(state.PID != 0 && (state.StartTime != winsyscall.Filetime{} || state.HyperV))
 }
 
 func (m *Manager) stateDir() string {
@@ -212,23 +556,64 @@ func (m *Manager) stateDir() string {
 
 func (m *Manager) loadState() (State, error) {
 	logrus.Debugf("load state")
-	contents, err := ioutil.ReadFile(filepath.Join(m.stateDir(), stateFile))
-	if err != nil {
-		return State{}, err
-	}
 	var state State
-	if err := json.Unmarshal(contents, &state); err != nil {
+	if err := readJSON(filepath.Join(m.stateDir(), stateFile), &state); err != nil {
 		return State{}, err
 	}
 	logrus.Debugf("state for bundle: %s", state.Bundle)
 	return state, nil
 }
 
+func readJSON(path string, v interface{}) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contents, v)
+}
+
 func (m *Manager) writeState(state State) error {
-	contents, err := json.Marshal(state)
+	return writeJSON(filepath.Join(m.stateDir(), stateFile), state)
+}
+
+// writeJSON writes v to a temporary file and renames it over path so that a
+// reader never sees a partially written file.
+func writeJSON(path string, v interface{}) error {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
 
-	return ioutil.WriteFile(filepath.Join(m.stateDir(), stateFile), contents, 0644)
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return renameWithRetry(tmp.Name(), path)
+}
+
+// renameWithRetry renames src over dst, retrying while a reader has dst open
+// as Windows does not allow replacing a file that is open.
+func renameWithRetry(src, dst string) error {
+	var err error
+	for i := 0; i < 100; i++ {
+		if err = os.Rename(src, dst); err == nil || !os.IsPermission(err) {
+			return err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+	return err
 }
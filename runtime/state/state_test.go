@@ -1,17 +1,21 @@
 package state_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"code.cloudfoundry.org/winc/hcs"
 	hcsfakes "code.cloudfoundry.org/winc/hcs/fakes"
 	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/state/fakes"
-	"github.com/Microsoft/hcsshim"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -44,7 +48,7 @@ var _ = Describe("State", func() {
 			Out: ioutil.Discard,
 		}).WithField("test", "state")
 
-		sm = state.New(logger, hcsClient, sc, containerId, rootDir)
+		sm = state.New(logger, hcsClient, sc, containerId, rootDir, time.Second, "1.2.3")
 	})
 
 	AfterEach(func() {
@@ -53,7 +57,7 @@ var _ = Describe("State", func() {
 
 	Describe("Initialize", func() {
 		It("writes the bundle path to state.json in <rootDir>/<containerId>/", func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
 
 			var state state.State
 			contents, err := ioutil.ReadFile(stateFile)
@@ -62,14 +66,69 @@ var _ = Describe("State", func() {
 
 			Expect(state.Bundle).To(Equal(bundlePath))
 			Expect(state.PID).To(Equal(0))
-			Expect(state.StartTime).To(Equal(syscall.Filetime{}))
+			Expect(state.StartTime).To(Equal(winsyscall.Filetime{}))
 			Expect(state.ExecFailed).To(Equal(false))
 		})
+
+		It("records when the container was created and the version of winc", func() {
+			before := time.Now().UTC()
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+			var state state.State
+			contents, err := ioutil.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &state)).To(Succeed())
+
+			Expect(state.Created).To(BeTemporally(">=", before))
+			Expect(state.Created).To(BeTemporally("<=", time.Now().UTC()))
+			Expect(state.WincVersion).To(Equal("1.2.3"))
+		})
+
+		It("records the annotations and owner from the spec", func() {
+			spec := &specs.Spec{
+				Annotations: map[string]string{"app-guid": "some-app-guid"},
+				Windows: &specs.Windows{
+					Network: &specs.WindowsNetwork{NetworkSharedContainerName: "owner-container"},
+				},
+			}
+			Expect(sm.Initialize(bundlePath, spec)).To(Succeed())
+
+			var state state.State
+			contents, err := ioutil.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &state)).To(Succeed())
+
+			Expect(state.Annotations).To(Equal(map[string]string{"app-guid": "some-app-guid"}))
+			Expect(state.Owner).To(Equal("owner-container"))
+		})
+
+		It("reports the annotations and creation metadata in the oci state", func() {
+			spec := &specs.Spec{
+				Annotations: map[string]string{
+					"app-guid":              "some-app-guid",
+					state.VersionAnnotation: "spoofed",
+				},
+				Windows: &specs.Windows{
+					Network: &specs.WindowsNetwork{NetworkSharedContainerName: "owner-container"},
+				},
+			}
+			Expect(sm.Initialize(bundlePath, spec)).To(Succeed())
+
+			ociState, err := sm.State()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState.Annotations).To(HaveKeyWithValue("app-guid", "some-app-guid"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.OwnerAnnotation, "owner-container"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.VersionAnnotation, "1.2.3"))
+
+			created, err := time.Parse(time.RFC3339Nano, ociState.Annotations[state.CreatedAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeTemporally("~", time.Now(), time.Minute))
+		})
 	})
 
 	Describe("Delete", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -82,7 +141,7 @@ var _ = Describe("State", func() {
 
 	Describe("SetFailure", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -96,7 +155,7 @@ var _ = Describe("State", func() {
 
 			Expect(state.Bundle).To(Equal(bundlePath))
 			Expect(state.PID).To(Equal(0))
-			Expect(state.StartTime).To(Equal(syscall.Filetime{}))
+			Expect(state.StartTime).To(Equal(winsyscall.Filetime{}))
 			Expect(state.ExecFailed).To(Equal(true))
 		})
 	})
@@ -104,18 +163,18 @@ var _ = Describe("State", func() {
 	Describe("SetSuccess", func() {
 		var (
 			proc *hcsfakes.Process
-			ph   syscall.Handle
+			ph   winsyscall.Handle
 		)
 
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 
 			proc = &hcsfakes.Process{}
 			proc.PidReturns(888)
 			ph = 0xbeef
 			sc.OpenProcessReturns(ph, nil)
-			sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 444, LowDateTime: 555}, nil)
+			sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 444, LowDateTime: 555}, nil)
 		})
 
 		It("sets the pid + start time in the state.json", func() {
@@ -128,11 +187,11 @@ var _ = Describe("State", func() {
 
 			Expect(state.Bundle).To(Equal(bundlePath))
 			Expect(state.PID).To(Equal(888))
-			Expect(state.StartTime).To(Equal(syscall.Filetime{HighDateTime: 444, LowDateTime: 555}))
+			Expect(state.StartTime).To(Equal(winsyscall.Filetime{HighDateTime: 444, LowDateTime: 555}))
 			Expect(state.ExecFailed).To(Equal(false))
 
 			flags, inherit, pid := sc.OpenProcessArgsForCall(0)
-			Expect(flags).To(Equal(uint32(syscall.PROCESS_QUERY_INFORMATION)))
+			Expect(flags).To(Equal(uint32(winsyscall.PROCESS_QUERY_INFORMATION)))
 			Expect(inherit).To(Equal(false))
 			Expect(pid).To(Equal(uint32(888)))
 
@@ -159,21 +218,21 @@ var _ = Describe("State", func() {
 
 				Expect(state.Bundle).To(Equal(bundlePath))
 				Expect(state.PID).To(Equal(888))
-				Expect(state.StartTime).To(Equal(syscall.Filetime{}))
+				Expect(state.StartTime).To(Equal(winsyscall.Filetime{}))
 				Expect(state.ExecFailed).To(Equal(true))
 			})
 
 			It("wraps the error", func() {
 				err := sm.SetSuccess(proc)
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("OpenProcess: Access is denied."))
+				Expect(err.Error()).To(Equal("OpenProcess: " + syscall.Errno(0x5).Error()))
 			})
 		})
 
 		Context("GetProcessStartTime fails", func() {
 			BeforeEach(func() {
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{}, syscall.Errno(0x6))
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{}, syscall.Errno(0x6))
 			})
 
 			It("sets exec failed in the state.json", func() {
@@ -187,19 +246,381 @@ var _ = Describe("State", func() {
 
 				Expect(state.Bundle).To(Equal(bundlePath))
 				Expect(state.PID).To(Equal(888))
-				Expect(state.StartTime).To(Equal(syscall.Filetime{}))
+				Expect(state.StartTime).To(Equal(winsyscall.Filetime{}))
 				Expect(state.ExecFailed).To(Equal(true))
 			})
 
 			It("wraps the error", func() {
 				err := sm.SetSuccess(proc)
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("GetProcessStartTime: The handle is invalid."))
+				Expect(err.Error()).To(Equal("GetProcessStartTime: " + syscall.Errno(0x6).Error()))
 
 				Expect(sc.CloseHandleCallCount()).To(Equal(1))
 				Expect(sc.CloseHandleArgsForCall(0)).To(Equal(ph))
 			})
 		})
+
+		Context("the container is hyperv isolated", func() {
+			BeforeEach(func() {
+				Expect(sm.SetHyperV()).To(Succeed())
+			})
+
+			It("sets the pid without opening the process on the host", func() {
+				Expect(sm.SetSuccess(proc)).To(Succeed())
+
+				var state state.State
+				contents, err := ioutil.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &state)).To(Succeed())
+
+				Expect(state.PID).To(Equal(888))
+				Expect(state.StartTime).To(Equal(winsyscall.Filetime{}))
+				Expect(state.HyperV).To(BeTrue())
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("ProcessUser", func() {
+		var ph winsyscall.Handle
+
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+			ph = 0xbeef
+			sc.OpenProcessReturns(ph, nil)
+			sc.GetProcessUserReturns("User Manager\\ContainerUser", nil)
+		})
+
+		It("returns the user the process runs as", func() {
+			user, err := sm.ProcessUser(888)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user).To(Equal("User Manager\\ContainerUser"))
+
+			flags, inherit, pid := sc.OpenProcessArgsForCall(0)
+			Expect(flags).To(Equal(uint32(winsyscall.PROCESS_QUERY_INFORMATION)))
+			Expect(inherit).To(Equal(false))
+			Expect(pid).To(Equal(uint32(888)))
+
+			Expect(sc.GetProcessUserArgsForCall(0)).To(Equal(ph))
+			Expect(sc.CloseHandleArgsForCall(0)).To(Equal(ph))
+		})
+
+		Context("OpenProcess fails", func() {
+			BeforeEach(func() {
+				sc.OpenProcessReturns(0, syscall.Errno(0x5))
+			})
+
+			It("wraps the error", func() {
+				_, err := sm.ProcessUser(888)
+				Expect(err).To(MatchError("OpenProcess: " + syscall.Errno(0x5).Error()))
+			})
+		})
+
+		Context("GetProcessUser fails", func() {
+			BeforeEach(func() {
+				sc.GetProcessUserReturns("", syscall.Errno(0x6))
+			})
+
+			It("wraps the error and closes the handle", func() {
+				_, err := sm.ProcessUser(888)
+				Expect(err).To(MatchError("GetProcessUser: " + syscall.Errno(0x6).Error()))
+				Expect(sc.CloseHandleArgsForCall(0)).To(Equal(ph))
+			})
+		})
+
+		Context("the container is hyperv isolated", func() {
+			BeforeEach(func() {
+				Expect(sm.SetHyperV()).To(Succeed())
+			})
+
+			It("does not look up the pid on the host", func() {
+				user, err := sm.ProcessUser(888)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(user).To(BeEmpty())
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("HyperV", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+		})
+
+		It("reports whether the container is hyperv isolated", func() {
+			hyperV, err := sm.HyperV()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hyperV).To(BeFalse())
+
+			Expect(sm.SetHyperV()).To(Succeed())
+			hyperV, err = sm.HyperV()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hyperV).To(BeTrue())
+		})
+	})
+
+	Describe("SetResources", func() {
+		var (
+			memoryLimit uint64
+			shares      uint16
+		)
+
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+			memoryLimit = 1024 * 1024 * 1024
+			shares = 5000
+			Expect(sm.SetResources(&specs.WindowsResources{
+				Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+				CPU:    &specs.WindowsCPUResources{Shares: &shares},
+			})).To(Succeed())
+		})
+
+		It("records the resources in state.json", func() {
+			resources, err := sm.Resources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*resources.Memory.Limit).To(Equal(memoryLimit))
+			Expect(*resources.CPU.Shares).To(Equal(shares))
+		})
+
+		It("only changes the fields that are set", func() {
+			newShares := uint16(100)
+			Expect(sm.SetResources(&specs.WindowsResources{
+				CPU: &specs.WindowsCPUResources{Shares: &newShares},
+			})).To(Succeed())
+
+			resources, err := sm.Resources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*resources.Memory.Limit).To(Equal(memoryLimit))
+			Expect(*resources.CPU.Shares).To(Equal(newShares))
+		})
+
+		It("reports the resources as annotations of the oci state", func() {
+			ociState, err := sm.State()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.MemoryLimitAnnotation, "1073741824"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.CPUSharesAnnotation, "5000"))
+			Expect(ociState.Annotations).NotTo(HaveKey(state.CPUCountAnnotation))
+		})
+
+		It("reports storage limits as annotations of the oci state", func() {
+			iops, bps, sandboxSize := uint64(500), uint64(1048576), uint64(42949672960)
+			Expect(sm.SetResources(&specs.WindowsResources{
+				Storage: &specs.WindowsStorageResources{Iops: &iops, Bps: &bps, SandboxSize: &sandboxSize},
+			})).To(Succeed())
+
+			ociState, err := sm.State()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.StorageIopsAnnotation, "500"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.StorageBpsAnnotation, "1048576"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.SandboxSizeAnnotation, "42949672960"))
+		})
+
+		Context("the state has not been initialized", func() {
+			BeforeEach(func() {
+				Expect(sm.Delete()).To(Succeed())
+			})
+
+			It("errors", func() {
+				Expect(sm.SetResources(&specs.WindowsResources{})).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("SetExited", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+			Expect(sm.SetExited(3)).To(Succeed())
+		})
+
+		It("records the exit code in state.json", func() {
+			exit, err := sm.ExitStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit.Code).To(Equal(3))
+			Expect(exit.Time).To(BeTemporally("~", time.Now(), 10*time.Second))
+		})
+
+		It("reports the container as stopped with its exit code and time as annotations", func() {
+			ociState, err := sm.State()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState.Status).To(Equal("stopped"))
+			Expect(ociState.Annotations).To(HaveKeyWithValue(state.ExitCodeAnnotation, "3"))
+
+			exitTime, err := time.Parse(time.RFC3339Nano, ociState.Annotations[state.ExitTimeAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exitTime).To(BeTemporally("~", time.Now(), 10*time.Second))
+		})
+
+		Context("the state has not been initialized", func() {
+			BeforeEach(func() {
+				Expect(sm.Delete()).To(Succeed())
+			})
+
+			It("errors", func() {
+				Expect(sm.SetExited(3)).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("SetConsoleSocket", func() {
+		BeforeEach(func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+		})
+
+		It("records the console socket in state.json", func() {
+			consoleSocket, err := sm.ConsoleSocket()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(consoleSocket).To(BeEmpty())
+
+			Expect(sm.SetConsoleSocket("C:\\console.sock")).To(Succeed())
+
+			consoleSocket, err = sm.ConsoleSocket()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(consoleSocket).To(Equal("C:\\console.sock"))
+		})
+
+		Context("the state has not been initialized", func() {
+			BeforeEach(func() {
+				Expect(sm.Delete()).To(Succeed())
+			})
+
+			It("errors", func() {
+				Expect(sm.SetConsoleSocket("C:\\console.sock")).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Lock", func() {
+		var lockFile string
+
+		BeforeEach(func() {
+			lockFile = filepath.Join(rootDir, containerId+".lock")
+		})
+
+		It("takes a lock file next to the state directory", func() {
+			Expect(sm.Lock()).To(Succeed())
+			Expect(lockFile).To(BeAnExistingFile())
+			Expect(sm.Unlock()).To(Succeed())
+		})
+
+		It("excludes other managers of the same container until it is unlocked", func() {
+			other := state.New(logrus.NewEntry(logrus.New()), hcsClient, sc, containerId, rootDir, 50*time.Millisecond, "1.2.3")
+
+			Expect(sm.Lock()).To(Succeed())
+			Expect(other.Lock()).To(Equal(&state.LockTimeoutError{Id: containerId, Timeout: 50 * time.Millisecond}))
+
+			Expect(sm.Unlock()).To(Succeed())
+			Expect(other.Lock()).To(Succeed())
+			Expect(other.Unlock()).To(Succeed())
+		})
+
+		It("stops waiting for the lock once its context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			other := state.New(logrus.NewEntry(logrus.New()), hcsClient, sc, containerId, rootDir, time.Minute, "1.2.3").WithContext(ctx)
+
+			Expect(sm.Lock()).To(Succeed())
+			cancel()
+			Expect(other.Lock()).To(Equal(context.Canceled))
+			Expect(sm.Unlock()).To(Succeed())
+		})
+
+		It("does not exclude managers of other containers", func() {
+			other := state.New(logrus.NewEntry(logrus.New()), hcsClient, sc, "other-container", rootDir, 50*time.Millisecond, "1.2.3")
+
+			Expect(sm.Lock()).To(Succeed())
+			Expect(other.Lock()).To(Succeed())
+
+			Expect(other.Unlock()).To(Succeed())
+			Expect(sm.Unlock()).To(Succeed())
+		})
+
+		It("keeps the lock file while the container has state", func() {
+			Expect(sm.Lock()).To(Succeed())
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+			Expect(sm.Unlock()).To(Succeed())
+			Expect(lockFile).To(BeAnExistingFile())
+		})
+
+		It("removes the lock file once the container has been deleted", func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+			Expect(sm.Lock()).To(Succeed())
+			Expect(sm.Delete()).To(Succeed())
+			Expect(sm.Unlock()).To(Succeed())
+			Expect(lockFile).NotTo(BeAnExistingFile())
+		})
+
+		It("serializes concurrent changes to the state", func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+			Expect(sm.SetExited(0)).To(Succeed())
+
+			const workers = 20
+			errs := make(chan error, workers)
+			for i := 0; i < workers; i++ {
+				go func() {
+					defer GinkgoRecover()
+
+					m := state.New(logrus.NewEntry(logrus.New()), hcsClient, sc, containerId, rootDir, time.Minute, "1.2.3")
+					if err := m.Lock(); err != nil {
+						errs <- err
+						return
+					}
+					defer m.Unlock()
+
+					exit, err := m.ExitStatus()
+					if err != nil {
+						errs <- err
+						return
+					}
+					errs <- m.SetExited(exit.Code + 1)
+				}()
+			}
+
+			for i := 0; i < workers; i++ {
+				Expect(<-errs).To(Succeed())
+			}
+
+			exit, err := sm.ExitStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit.Code).To(Equal(workers))
+		})
+
+		It("never lets a reader see a partially written state", func() {
+			Expect(sm.Initialize(bundlePath, nil)).To(Succeed())
+
+			done := make(chan struct{})
+			readErrs := make(chan error, 1)
+			go func() {
+				defer GinkgoRecover()
+
+				reader := state.New(logrus.NewEntry(logrus.New()), hcsClient, sc, containerId, rootDir, time.Minute, "1.2.3")
+				for {
+					select {
+					case <-done:
+						readErrs <- nil
+						return
+					default:
+					}
+
+					if _, err := reader.ConsoleSocket(); err != nil {
+						readErrs <- err
+						return
+					}
+				}
+			}()
+
+			for i := 0; i < 100; i++ {
+				Expect(sm.SetConsoleSocket(fmt.Sprintf("C:\\console-%d.sock", i))).To(Succeed())
+			}
+			close(done)
+			Expect(<-readErrs).To(Succeed())
+
+			entries, err := ioutil.ReadDir(filepath.Dir(stateFile))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal("state.json"))
+		})
 	})
 
 	Describe("State", func() {
@@ -210,7 +631,7 @@ var _ = Describe("State", func() {
 			s = state.State{
 				PID:        1234,
 				Bundle:     bundlePath,
-				StartTime:  syscall.Filetime{HighDateTime: 123, LowDateTime: 456},
+				StartTime:  winsyscall.Filetime{HighDateTime: 123, LowDateTime: 456},
 				ExecFailed: false,
 			}
 
@@ -228,11 +649,12 @@ var _ = Describe("State", func() {
 			Expect(ociState.Pid).To(Equal(1234))
 			Expect(ociState.ID).To(Equal(containerId))
 			Expect(ociState.Version).To(Equal(specs.Version))
+			Expect(ociState.Annotations).To(BeNil())
 		})
 
 		Context("hcsshim reports the container as stopped", func() {
 			BeforeEach(func() {
-				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{Stopped: true}, nil)
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{Stopped: true}, nil)
 			})
 
 			It("reports the container is stopped", func() {
@@ -242,10 +664,23 @@ var _ = Describe("State", func() {
 			})
 		})
 
+		Context("hcsshim reports the container as paused", func() {
+			BeforeEach(func() {
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{State: "Paused"}, nil)
+			})
+
+			It("reports the container is paused without checking the init process", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Status).To(Equal("paused"))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("state.json has no pid and no stop time", func() {
 			BeforeEach(func() {
 				s.PID = 0
-				s.StartTime = syscall.Filetime{}
+				s.StartTime = winsyscall.Filetime{}
 				c, err := json.Marshal(s)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ioutil.WriteFile(stateFile, c, 0644)).To(Succeed())
@@ -259,12 +694,12 @@ var _ = Describe("State", func() {
 		})
 
 		Context("container init process is running", func() {
-			var ph syscall.Handle
+			var ph winsyscall.Handle
 
 			BeforeEach(func() {
 				ph = 0xf00d
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 123, LowDateTime: 456}, nil)
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 123, LowDateTime: 456}, nil)
 				sc.GetExitCodeProcessReturns(259, nil)
 			})
 
@@ -274,7 +709,7 @@ var _ = Describe("State", func() {
 				Expect(ociState.Status).To(Equal("running"))
 
 				flags, inherit, pid := sc.OpenProcessArgsForCall(0)
-				Expect(flags).To(Equal(uint32(syscall.PROCESS_QUERY_INFORMATION)))
+				Expect(flags).To(Equal(uint32(winsyscall.PROCESS_QUERY_INFORMATION)))
 				Expect(inherit).To(Equal(false))
 				Expect(pid).To(Equal(uint32(1234)))
 
@@ -300,19 +735,19 @@ var _ = Describe("State", func() {
 				Expect(ociState.Status).To(Equal("stopped"))
 
 				flags, inherit, pid := sc.OpenProcessArgsForCall(0)
-				Expect(flags).To(Equal(uint32(syscall.PROCESS_QUERY_INFORMATION)))
+				Expect(flags).To(Equal(uint32(winsyscall.PROCESS_QUERY_INFORMATION)))
 				Expect(inherit).To(Equal(false))
 				Expect(pid).To(Equal(uint32(1234)))
 			})
 		})
 
 		Context("a process with container init pid is running, but with a different start time", func() {
-			var ph syscall.Handle
+			var ph winsyscall.Handle
 
 			BeforeEach(func() {
 				ph = 0xf00d
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
 				sc.GetExitCodeProcessReturns(259, nil)
 			})
 
@@ -322,7 +757,7 @@ var _ = Describe("State", func() {
 				Expect(ociState.Status).To(Equal("stopped"))
 
 				flags, inherit, pid := sc.OpenProcessArgsForCall(0)
-				Expect(flags).To(Equal(uint32(syscall.PROCESS_QUERY_INFORMATION)))
+				Expect(flags).To(Equal(uint32(winsyscall.PROCESS_QUERY_INFORMATION)))
 				Expect(inherit).To(Equal(false))
 				Expect(pid).To(Equal(uint32(1234)))
 
@@ -338,12 +773,12 @@ var _ = Describe("State", func() {
 		})
 
 		Context("init process has exited", func() {
-			var ph syscall.Handle
+			var ph winsyscall.Handle
 
 			BeforeEach(func() {
 				ph = 0xf00d
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
 				sc.GetExitCodeProcessReturns(0, nil)
 			})
 
@@ -362,12 +797,12 @@ var _ = Describe("State", func() {
 		})
 
 		Context("getting process exit code fails", func() {
-			var ph syscall.Handle
+			var ph winsyscall.Handle
 
 			BeforeEach(func() {
 				ph = 0xf00d
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{HighDateTime: 123, LowDateTime: 789}, nil)
 				sc.GetExitCodeProcessReturns(0, errors.New("failed to get exit code for process"))
 			})
 
@@ -395,7 +830,7 @@ var _ = Describe("State", func() {
 
 		Context("getting container properties fails", func() {
 			BeforeEach(func() {
-				hcsClient.GetContainerPropertiesReturns(hcsshim.ContainerProperties{}, errors.New("hcsshim failed"))
+				hcsClient.GetContainerPropertiesReturns(hcs.ContainerProperties{}, errors.New("hcsshim failed"))
 			})
 
 			It("returns the error", func() {
@@ -413,24 +848,24 @@ var _ = Describe("State", func() {
 			It("wraps the error", func() {
 				_, err := sm.State()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("OpenProcess: Access is denied."))
+				Expect(err.Error()).To(Equal("OpenProcess: " + syscall.Errno(0x5).Error()))
 			})
 		})
 
 		Context("getprocesstimes fails", func() {
-			var ph syscall.Handle
+			var ph winsyscall.Handle
 
 			BeforeEach(func() {
 				ph = 0xf00d
 				sc.OpenProcessReturns(ph, nil)
-				sc.GetProcessStartTimeReturns(syscall.Filetime{}, syscall.Errno(0x6))
+				sc.GetProcessStartTimeReturns(winsyscall.Filetime{}, syscall.Errno(0x6))
 				sc.GetExitCodeProcessReturns(259, nil)
 			})
 
 			It("wraps the error", func() {
 				_, err := sm.State()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("GetProcessStartTime: The handle is invalid."))
+				Expect(err.Error()).To(Equal("GetProcessStartTime: " + syscall.Errno(0x6).Error()))
 
 				Expect(sc.CloseHandleCallCount()).To(Equal(1))
 				Expect(sc.CloseHandleArgsForCall(0)).To(Equal(ph))
@@ -439,7 +874,7 @@ var _ = Describe("State", func() {
 
 		Context("pid is set in state.json but no start time is set", func() {
 			BeforeEach(func() {
-				s.StartTime = syscall.Filetime{}
+				s.StartTime = winsyscall.Filetime{}
 				c, err := json.Marshal(s)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(ioutil.WriteFile(stateFile, c, 0644)).To(Succeed())
@@ -452,6 +887,55 @@ var _ = Describe("State", func() {
 			})
 		})
 
+		Context("the container is hyperv isolated", func() {
+			var fakeContainer *hcsfakes.Container
+
+			BeforeEach(func() {
+				s.StartTime = winsyscall.Filetime{}
+				s.HyperV = true
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(stateFile, c, 0644)).To(Succeed())
+
+				fakeContainer = &hcsfakes.Container{}
+				hcsClient.OpenContainerReturns(fakeContainer, nil)
+				fakeContainer.ProcessListReturns([]hcs.ProcessListItem{{ProcessId: 1234}}, nil)
+			})
+
+			It("reports the container is running when the init process is in its process list", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Status).To(Equal("running"))
+
+				Expect(hcsClient.OpenContainerArgsForCall(0)).To(Equal(containerId))
+				Expect(fakeContainer.CloseCallCount()).To(Equal(1))
+				Expect(sc.OpenProcessCallCount()).To(Equal(0))
+			})
+
+			Context("the init process is not in its process list", func() {
+				BeforeEach(func() {
+					fakeContainer.ProcessListReturns([]hcs.ProcessListItem{{ProcessId: 5678}}, nil)
+				})
+
+				It("reports the container is stopped", func() {
+					ociState, err := sm.State()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ociState.Status).To(Equal("stopped"))
+				})
+			})
+
+			Context("listing the processes fails", func() {
+				BeforeEach(func() {
+					fakeContainer.ProcessListReturns(nil, errors.New("couldn't list"))
+				})
+
+				It("wraps the error", func() {
+					_, err := sm.State()
+					Expect(err).To(MatchError("ProcessList: couldn't list"))
+				})
+			})
+		})
+
 		Context("start time is set in state.json but no pid is set", func() {
 			BeforeEach(func() {
 				s.PID = 0
@@ -2,10 +2,12 @@ package runtime_test
 
 import (
 	"errors"
+	"time"
 
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -27,13 +29,14 @@ var _ = Describe("State", func() {
 		cm               *fakes.ContainerManager
 		processWrapper   *fakes.ProcessWrapper
 		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
 		r                *runtime.Runtime
-		output           *gbytes.Buffer
 	)
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
 		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
 		containerFactory = &fakes.ContainerFactory{}
@@ -43,26 +46,18 @@ var _ = Describe("State", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		output = gbytes.NewBuffer()
-
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir)
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
 	})
 
 	Context("state succeeds", func() {
-		var expectedJSON string
-
 		BeforeEach(func() {
 			sm.StateReturns(&specs.State{ID: containerId}, nil)
-			expectedJSON = `{
-  "ociVersion": "",
-  "id": "container-for-state",
-  "status": "",
-  "bundle": ""
-}`
 		})
 
-		It("writes the state to output", func() {
-			Expect(r.State(containerId, output)).To(Succeed())
+		It("returns the state", func() {
+			ociState, err := r.State(containerId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ociState).To(Equal(&specs.State{ID: containerId}))
 
 			_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
 			Expect(*c).To(Equal(hcs.Client{}))
@@ -70,7 +65,19 @@ var _ = Describe("State", func() {
 			Expect(id).To(Equal(containerId))
 			Expect(rd).To(Equal(rootDir))
 
-			Expect(string(output.Contents())).To(Equal(expectedJSON))
+			Expect(sm.LockCallCount()).To(Equal(1))
+			Expect(sm.UnlockCallCount()).To(Equal(1))
+		})
+
+		Context("the runtime has a tracer", func() {
+			It("records the calls to HCS with it", func() {
+				tracer := hcs.NewTracer(gbytes.NewBuffer())
+				_, err := r.WithTracer(tracer).State(containerId)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, c, _, _, _ := stateFactory.NewManagerArgsForCall(0)
+				Expect(c.Tracer).To(BeIdenticalTo(tracer))
+			})
 		})
 	})
 
@@ -80,15 +87,69 @@ var _ = Describe("State", func() {
 		})
 
 		It("returns an error", func() {
-			err := r.State(containerId, output)
+			_, err := r.State(containerId)
 			Expect(err).To(MatchError("couldn't get state"))
 		})
 	})
 
-	Context("provided output is nil", func() {
-		It("returns an error", func() {
-			err := r.State(containerId, nil)
-			Expect(err).To(MatchError("provided output is nil"))
+	Describe("ProcessState", func() {
+		var created time.Time
+
+		BeforeEach(func() {
+			created = time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+			sm.ProcessReturns(&state.Process{
+				ID:      "some-exec",
+				PID:     123,
+				Created: created,
+				Args:    []string{"cmd.exe", "/c", "ver"},
+				User:    "vcap",
+				Detach:  true,
+			}, nil)
+			sm.ProcessStatusReturns("running", nil)
+		})
+
+		It("returns the state of the process", func() {
+			processState, err := r.ProcessState(containerId, "some-exec")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+			Expect(sm.StateCallCount()).To(Equal(0))
+
+			Expect(processState).To(Equal(&runtime.ProcessState{
+				ContainerID: containerId,
+				ExecID:      "some-exec",
+				Pid:         123,
+				Status:      "running",
+				Created:     created,
+				Args:        []string{"cmd.exe", "/c", "ver"},
+				User:        "vcap",
+				Detach:      true,
+			}))
+		})
+
+		Context("the exit code of the process has been recorded", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 123, Exit: &state.ExitStatus{Code: 4}}, nil)
+				sm.ProcessStatusReturns("stopped", nil)
+			})
+
+			It("returns the exit code with the state of the process", func() {
+				processState, err := r.ProcessState(containerId, "some-exec")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(processState.Status).To(Equal("stopped"))
+				Expect(*processState.ExitCode).To(Equal(4))
+			})
+		})
+
+		Context("getting the status of the process fails", func() {
+			BeforeEach(func() {
+				sm.ProcessStatusReturns("", errors.New("couldn't get status"))
+			})
+
+			It("returns an error", func() {
+				_, err := r.ProcessState(containerId, "some-exec")
+				Expect(err).To(MatchError("couldn't get status"))
+			})
 		})
 	})
 })
@@ -0,0 +1,165 @@
+package runtime_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/config"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Update", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-update"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+		output           *gbytes.Buffer
+		overrides        *specs.WindowsResources
+		memoryLimit      uint64
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+		output = gbytes.NewBuffer()
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+		sm.StateReturns(&specs.State{Status: "running"}, nil)
+
+		memoryLimit = 1024 * 1024 * 1024
+		overrides = &specs.WindowsResources{
+			Memory: &specs.WindowsMemoryResources{Limit: &memoryLimit},
+		}
+		cm.UpdateReturns(container.UpdateResult{
+			Accepted: overrides,
+			Rejected: map[string]string{},
+		}, nil)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+	})
+
+	It("updates the container and records the accepted limits", func() {
+		Expect(r.Update(containerId, "", overrides, output)).To(Succeed())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		Expect(cm.UpdateArgsForCall(0)).To(Equal(overrides))
+		Expect(sm.SetResourcesArgsForCall(0)).To(Equal(overrides))
+		Expect(output.Contents()).To(MatchJSON(`{"accepted": {"memory": {"limit": 1073741824}}, "rejected": {}}`))
+	})
+
+	Context("a resources file is provided", func() {
+		var resourcesFile string
+
+		BeforeEach(func() {
+			dir, err := ioutil.TempDir("", "update.test")
+			Expect(err).NotTo(HaveOccurred())
+			resourcesFile = filepath.Join(dir, "resources.json")
+			Expect(ioutil.WriteFile(resourcesFile, []byte(`{"memory": {"limit": 1024}, "cpu": {"shares": 100}}`), 0644)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(filepath.Dir(resourcesFile))).To(Succeed())
+		})
+
+		It("applies the file with the overrides taking precedence", func() {
+			Expect(r.Update(containerId, resourcesFile, overrides, output)).To(Succeed())
+
+			resources := cm.UpdateArgsForCall(0)
+			Expect(*resources.Memory.Limit).To(Equal(memoryLimit))
+			Expect(*resources.CPU.Shares).To(Equal(uint16(100)))
+		})
+	})
+
+	Context("no resources are given", func() {
+		It("returns a validation error", func() {
+			err := r.Update(containerId, "", &specs.WindowsResources{}, output)
+			Expect(err).To(BeAssignableToTypeOf(&config.ResourcesConfigValidationError{}))
+			Expect(cm.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the container has stopped", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "stopped"}, nil)
+		})
+
+		It("returns an InvalidStateError", func() {
+			err := r.Update(containerId, "", overrides, output)
+			Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "update", State: "stopped"}))
+			Expect(cm.UpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("HCS rejects some of the limits", func() {
+		BeforeEach(func() {
+			cm.UpdateReturns(container.UpdateResult{
+				Accepted: overrides,
+				Rejected: map[string]string{"cpu.shares": "not supported", "cpu.count": "not supported"},
+			}, nil)
+		})
+
+		It("records the accepted limits, reports both, and returns an error naming the rejected ones", func() {
+			err := r.Update(containerId, "", overrides, output)
+			Expect(err).To(Equal(&container.ResourcesRejectedError{Id: containerId, Fields: []string{"cpu.count", "cpu.shares"}}))
+
+			Expect(sm.SetResourcesArgsForCall(0)).To(Equal(overrides))
+			Expect(output.Contents()).To(MatchJSON(`{
+				"accepted": {"memory": {"limit": 1073741824}},
+				"rejected": {"cpu.shares": "not supported", "cpu.count": "not supported"}
+			}`))
+		})
+	})
+
+	Context("updating the container fails", func() {
+		BeforeEach(func() {
+			cm.UpdateReturns(container.UpdateResult{}, errors.New("open failed"))
+		})
+
+		It("returns the error", func() {
+			Expect(r.Update(containerId, "", overrides, output)).To(MatchError("open failed"))
+			Expect(sm.SetResourcesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("update is passed a nil io.Writer", func() {
+		It("returns an error", func() {
+			Expect(r.Update(containerId, "", overrides, nil)).To(MatchError("provided output is nil"))
+		})
+	})
+})
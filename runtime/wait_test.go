@@ -0,0 +1,282 @@
+package runtime_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/state"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Wait", func() {
+	const (
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-to-wait-on"
+	)
+	var (
+		mounter          *fakes.Mounter
+		stateFactory     *fakes.StateFactory
+		sm               *fakes.StateManager
+		containerFactory *fakes.ContainerFactory
+		cm               *fakes.ContainerManager
+		processWrapper   *fakes.ProcessWrapper
+		hcsQuery         *fakes.HCSQuery
+		hookRunner       *fakes.HookRunner
+		r                *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		hcsQuery = &fakes.HCSQuery{}
+		hookRunner = &fakes.HookRunner{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, hookRunner, rootDir)
+
+		sm.StateReturns(&specs.State{Status: "running", Pid: 88}, nil)
+		cm.WaitReturns(6, nil)
+	})
+
+	It("waits for the init process, records and returns its exit code", func() {
+		exit, err := r.Wait(containerId, 0, "", 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, c, id := containerFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(id).To(Equal(containerId))
+
+		_, c, wc, id, rd := stateFactory.NewManagerArgsForCall(0)
+		Expect(*c).To(Equal(hcs.Client{}))
+		Expect(*wc).To(Equal(winsyscall.WinSyscall{}))
+		Expect(id).To(Equal(containerId))
+		Expect(rd).To(Equal(rootDir))
+
+		pid, timeout := cm.WaitArgsForCall(0)
+		Expect(pid).To(Equal(88))
+		Expect(timeout).To(BeZero())
+		Expect(sm.SetExitedArgsForCall(0)).To(Equal(6))
+		Expect(exit).To(Equal(runtime.ProcessExit{Pid: 88, ExitCode: 6}))
+	})
+
+	Context("an exec pid and a timeout are provided", func() {
+		It("waits for the exec'd process for at most the timeout", func() {
+			exit, err := r.Wait(containerId, 123, "", time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			pid, timeout := cm.WaitArgsForCall(0)
+			Expect(pid).To(Equal(123))
+			Expect(timeout).To(Equal(time.Minute))
+			Expect(sm.SetExitedCallCount()).To(Equal(0))
+			Expect(exit).To(Equal(runtime.ProcessExit{Pid: 123, ExitCode: 6}))
+		})
+
+		Context("waiting fails", func() {
+			BeforeEach(func() {
+				cm.WaitReturns(-1, errors.New("couldn't open process"))
+			})
+
+			It("returns the error without falling back to the recorded exit code", func() {
+				_, err := r.Wait(containerId, 123, "", time.Minute)
+				Expect(err).To(MatchError("couldn't open process"))
+				Expect(sm.ExitStatusCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the pid belongs to a process started by winc exec", func() {
+			BeforeEach(func() {
+				sm.ProcessesReturns([]state.Process{{ID: "other-exec", PID: 456}, {ID: "some-exec", PID: 123}}, nil)
+			})
+
+			It("removes its record once it has exited", func() {
+				_, err := r.Wait(containerId, 123, "", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+			})
+
+			Context("the process has already exited", func() {
+				BeforeEach(func() {
+					sm.ProcessesReturns([]state.Process{{ID: "some-exec", PID: 123, Exit: &state.ExitStatus{Code: 4}}}, nil)
+				})
+
+				It("returns the recorded exit code without waiting", func() {
+					exit, err := r.Wait(containerId, 123, "", 0)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cm.WaitCallCount()).To(Equal(0))
+					Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+					Expect(exit).To(Equal(runtime.ProcessExit{Pid: 123, ExitCode: 4}))
+				})
+			})
+		})
+	})
+
+	Context("an exec id is provided", func() {
+		BeforeEach(func() {
+			sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 123}, nil)
+		})
+
+		It("waits for the process started under that exec id and removes its record", func() {
+			exit, err := r.Wait(containerId, 0, "some-exec", 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sm.ProcessArgsForCall(0)).To(Equal("some-exec"))
+			pid, _ := cm.WaitArgsForCall(0)
+			Expect(pid).To(Equal(123))
+			Expect(sm.SetExitedCallCount()).To(Equal(0))
+			Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+			Expect(exit).To(Equal(runtime.ProcessExit{Pid: 123, ExitCode: 6}))
+		})
+
+		Context("waiting fails", func() {
+			BeforeEach(func() {
+				cm.WaitReturns(-1, &container.WaitTimeoutError{Id: containerId, Pid: 123, Timeout: time.Second})
+			})
+
+			It("returns the error and keeps the record", func() {
+				_, err := r.Wait(containerId, 0, "some-exec", time.Second)
+				Expect(err).To(Equal(&container.WaitTimeoutError{Id: containerId, Pid: 123, Timeout: time.Second}))
+				Expect(sm.DeleteProcessCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the process has already exited", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(&state.Process{ID: "some-exec", PID: 123, Exit: &state.ExitStatus{Code: 4}}, nil)
+			})
+
+			It("returns the recorded exit code without waiting and removes the record", func() {
+				exit, err := r.Wait(containerId, 0, "some-exec", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cm.WaitCallCount()).To(Equal(0))
+				Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+				Expect(exit).To(Equal(runtime.ProcessExit{Pid: 123, ExitCode: 4}))
+			})
+		})
+
+		Context("the process exits before it can be opened", func() {
+			BeforeEach(func() {
+				cm.WaitReturns(-1, errors.New("couldn't open process"))
+				sm.ProcessReturnsOnCall(1, &state.Process{ID: "some-exec", PID: 123, Exit: &state.ExitStatus{Code: 4}}, nil)
+			})
+
+			It("falls back to the exit code recorded by the monitor", func() {
+				exit, err := r.Wait(containerId, 0, "some-exec", 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit.ExitCode).To(Equal(4))
+				Expect(sm.DeleteProcessArgsForCall(0)).To(Equal("some-exec"))
+			})
+
+			Context("no exit code was recorded", func() {
+				BeforeEach(func() {
+					sm.ProcessReturnsOnCall(1, &state.Process{ID: "some-exec", PID: 123}, nil)
+				})
+
+				It("returns the error and keeps the record", func() {
+					_, err := r.Wait(containerId, 0, "some-exec", 0)
+					Expect(err).To(MatchError("couldn't open process"))
+					Expect(sm.DeleteProcessCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("there is no process with that exec id", func() {
+			BeforeEach(func() {
+				sm.ProcessReturns(nil, &state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"})
+			})
+
+			It("returns an error without waiting", func() {
+				_, err := r.Wait(containerId, 0, "some-exec", 0)
+				Expect(err).To(Equal(&state.ProcessNotFoundError{Id: containerId, ExecId: "some-exec"}))
+				Expect(cm.WaitCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("the init process has already exited", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "stopped", Pid: 88}, nil)
+			sm.ExitStatusReturns(&state.ExitStatus{Code: 3, Time: time.Now()}, nil)
+		})
+
+		It("returns the recorded exit code without waiting", func() {
+			exit, err := r.Wait(containerId, 0, "", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cm.WaitCallCount()).To(Equal(0))
+			Expect(exit).To(Equal(runtime.ProcessExit{Pid: 88, ExitCode: 3}))
+		})
+
+		Context("no exit code was recorded", func() {
+			BeforeEach(func() {
+				sm.ExitStatusReturns(nil, nil)
+			})
+
+			It("returns a NoExitStatusError", func() {
+				_, err := r.Wait(containerId, 0, "", 0)
+				Expect(err).To(Equal(&runtime.NoExitStatusError{Id: containerId}))
+			})
+		})
+	})
+
+	Context("the init process exits before it can be opened", func() {
+		BeforeEach(func() {
+			cm.WaitReturns(-1, errors.New("process not found"))
+			sm.ExitStatusReturns(&state.ExitStatus{Code: 2, Time: time.Now()}, nil)
+		})
+
+		It("falls back to the recorded exit code", func() {
+			exit, err := r.Wait(containerId, 0, "", 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit.ExitCode).To(Equal(2))
+		})
+	})
+
+	Context("the wait times out", func() {
+		var timeoutErr error
+
+		BeforeEach(func() {
+			timeoutErr = &container.WaitTimeoutError{Id: containerId, Pid: 88, Timeout: time.Second}
+			cm.WaitReturns(-1, timeoutErr)
+		})
+
+		It("returns the error", func() {
+			_, err := r.Wait(containerId, 0, "", time.Second)
+			Expect(err).To(Equal(timeoutErr))
+			Expect(sm.ExitStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("the container has not been started", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "created"}, nil)
+		})
+
+		It("returns an InvalidStateError", func() {
+			_, err := r.Wait(containerId, 0, "", 0)
+			Expect(err).To(Equal(&container.InvalidStateError{Id: containerId, Action: "wait on", State: "created"}))
+		})
+	})
+
+	Context("getting the state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns an error", func() {
+			_, err := r.Wait(containerId, 0, "", 0)
+			Expect(err).To(MatchError("couldn't get state"))
+		})
+	})
+})
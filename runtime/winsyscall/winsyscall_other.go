@@ -0,0 +1,58 @@
+// +build !windows
+
+package winsyscall
+
+import (
+	"errors"
+)
+
+// PROCESS_QUERY_INFORMATION is the access right OpenProcess needs to query a
+// process.
+const PROCESS_QUERY_INFORMATION = 0x0400
+
+// Handle and Filetime stand in for the Windows types of package syscall, so
+// that the packages using them build on platforms without them.
+type Handle uintptr
+
+type Filetime struct {
+	LowDateTime  uint32
+	HighDateTime uint32
+}
+
+// NsecToFiletime converts nanoseconds since the Unix epoch to a Filetime, as
+// syscall.NsecToFiletime does on Windows.
+func NsecToFiletime(nsec int64) Filetime {
+	// a Filetime counts 100-nanosecond intervals since January 1, 1601
+	nsec /= 100
+	nsec += 116444736000000000
+
+	return Filetime{
+		LowDateTime:  uint32(nsec & 0xffffffff),
+		HighDateTime: uint32(nsec >> 32 & 0xffffffff),
+	}
+}
+
+var errNotSupported = errors.New("winsyscall: not supported on this platform")
+
+// WinSyscall fails every call on platforms other than Windows.
+type WinSyscall struct{}
+
+func (w *WinSyscall) OpenProcess(flags uint32, inherit bool, pid uint32) (Handle, error) {
+	return 0, errNotSupported
+}
+
+func (w *WinSyscall) GetProcessStartTime(handle Handle) (Filetime, error) {
+	return Filetime{}, errNotSupported
+}
+
+func (w *WinSyscall) CloseHandle(handle Handle) error {
+	return errNotSupported
+}
+
+func (w *WinSyscall) GetExitCodeProcess(handle Handle) (uint32, error) {
+	return 0, errNotSupported
+}
+
+func (w *WinSyscall) GetProcessUser(handle Handle) (string, error) {
+	return "", errNotSupported
+}
@@ -0,0 +1,73 @@
+package winsyscall
+
+import (
+	"syscall"
+)
+
+// PROCESS_QUERY_INFORMATION is the access right OpenProcess needs to query a
+// process.
+const PROCESS_QUERY_INFORMATION = syscall.PROCESS_QUERY_INFORMATION
+
+// Handle and Filetime are the Windows types of package syscall, which other
+// platforms define in winsyscall_other.go.
+type (
+	Handle   = syscall.Handle
+	Filetime = syscall.Filetime
+)
+
+// NsecToFiletime converts nanoseconds since the Unix epoch to a Filetime.
+func NsecToFiletime(nsec int64) Filetime {
+	return syscall.NsecToFiletime(nsec)
+}
+
+type WinSyscall struct{}
+
+func (w *WinSyscall) OpenProcess(flags uint32, inherit bool, pid uint32) (Handle, error) {
+	return syscall.OpenProcess(flags, inherit, pid)
+}
+
+func (w *WinSyscall) GetProcessStartTime(handle Handle) (Filetime, error) {
+	var (
+		creationTime Filetime
+		exitTime     Filetime
+		kernelTime   Filetime
+		userTime     Filetime
+	)
+
+	if err := syscall.GetProcessTimes(handle, &creationTime, &exitTime, &kernelTime, &userTime); err != nil {
+		return Filetime{}, err
+	}
+	return creationTime, nil
+}
+
+func (w *WinSyscall) CloseHandle(handle Handle) error {
+	return syscall.CloseHandle(handle)
+}
+
+func (w *WinSyscall) GetExitCodeProcess(handle Handle) (uint32, error) {
+	var exitCode uint32
+	err := syscall.GetExitCodeProcess(handle, &exitCode)
+	return exitCode, err
+}
+
+func (w *WinSyscall) GetProcessUser(handle Handle) (string, error) {
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(handle, syscall.TOKEN_QUERY, &token); err != nil {
+		return "", err
+	}
+	defer token.Close()
+
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return "", err
+	}
+
+	// container users such as ContainerUser are virtual accounts that the
+	// host may not be able to resolve, so fall back to the raw SID
+	account, domain, _, err := tokenUser.User.Sid.LookupAccount("")
+	if err != nil {
+		return tokenUser.User.Sid.String()
+	}
+
+	return domain + "\\" + account, nil
+}